@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"mcp-architecture-service/pkg/logging"
+)
+
+// maxChildLogLineSize caps a single stderr line from the child. Structured log
+// entries carrying sample errors can exceed bufio.Scanner's 64KB default.
+const maxChildLogLineSize = 1024 * 1024
+
+// Keys written by the child's structured logger that the bridge logger sets itself
+var childLogReservedKeys = map[string]bool{
+	"timestamp": true,
+	"time":      true,
+	"level":     true,
+	"message":   true,
+	"msg":       true,
+}
+
+// forwardChildLogs reads the child's stderr line by line. JSON log entries are
+// re-emitted through the session logger at their original level, tagged with
+// source=child so they can be told apart from the bridge's own entries and tied
+// to a session. Anything else (panics, plain prints) is passed through verbatim.
+// If a line cannot be scanned, for example because it is longer than
+// maxChildLogLineSize, the rest of stderr is passed through verbatim so the child
+// never blocks writing to a full pipe.
+func (s *MCPSession) forwardChildLogs(stderr io.Reader, passthrough io.Writer) {
+	scanner := bufio.NewScanner(stderr)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxChildLogLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !s.reemitChildLog(line) {
+			fmt.Fprintln(passthrough, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		s.logger.WithError(err).
+			WithContext("source", "child").
+			Warn("Cannot scan child stderr, passing the rest through verbatim")
+		io.Copy(passthrough, stderr)
+	}
+}
+
+// reemitChildLog logs a structured child entry, returning false if line is not one
+func (s *MCPSession) reemitChildLog(line string) bool {
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return false
+	}
+
+	level, ok := entry["level"].(string)
+	if !ok {
+		return false
+	}
+	message, _ := entry["message"].(string)
+	if message == "" {
+		message, _ = entry["msg"].(string)
+	}
+
+	logger := s.logger.WithContext("source", "child")
+	for key, value := range entry {
+		if childLogReservedKeys[key] {
+			continue
+		}
+		// The bridge logger stamps its own component, keep the child's alongside it
+		if key == "component" {
+			key = "child_component"
+		}
+		logger = logger.WithContext(key, value)
+	}
+
+	logAtLevel(logger, level, message)
+	return true
+}
+
+// logAtLevel emits message at the named slog level, defaulting to INFO
+func logAtLevel(logger *logging.StructuredLogger, level, message string) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		logger.Debug(message)
+	case "WARN", "WARNING":
+		logger.Warn(message)
+	case "ERROR":
+		logger.Error(message)
+	default:
+		logger.Info(message)
+	}
+}
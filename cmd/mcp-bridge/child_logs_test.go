@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/pkg/logging"
+)
+
+// newCapturedSession returns a session whose logger writes into a pipe instead of
+// the real stderr, plus a function that returns the decoded log entries
+func newCapturedSession(t *testing.T, logLevel string) (*MCPSession, func() []map[string]interface{}) {
+	t.Helper()
+
+	logger, collect := newCapturedLogger(t, logLevel)
+	session := &MCPSession{
+		id:     "session_test",
+		logger: logger.WithContext("session_id", "session_test"),
+	}
+	return session, collect
+}
+
+// newCapturedLogger returns a bridge logger that writes into a pipe instead of the
+// real stderr, plus a function that returns the decoded log entries
+func newCapturedLogger(t *testing.T, logLevel string) (*logging.StructuredLogger, func() []map[string]interface{}) {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	// The structured logger binds to os.Stderr at creation time
+	originalStderr := os.Stderr
+	os.Stderr = writer
+	manager := logging.NewLoggingManager()
+	manager.SetLogLevel(logLevel)
+	logger := manager.GetLogger("bridge")
+	os.Stderr = originalStderr
+
+	collect := func() []map[string]interface{} {
+		writer.Close()
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+
+		var entries []map[string]interface{}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var entry map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				t.Fatalf("Bridge emitted non-JSON log line: %s", scanner.Text())
+			}
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	return logger, collect
+}
+
+func TestForwardChildLogs_ReemitsJSONWithSessionContext(t *testing.T) {
+	session, collect := newCapturedSession(t, "DEBUG")
+
+	childOutput := `{"timestamp":"2025-01-01T00:00:00Z","level":"WARN","message":"Failed to load prompts","component":"prompts","prompt_count":3}` + "\n" +
+		`{"timestamp":"2025-01-01T00:00:01Z","level":"ERROR","message":"Tool execution failed","component":"tools"}` + "\n"
+
+	var passthrough bytes.Buffer
+	session.forwardChildLogs(strings.NewReader(childOutput), &passthrough)
+	entries := collect()
+
+	if passthrough.Len() != 0 {
+		t.Errorf("Expected no passthrough output for JSON logs, got %q", passthrough.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 re-emitted entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	checks := map[string]interface{}{
+		"level":           "WARN",
+		"message":         "Failed to load prompts",
+		"source":          "child",
+		"session_id":      "session_test",
+		"component":       "bridge",
+		"child_component": "prompts",
+		"prompt_count":    float64(3),
+	}
+	for key, want := range checks {
+		if first[key] != want {
+			t.Errorf("Entry field %s = %v, want %v", key, first[key], want)
+		}
+	}
+
+	if entries[1]["level"] != "ERROR" {
+		t.Errorf("Expected ERROR level preserved, got %v", entries[1]["level"])
+	}
+}
+
+func TestForwardChildLogs_PassesThroughPlainLines(t *testing.T) {
+	session, collect := newCapturedSession(t, "DEBUG")
+
+	childOutput := "panic: runtime error: index out of range\n" +
+		"goroutine 1 [running]:\n" +
+		`{"no_level":"just json"}` + "\n"
+
+	var passthrough bytes.Buffer
+	session.forwardChildLogs(strings.NewReader(childOutput), &passthrough)
+	entries := collect()
+
+	if passthrough.String() != childOutput {
+		t.Errorf("Plain lines not preserved verbatim:\ngot  %q\nwant %q", passthrough.String(), childOutput)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no re-emitted entries, got %d", len(entries))
+	}
+}
+
+func TestForwardChildLogs_DrainsAfterOversizedLine(t *testing.T) {
+	session, collect := newCapturedSession(t, "DEBUG")
+
+	after := "panic: after the long line\n" + `{"level":"INFO","message":"Server ready"}` + "\n"
+	childOutput := strings.Repeat("x", maxChildLogLineSize+1) + "\n" + after
+
+	var passthrough bytes.Buffer
+	session.forwardChildLogs(strings.NewReader(childOutput), &passthrough)
+	entries := collect()
+
+	if !strings.HasSuffix(passthrough.String(), after) {
+		t.Errorf("Expected output after the oversized line to be passed through, got %d bytes", passthrough.Len())
+	}
+	if len(entries) != 1 || entries[0]["level"] != "WARN" {
+		t.Errorf("Expected one warning about the unscannable line, got %v", entries)
+	}
+}
+
+func TestForwardChildLogs_RespectsBridgeLogLevel(t *testing.T) {
+	session, collect := newCapturedSession(t, "WARN")
+
+	childOutput := `{"level":"INFO","message":"Server ready"}` + "\n" +
+		`{"level":"ERROR","message":"Shutdown failed"}` + "\n"
+
+	session.forwardChildLogs(strings.NewReader(childOutput), io.Discard)
+	entries := collect()
+
+	if len(entries) != 1 || entries[0]["message"] != "Shutdown failed" {
+		t.Errorf("Expected only the ERROR entry to pass the WARN filter, got %v", entries)
+	}
+}
+
+func TestForwardServerToClient_LogsSkippedBannerLines(t *testing.T) {
+	session, collect := newCapturedSession(t, "INFO")
+	session.skipBanner = true
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	session.conn = serverConn
+	session.stdout = io.NopCloser(strings.NewReader("Starting server...\n" +
+		`{"jsonrpc":"2.0","id":1,"result":{}}` + "\n"))
+
+	received := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(clientConn).ReadString('\n')
+		received <- line
+	}()
+
+	session.forwardServerToClient()
+	if line := <-received; line != `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n" {
+		t.Errorf("Expected the JSON-RPC message to be forwarded, got %q", line)
+	}
+
+	entries := collect()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one log entry for the skipped line, got %v", entries)
+	}
+	if entries[0]["level"] != "WARN" || entries[0]["line"] != "Starting server..." || entries[0]["session_id"] != "session_test" {
+		t.Errorf("Unexpected skipped line entry: %v", entries[0])
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net"
+)
+
+// clientIP returns the IP address a connection comes from, or "" when the peer has
+// none, such as a unix socket peer. IPv6 addresses are returned without a zone so
+// one host counts once.
+func clientIP(conn net.Conn) string {
+	switch addr := conn.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		if addr.IP == nil {
+			return ""
+		}
+		return addr.IP.String()
+	case nil:
+		return ""
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil || net.ParseIP(host) == nil {
+			return ""
+		}
+		return host
+	}
+}
+
+// reserveIPSession takes one of ip's session slots, returning false if
+// maxSessionsPerIP sessions from ip are already open. Connections without an IP
+// are not limited. A successful reservation is released with releaseIPSession.
+func (b *MCPBridge) reserveIPSession(ip string) bool {
+	if b.maxSessionsPerIP <= 0 || ip == "" {
+		return true
+	}
+
+	b.ipMu.Lock()
+	defer b.ipMu.Unlock()
+
+	if b.ipSessions[ip] >= b.maxSessionsPerIP {
+		return false
+	}
+	if b.ipSessions == nil {
+		b.ipSessions = make(map[string]int)
+	}
+	b.ipSessions[ip]++
+	return true
+}
+
+// releaseIPSession gives back a slot taken by reserveIPSession, forgetting ip once
+// it has no sessions left so the map only holds connected clients
+func (b *MCPBridge) releaseIPSession(ip string) {
+	if b.maxSessionsPerIP <= 0 || ip == "" {
+		return
+	}
+
+	b.ipMu.Lock()
+	defer b.ipMu.Unlock()
+
+	if b.ipSessions[ip] <= 1 {
+		delete(b.ipSessions, ip)
+		return
+	}
+	b.ipSessions[ip]--
+}
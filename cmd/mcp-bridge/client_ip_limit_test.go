@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// remoteAddrConn makes a connection appear to come from remoteAddr, so pipes can
+// stand in for clients on different hosts
+type remoteAddrConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c remoteAddrConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// connectFrom starts handling a pipe connection from ip, returning the client end
+// and a channel closed once the bridge is done with it
+func connectFrom(t *testing.T, bridge *MCPBridge, ip string) (net.Conn, chan struct{}) {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(remoteAddrConn{serverConn, &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}})
+		close(done)
+	}()
+	return clientConn, done
+}
+
+// openEchoSessionFrom opens a session from ip and waits until it echoes
+func openEchoSessionFrom(t *testing.T, bridge *MCPBridge, ip string) (net.Conn, chan struct{}) {
+	t.Helper()
+
+	clientConn, done := connectFrom(t, bridge, ip)
+	go clientConn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := bufio.NewReader(clientConn).ReadString('\n'); err != nil {
+		t.Fatalf("Session from %s did not echo: %v", ip, err)
+	}
+	clientConn.SetReadDeadline(time.Time{})
+	return clientConn, done
+}
+
+// assertRefused checks that the bridge closes a connection from ip without a session
+func assertRefused(t *testing.T, bridge *MCPBridge, ip string) {
+	t.Helper()
+
+	clientConn, done := connectFrom(t, bridge, ip)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Connection from %s over the per-IP limit was not refused", ip)
+	}
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := clientConn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected the refused connection to be closed, got %v", err)
+	}
+}
+
+func TestMaxSessionsPerIP_RefusesExcessConnectionsFromOneIP(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.maxSessionsPerIP = 2
+
+	first, firstDone := openEchoSessionFrom(t, bridge, "192.0.2.10")
+	openEchoSessionFrom(t, bridge, "192.0.2.10")
+	assertRefused(t, bridge, "192.0.2.10")
+
+	// Other clients are unaffected by one IP reaching its limit
+	openEchoSessionFrom(t, bridge, "192.0.2.20")
+	openEchoSessionFrom(t, bridge, "2001:db8::1")
+	waitForSessionCount(t, bridge, 4, time.Second)
+
+	// Ending a session frees a slot for its IP
+	first.Close()
+	<-firstDone
+	openEchoSessionFrom(t, bridge, "192.0.2.10")
+	waitForSessionCount(t, bridge, 4, time.Second)
+
+	bridge.ipMu.Lock()
+	counts := map[string]int{}
+	for ip, count := range bridge.ipSessions {
+		counts[ip] = count
+	}
+	bridge.ipMu.Unlock()
+	if counts["192.0.2.10"] != 2 || counts["192.0.2.20"] != 1 || counts["2001:db8::1"] != 1 {
+		t.Errorf("Unexpected per-IP session counts %v", counts)
+	}
+}
+
+func TestMaxSessionsPerIP_ForgetsIPsWithoutSessions(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.maxSessionsPerIP = 1
+
+	clientConn, done := openEchoSessionFrom(t, bridge, "192.0.2.10")
+	clientConn.Close()
+	<-done
+	trackedIPs := func() int {
+		bridge.ipMu.Lock()
+		defer bridge.ipMu.Unlock()
+		return len(bridge.ipSessions)
+	}
+	if n := trackedIPs(); n != 0 {
+		t.Errorf("Expected no IPs tracked once their sessions ended, got %d", n)
+	}
+
+	// A connection refused by the global cap must not hold a per-IP slot
+	bridge.maxSessions = 1
+	openEchoSessionFrom(t, bridge, "192.0.2.20")
+	assertRefused(t, bridge, "192.0.2.30")
+	if n := trackedIPs(); n != 1 {
+		t.Errorf("Expected only the open session's IP to be tracked, got %d", n)
+	}
+}
+
+func TestMaxSessionsPerIP_DisabledByDefault(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	for i := 0; i < 3; i++ {
+		openEchoSessionFrom(t, bridge, "192.0.2.10")
+	}
+	waitForSessionCount(t, bridge, 3, time.Second)
+	if len(bridge.ipSessions) != 0 {
+		t.Errorf("Expected no per-IP tracking without a limit, got %v", bridge.ipSessions)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	pipe, other := net.Pipe()
+	defer pipe.Close()
+	defer other.Close()
+
+	tests := []struct {
+		name string
+		addr net.Addr
+		want string
+	}{
+		{"IPv4", &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 5000}, "192.0.2.10"},
+		{"IPv6 zone dropped", &net.TCPAddr{IP: net.ParseIP("fe80::1"), Port: 5000, Zone: "eth0"}, "fe80::1"},
+		{"other network with host and port", &net.UDPAddr{IP: net.ParseIP("192.0.2.10"), Port: 5000}, "192.0.2.10"},
+		{"unix socket peer", &net.UnixAddr{Name: "", Net: "unix"}, ""},
+		{"no address", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientIP(remoteAddrConn{pipe, tt.addr}); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := clientIP(pipe); got != "" {
+		t.Errorf("clientIP() of a pipe = %q, want none", got)
+	}
+}
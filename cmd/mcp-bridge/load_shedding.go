@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"runtime/metrics"
+	"time"
+)
+
+// serverBusyCode is the JSON-RPC error code sent to connections shed under load
+// (implementation-defined server error)
+const serverBusyCode = -32000
+
+// heapMetric is the runtime metric read for -shed-memory: bytes held by live and
+// not yet swept heap objects
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// heapInUse returns the bytes the bridge's heap objects occupy. Unlike
+// runtime.ReadMemStats it does not stop the world, so it is cheap enough to read
+// on every connection.
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// shedReason returns why a new connection should be shed, or "" to accept it.
+// active counts the open sessions including the new one.
+func (b *MCPBridge) shedReason(active int64) (string, map[string]interface{}) {
+	if b.shedSessions > 0 && active > int64(b.shedSessions) {
+		return "active_sessions", map[string]interface{}{
+			"activeSessions": active - 1,
+			"threshold":      b.shedSessions,
+		}
+	}
+	if b.shedMemory > 0 {
+		usage := b.memoryUsage
+		if usage == nil {
+			usage = heapInUse
+		}
+		if inUse := usage(); inUse > b.shedMemory {
+			return "memory", map[string]interface{}{
+				"heapBytes": inUse,
+				"threshold": b.shedMemory,
+			}
+		}
+	}
+	return "", nil
+}
+
+// shedConnection tells a client the bridge is too busy to serve it and closes the
+// connection without spawning a child. No request has been read, so the error
+// carries a null id.
+func (b *MCPBridge) shedConnection(conn net.Conn, reason string, details map[string]interface{}) {
+	logger := b.logger.WithContext("remote_addr", remoteAddrString(conn)).
+		WithContext("reason", reason)
+	for key, value := range details {
+		logger = logger.WithContext(key, value)
+	}
+	logger.Warn("Shedding connection under load")
+
+	details["reason"] = reason
+	response, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error": map[string]interface{}{
+			"code":    serverBusyCode,
+			"message": "Server busy, try again later",
+			"data":    details,
+		},
+	})
+
+	// A client that never reads must not hold the slot it was refused
+	conn.SetWriteDeadline(time.Now().Add(expiryNotifyTimeout))
+	conn.Write(append(response, '\n'))
+	conn.Close()
+}
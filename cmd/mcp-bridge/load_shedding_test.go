@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// connectShed connects a client that the bridge is expected to shed, returning the
+// error it was answered with
+func connectShed(t *testing.T, bridge *MCPBridge) map[string]interface{} {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	reader := bufio.NewReader(clientConn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Expected a server busy error, got %v", err)
+	}
+	var response struct {
+		ID    interface{}            `json:"id"`
+		Error map[string]interface{} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(line), &response); err != nil || response.Error == nil {
+		t.Fatalf("Expected a JSON-RPC error, got %q", line)
+	}
+	if response.ID != nil {
+		t.Errorf("Expected a null id, got %v", response.ID)
+	}
+	if code := response.Error["code"]; code != float64(serverBusyCode) {
+		t.Errorf("Error code = %v, want %d", code, serverBusyCode)
+	}
+	if _, err := reader.ReadByte(); err != io.EOF {
+		t.Errorf("Expected the shed connection to be closed, got %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Shed connection was not released")
+	}
+	data, _ := response.Error["data"].(map[string]interface{})
+	return data
+}
+
+// assertEchoes checks that a session still forwards messages round trip
+func assertEchoes(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	go conn.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}` + "\n"))
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		t.Errorf("Existing session stopped echoing: %v", err)
+	}
+}
+
+func TestShedSessions_ShedsNewConnectionsPastThreshold(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.shedSessions = 2
+
+	first, firstDone := openEchoSession(t, bridge)
+	second, _ := openEchoSession(t, bridge)
+
+	data := connectShed(t, bridge)
+	if data["reason"] != "active_sessions" {
+		t.Errorf("Shed reason = %v, want active_sessions", data["reason"])
+	}
+
+	// Sessions opened before the threshold was reached carry on
+	assertEchoes(t, first)
+	assertEchoes(t, second)
+	waitForSessionCount(t, bridge, 2, time.Second)
+
+	// Below the threshold again, connections are accepted
+	first.Close()
+	<-firstDone
+	openEchoSession(t, bridge)
+}
+
+func TestShedMemory_ShedsNewConnectionsPastWatermark(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.shedMemory = 1 << 20
+	var heap uint64 = 1 << 10
+	bridge.memoryUsage = func() uint64 { return heap }
+
+	existing, _ := openEchoSession(t, bridge)
+
+	heap = 2 << 20
+	data := connectShed(t, bridge)
+	if data["reason"] != "memory" {
+		t.Errorf("Shed reason = %v, want memory", data["reason"])
+	}
+	assertEchoes(t, existing)
+
+	heap = 1 << 10
+	openEchoSession(t, bridge)
+	waitForSessionCount(t, bridge, 2, time.Second)
+}
+
+func TestShedReason_DisabledByDefault(t *testing.T) {
+	bridge := &MCPBridge{memoryUsage: func() uint64 { return 1 << 40 }}
+	if reason, _ := bridge.shedReason(1000); reason != "" {
+		t.Errorf("Expected no shedding without thresholds, got %q", reason)
+	}
+}
+
+func TestHeapInUse(t *testing.T) {
+	if heapInUse() == 0 {
+		t.Error("Expected a non-zero heap size")
+	}
+}
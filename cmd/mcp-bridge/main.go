@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -12,6 +13,8 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -22,33 +25,139 @@ import (
 type MCPBridge struct {
 	port         int
 	host         string
+	network      string
 	serverPath   string
-	listener     net.Listener
-	sessions     map[string]*MCPSession
-	mu           sync.RWMutex
-	shutdownFlag atomic.Bool
-	logger       *logging.StructuredLogger
+	serverDir    string
+	serverEnv    []string
+	readTimeout  time.Duration
+	maxLifetime  time.Duration
+	skipBanner   bool
+	readyTimeout time.Duration
+	// shutdownTimeout is how long Shutdown lets children exit after their stdin is
+	// closed before killing them; zero kills them at once
+	shutdownTimeout time.Duration
+	// maxMessageSize caps the size in bytes of a message forwarded to the client;
+	// zero disables the limit
+	maxMessageSize int
+	// maxSessions caps concurrent sessions, and with them child processes and
+	// goroutines; connections beyond it are refused. Zero means no limit.
+	maxSessions int
+	// maxSessionsPerIP caps concurrent sessions from one client IP, so a single
+	// misbehaving client cannot take every slot; zero means no limit. ipSessions
+	// counts the open sessions of each IP, guarded by ipMu.
+	maxSessionsPerIP int
+	ipSessions       map[string]int
+	ipMu             sync.Mutex
+	// shedSessions sheds new connections with a server busy error while this many
+	// sessions are already open, below the hard maxSessions cap; zero disables it
+	shedSessions int
+	// shedMemory sheds new connections while the bridge's heap holds more than this
+	// many bytes; zero disables it
+	shedMemory uint64
+	// memoryUsage reports the heap bytes compared with shedMemory; nil reads the
+	// runtime's heap metrics
+	memoryUsage func() uint64
+	// allowedClientArgs names the MCP server flags clients may set through
+	// ClientArgsMetaKey; when set, each child is spawned after the client's first
+	// message so it can carry them
+	allowedClientArgs []string
+	listener          net.Listener
+	sessions          map[string]*MCPSession
+	mu                sync.RWMutex
+	shutdownFlag      atomic.Bool
+	sessionSeq        atomic.Uint64 // Numbers sessions so their IDs never repeat
+	activeSessions    atomic.Int64  // Sessions holding a slot under maxSessions
+	logger            *logging.StructuredLogger
 }
 
 // MCPSession represents a client session with its own MCP server process
 type MCPSession struct {
-	id      string
-	conn    net.Conn
-	process *exec.Cmd
-	stdin   io.WriteCloser
-	stdout  io.ReadCloser
-	stderr  io.ReadCloser
-	done    chan struct{}
-	mu      sync.Mutex
-	logger  *logging.StructuredLogger
+	id          string
+	conn        net.Conn
+	process     *exec.Cmd
+	stdin       io.WriteCloser
+	stdout      io.ReadCloser
+	stderr      io.ReadCloser
+	client      io.Reader       // Client input, conn unless the bridge read ahead
+	pending     json.RawMessage // Client message read before the child started
+	readTimeout time.Duration
+	maxLifetime time.Duration
+	skipBanner  bool // Drop non-JSON-RPC stdout lines until the first message
+	// readyTimeout enables the readiness probe: client messages are held until the
+	// child answers the probe, and the session is closed if it does not in time
+	readyTimeout time.Duration
+	ready        chan struct{} // Closed once the child answers the readiness probe
+	readyOnce    sync.Once
+	serverDone   chan struct{} // Closed once the child's stdout has been drained
+	// maxMessageSize rejects child messages longer than this many bytes instead of
+	// forwarding them; zero disables the limit
+	maxMessageSize int
+	done           chan struct{}
+	mu             sync.Mutex
+	writeMu        sync.Mutex // Serializes writes to conn from forwarding and expiry
+	logger         *logging.StructuredLogger
+}
+
+// Supported listener networks
+const (
+	NetworkTCP  = "tcp"
+	NetworkUnix = "unix"
+)
+
+// SessionExpiredNotification is sent to a client just before its session is closed
+// for exceeding -max-session-lifetime
+const SessionExpiredNotification = "notifications/bridge/session_expired"
+
+// expiryNotifyTimeout bounds how long delivering the expiry notification may block
+const expiryNotifyTimeout = time.Second
+
+// DefaultReadTimeout leaves idle client sessions open, since MCP clients may stay
+// silent for long stretches. Operators opt in with -read-timeout to reap idle or
+// half-open connections, each of which otherwise pins a child process.
+const DefaultReadTimeout time.Duration = 0
+
+// DefaultShutdownTimeout bounds how long Shutdown waits for children to exit after
+// their stdin is closed. Without it a child that ignores EOF would hang shutdown.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// envFlags collects repeated -server-env KEY=VALUE flags
+type envFlags []string
+
+func (e *envFlags) String() string {
+	return strings.Join(*e, ",")
+}
+
+func (e *envFlags) Set(value string) error {
+	if key, _, found := strings.Cut(value, "="); !found || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	*e = append(*e, value)
+	return nil
 }
 
 func main() {
+	var serverEnv envFlags
+	flag.Var(&serverEnv, "server-env", "Extra KEY=VALUE environment variable for the MCP server process (repeatable)")
+	var clientArgs clientArgFlags
+	flag.Var(&clientArgs, "client-arg", "Name of an MCP server flag, such as log-level, clients may set in their initialize request's _meta \""+ClientArgsMetaKey+"\" (repeatable)")
+
 	var (
-		port       = flag.Int("port", 8080, "TCP server port")
-		host       = flag.String("host", "localhost", "TCP server host")
-		serverPath = flag.String("server", "./bin/mcp-server", "Path to MCP server binary")
-		logLevel   = flag.String("log-level", "INFO", "Logging level (DEBUG, INFO, WARN, ERROR)")
+		port            = flag.Int("port", 8080, "TCP server port")
+		host            = flag.String("host", "localhost", "TCP server host, or socket path when -network is unix")
+		network         = flag.String("network", NetworkTCP, "Listener network (tcp, unix)")
+		serverPath      = flag.String("server", "./bin/mcp-server", "Path to MCP server binary")
+		serverDir       = flag.String("server-dir", "", "Working directory for the MCP server process (default: bridge working directory)")
+		readTimeout     = flag.Duration("read-timeout", DefaultReadTimeout, "Close client sessions idle for longer than this (0 disables)")
+		maxLifetime     = flag.Duration("max-session-lifetime", 0, "Close client sessions older than this regardless of activity (0 disables)")
+		logLevel        = flag.String("log-level", "INFO", "Logging level (DEBUG, INFO, WARN, ERROR)")
+		skipBanner      = flag.Bool("skip-stdout-banner", false, "Drop and log MCP server stdout lines that are not JSON-RPC until its first JSON-RPC message")
+		readyTimeout    = flag.Duration("ready-timeout", 0, "Hold client messages until the MCP server answers a ping probe, closing the session if it takes longer than this (0 disables)")
+		shutdownTimeout = flag.Duration("shutdown-timeout", DefaultShutdownTimeout, "How long shutdown waits for MCP server processes to exit after closing their stdin before killing them (0 kills them at once)")
+		maxMessageSize  = flag.Int("max-message-size", 0, "Reject MCP server messages larger than this many bytes instead of forwarding them, answering responses with an error (0 disables)")
+		maxSessions     = flag.Int("max-sessions", 0, "Refuse client connections while this many sessions are open (0 disables)")
+		maxSessionsIP   = flag.Int("max-sessions-per-ip", 0, "Refuse client connections from an IP address while this many of its sessions are open; unix socket clients are not limited (0 disables)")
+		shedSessions    = flag.Int("shed-sessions", 0, "Answer new client connections with a server busy error and close them while this many sessions are open (0 disables)")
+		shedMemory      = flag.Uint64("shed-memory", 0, "Answer new client connections with a server busy error and close them while the bridge's heap exceeds this many bytes (0 disables)")
 	)
 	flag.Parse()
 
@@ -61,15 +170,43 @@ func main() {
 
 	logger.WithContext("port", *port).
 		WithContext("host", *host).
+		WithContext("network", *network).
 		WithContext("server_path", *serverPath).
+		WithContext("server_dir", *serverDir).
+		WithContext("server_env_count", len(serverEnv)).
+		WithContext("client_args", clientArgs.String()).
+		WithContext("read_timeout", readTimeout.String()).
+		WithContext("max_session_lifetime", maxLifetime.String()).
+		WithContext("skip_stdout_banner", *skipBanner).
+		WithContext("ready_timeout", readyTimeout.String()).
+		WithContext("shutdown_timeout", shutdownTimeout.String()).
+		WithContext("max_message_size", *maxMessageSize).
+		WithContext("max_sessions", *maxSessions).
+		WithContext("max_sessions_per_ip", *maxSessionsIP).
+		WithContext("shed_sessions", *shedSessions).
+		WithContext("shed_memory", *shedMemory).
 		Info("Starting MCP Bridge")
 
 	bridge := &MCPBridge{
-		port:       *port,
-		host:       *host,
-		serverPath: *serverPath,
-		sessions:   make(map[string]*MCPSession),
-		logger:     loggingManager.GetLogger("bridge"),
+		port:              *port,
+		host:              *host,
+		network:           *network,
+		serverPath:        *serverPath,
+		serverDir:         *serverDir,
+		serverEnv:         serverEnv,
+		readTimeout:       *readTimeout,
+		maxLifetime:       *maxLifetime,
+		skipBanner:        *skipBanner,
+		readyTimeout:      *readyTimeout,
+		shutdownTimeout:   *shutdownTimeout,
+		maxMessageSize:    *maxMessageSize,
+		maxSessions:       *maxSessions,
+		maxSessionsPerIP:  *maxSessionsIP,
+		shedSessions:      *shedSessions,
+		shedMemory:        *shedMemory,
+		allowedClientArgs: clientArgs,
+		sessions:          make(map[string]*MCPSession),
+		logger:            loggingManager.GetLogger("bridge"),
 	}
 
 	// Create context for graceful shutdown
@@ -103,14 +240,28 @@ func main() {
 }
 
 func (b *MCPBridge) Start(ctx context.Context) error {
-	var err error
-	b.listener, err = net.Listen("tcp", fmt.Sprintf("%s:%d", b.host, b.port))
+	if err := validateServerDir(b.serverDir); err != nil {
+		return err
+	}
+
+	network, address, err := b.listenAddress()
+	if err != nil {
+		return err
+	}
+
+	if network == NetworkUnix {
+		if err := removeStaleSocket(address); err != nil {
+			return err
+		}
+	}
+
+	b.listener, err = net.Listen(network, address)
 	if err != nil {
 		return fmt.Errorf("failed to start listener: %v", err)
 	}
 
-	b.logger.WithContext("host", b.host).
-		WithContext("port", b.port).
+	b.logger.WithContext("network", network).
+		WithContext("address", b.listener.Addr().String()).
 		Info("MCP Bridge server listening")
 
 	b.logger.WithContext("server_path", b.serverPath).
@@ -144,6 +295,67 @@ func (b *MCPBridge) Start(ctx context.Context) error {
 	}
 }
 
+// listenAddress resolves the network and address to listen on.
+// TCP addresses go through JoinHostPort so IPv6 hosts like "::1" get bracketed.
+func (b *MCPBridge) listenAddress() (string, string, error) {
+	switch b.network {
+	case "", NetworkTCP:
+		return NetworkTCP, net.JoinHostPort(b.host, strconv.Itoa(b.port)), nil
+	case NetworkUnix:
+		if b.host == "" {
+			return "", "", fmt.Errorf("unix network requires a socket path as host")
+		}
+		return NetworkUnix, b.host, nil
+	default:
+		return "", "", fmt.Errorf("unsupported network: %s", b.network)
+	}
+}
+
+// validateServerDir fails fast on a bad -server-dir instead of letting every
+// spawned session fail individually
+func validateServerDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid server working directory: %v", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("server working directory is not a directory: %s", dir)
+	}
+	return nil
+}
+
+// removeStaleSocket deletes a socket file left behind by a previous run.
+// Regular files are never removed to avoid clobbering a mistyped path.
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat socket path: %v", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("socket path exists and is not a socket: %s", path)
+	}
+	return os.Remove(path)
+}
+
+// remoteAddrString formats a connection's peer address for logging.
+// Unix socket peers are usually unnamed, so fall back to the network name.
+func remoteAddrString(conn net.Conn) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return "unknown"
+	}
+	if str := addr.String(); str != "" {
+		return str
+	}
+	return addr.Network()
+}
+
 func (b *MCPBridge) Shutdown() error {
 	// Set shutdown flag BEFORE closing listener
 	b.shutdownFlag.Store(true)
@@ -152,27 +364,104 @@ func (b *MCPBridge) Shutdown() error {
 		b.listener.Close()
 	}
 
-	// Close all sessions
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	// Closing a unix listener normally unlinks the socket, but remove it explicitly
+	// so a restart never trips over a leftover file
+	if b.network == NetworkUnix {
+		if err := os.Remove(b.host); err != nil && !os.IsNotExist(err) {
+			b.logger.WithError(err).
+				WithContext("socket_path", b.host).
+				Warn("Failed to remove unix socket")
+		}
+	}
 
+	// Snapshot the sessions so the lock is not held while children exit
+	b.mu.RLock()
+	sessions := make([]*MCPSession, 0, len(b.sessions))
 	for _, session := range b.sessions {
-		session.Close()
+		sessions = append(sessions, session)
+	}
+	b.mu.RUnlock()
+
+	if b.shutdownTimeout <= 0 {
+		for _, session := range sessions {
+			session.Close()
+		}
+		b.logger.WithContext("sessions", len(sessions)).Info("MCP Bridge shutdown completed")
+		return nil
 	}
 
-	b.logger.Info("MCP Bridge shutdown completed")
+	// Let every child finish and exit, killing those still running at the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), b.shutdownTimeout)
+	defer cancel()
+
+	var forced atomic.Int32
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		wg.Add(1)
+		go func(session *MCPSession) {
+			defer wg.Done()
+			if session.shutdown(ctx) {
+				forced.Add(1)
+			}
+		}(session)
+	}
+	wg.Wait()
+
+	b.logger.WithContext("sessions", len(sessions)).
+		WithContext("forced_sessions", forced.Load()).
+		Info("MCP Bridge shutdown completed")
 	return nil
 }
 
 func (b *MCPBridge) handleConnection(conn net.Conn) {
-	sessionId := fmt.Sprintf("session_%d", time.Now().UnixNano())
+	active, ok := b.reserveSession()
+	if !ok {
+		b.logger.WithContext("remote_addr", remoteAddrString(conn)).
+			WithContext("max_sessions", b.maxSessions).
+			Warn("Session limit reached, refusing connection")
+		conn.Close()
+		return
+	}
+	defer b.activeSessions.Add(-1)
+
+	ip := clientIP(conn)
+	if !b.reserveIPSession(ip) {
+		b.logger.WithContext("remote_addr", remoteAddrString(conn)).
+			WithContext("max_sessions_per_ip", b.maxSessionsPerIP).
+			Warn("Session limit for client IP reached, refusing connection")
+		conn.Close()
+		return
+	}
+	defer b.releaseIPSession(ip)
+
+	// Shedding answers the client, unlike the maxSessions cap, so it can back off
+	if reason, details := b.shedReason(active); reason != "" {
+		b.shedConnection(conn, reason, details)
+		return
+	}
+
+	sessionId := b.newSessionID()
 
 	b.logger.WithContext("session_id", sessionId).
-		WithContext("remote_addr", conn.RemoteAddr().String()).
+		WithContext("remote_addr", remoteAddrString(conn)).
 		Info("New connection")
 
+	// Clients choosing server flags name them in their first message, so the
+	// child cannot be spawned before it arrives
+	start := sessionStart{client: conn}
+	if len(b.allowedClientArgs) > 0 {
+		var err error
+		if start, err = b.readSessionStart(conn); err != nil {
+			b.logger.WithError(err).
+				WithContext("session_id", sessionId).
+				Warn("Refusing session")
+			conn.Close()
+			return
+		}
+	}
+
 	// Create new MCP session
-	session, err := b.createSession(sessionId, conn)
+	session, err := b.createSession(sessionId, conn, start)
 	if err != nil {
 		b.logger.WithError(err).
 			WithContext("session_id", sessionId).
@@ -182,9 +471,12 @@ func (b *MCPBridge) handleConnection(conn net.Conn) {
 	}
 
 	// Store session
-	b.mu.Lock()
-	b.sessions[sessionId] = session
-	b.mu.Unlock()
+	if !b.addSession(session) {
+		b.logger.WithContext("session_id", sessionId).
+			Error("Session ID already in use, closing connection")
+		session.Close()
+		return
+	}
 
 	// Handle the session
 	session.Handle()
@@ -197,9 +489,46 @@ func (b *MCPBridge) handleConnection(conn net.Conn) {
 	b.logger.WithContext("session_id", sessionId).Info("Session ended")
 }
 
-func (b *MCPBridge) createSession(id string, conn net.Conn) (*MCPSession, error) {
+// newSessionID returns a session ID unique within this bridge. The sequence number
+// keeps IDs distinct even when connections arrive within one clock tick.
+func (b *MCPBridge) newSessionID() string {
+	return fmt.Sprintf("session_%d_%d", time.Now().UnixNano(), b.sessionSeq.Add(1))
+}
+
+// reserveSession takes a session slot, returning false if maxSessions are already
+// open, and otherwise how many slots are taken including this one. A successful
+// reservation is released by decrementing activeSessions.
+func (b *MCPBridge) reserveSession() (int64, bool) {
+	active := b.activeSessions.Add(1)
+	if b.maxSessions > 0 && active > int64(b.maxSessions) {
+		b.activeSessions.Add(-1)
+		return 0, false
+	}
+	return active, true
+}
+
+// addSession tracks session, refusing it if its ID is already taken so a
+// colliding session can never overwrite one whose process is still running
+func (b *MCPBridge) addSession(session *MCPSession) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.sessions[session.id]; exists {
+		return false
+	}
+	b.sessions[session.id] = session
+	return true
+}
+
+func (b *MCPBridge) createSession(id string, conn net.Conn, start sessionStart) (*MCPSession, error) {
 	// Start MCP server process
-	cmd := exec.Command(b.serverPath)
+	cmd := exec.Command(b.serverPath, start.args...)
+	// Documents are resolved relative to the working directory, so each child
+	// must start where its docs live rather than wherever the bridge was launched
+	cmd.Dir = b.serverDir
+	if len(b.serverEnv) > 0 {
+		cmd.Env = append(os.Environ(), b.serverEnv...)
+	}
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
@@ -228,17 +557,30 @@ func (b *MCPBridge) createSession(id string, conn net.Conn) (*MCPSession, error)
 
 	// Create session logger with session context
 	sessionLogger := b.logger.WithContext("session_id", id).
-		WithContext("remote_addr", conn.RemoteAddr().String())
+		WithContext("remote_addr", remoteAddrString(conn))
+	if len(start.args) > 0 {
+		sessionLogger.WithContext("server_args", strings.Join(start.args, " ")).
+			Info("Started MCP server with client arguments")
+	}
 
 	session := &MCPSession{
-		id:      id,
-		conn:    conn,
-		process: cmd,
-		stdin:   stdin,
-		stdout:  stdout,
-		stderr:  stderr,
-		done:    make(chan struct{}),
-		logger:  sessionLogger,
+		id:             id,
+		conn:           conn,
+		process:        cmd,
+		stdin:          stdin,
+		stdout:         stdout,
+		stderr:         stderr,
+		client:         start.client,
+		pending:        start.first,
+		readTimeout:    b.readTimeout,
+		maxLifetime:    b.maxLifetime,
+		skipBanner:     b.skipBanner,
+		readyTimeout:   b.readyTimeout,
+		ready:          make(chan struct{}),
+		serverDone:     make(chan struct{}),
+		maxMessageSize: b.maxMessageSize,
+		done:           make(chan struct{}),
+		logger:         sessionLogger,
 	}
 
 	return session, nil
@@ -247,7 +589,13 @@ func (b *MCPBridge) createSession(id string, conn net.Conn) (*MCPSession, error)
 func (s *MCPSession) Handle() {
 	defer s.Close()
 
-	// Start goroutines to handle bidirectional communication
+	if s.maxLifetime > 0 {
+		expiry := time.AfterFunc(s.maxLifetime, s.expire)
+		defer expiry.Stop()
+	}
+
+	// Each blocking read needs its own goroutine; the connection's goroutine takes
+	// one of them, so a session costs three goroutines in all
 	var wg sync.WaitGroup
 
 	// Client -> MCP Server
@@ -257,43 +605,71 @@ func (s *MCPSession) Handle() {
 		s.forwardClientToServer()
 	}()
 
-	// MCP Server -> Client
+	// Re-emit child logs with session context
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		s.forwardServerToClient()
+		s.forwardChildLogs(s.stderr, os.Stderr)
 	}()
 
-	// Monitor stderr for debugging
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		s.monitorServerErrors()
-	}()
+	// MCP Server -> Client
+	s.forwardServerToClient()
 
-	// Wait for all goroutines to complete
+	// Wait for the other goroutines to complete
 	wg.Wait()
 }
 
+// forwardClientToServer frames client input with a JSON decoder rather than a line
+// scanner, so pretty-printed messages spanning several lines are forwarded intact.
+// Each value is re-encoded compactly as a single line, which is what the child expects.
 func (s *MCPSession) forwardClientToServer() {
-	scanner := bufio.NewScanner(s.conn)
-	encoder := json.NewEncoder(s.stdin)
+	// Once the client is gone nothing can receive the child's output, so the
+	// session is closed: that kills the child and unblocks the other goroutines
+	defer s.Close()
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		s.logger.WithContext("direction", "client_to_server").
-			WithContext("message", line).
-			Debug("Forwarding message")
+	// Client messages stay unread in the connection until the child is ready
+	if s.readyTimeout > 0 && !s.awaitReady() {
+		return
+	}
 
-		// Parse and forward the JSON message
-		var message json.RawMessage
-		if err := json.Unmarshal([]byte(line), &message); err != nil {
+	if s.client == nil {
+		s.client = s.conn
+	}
+	decoder := json.NewDecoder(s.client)
+	encoder := json.NewEncoder(s.stdin)
+
+	if s.pending != nil {
+		if err := encoder.Encode(s.pending); err != nil {
 			s.logger.WithError(err).
 				WithContext("direction", "client_to_server").
-				Warn("Invalid JSON from client")
-			continue
+				Error("Error forwarding to server")
+			return
+		}
+	}
+
+	for {
+		s.extendReadDeadline()
+
+		var message json.RawMessage
+		err := decoder.Decode(&message)
+		if err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				s.logger.WithError(err).
+					WithContext("direction", "client_to_server").
+					Warn("Invalid JSON from client")
+				decoder = s.resyncDecoder(decoder)
+				continue
+			}
+			s.handleClientReadError(err)
+			return
 		}
 
+		s.logger.WithContext("direction", "client_to_server").
+			WithContext("message", string(message)).
+			Debug("Forwarding message")
+
+		// Encoding a RawMessage compacts it, collapsing any internal newlines
 		if err := encoder.Encode(message); err != nil {
 			s.logger.WithError(err).
 				WithContext("direction", "client_to_server").
@@ -301,43 +677,278 @@ func (s *MCPSession) forwardClientToServer() {
 			return
 		}
 	}
+}
+
+// resyncDecoder recovers from a syntax error. A json.Decoder cannot continue after
+// one, so the rest of the offending line is discarded and decoding resumes from
+// whatever follows it, preserving the old skip-bad-line behaviour.
+func (s *MCPSession) resyncDecoder(decoder *json.Decoder) *json.Decoder {
+	reader := bufio.NewReader(io.MultiReader(decoder.Buffered(), s.client))
+	reader.ReadString('\n')
+	// Input the reader has buffered must not be skipped by a later resync
+	s.client = reader
+	return json.NewDecoder(reader)
+}
+
+// handleClientReadError logs why the client stream ended, distinguishing an idle
+// timeout and a truncated message from an orderly disconnect
+func (s *MCPSession) handleClientReadError(err error) {
+	logger := s.logger.WithContext("direction", "client_to_server")
+
+	switch {
+	case err == io.EOF:
+		logger.Info("Client closed connection")
+	case err == io.ErrUnexpectedEOF:
+		logger.Warn("Client closed connection mid-message")
+	case isTimeout(err):
+		logger.WithContext("read_timeout", s.readTimeout.String()).
+			Warn("Client read timeout, closing idle session")
+	case s.isClosed():
+		// The session was closed from elsewhere, which closed the connection
+	default:
+		logger.WithError(err).Error("Client read error")
+	}
+}
 
-	if err := scanner.Err(); err != nil {
-		s.logger.WithError(err).
-			WithContext("direction", "client_to_server").
-			Error("Client read error")
+// extendReadDeadline pushes the connection read deadline forward before each read
+func (s *MCPSession) extendReadDeadline() {
+	if s.readTimeout > 0 {
+		s.conn.SetReadDeadline(time.Now().Add(s.readTimeout))
 	}
 }
 
+// isTimeout reports whether err is a network deadline expiry rather than a real I/O failure
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// forwardServerToClient relays the child's stdout to the client line by line. With
+// skipBanner set, lines before the child's first JSON-RPC message (startup banners
+// and the like) are logged and dropped rather than handed to the client.
 func (s *MCPSession) forwardServerToClient() {
-	scanner := bufio.NewScanner(s.stdout)
+	if s.serverDone != nil {
+		defer close(s.serverDone)
+	}
+
+	reader := bufio.NewReader(s.stdout)
+	awaitingFirstMessage := s.skipBanner
+
+	for {
+		next, err := readServerLine(reader, s.maxMessageSize)
+		if err != nil {
+			// Closing the session closes stdout under us, which is not an error
+			if err != io.EOF && !s.isClosed() {
+				s.logger.WithError(err).
+					WithContext("direction", "server_to_client").
+					Error("Server read error")
+			}
+			return
+		}
+
+		if next.oversized {
+			if err := s.rejectOversizedMessage(next); err != nil {
+				s.logClientWriteError(err)
+				return
+			}
+			continue
+		}
+
+		line := string(next.data)
+		// Answers to probes resent before the child was ready may still arrive after it
+		if s.readyTimeout > 0 && isReadinessProbeResponse(line) {
+			s.readyOnce.Do(func() { close(s.ready) })
+			continue
+		}
+
+		if awaitingFirstMessage {
+			if !isJSONRPCMessage(line) {
+				s.logger.WithContext("direction", "server_to_client").
+					WithContext("line", line).
+					Warn("Skipping non-JSON-RPC server output before first message")
+				continue
+			}
+			awaitingFirstMessage = false
+		}
 
-	for scanner.Scan() {
-		line := scanner.Text()
 		s.logger.WithContext("direction", "server_to_client").
 			WithContext("message", line).
 			Debug("Forwarding message")
 
 		// Forward the response to the client
-		if _, err := fmt.Fprintf(s.conn, "%s\n", line); err != nil {
-			s.logger.WithError(err).
-				WithContext("direction", "server_to_client").
-				Error("Error forwarding to client")
+		if err := s.writeToClient([]byte(line)); err != nil {
+			s.logClientWriteError(err)
 			return
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		s.logger.WithError(err).
-			WithContext("direction", "server_to_client").
-			Error("Server read error")
+// logClientWriteError logs a failure to forward to the client, unless it came from
+// the session closing the connection
+func (s *MCPSession) logClientWriteError(err error) {
+	if s.isClosed() {
+		return
 	}
+	s.logger.WithError(err).
+		WithContext("direction", "server_to_client").
+		Error("Error forwarding to client")
 }
 
-func (s *MCPSession) monitorServerErrors() {
-	// Simply copy MCP server stderr to bridge stderr
-	// This preserves the original JSON logs without re-parsing
-	io.Copy(os.Stderr, s.stderr)
+// readinessProbeID identifies the bridge's own ping requests so the child's
+// answers are not forwarded to the client
+const readinessProbeID = "mcp-bridge-readiness-probe"
+
+// readinessProbeInterval is how often the readiness probe is resent while the
+// child has not answered, since a child that is still starting up may drop it
+const readinessProbeInterval = 100 * time.Millisecond
+
+// awaitReady pings the child and waits up to readyTimeout for an answer. Children
+// that are still starting up may drop what they read, so client messages are only
+// forwarded once this returns true. The probe is a ping rather than an initialize
+// so the client's own handshake is the only one the child sees; any answer,
+// including a method not found error, shows the child is reading its input.
+func (s *MCPSession) awaitReady() bool {
+	logger := s.logger.WithContext("ready_timeout", s.readyTimeout.String())
+
+	probe, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      readinessProbeID,
+		"method":  "ping",
+	})
+	probe = append(probe, '\n')
+
+	startTime := time.Now()
+	timer := time.NewTimer(s.readyTimeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(readinessProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.stdin.Write(probe); err != nil {
+			logger.WithError(err).Error("Failed to send readiness probe to MCP server")
+			return false
+		}
+
+		select {
+		case <-s.ready:
+			logger.WithContext("wait_ms", time.Since(startTime).Milliseconds()).
+				Debug("MCP server ready")
+			return true
+		case <-timer.C:
+			logger.Error("MCP server did not answer readiness probe in time, closing session")
+			return false
+		case <-s.done:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// isClosed reports whether the session has been closed
+func (s *MCPSession) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// isReadinessProbeResponse reports whether line answers the readiness probe
+func isReadinessProbeResponse(line string) bool {
+	if !strings.Contains(line, readinessProbeID) {
+		return false
+	}
+	var message struct {
+		ID     interface{}     `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal([]byte(line), &message) != nil {
+		return false
+	}
+	return message.ID == readinessProbeID && (message.Result != nil || message.Error != nil)
+}
+
+// isJSONRPCMessage reports whether line is a JSON-RPC 2.0 message
+func isJSONRPCMessage(line string) bool {
+	var message struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	return json.Unmarshal([]byte(line), &message) == nil && message.JSONRPC == "2.0"
+}
+
+// writeToClient sends a single newline-terminated message to the client
+func (s *MCPSession) writeToClient(message []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_, err := s.conn.Write(append(message, '\n'))
+	return err
+}
+
+// expire ends a session that has reached its maximum lifetime. The client is told
+// why before the connection drops so it can reconnect to a freshly loaded server.
+func (s *MCPSession) expire() {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	notification, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  SessionExpiredNotification,
+		"params": map[string]interface{}{
+			"reason":   "max_session_lifetime",
+			"lifetime": s.maxLifetime.String(),
+		},
+	})
+
+	// A stalled client must not be able to keep an expired session alive
+	s.conn.SetWriteDeadline(time.Now().Add(expiryNotifyTimeout))
+	if err := s.writeToClient(notification); err != nil {
+		s.logger.WithError(err).Debug("Failed to send session expiry notification")
+	}
+
+	s.logger.WithContext("max_session_lifetime", s.maxLifetime.String()).
+		Info("Session reached maximum lifetime, closing")
+	s.Close()
+}
+
+// shutdown closes the child's stdin so it can finish its work and exit, then closes
+// the session. If the child's output has not been drained by the time ctx is done,
+// the child is killed and shutdown reports true.
+func (s *MCPSession) shutdown(ctx context.Context) bool {
+	s.mu.Lock()
+	if s.stdin != nil {
+		s.stdin.Close()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.serverDone:
+		s.Close()
+		return false
+	case <-s.done:
+		return false
+	case <-ctx.Done():
+	}
+
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	pid := 0
+	if s.process != nil && s.process.Process != nil {
+		pid = s.process.Process.Pid
+	}
+	s.logger.WithContext("pid", pid).
+		Warn("MCP server did not exit within shutdown timeout, killing it")
+	s.Close()
+	return true
 }
 
 func (s *MCPSession) Close() {
@@ -369,7 +980,7 @@ func (s *MCPSession) Close() {
 
 	// Terminate process
 	if s.process != nil {
-		if err := s.process.Process.Kill(); err != nil {
+		if err := s.process.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
 			s.logger.WithError(err).Error("Error killing process")
 		}
 		s.process.Wait() // Clean up zombie process
@@ -0,0 +1,1082 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-architecture-service/pkg/logging"
+)
+
+// testChildEnv makes the test binary act as an MCP server child instead of running
+// tests, so the bridge can spawn it without arguments
+const testChildEnv = "MCP_BRIDGE_TEST_CHILD"
+
+// slowChildStartup is how long the slow test child takes to become ready
+const slowChildStartup = 300 * time.Millisecond
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(testChildEnv) {
+	case "slow":
+		runSlowChild()
+		os.Exit(0)
+	case "strict":
+		runStrictChild()
+		os.Exit(0)
+	case "silent":
+		io.Copy(io.Discard, os.Stdin)
+		os.Exit(0)
+	case "args":
+		// Reports its arguments, then echoes whatever it is sent
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "test/args",
+			"params":  map[string]interface{}{"args": os.Args[1:]},
+		})
+		io.Copy(os.Stdout, os.Stdin)
+		os.Exit(0)
+	case "stuck":
+		// Ignores EOF on stdin, like a server wedged on shutdown
+		io.Copy(io.Discard, os.Stdin)
+		time.Sleep(time.Hour)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runSlowChild behaves like an MCP server that takes slowChildStartup to start:
+// requests read before then are dropped, except initialize, which is answered once
+// startup completes. Afterwards every request is answered with its method name.
+func runSlowChild() {
+	var mu sync.Mutex
+	ready := false
+	var pendingInit []interface{}
+	encoder := json.NewEncoder(os.Stdout)
+	respond := func(id interface{}, method string) {
+		encoder.Encode(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": map[string]interface{}{"method": method}})
+	}
+
+	time.AfterFunc(slowChildStartup, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		ready = true
+		for _, id := range pendingInit {
+			respond(id, "initialize")
+		}
+	})
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &request) != nil || request.ID == nil {
+			continue
+		}
+
+		mu.Lock()
+		switch {
+		case ready:
+			respond(request.ID, request.Method)
+		case request.Method == "initialize":
+			pendingInit = append(pendingInit, request.ID)
+		}
+		mu.Unlock()
+	}
+}
+
+// runStrictChild behaves like an MCP server that drops every request read during
+// its first slowChildStartup, then answers ping and accepts only one initialize,
+// answering any later one with an error
+func runStrictChild() {
+	start := time.Now()
+	initialized := false
+	encoder := json.NewEncoder(os.Stdout)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &request) != nil || request.ID == nil {
+			continue
+		}
+		if time.Since(start) < slowChildStartup {
+			continue
+		}
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		switch {
+		case request.Method == "initialize" && initialized:
+			response["error"] = map[string]interface{}{"code": -32600, "message": "already initialized"}
+		case request.Method == "initialize":
+			initialized = true
+			response["result"] = map[string]interface{}{"method": request.Method}
+		default:
+			response["result"] = map[string]interface{}{}
+		}
+		encoder.Encode(response)
+	}
+}
+
+// newTestBridge creates a bridge that spawns `cat` as its child process,
+// which echoes every forwarded message straight back to the client
+func newTestBridge(t *testing.T) *MCPBridge {
+	t.Helper()
+
+	catPath, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat binary not available")
+	}
+
+	bridge := &MCPBridge{
+		host:       "localhost",
+		serverPath: catPath,
+		sessions:   make(map[string]*MCPSession),
+		logger:     logging.NewLoggingManager().GetLogger("bridge"),
+	}
+	t.Cleanup(func() { bridge.Shutdown() })
+
+	return bridge
+}
+
+// waitForSessionCount polls until the bridge tracks the expected number of sessions
+func waitForSessionCount(t *testing.T, bridge *MCPBridge, want int, timeout time.Duration) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		bridge.mu.RLock()
+		got := len(bridge.sessions)
+		bridge.mu.RUnlock()
+		if got == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d active sessions within %s", want, timeout)
+}
+
+func TestReadTimeout_IdleConnectionIsReaped(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.readTimeout = 100 * time.Millisecond
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	waitForSessionCount(t, bridge, 1, time.Second)
+
+	// Send nothing - the session must be closed once the read deadline passes
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Idle session was not reaped after read timeout")
+	}
+
+	waitForSessionCount(t, bridge, 0, time.Second)
+
+	// The client side should observe the closed connection
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := clientConn.Read(make([]byte, 1)); err == nil {
+		t.Error("Expected client connection to be closed")
+	}
+}
+
+func TestReadTimeout_ActiveConnectionStaysOpen(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.readTimeout = 200 * time.Millisecond
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	reader := bufio.NewReader(clientConn)
+
+	// Keep sending within the timeout window; each message extends the deadline
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		if _, err := clientConn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n")); err != nil {
+			t.Fatalf("Write %d failed: %v", i, err)
+		}
+		clientConn.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatalf("Expected echoed response %d, got error: %v", i, err)
+		}
+	}
+
+	select {
+	case <-done:
+		t.Fatal("Active session was closed despite regular traffic")
+	default:
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	serverConn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	_, err := serverConn.Read(make([]byte, 1))
+	if !isTimeout(err) {
+		t.Errorf("Expected deadline error to be a timeout, got %v", err)
+	}
+
+	clientConn.Close()
+	serverConn.SetReadDeadline(time.Time{})
+	_, err = serverConn.Read(make([]byte, 1))
+	if isTimeout(err) {
+		t.Errorf("Expected closed-pipe error not to be a timeout, got %v", err)
+	}
+}
+
+func TestListenAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		host    string
+		port    int
+		want    string
+		wantErr bool
+	}{
+		{"default network is tcp", "", "localhost", 8080, "localhost:8080", false},
+		{"ipv4 host", NetworkTCP, "127.0.0.1", 9000, "127.0.0.1:9000", false},
+		{"ipv6 host is bracketed", NetworkTCP, "::1", 9000, "[::1]:9000", false},
+		{"unix socket path", NetworkUnix, "/tmp/mcp.sock", 0, "/tmp/mcp.sock", false},
+		{"unix requires path", NetworkUnix, "", 0, "", true},
+		{"unsupported network", "udp", "localhost", 8080, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge := &MCPBridge{network: tt.network, host: tt.host, port: tt.port}
+			_, got, err := bridge.listenAddress()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnixSocket_Handshake(t *testing.T) {
+	bridge := newTestBridge(t)
+	// Keep the path short: unix socket paths are limited to ~104 bytes
+	socketDir, err := os.MkdirTemp("", "bridge")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(socketDir)
+	socketPath := filepath.Join(socketDir, "mcp.sock")
+
+	bridge.network = NetworkUnix
+	bridge.host = socketPath
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bridge.Start(ctx)
+
+	var conn net.Conn
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if conn, err = net.Dial(NetworkUnix, socketPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if conn == nil {
+		t.Fatalf("Failed to connect to unix socket: %v", err)
+	}
+	defer conn.Close()
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`
+	if _, err := conn.Write([]byte(request + "\n")); err != nil {
+		t.Fatalf("Failed to write initialize request: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &response); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+	if response["method"] != "initialize" {
+		t.Errorf("Expected initialize round-trip, got %v", response)
+	}
+
+	if err := bridge.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("Expected socket file to be removed on shutdown, stat err = %v", err)
+	}
+}
+
+func TestRemoveStaleSocket_RefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-socket")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := removeStaleSocket(path); err == nil {
+		t.Error("Expected error for regular file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Regular file should not be removed: %v", err)
+	}
+}
+
+func TestForwardClientToServer_MultiLineJSON(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go bridge.handleConnection(serverConn)
+
+	// Pretty-printed request followed by a garbage line and a compact request
+	input := "{\n  \"jsonrpc\": \"2.0\",\n  \"id\": 1,\n  \"method\": \"initialize\"\n}\n" +
+		"not json at all\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
+
+	go clientConn.Write([]byte(input))
+
+	reader := bufio.NewReader(clientConn)
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	expected := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	}
+	for i, want := range expected {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read forwarded message %d: %v", i, err)
+		}
+		if got := line[:len(line)-1]; got != want {
+			t.Errorf("Message %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestForwardClientToServer_MessagesWithoutNewlines(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go bridge.handleConnection(serverConn)
+
+	// Two values back to back on one line must be framed as separate messages
+	go clientConn.Write([]byte(`{"id":1}{"id":2}` + "\n"))
+
+	reader := bufio.NewReader(clientConn)
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	for _, want := range []string{`{"id":1}`, `{"id":2}`} {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read forwarded message: %v", err)
+		}
+		if got := line[:len(line)-1]; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+// readChildOutput connects a client to a session running binary and returns what the
+// child writes to stdout, up to and including the first line containing until
+func readChildOutput(t *testing.T, bridge *MCPBridge, binary, until string) string {
+	t.Helper()
+
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		t.Skipf("%s binary not available", binary)
+	}
+	bridge.serverPath = path
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go bridge.handleConnection(serverConn)
+
+	var output strings.Builder
+	reader := bufio.NewReader(clientConn)
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		line, err := reader.ReadString('\n')
+		output.WriteString(line)
+		if err != nil || strings.Contains(line, until) {
+			break
+		}
+	}
+	return output.String()
+}
+
+// writeBannerServer writes a fake MCP server script that prints a startup banner
+// before its first JSON-RPC message, then more plain output after it
+func writeBannerServer(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh binary not available")
+	}
+	script := filepath.Join(t.TempDir(), "banner-server")
+	content := "#!/bin/sh\n" +
+		"echo 'Architecture MCP server v1.0'\n" +
+		"echo ''\n" +
+		"echo '{\"status\":\"starting\"}'\n" +
+		"echo '{\"jsonrpc\":\"2.0\",\"method\":\"notifications/ready\"}'\n" +
+		"echo 'after first message'\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatalf("Failed to write banner server: %v", err)
+	}
+	return script
+}
+
+func TestSkipStdoutBanner(t *testing.T) {
+	tests := []struct {
+		name       string
+		skipBanner bool
+		want       string
+	}{
+		{
+			name:       "banner skipped until first JSON-RPC message",
+			skipBanner: true,
+			want: `{"jsonrpc":"2.0","method":"notifications/ready"}` + "\n" +
+				"after first message\n",
+		},
+		{
+			name: "banner forwarded by default",
+			want: "Architecture MCP server v1.0\n" +
+				"\n" +
+				`{"status":"starting"}` + "\n" +
+				`{"jsonrpc":"2.0","method":"notifications/ready"}` + "\n" +
+				"after first message\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge := newTestBridge(t)
+			bridge.skipBanner = tt.skipBanner
+
+			output := readChildOutput(t, bridge, writeBannerServer(t), "after first message")
+			if output != tt.want {
+				t.Errorf("Client received:\n%q\nwant:\n%q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsJSONRPCMessage(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`{"jsonrpc":"2.0","id":1,"result":{}}`, true},
+		{`{"jsonrpc":"2.0","method":"notifications/ready"}`, true},
+		{`{"jsonrpc":"1.0","id":1}`, false},
+		{`{"status":"starting"}`, false},
+		{`["jsonrpc"]`, false},
+		{"Architecture MCP server v1.0", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isJSONRPCMessage(tt.line); got != tt.want {
+			t.Errorf("isJSONRPCMessage(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCreateSession_WorkingDirectory(t *testing.T) {
+	bridge := newTestBridge(t)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	bridge.serverDir = dir
+
+	output := readChildOutput(t, bridge, "pwd", "/")
+	if got := strings.TrimSpace(output); got != dir {
+		t.Errorf("Child working directory = %q, want %q", got, dir)
+	}
+}
+
+func TestCreateSession_ExtraEnvironment(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.serverEnv = []string{"MCP_DOCS_ROOT=/srv/docs"}
+
+	output := readChildOutput(t, bridge, "env", "MCP_DOCS_ROOT")
+	if !strings.Contains(output, "MCP_DOCS_ROOT=/srv/docs\n") {
+		t.Errorf("Expected MCP_DOCS_ROOT in child environment, got:\n%s", output)
+	}
+	// Extra variables are appended to, not substituted for, the inherited environment
+	if os.Getenv("PATH") != "" && !strings.Contains(output, "PATH=") {
+		t.Error("Expected inherited PATH in child environment")
+	}
+}
+
+func TestValidateServerDir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{"empty uses bridge cwd", "", false},
+		{"existing directory", t.TempDir(), false},
+		{"missing directory", filepath.Join(t.TempDir(), "missing"), true},
+		{"regular file", file, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateServerDir(tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnvFlags_Set(t *testing.T) {
+	var env envFlags
+	if err := env.Set("MCP_DOCS_ROOT=/docs"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := env.Set("EMPTY="); err != nil {
+		t.Errorf("Unexpected error for empty value: %v", err)
+	}
+	if err := env.Set("NO_EQUALS"); err == nil {
+		t.Error("Expected error for missing '='")
+	}
+	if err := env.Set("=value"); err == nil {
+		t.Error("Expected error for empty key")
+	}
+	if len(env) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(env))
+	}
+}
+
+func TestMaxSessionLifetime_ClosesSessionWithNotification(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.maxLifetime = 150 * time.Millisecond
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := bufio.NewReader(clientConn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Expected expiry notification, got error: %v", err)
+	}
+
+	var notification map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &notification); err != nil {
+		t.Fatalf("Notification is not valid JSON: %v", err)
+	}
+	if notification["method"] != SessionExpiredNotification {
+		t.Errorf("Expected %s, got %v", SessionExpiredNotification, notification["method"])
+	}
+	if _, hasID := notification["id"]; hasID {
+		t.Error("Expiry notification must not carry an id")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Session was not closed after max lifetime")
+	}
+	waitForSessionCount(t, bridge, 0, time.Second)
+}
+
+func TestMaxSessionLifetime_DisabledByDefault(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Session closed without a lifetime limit configured")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+// startTestChildSession connects a client to a session running the test binary as
+// a child in the given mode
+func startTestChildSession(t *testing.T, bridge *MCPBridge, mode string) (net.Conn, chan struct{}) {
+	t.Helper()
+
+	bridge.serverPath = os.Args[0]
+	bridge.serverEnv = []string{testChildEnv + "=" + mode}
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+	return clientConn, done
+}
+
+// readResponseIDs reads responses from conn until it has want of them, the read
+// deadline passes or the connection closes, returning the IDs seen
+func readResponseIDs(t *testing.T, conn net.Conn, want int, deadline time.Duration) []string {
+	t.Helper()
+
+	var ids []string
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(deadline))
+	for len(ids) < want {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		var response struct {
+			ID interface{} `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(line), &response); err != nil {
+			t.Fatalf("Client received non-JSON output: %q", line)
+		}
+		ids = append(ids, fmt.Sprint(response.ID))
+	}
+	return ids
+}
+
+func TestReadyTimeout_HoldsMessagesForSlowChild(t *testing.T) {
+	tests := []struct {
+		name         string
+		readyTimeout time.Duration
+		want         []string
+	}{
+		{
+			name:         "messages held until the child is ready",
+			readyTimeout: 5 * time.Second,
+			want:         []string{"1", "2"},
+		},
+		{
+			// Without the probe the slow child drops the request sent during startup
+			name: "early messages dropped without readiness probe",
+			want: []string{"1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge := newTestBridge(t)
+			bridge.readyTimeout = tt.readyTimeout
+			clientConn, _ := startTestChildSession(t, bridge, "slow")
+
+			requests := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` + "\n" +
+				`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
+			if _, err := clientConn.Write([]byte(requests)); err != nil {
+				t.Fatalf("Failed to write requests: %v", err)
+			}
+
+			ids := readResponseIDs(t, clientConn, 2, slowChildStartup+time.Second)
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected responses to %v, got %v", tt.want, ids)
+			}
+		})
+	}
+}
+
+func TestReadyTimeout_ProbeLeavesHandshakeToClient(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.readyTimeout = 5 * time.Second
+	clientConn, _ := startTestChildSession(t, bridge, "strict")
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` + "\n"
+	if _, err := clientConn.Write([]byte(request)); err != nil {
+		t.Fatalf("Failed to write initialize: %v", err)
+	}
+
+	clientConn.SetReadDeadline(time.Now().Add(slowChildStartup + 2*time.Second))
+	line, err := bufio.NewReader(clientConn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read initialize response: %v", err)
+	}
+	var response struct {
+		ID     interface{}     `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(line), &response); err != nil {
+		t.Fatalf("Client received non-JSON output: %q", line)
+	}
+	if fmt.Sprint(response.ID) != "1" || response.Error != nil || response.Result == nil {
+		t.Errorf("Expected the client's initialize to be the child's first, got %s", line)
+	}
+}
+
+func TestReadyTimeout_ClosesSessionWhenChildNeverReady(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.readyTimeout = 100 * time.Millisecond
+	clientConn, done := startTestChildSession(t, bridge, "silent")
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Session was not closed after the readiness timeout")
+	}
+
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := clientConn.Read(make([]byte, 1)); err == nil {
+		t.Error("Expected the client connection to be closed")
+	}
+}
+
+func TestIsReadinessProbeResponse(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`{"jsonrpc":"2.0","id":"` + readinessProbeID + `","result":{}}`, true},
+		{`{"jsonrpc":"2.0","id":"` + readinessProbeID + `","error":{"code":-32603,"message":"x"}}`, true},
+		{`{"jsonrpc":"2.0","id":"` + readinessProbeID + `","method":"initialize"}`, false},
+		{`{"jsonrpc":"2.0","id":1,"result":{}}`, false},
+		{"not json", false},
+	}
+
+	for _, tt := range tests {
+		if got := isReadinessProbeResponse(tt.line); got != tt.want {
+			t.Errorf("isReadinessProbeResponse(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+// shutdownEntry returns the bridge's shutdown completed log entry
+func shutdownEntry(t *testing.T, entries []map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	for _, entry := range entries {
+		if entry["message"] == "MCP Bridge shutdown completed" {
+			return entry
+		}
+	}
+	t.Fatal("Expected a shutdown completed log entry")
+	return nil
+}
+
+func TestShutdownTimeout_KillsStuckChild(t *testing.T) {
+	bridge := newTestBridge(t)
+	logger, collect := newCapturedLogger(t, "INFO")
+	bridge.logger = logger
+	bridge.shutdownTimeout = 200 * time.Millisecond
+
+	_, done := startTestChildSession(t, bridge, "stuck")
+	waitForSessionCount(t, bridge, 1, 2*time.Second)
+
+	start := time.Now()
+	if err := bridge.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	elapsed := time.Since(start)
+	if elapsed < bridge.shutdownTimeout || elapsed > 2*time.Second {
+		t.Errorf("Expected Shutdown to return after the timeout, took %v", elapsed)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Session did not end after its child was killed")
+	}
+
+	entries := collect()
+	killed := false
+	for _, entry := range entries {
+		if entry["message"] == "MCP server did not exit within shutdown timeout, killing it" {
+			killed = true
+			if pid, _ := entry["pid"].(float64); pid <= 0 {
+				t.Errorf("Expected the killed child's pid to be logged, got %v", entry["pid"])
+			}
+		}
+	}
+	if !killed {
+		t.Error("Expected the forced kill to be logged")
+	}
+	if forced := shutdownEntry(t, entries)["forced_sessions"]; forced != float64(1) {
+		t.Errorf("Expected 1 forced session, got %v", forced)
+	}
+}
+
+func TestShutdownTimeout_CooperativeChildExitsGracefully(t *testing.T) {
+	bridge := newTestBridge(t)
+	logger, collect := newCapturedLogger(t, "INFO")
+	bridge.logger = logger
+	bridge.shutdownTimeout = 5 * time.Second
+
+	_, done := startTestChildSession(t, bridge, "silent")
+	waitForSessionCount(t, bridge, 1, 2*time.Second)
+
+	start := time.Now()
+	if err := bridge.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected a child that exits on EOF to end shutdown early, took %v", elapsed)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Session did not end after shutdown")
+	}
+
+	if forced := shutdownEntry(t, collect())["forced_sessions"]; forced != float64(0) {
+		t.Errorf("Expected no forced sessions, got %v", forced)
+	}
+}
+
+func TestNewSessionID_UniqueUnderConcurrency(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	const workers, perWorker = 16, 500
+	ids := make(chan string, workers*perWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				ids <- bridge.newSessionID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("Session ID %s generated twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestHandleConnection_ConcurrentConnectionsGetDistinctSessions(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	// A colliding ID would overwrite a session in the map, so fewer would be tracked
+	const connections = 50
+	for i := 0; i < connections; i++ {
+		clientConn, serverConn := net.Pipe()
+		t.Cleanup(func() { clientConn.Close() })
+		go bridge.handleConnection(serverConn)
+	}
+
+	waitForSessionCount(t, bridge, connections, 5*time.Second)
+}
+
+func TestAddSession_RefusesDuplicateID(t *testing.T) {
+	bridge := &MCPBridge{sessions: make(map[string]*MCPSession)}
+
+	first := &MCPSession{id: "session_1_1"}
+	if !bridge.addSession(first) {
+		t.Fatal("Expected the first session to be added")
+	}
+	if bridge.addSession(&MCPSession{id: "session_1_1"}) {
+		t.Error("Expected a session with a duplicate ID to be refused")
+	}
+	if bridge.sessions["session_1_1"] != first {
+		t.Error("Duplicate session overwrote the original")
+	}
+}
+
+func TestClientDisconnect_ReapsChildPromptly(t *testing.T) {
+	bridge := newTestBridge(t)
+	logger, collect := newCapturedLogger(t, "DEBUG")
+	bridge.logger = logger
+
+	clientConn, serverConn := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	// Wait for the echo so the child is known to be running
+	go clientConn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := bufio.NewReader(clientConn).ReadString('\n'); err != nil {
+		t.Fatalf("Failed to read echoed message: %v", err)
+	}
+
+	bridge.mu.RLock()
+	var session *MCPSession
+	for _, s := range bridge.sessions {
+		session = s
+	}
+	bridge.mu.RUnlock()
+	if session == nil {
+		t.Fatal("Expected an active session")
+	}
+
+	clientConn.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Session was not cleaned up promptly after the client disconnected")
+	}
+	if session.process.ProcessState == nil {
+		t.Error("Expected the child process to be reaped")
+	}
+	waitForSessionCount(t, bridge, 0, time.Second)
+
+	for _, entry := range collect() {
+		if entry["level"] == "ERROR" {
+			t.Errorf("Unexpected error logged on disconnect: %v", entry["message"])
+		}
+	}
+}
+
+// sessionGoroutines is how many goroutines one session runs, the connection's
+// own included
+const sessionGoroutines = 3
+
+// openEchoSession connects a client to a cat-backed session and waits until a
+// message has made the round trip, returning the client side of the connection
+// and a channel closed when handleConnection returns
+func openEchoSession(t *testing.T, bridge *MCPBridge) (net.Conn, chan struct{}) {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+	done := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(done)
+	}()
+
+	go clientConn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := bufio.NewReader(clientConn).ReadString('\n'); err != nil {
+		t.Fatalf("Session did not echo: %v", err)
+	}
+	clientConn.SetReadDeadline(time.Time{})
+	return clientConn, done
+}
+
+// waitForGoroutines polls until at most want goroutines are running
+func waitForGoroutines(t *testing.T, want int, timeout time.Duration) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected at most %d goroutines, %d running", want, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSessions_GoroutinesBounded(t *testing.T) {
+	bridge := newTestBridge(t)
+	baseline := runtime.NumGoroutine()
+
+	const concurrent = 5
+	var clients []net.Conn
+	var dones []chan struct{}
+	for i := 0; i < concurrent; i++ {
+		clientConn, done := openEchoSession(t, bridge)
+		clients = append(clients, clientConn)
+		dones = append(dones, done)
+	}
+	if got := runtime.NumGoroutine() - baseline; got > concurrent*sessionGoroutines {
+		t.Errorf("Expected at most %d goroutines for %d sessions, got %d",
+			concurrent*sessionGoroutines, concurrent, got)
+	}
+
+	for i, clientConn := range clients {
+		clientConn.Close()
+		<-dones[i]
+	}
+
+	// Many short-lived sessions must not leave goroutines behind
+	for i := 0; i < 30; i++ {
+		clientConn, done := openEchoSession(t, bridge)
+		clientConn.Close()
+		<-done
+	}
+	waitForGoroutines(t, baseline, time.Second)
+}
+
+func TestMaxSessions_RefusesConnectionsOverLimit(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.maxSessions = 2
+
+	first, firstDone := openEchoSession(t, bridge)
+	openEchoSession(t, bridge)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	refused := make(chan struct{})
+	go func() {
+		bridge.handleConnection(serverConn)
+		close(refused)
+	}()
+	select {
+	case <-refused:
+	case <-time.After(time.Second):
+		t.Fatal("Connection over the session limit was not refused")
+	}
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := clientConn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected the refused connection to be closed, got %v", err)
+	}
+
+	// Ending a session frees its slot
+	first.Close()
+	<-firstDone
+	openEchoSession(t, bridge)
+	waitForSessionCount(t, bridge, 2, time.Second)
+}
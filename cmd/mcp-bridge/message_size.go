@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// oversizedMessageCode is the JSON-RPC error code sent in place of a response that
+// exceeds -max-message-size (internal error)
+const oversizedMessageCode = -32603
+
+// serverLine is one line of child stdout, without its line terminator
+type serverLine struct {
+	data []byte // The whole line, or only its first bytes when oversized is set
+	size int    // Length of the whole line in bytes
+	// oversized is set when the line is longer than the limit it was read with;
+	// data then holds just enough of it to identify the message
+	oversized bool
+}
+
+// readServerLine reads the next line from r. Lines of any length are read; with a
+// positive limit, only the first limit+1 bytes of a longer line are kept.
+func readServerLine(r *bufio.Reader, limit int) (serverLine, error) {
+	var line serverLine
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			if err == io.EOF && line.size > 0 {
+				return line, nil
+			}
+			return line, err
+		}
+
+		line.size += len(chunk)
+		if limit > 0 && line.size > limit {
+			line.oversized = true
+		}
+		if limit > 0 && len(line.data)+len(chunk) > limit+1 {
+			chunk = chunk[:max(limit+1-len(line.data), 0)]
+		}
+		line.data = append(line.data, chunk...)
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// leadingMessageID returns the id of the JSON-RPC message data starts with. It only
+// decodes the members before the id, so it works on a truncated message as long as
+// the id comes first, as it does in the server's responses.
+func leadingMessageID(data []byte) (interface{}, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		if key, _ := token.(string); key == "id" {
+			var id interface{}
+			if decoder.Decode(&id) != nil || id == nil {
+				return nil, false
+			}
+			return id, true
+		}
+		var skipped json.RawMessage
+		if decoder.Decode(&skipped) != nil {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// rejectOversizedMessage handles a child message larger than maxMessageSize.
+// JSON-RPC has no way to split a message across frames, so it is never chunked:
+// a response is replaced by an error response with the same id so the client is
+// not left waiting, and anything else is dropped. Either way it is logged.
+func (s *MCPSession) rejectOversizedMessage(line serverLine) error {
+	logger := s.logger.WithContext("direction", "server_to_client").
+		WithContext("message_size", line.size).
+		WithContext("max_message_size", s.maxMessageSize)
+
+	id, ok := leadingMessageID(line.data)
+	if !ok {
+		logger.Warn("Dropped server message exceeding maximum message size")
+		return nil
+	}
+
+	logger.WithContext("request_id", id).
+		Warn("Rejected server response exceeding maximum message size")
+
+	response, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]interface{}{
+			"code":    oversizedMessageCode,
+			"message": fmt.Sprintf("Response of %d bytes exceeds the bridge's maximum message size of %d bytes", line.size, s.maxMessageSize),
+			"data": map[string]interface{}{
+				"size":  line.size,
+				"limit": s.maxMessageSize,
+			},
+		},
+	})
+	return s.writeToClient(response)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoThroughBridge sends input through a cat-backed session and returns the first
+// want lines the client receives, without their newlines
+func echoThroughBridge(t *testing.T, bridge *MCPBridge, input string, want int) []string {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+	go bridge.handleConnection(serverConn)
+	go clientConn.Write([]byte(input))
+
+	reader := bufio.NewReader(clientConn)
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var lines []string
+	for len(lines) < want {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read message %d: %v", len(lines), err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	return lines
+}
+
+func TestMaxMessageSize_RejectsOversizedResponse(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.maxMessageSize = 256
+
+	small := `{"jsonrpc":"2.0","id":1,"result":{"text":"short"}}`
+	oversized := `{"jsonrpc":"2.0","id":"read-2","result":{"text":"` + strings.Repeat("x", 1000) + `"}}`
+	lines := echoThroughBridge(t, bridge, small+"\n"+oversized+"\n", 2)
+
+	if lines[0] != small {
+		t.Errorf("Message within the limit: got %q, want %q", lines[0], small)
+	}
+
+	var response struct {
+		ID     interface{}     `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code int `json:"code"`
+			Data struct {
+				Size  int `json:"size"`
+				Limit int `json:"limit"`
+			} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &response); err != nil {
+		t.Fatalf("Rejection is not valid JSON: %v", err)
+	}
+	if response.ID != "read-2" {
+		t.Errorf("Expected rejection for id read-2, got %v", response.ID)
+	}
+	if response.Result != nil {
+		t.Error("Rejection must not carry the oversized result")
+	}
+	if response.Error == nil {
+		t.Fatal("Expected an error response")
+	}
+	if response.Error.Code != oversizedMessageCode {
+		t.Errorf("Expected error code %d, got %d", oversizedMessageCode, response.Error.Code)
+	}
+	if response.Error.Data.Size != len(oversized) || response.Error.Data.Limit != 256 {
+		t.Errorf("Expected size %d and limit 256, got %+v", len(oversized), response.Error.Data)
+	}
+}
+
+func TestMaxMessageSize_DropsOversizedNotification(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.maxMessageSize = 256
+
+	notification := `{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"` + strings.Repeat("x", 1000) + `"}}`
+	next := `{"jsonrpc":"2.0","id":3,"result":{}}`
+	lines := echoThroughBridge(t, bridge, notification+"\n"+next+"\n", 1)
+
+	if lines[0] != next {
+		t.Errorf("Expected the oversized notification to be dropped, got %q", lines[0])
+	}
+}
+
+func TestMaxMessageSize_DisabledForwardsLargeMessages(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	// Larger than bufio.Scanner's default 64KB token limit
+	large := `{"jsonrpc":"2.0","id":4,"result":{"text":"` + strings.Repeat("x", 100*1024) + `"}}`
+	lines := echoThroughBridge(t, bridge, large+"\n", 1)
+
+	if lines[0] != large {
+		t.Errorf("Expected the %d byte message forwarded intact, got %d bytes", len(large), len(lines[0]))
+	}
+}
+
+func TestReadServerLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		limit     int
+		wantData  string
+		wantSize  int
+		oversized bool
+	}{
+		{name: "no limit", input: strings.Repeat("a", 40) + "\n", wantData: strings.Repeat("a", 40), wantSize: 40},
+		{name: "within limit", input: "abc\r\n", limit: 3, wantData: "abc", wantSize: 3},
+		{name: "over limit keeps prefix", input: strings.Repeat("a", 40) + "\n", limit: 20, wantData: strings.Repeat("a", 21), wantSize: 40, oversized: true},
+		{name: "last line without newline", input: "abc", wantData: "abc", wantSize: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A 16 byte buffer makes long lines arrive in several chunks
+			reader := bufio.NewReaderSize(strings.NewReader(tt.input), 16)
+			line, err := readServerLine(reader, tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(line.data) != tt.wantData || line.size != tt.wantSize || line.oversized != tt.oversized {
+				t.Errorf("got data %q size %d oversized %v, want %q %d %v",
+					line.data, line.size, line.oversized, tt.wantData, tt.wantSize, tt.oversized)
+			}
+		})
+	}
+}
+
+func TestLeadingMessageID(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		wantID interface{}
+		wantOK bool
+	}{
+		{name: "numeric id", data: `{"jsonrpc":"2.0","id":7,"result":{}}`, wantID: float64(7), wantOK: true},
+		{name: "truncated after id", data: `{"jsonrpc":"2.0","id":"abc","result":{"text":"xx`, wantID: "abc", wantOK: true},
+		{name: "truncated before id", data: `{"jsonrpc":"2.0","result":{"text":"xx`},
+		{name: "notification", data: `{"jsonrpc":"2.0","method":"notifications/message"}`},
+		{name: "null id", data: `{"jsonrpc":"2.0","id":null,"error":{}}`},
+		{name: "not JSON", data: `server starting`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := leadingMessageID([]byte(tt.data))
+			if ok != tt.wantOK || id != tt.wantID {
+				t.Errorf("got (%v, %v), want (%v, %v)", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClientArgsMetaKey is the initialize params _meta entry in which a client asks for
+// MCP server flags, as an object of flag name to value such as
+// {"log-level": "DEBUG"}. Only flags allowed with -client-arg are accepted.
+const ClientArgsMetaKey = "mcp-bridge/serverArgs"
+
+// rejectedArgsCode is the JSON-RPC error code sent when a client asks for server
+// flags it may not set (invalid params)
+const rejectedArgsCode = -32602
+
+// clientArgFlags collects repeated -client-arg flags, each naming an MCP server
+// flag clients may set
+type clientArgFlags []string
+
+func (c *clientArgFlags) String() string {
+	return strings.Join(*c, ",")
+}
+
+func (c *clientArgFlags) Set(value string) error {
+	if value == "" || strings.HasPrefix(value, "-") || strings.ContainsAny(value, "= \t\r\n") {
+		return fmt.Errorf("expected a flag name without dashes, such as log-level, got %q", value)
+	}
+	*c = append(*c, value)
+	return nil
+}
+
+// sessionStart is what the bridge has read from a client before spawning its child
+type sessionStart struct {
+	args   []string        // Extra MCP server arguments the client asked for
+	client io.Reader       // Client input still to be read, following first
+	first  json.RawMessage // The client's first message, not yet forwarded
+}
+
+// readSessionStart reads the client's first message so the child can be started
+// with the server flags it asks for. A client asking for a flag outside the
+// allow-list is answered with an error and refused.
+func (b *MCPBridge) readSessionStart(conn net.Conn) (sessionStart, error) {
+	if b.readTimeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(b.readTimeout))
+	}
+
+	decoder := json.NewDecoder(conn)
+	var first json.RawMessage
+	if err := decoder.Decode(&first); err != nil {
+		return sessionStart{}, fmt.Errorf("failed to read first client message: %v", err)
+	}
+
+	args, err := b.clientArgs(first)
+	if err != nil {
+		if id, ok := leadingMessageID(first); ok {
+			response, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      id,
+				"error": map[string]interface{}{
+					"code":    rejectedArgsCode,
+					"message": err.Error(),
+					"data":    map[string]interface{}{"allowed": b.allowedClientArgs},
+				},
+			})
+			conn.SetWriteDeadline(time.Now().Add(expiryNotifyTimeout))
+			conn.Write(append(response, '\n'))
+		}
+		return sessionStart{}, err
+	}
+
+	return sessionStart{
+		args:   args,
+		client: io.MultiReader(decoder.Buffered(), conn),
+		first:  first,
+	}, nil
+}
+
+// clientArgs returns the MCP server arguments an initialize message asks for under
+// ClientArgsMetaKey, as -name=value in name order. Other messages ask for none.
+func (b *MCPBridge) clientArgs(message json.RawMessage) ([]string, error) {
+	var request struct {
+		Method string `json:"method"`
+		Params struct {
+			Meta map[string]json.RawMessage `json:"_meta"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(message, &request) != nil || request.Method != "initialize" {
+		return nil, nil
+	}
+	raw, ok := request.Params.Meta[ClientArgsMetaKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var requested map[string]string
+	if err := json.Unmarshal(raw, &requested); err != nil {
+		return nil, fmt.Errorf("%s must map server flag names to string values", ClientArgsMetaKey)
+	}
+
+	names := make([]string, 0, len(requested))
+	for name := range requested {
+		if !slices.Contains(b.allowedClientArgs, name) {
+			return nil, fmt.Errorf("server flag %q may not be set by clients", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Passing each flag as one -name=value argument keeps a value from being read
+	// as a further flag
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, "-"+name+"="+requested[name])
+	}
+	return args, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientArgFlags_Set(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"log-level", false},
+		{"docs-root", false},
+		{"", true},
+		{"-log-level", true},
+		{"log-level=DEBUG", true},
+		{"log level", true},
+	}
+
+	for _, tt := range tests {
+		var flags clientArgFlags
+		err := flags.Set(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestClientArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "allowed flags passed in name order",
+			message: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"_meta":{"mcp-bridge/serverArgs":{"log-level":"DEBUG","checksum":"none"}}}}`,
+			want:    []string{"-checksum=none", "-log-level=DEBUG"},
+		},
+		{
+			name:    "value that looks like a flag stays one argument",
+			message: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"_meta":{"mcp-bridge/serverArgs":{"log-level":"DEBUG -audit-log=/etc/passwd"}}}}`,
+			want:    []string{"-log-level=DEBUG -audit-log=/etc/passwd"},
+		},
+		{
+			name:    "disallowed flag rejected",
+			message: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"_meta":{"mcp-bridge/serverArgs":{"log-level":"DEBUG","audit-log":"/tmp/audit"}}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "non-string value rejected",
+			message: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"_meta":{"mcp-bridge/serverArgs":{"log-level":3}}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "initialize without metadata",
+			message: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		},
+		{
+			name:    "other methods ignored",
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"_meta":{"mcp-bridge/serverArgs":{"audit-log":"/tmp/audit"}}}}`,
+		},
+	}
+
+	bridge := &MCPBridge{allowedClientArgs: []string{"log-level", "checksum"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bridge.clientArgs(json.RawMessage(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clientArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("clientArgs() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClientArgs_AllowedArgsPassedToChild(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.allowedClientArgs = []string{"log-level"}
+	clientConn, _ := startTestChildSession(t, bridge, "args")
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"_meta":{"mcp-bridge/serverArgs":{"log-level":"DEBUG"}}}}`
+	followUp := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	go clientConn.Write([]byte(initialize + "\n" + followUp + "\n"))
+
+	reader := bufio.NewReader(clientConn)
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read from session after %d lines: %v", len(lines), err)
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+
+	var report struct {
+		Params struct {
+			Args []string `json:"args"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &report); err != nil {
+		t.Fatalf("Expected argument report first, got %q", lines[0])
+	}
+	if want := []string{"-log-level=DEBUG"}; !reflect.DeepEqual(report.Params.Args, want) {
+		t.Errorf("Child arguments = %q, want %q", report.Params.Args, want)
+	}
+
+	// The message the arguments were read from still reaches the child, in order
+	if lines[1] != initialize || lines[2] != followUp {
+		t.Errorf("Child received %q, want the initialize request then the follow-up", lines[1:])
+	}
+}
+
+func TestClientArgs_DisallowedArgsRejected(t *testing.T) {
+	bridge := newTestBridge(t)
+	bridge.allowedClientArgs = []string{"log-level"}
+	clientConn, done := startTestChildSession(t, bridge, "args")
+
+	go clientConn.Write([]byte(`{"jsonrpc":"2.0","id":7,"method":"initialize","params":{"_meta":{"mcp-bridge/serverArgs":{"audit-log":"/tmp/audit"}}}}` + "\n"))
+
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := bufio.NewReader(clientConn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Expected an error response, got %v", err)
+	}
+
+	var response struct {
+		ID    interface{} `json:"id"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(line), &response); err != nil || response.Error == nil {
+		t.Fatalf("Expected a JSON-RPC error, got %q", line)
+	}
+	if response.ID != float64(7) || response.Error.Code != rejectedArgsCode {
+		t.Errorf("Got id %v code %d, want id 7 code %d", response.ID, response.Error.Code, rejectedArgsCode)
+	}
+	if !strings.Contains(response.Error.Message, "audit-log") {
+		t.Errorf("Expected error to name the flag, got %q", response.Error.Message)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the connection to be refused")
+	}
+	waitForSessionCount(t, bridge, 0, time.Second)
+}
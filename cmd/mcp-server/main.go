@@ -5,15 +5,71 @@ import (
 	"flag"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"mcp-architecture-service/internal/server"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
+	"mcp-architecture-service/pkg/prompts"
+	"mcp-architecture-service/pkg/scanner"
+	"mcp-architecture-service/pkg/tools"
 )
 
 func main() {
 	// Parse command-line flags
 	logLevel := flag.String("log-level", "INFO", "Logging level (DEBUG, INFO, WARN, ERROR)")
+	adrNamingPattern := flag.String("adr-naming-pattern", tools.DefaultADRNamingPattern, "Regular expression ADR filenames must match for check-naming-conventions")
+	patternNamingPattern := flag.String("pattern-naming-pattern", tools.DefaultKebabCaseNamePattern, "Regular expression pattern filenames must match for check-naming-conventions")
+	guidelineNamingPattern := flag.String("guideline-naming-pattern", tools.DefaultKebabCaseNamePattern, "Regular expression guideline filenames must match for check-naming-conventions")
+	selfTest := flag.Bool("selftest", false, "Load documentation, prompts and tools, print a report to stdout and exit without serving; exits 1 on fatal issues")
+	loadWorkers := flag.Int("load-workers", 0, "Files read and parsed concurrently during the initial documentation load (0 uses one per CPU, up to 4)")
+	adrProximityWindow := flag.Int("adr-proximity-window", tools.DefaultProximityWindow, "How close, in characters, an opposing keyword such as avoid must be to a decision keyword for check-adr-alignment to report a conflict")
+	adrMinConfidence := flag.Float64("adr-min-confidence", tools.DefaultMinAlignmentConfidence, "Confidence between 0 and 1 check-adr-alignment needs to report an ADR as supporting or conflicting rather than related")
+	staleThresholdDays := flag.Int("stale-threshold-days", tools.DefaultStaleThresholdDays, "Days a document may go unmodified before find-stale-documents reports it when a call leaves out threshold_days")
+	cacheCleanupInterval := flag.Duration("cache-cleanup-interval", server.DefaultCacheCleanupInterval, "How often the document cache is cleaned up")
+	cacheTTL := flag.Duration("cache-ttl", 0, "How long documents stay cached before cleanup expires them (0 disables expiry)")
+	cacheHitRatioThreshold := flag.Float64("cache-hit-ratio-threshold", cache.DefaultHitRatioThreshold, "Cache hit ratio percentage, measured over each minute of lookups, below which a warning is logged (0 disables)")
+	cacheInternContent := flag.Bool("cache-intern-content", false, "Keep one copy of the content of documents with identical content in the cache, saving memory when documents are duplicated")
+	strict := flag.Bool("strict", false, "Fail startup if any document file is skipped or fails to parse, any prompt fails to load, or documents share a resource URI such as a duplicate ADR ID")
+	readThrough := flag.Bool("read-through", false, "Load a document missing from the cache from disk when resources/read asks for it, instead of answering not found")
+	checksumAlgorithm := flag.String("checksum", string(scanner.DefaultChecksumAlgorithm), "Algorithm document checksums are computed with ("+strings.Join(scanner.ChecksumAlgorithms(), ", ")+"); none skips them for faster loading")
+	followSymlinks := flag.Bool("follow-symlinks", false, "Load documentation reached through symbolic links (loops are detected and skipped)")
+	responseFormat := flag.String("response-format", string(server.ResponseFormatCompact), "How responses are marshaled: compact for production, indented for reading while debugging (still one message per line)")
+	validateResponses := flag.Bool("validate-responses", false, "Check every message sent against the embedded MCP schema and log violations as errors; a debugging aid that slows responses down")
+	errorVerbosity := flag.String("error-verbosity", string(errors.ErrorVerbosityTerse), "Internal detail in JSON-RPC errors: terse leaves out causes, verbose includes them for development")
+	stopWordLanguage := flag.String("stop-words", "", "Language of the stop words left out of search and ADR alignment queries ("+strings.Join(tools.StopWordLanguages(), ", ")+"); empty keeps every search word and drops English ones from ADR alignment")
+	minQueryLength := flag.Int("min-query-length", tools.DefaultMinQueryLength, "Shortest search-architecture query accepted, in characters after trimming whitespace")
+	maxListedResources := flag.Int("max-listed-resources", 0, "Most resources advertised per resources/list page; the rest are paged through nextCursor (0 lists all)")
+	maxListedPrompts := flag.Int("max-listed-prompts", 0, "Most prompts advertised per prompts/list page; the rest are paged through nextCursor (0 lists all)")
+	maxPromptArguments := flag.Int("max-prompt-arguments", server.DefaultMaxPromptArguments, "Most arguments one prompts/get or prompts/preview request may pass (0 disables)")
+	maxPromptArgumentsSize := flag.Int("max-prompt-arguments-size", server.DefaultMaxPromptArgumentsSize, "Largest total size in bytes, as JSON, of the arguments one prompts/get or prompts/preview request may pass (0 disables)")
+	maxBatchPrompts := flag.Int("max-batch-prompts", server.DefaultMaxBatchPrompts, "Most prompts one prompts/get-batch request may render (0 disables)")
+	auditLogPath := flag.String("audit-log", "", "File to append a JSON audit record of every tool invocation to (empty disables auditing)")
+	logArgLength := flag.Int("log-arg-length", tools.DefaultSanitizeLength, "Characters of a tool argument kept in logs and audit records before truncation")
+	redactFields := flag.String("redact-fields", strings.Join(tools.DefaultRedactedFields, ","), "Comma-separated tool argument names logged and audited as [redacted] (empty disables redaction)")
+	sectionLevels := flag.String("section-levels", "2", "Comma-separated heading levels of the named sections validate-against-pattern and check-adr-alignment read, such as Decision or Best Practices")
+	relatedResources := flag.Int("related-resources", server.DefaultRelatedResources, "Related resource URIs returned with each resources/read, ranked like find-similar (0 disables)")
+	readOnlyDocs := flag.Bool("read-only-docs", false, "Fail fast if anything tries to write inside the documentation roots, for read-only mounts")
+	outputDir := flag.String("output-dir", "", "Directory relative output files such as -audit-log are created in (empty uses the working directory)")
+	searchBoosts := flag.String("search-boosts", "", "Comma-separated category=multiplier search score boosts such as adr=1.5,patterns=2 (categories left out stay at 1.0)")
+	collapseCodeExcerpts := flag.Bool("collapse-code-excerpts", false, "Collapse fenced code blocks in search-architecture excerpts to [code omitted]; terms inside them still count toward relevance")
+	promptDirs := flag.String("prompt-dirs", config.PromptsBasePath, "Comma-separated directories prompts are loaded from, lowest precedence first; a prompt in a later directory overrides one with the same name in an earlier directory")
+	promptNamePattern := flag.String("prompt-name-pattern", prompts.DefaultNamePattern, "Regular expression prompt names must match; prompts with other names are skipped with an error")
+	lenientPromptNames := flag.Bool("lenient-prompt-names", false, "Load prompts whose names do not match -prompt-name-pattern with a warning instead of skipping them (ignored with -strict)")
+	promptDelimiters := flag.String("prompt-delimiters", prompts.DefaultDelimiters.Open+" "+prompts.DefaultDelimiters.Close, "Open and close delimiters of prompt template placeholders separated by a space, such as \"<< >>\"; prompts may set their own")
+	embedHeader := flag.String("embed-header", escapeFlagDefault(prompts.DefaultEmbedFormat.Header), "Header before each document a prompt embeds; may use {{title}}, {{path}}, {{uri}}, {{category}}, {{tokens}} and escapes such as \\n")
+	embedFooter := flag.String("embed-footer", escapeFlagDefault(prompts.DefaultEmbedFormat.Footer), "Footer after each document a prompt embeds; same placeholders as -embed-header")
+	embedSeparator := flag.String("embed-separator", escapeFlagDefault(prompts.DefaultEmbedFormat.Separator), "Text between consecutive documents a prompt embeds")
+	embedTokenBudget := flag.Int("embed-token-budget", 0, "Most estimated tokens of document content one prompt message may embed (0 disables)")
+	embedOverflow := flag.String("embed-overflow", string(prompts.EmbedOverflowError), "What exceeding -embed-token-budget does: error fails the prompt, truncate cuts the largest documents to fit and notes what was left out")
+	trimPromptArgs := flag.Bool("trim-prompt-args", prompts.DefaultArgumentNormalization.Trim, "Strip leading and trailing whitespace from prompt argument values, except arguments a prompt flags preserve")
+	collapsePromptArgWhitespace := flag.Bool("collapse-prompt-arg-whitespace", prompts.DefaultArgumentNormalization.CollapseWhitespace, "Replace each run of whitespace in prompt argument values with a single space, except arguments a prompt flags preserve")
+	maxConcurrentRequests := flag.Int("max-concurrent-requests", server.DefaultMaxConcurrentRequests, "Most requests from the client handled at once; further requests wait until one finishes (0 disables)")
+	shutdownTimeout := flag.Duration("shutdown-timeout", server.DefaultShutdownTimeout, "How long shutdown waits for in-flight requests and cleanup before abandoning them (0 abandons in-flight requests at once)")
 	flag.Parse()
 
 	// Initialize logging system
@@ -25,6 +81,24 @@ func main() {
 
 	logger.Info("Starting MCP Server")
 
+	verbosity, err := errors.ParseErrorVerbosity(*errorVerbosity)
+	if err != nil {
+		logger.WithError(err).Error("Invalid -error-verbosity")
+		os.Exit(2)
+	}
+
+	format, err := server.ParseResponseFormat(*responseFormat)
+	if err != nil {
+		logger.WithError(err).Error("Invalid -response-format")
+		os.Exit(2)
+	}
+
+	checksum, err := scanner.ParseChecksumAlgorithm(*checksumAlgorithm)
+	if err != nil {
+		logger.WithError(err).Error("Invalid -checksum")
+		os.Exit(2)
+	}
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -35,11 +109,143 @@ func main() {
 
 	// Initialize and start MCP server with log level
 	mcpServer := server.NewMCPServerWithLogLevel(*logLevel)
+	if err := mcpServer.SetNamingConventions(map[string]string{
+		config.CategoryADR:       *adrNamingPattern,
+		config.CategoryPattern:   *patternNamingPattern,
+		config.CategoryGuideline: *guidelineNamingPattern,
+	}); err != nil {
+		logger.WithError(err).Error("Invalid -adr-naming-pattern, -pattern-naming-pattern or -guideline-naming-pattern")
+		os.Exit(2)
+	}
+	mcpServer.SetLoadWorkers(*loadWorkers)
+	if err := mcpServer.SetADRProximityWindow(*adrProximityWindow); err != nil {
+		logger.WithError(err).Error("Invalid -adr-proximity-window")
+		os.Exit(2)
+	}
+	if err := mcpServer.SetADRMinConfidence(*adrMinConfidence); err != nil {
+		logger.WithError(err).Error("Invalid -adr-min-confidence")
+		os.Exit(2)
+	}
+	if err := mcpServer.SetStaleThresholdDays(*staleThresholdDays); err != nil {
+		logger.WithError(err).Error("Invalid -stale-threshold-days")
+		os.Exit(2)
+	}
+	mcpServer.SetFollowSymlinks(*followSymlinks)
+	mcpServer.SetChecksumAlgorithm(checksum)
+	mcpServer.SetReadThrough(*readThrough)
+	mcpServer.SetStrict(*strict)
+	mcpServer.SetCacheCleanupInterval(*cacheCleanupInterval)
+	mcpServer.SetCacheTTL(*cacheTTL)
+	mcpServer.SetCacheHitRatioThreshold(*cacheHitRatioThreshold)
+	mcpServer.SetCacheContentInterning(*cacheInternContent)
+	mcpServer.SetErrorVerbosity(verbosity)
+	mcpServer.SetResponseFormat(format)
+	mcpServer.SetValidateResponses(*validateResponses)
+	mcpServer.SetMaxListedResources(*maxListedResources)
+	mcpServer.SetMaxListedPrompts(*maxListedPrompts)
+	mcpServer.SetPromptArgumentLimits(*maxPromptArguments, *maxPromptArgumentsSize)
+	mcpServer.SetMaxBatchPrompts(*maxBatchPrompts)
+	mcpServer.SetPromptArgumentNormalization(prompts.ArgumentNormalization{
+		Trim:               *trimPromptArgs,
+		CollapseWhitespace: *collapsePromptArgWhitespace,
+	})
+	mcpServer.SetRelatedResources(*relatedResources)
+	mcpServer.SetReadOnlyDocs(*readOnlyDocs)
+	mcpServer.SetOutputDir(*outputDir)
+	mcpServer.SetMaxConcurrentRequests(*maxConcurrentRequests)
+	mcpServer.SetShutdownTimeout(*shutdownTimeout)
+	mcpServer.SetCollapseCodeExcerpts(*collapseCodeExcerpts)
+	if err := mcpServer.SetPromptDirectories(strings.Split(*promptDirs, ",")); err != nil {
+		logger.WithError(err).Error("Invalid -prompt-dirs")
+		os.Exit(2)
+	}
+	if err := mcpServer.SetArgumentSanitization(*logArgLength, strings.Split(*redactFields, ",")); err != nil {
+		logger.WithError(err).Error("Invalid -log-arg-length")
+		os.Exit(2)
+	}
+	if *auditLogPath != "" {
+		auditLog, err := mcpServer.OpenOutputFile(*auditLogPath)
+		if err != nil {
+			logger.WithError(err).Error("Failed to open -audit-log")
+			os.Exit(2)
+		}
+		defer auditLog.Close()
+		mcpServer.SetAuditLog(auditLog)
+	}
+	if err := mcpServer.SetStopWordLanguage(*stopWordLanguage); err != nil {
+		logger.WithError(err).Error("Invalid -stop-words")
+		os.Exit(2)
+	}
+	if err := mcpServer.SetMinQueryLength(*minQueryLength); err != nil {
+		logger.WithError(err).Error("Invalid -min-query-length")
+		os.Exit(2)
+	}
+	boosts, err := tools.ParseCategoryBoosts(*searchBoosts)
+	if err == nil {
+		err = mcpServer.SetCategoryBoosts(boosts)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Invalid -search-boosts")
+		os.Exit(2)
+	}
+	embedFormat, err := parseEmbedFormat(*embedHeader, *embedFooter, *embedSeparator)
+	if err == nil {
+		err = mcpServer.SetEmbedFormat(embedFormat)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Invalid -embed-header, -embed-footer or -embed-separator")
+		os.Exit(2)
+	}
+	overflow, err := prompts.ParseEmbedOverflow(*embedOverflow)
+	if err == nil {
+		err = mcpServer.SetEmbedBudget(prompts.EmbedBudget{Tokens: *embedTokenBudget, Overflow: overflow})
+	}
+	if err != nil {
+		logger.WithError(err).Error("Invalid -embed-token-budget or -embed-overflow")
+		os.Exit(2)
+	}
+	if err := mcpServer.SetPromptNamePattern(*promptNamePattern); err != nil {
+		logger.WithError(err).Error("Invalid -prompt-name-pattern")
+		os.Exit(2)
+	}
+	mcpServer.SetLenientPromptNames(*lenientPromptNames)
+	delimiters, err := prompts.ParseDelimiters(*promptDelimiters)
+	if err == nil {
+		err = mcpServer.SetPromptDelimiters(delimiters)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Invalid -prompt-delimiters")
+		os.Exit(2)
+	}
+	levels, err := tools.ParseSectionLevels(*sectionLevels)
+	if err == nil {
+		err = mcpServer.SetSectionLevels(levels)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Invalid -section-levels")
+		os.Exit(2)
+	}
+
+	if *selfTest {
+		os.Exit(runSelfTest(ctx, mcpServer, os.Stdout))
+	}
+
+	// SIGHUP reloads changed documentation and prompts without dropping connections
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go reloadOnSignal(ctx, reloadChan, func() {
+		logger.Info("Received SIGHUP, reloading documentation and prompts")
+		if err := mcpServer.Reload(ctx); err != nil {
+			logger.WithError(err).Error("Reload failed")
+		}
+	})
 
 	// Start server in a goroutine
+	startFailed := make(chan struct{})
 	go func() {
 		if err := mcpServer.Start(ctx); err != nil {
 			logger.WithError(err).Error("MCP server error")
+			close(startFailed)
 			cancel()
 		}
 	}()
@@ -56,4 +262,47 @@ func main() {
 	if err := mcpServer.Shutdown(ctx); err != nil {
 		logger.WithError(err).Error("Error during shutdown")
 	}
+
+	// A server that failed to start, for example in -strict mode, must not look
+	// like one that was shut down cleanly
+	select {
+	case <-startFailed:
+		os.Exit(1)
+	default:
+	}
+}
+
+// escapeFlagDefault shows a default template on one line, as it would be typed
+func escapeFlagDefault(template string) string {
+	quoted := strconv.Quote(template)
+	return quoted[1 : len(quoted)-1]
+}
+
+// parseEmbedFormat builds the embed format from the -embed-* flags, expanding escapes
+func parseEmbedFormat(header, footer, separator string) (prompts.EmbedFormat, error) {
+	var format prompts.EmbedFormat
+	for _, field := range []struct {
+		value  string
+		target *string
+	}{{header, &format.Header}, {footer, &format.Footer}, {separator, &format.Separator}} {
+		unescaped, err := prompts.UnescapeEmbedTemplate(field.value)
+		if err != nil {
+			return prompts.EmbedFormat{}, err
+		}
+		*field.target = unescaped
+	}
+	return format, nil
+}
+
+// reloadOnSignal calls reload for each signal received on signals until ctx is done.
+// Reloads run one at a time; a signal arriving mid-reload triggers one more.
+func reloadOnSignal(ctx context.Context, signals <-chan os.Signal, reload func()) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			reload()
+		}
+	}
 }
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"os"
 	"os/exec"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -156,3 +158,118 @@ func TestSignalChannel(t *testing.T) {
 		t.Error("Did not receive signal from channel")
 	}
 }
+
+func TestReloadOnSignal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	reloads := make(chan struct{}, 2)
+
+	done := make(chan struct{})
+	go func() {
+		reloadOnSignal(ctx, signals, func() { reloads <- struct{}{} })
+		close(done)
+	}()
+
+	for i := 0; i < 2; i++ {
+		signals <- syscall.SIGHUP
+		select {
+		case <-reloads:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected reload %d to be triggered", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reloadOnSignal did not return after the context was cancelled")
+	}
+}
+
+func TestMainServerSIGHUPReloads(t *testing.T) {
+	if os.Getenv("TEST_SIGHUP") == "1" {
+		main()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestMainServerSIGHUPReloads")
+	cmd.Env = append(os.Environ(), "TEST_SIGHUP=1")
+
+	// Keep stdin open so the server keeps serving until it is signalled
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("Failed to create stdin pipe: %v", err)
+	}
+	defer stdin.Close()
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatalf("Failed to create stderr pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start main process: %v", err)
+	}
+
+	logLines := make(chan string, 100)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			logLines <- scanner.Text()
+		}
+		close(logLines)
+	}()
+	waitForLog := func(message string) bool {
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case line, ok := <-logLines:
+				if !ok {
+					return false
+				}
+				if strings.Contains(line, message) {
+					return true
+				}
+			case <-timeout:
+				return false
+			}
+		}
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	if !waitForLog(`"Server ready"`) {
+		cmd.Process.Kill()
+		t.Fatal("Server did not become ready")
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("Failed to send SIGHUP: %v", err)
+	}
+	if !waitForLog(`"Reload completed"`) {
+		cmd.Process.Kill()
+		t.Fatal("Expected SIGHUP to reload documentation and prompts")
+	}
+	go func() {
+		for range logLines {
+		}
+	}()
+
+	select {
+	case err := <-exited:
+		t.Fatalf("Expected the server to keep running after SIGHUP, exited: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGINT); err != nil {
+		t.Fatalf("Failed to send SIGINT: %v", err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Error("Process did not exit within timeout after SIGINT")
+	}
+}
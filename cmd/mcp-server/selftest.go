@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"mcp-architecture-service/internal/server"
+)
+
+// runSelfTest initializes the server without serving, writes the self-test report
+// to w and shuts the server down again. It returns the process exit code: 0 when
+// the self-test passed, 1 when it found fatal issues.
+func runSelfTest(ctx context.Context, mcpServer *server.MCPServer, w io.Writer) int {
+	report := mcpServer.SelfTest(ctx)
+	mcpServer.Shutdown(ctx)
+
+	writeSelfTestReport(w, report)
+	if !report.Passed() {
+		return 1
+	}
+	return 0
+}
+
+// writeSelfTestReport writes report as plain text for operators
+func writeSelfTestReport(w io.Writer, report *server.SelfTestReport) {
+	fmt.Fprintln(w, "MCP server self-test")
+
+	if doc := report.Documentation; doc != nil {
+		categories := make([]string, 0, len(doc.CategoryCounts))
+		for category, count := range doc.CategoryCounts {
+			categories = append(categories, fmt.Sprintf("%s=%d", category, count))
+		}
+		sort.Strings(categories)
+		fmt.Fprintf(w, "Documents: %d (%s)\n", doc.TotalDocuments, strings.Join(categories, ", "))
+	} else {
+		fmt.Fprintln(w, "Documents: not loaded")
+	}
+	fmt.Fprintf(w, "Prompts: %d (%s)\n", len(report.Prompts), strings.Join(report.Prompts, ", "))
+	fmt.Fprintf(w, "Tools: %d (%s)\n", len(report.Tools), strings.Join(report.Tools, ", "))
+
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "WARNING: %s\n", warning)
+	}
+	for _, failure := range report.Failures {
+		fmt.Fprintf(w, "FAILURE: %s\n", failure)
+	}
+
+	if report.Passed() {
+		fmt.Fprintf(w, "Self-test passed with %d warnings\n", len(report.Warnings))
+	} else {
+		fmt.Fprintf(w, "Self-test failed with %d failures and %d warnings\n", len(report.Failures), len(report.Warnings))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// selfTestEnv makes the test binary run main with the flags it holds instead of
+// running tests
+const selfTestEnv = "TEST_SELFTEST"
+
+// writeSelfTestTree writes files, keyed by path relative to a new temp directory
+func writeSelfTestTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	return dir
+}
+
+func TestSelfTest(t *testing.T) {
+	if os.Getenv(selfTestEnv) != "" {
+		os.Args = append([]string{os.Args[0]}, strings.Fields(os.Getenv(selfTestEnv))...)
+		main()
+		return
+	}
+
+	validPrompt := `{"name": "review", "description": "Review code", "messages": [{"role": "user", "content": {"type": "text", "text": "Review this"}}]}`
+	pattern := "# Layered Architecture\n\nSeparate concerns into layers.\n"
+
+	tests := []struct {
+		name         string
+		files        map[string]string
+		flags        string
+		wantExitCode int
+		wantOutput   []string
+	}{
+		{
+			name: "healthy tree passes",
+			files: map[string]string{
+				"mcp/resources/patterns/layered.md": pattern,
+				"mcp/resources/guidelines/empty.md": "",
+				"mcp/prompts/review.json":           validPrompt,
+			},
+			wantExitCode: 0,
+			wantOutput: []string{
+				"Documents: 1 (pattern=1)",
+				"Prompts: 1 (review)",
+				"search-architecture",
+				"WARNING: documentation: parse error for mcp/resources/guidelines/empty.md",
+				"Self-test passed with 1 warnings",
+			},
+		},
+		{
+			name: "invalid prompt fails",
+			files: map[string]string{
+				"mcp/resources/patterns/layered.md": pattern,
+				"mcp/prompts/broken.json":           `{"name": "broken"`,
+			},
+			wantExitCode: 1,
+			wantOutput:   []string{"FAILURE: invalid prompt mcp/prompts/broken.json", "Self-test failed"},
+		},
+		{
+			name: "strict mode fails on a malformed document",
+			files: map[string]string{
+				"mcp/resources/patterns/layered.md": pattern,
+				"mcp/resources/guidelines/empty.md": "",
+				"mcp/prompts/review.json":           validPrompt,
+			},
+			flags:        "-strict",
+			wantExitCode: 1,
+			wantOutput:   []string{"Documents: 1 (pattern=1)", "FAILURE: startup: strict mode", "Self-test failed"},
+		},
+		{
+			name:         "missing documentation fails",
+			files:        map[string]string{"mcp/prompts/review.json": validPrompt},
+			wantExitCode: 1,
+			wantOutput:   []string{"Documents: 0", "FAILURE: no documentation loaded", "Self-test failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestSelfTest$")
+			cmd.Dir = writeSelfTestTree(t, tt.files)
+			cmd.Env = append(os.Environ(), selfTestEnv+"=-selftest "+tt.flags)
+			var stdout bytes.Buffer
+			cmd.Stdout = &stdout
+
+			err := cmd.Run()
+			exitCode := 0
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				exitCode = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Failed to run self-test: %v", err)
+			}
+
+			output := stdout.String()
+			if exitCode != tt.wantExitCode {
+				t.Errorf("Exit code = %d, want %d\n%s", exitCode, tt.wantExitCode, output)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected report to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -12,6 +12,9 @@ type DocumentMetadata struct {
 	LastModified time.Time `json:"lastModified"`
 	Size         int64     `json:"size"`
 	Checksum     string    `json:"checksum"`
+	WordCount    int       `json:"wordCount"`
+	MimeType     string    `json:"mimeType"`
+	Status       string    `json:"status,omitempty"` // Declared status such as "draft" or "accepted"; empty when none
 }
 
 // DocumentContent represents the parsed content of a documentation file
@@ -40,6 +43,32 @@ type DocumentIndex struct {
 	Documents []DocumentMetadata `json:"documents"`
 	Count     int                `json:"count"`
 	Errors    []string           `json:"errors,omitempty"`
+	Skipped   []SkippedFile      `json:"skipped,omitempty"`
+}
+
+// SkippedFile records a file the scanner deliberately did not load
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// DuplicateDocumentID records documents that resolve to the same resource URI,
+// so only one of them is reachable
+type DuplicateDocumentID struct {
+	URI   string   `json:"uri"`
+	Paths []string `json:"paths"`
+}
+
+// DocumentationReport summarizes how cleanly documentation loaded at startup
+type DocumentationReport struct {
+	GeneratedAt    time.Time             `json:"generatedAt"`
+	DurationMs     int64                 `json:"durationMs"`
+	TotalDocuments int                   `json:"totalDocuments"`
+	CategoryCounts map[string]int        `json:"categoryCounts"`
+	SkippedFiles   []SkippedFile         `json:"skippedFiles"`
+	ParseWarnings  []string              `json:"parseWarnings"`
+	DuplicateIDs   []DuplicateDocumentID `json:"duplicateIds"`
+	EmptyDocuments []string              `json:"emptyDocuments"` // Paths of documents with no content, which search skips
 }
 
 // ADRDocument represents an Architecture Decision Record with specific fields
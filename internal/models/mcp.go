@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // MCPMessage represents a JSON-RPC 2.0 message for MCP protocol
 type MCPMessage struct {
 	JSONRPC string      `json:"jsonrpc"`
@@ -37,6 +39,12 @@ type MCPResourceCapabilities struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// MCPCancelledParams represents notifications/cancelled parameters
+type MCPCancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
 // MCPInitializeParams represents initialization parameters
 type MCPInitializeParams struct {
 	ProtocolVersion string                 `json:"protocolVersion"`
@@ -76,7 +84,8 @@ type MCPResourceContent struct {
 
 // MCPResourcesListParams represents parameters for resources/list
 type MCPResourcesListParams struct {
-	Cursor string `json:"cursor,omitempty"`
+	Cursor   string `json:"cursor,omitempty"`
+	Category string `json:"category,omitempty"` // Restricts the list to one category; empty lists all
 }
 
 // MCPResourcesListResult represents result for resources/list
@@ -93,6 +102,28 @@ type MCPResourcesReadParams struct {
 // MCPResourcesReadResult represents result for resources/read
 type MCPResourcesReadResult struct {
 	Contents []MCPResourceContent `json:"contents"`
+
+	// RelatedResources lists the URIs of the documents most similar to the one read
+	RelatedResources []string `json:"relatedResources,omitempty"`
+}
+
+// MCPResourcesHistoryParams represents parameters for resources/history
+type MCPResourcesHistoryParams struct {
+	URI string `json:"uri"`
+}
+
+// MCPResourceVersion represents a prior version of a resource
+type MCPResourceVersion struct {
+	Timestamp time.Time `json:"timestamp"`
+	Author    string    `json:"author,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+	Revision  string    `json:"revision,omitempty"`
+}
+
+// MCPResourcesHistoryResult represents result for resources/history
+type MCPResourcesHistoryResult struct {
+	URI      string               `json:"uri"`
+	Versions []MCPResourceVersion `json:"versions"`
 }
 
 // MCPCompletionCapabilities represents completion-related capabilities
@@ -14,9 +14,15 @@ type MCPPromptArgument struct {
 	Required    bool   `json:"required"`
 }
 
+// MCPPromptsListParams represents parameters for prompts/list
+type MCPPromptsListParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
 // MCPPromptsListResult represents the result of prompts/list
 type MCPPromptsListResult struct {
-	Prompts []MCPPrompt `json:"prompts"`
+	Prompts    []MCPPrompt `json:"prompts"`
+	NextCursor string      `json:"nextCursor,omitempty"`
 }
 
 // MCPPromptsGetParams represents parameters for prompts/get
@@ -43,6 +49,35 @@ type MCPPromptsGetResult struct {
 	Messages    []MCPPromptMessage `json:"messages"`
 }
 
+// MCPPromptsGetBatchParams represents parameters for prompts/get-batch: the
+// prompts/get requests to render together
+type MCPPromptsGetBatchParams struct {
+	Prompts []MCPPromptsGetParams `json:"prompts"`
+}
+
+// MCPPromptsGetBatchResult represents the result of prompts/get-batch, one item per
+// requested prompt in request order
+type MCPPromptsGetBatchResult struct {
+	Results []MCPPromptsGetBatchItem `json:"results"`
+}
+
+// MCPPromptsGetBatchItem is the outcome of one prompt in prompts/get-batch: the
+// rendered prompt, or the error prompts/get would have returned for it
+type MCPPromptsGetBatchItem struct {
+	Name   string               `json:"name"`
+	Result *MCPPromptsGetResult `json:"result,omitempty"`
+	Error  *MCPError            `json:"error,omitempty"`
+}
+
+// MCPPromptsPreviewResult represents the result of prompts/preview: a rendered
+// prompt flattened into one text, each message headed by its role
+type MCPPromptsPreviewResult struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	MessageCount int    `json:"messageCount"`
+	Text         string `json:"text"`
+}
+
 // MCPPromptCapabilities represents prompt-related capabilities
 type MCPPromptCapabilities struct {
 	ListChanged bool `json:"listChanged,omitempty"`
@@ -12,6 +12,22 @@ type MCPToolsListResult struct {
 	Tools []MCPTool `json:"tools"`
 }
 
+// MCPToolsSchemaParams represents parameters for tools/schema
+type MCPToolsSchemaParams struct {
+	Name string `json:"name,omitempty"` // empty returns every tool
+}
+
+// MCPToolSchema pairs a tool name with its input JSON Schema
+type MCPToolSchema struct {
+	Name        string                 `json:"name"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// MCPToolsSchemaResult represents the result of tools/schema
+type MCPToolsSchemaResult struct {
+	Schemas []MCPToolSchema `json:"schemas"`
+}
+
 // MCPToolsCallParams represents parameters for tools/call
 type MCPToolsCallParams struct {
 	Name      string                 `json:"name"`
@@ -20,7 +36,9 @@ type MCPToolsCallParams struct {
 
 // MCPToolsCallResult represents the result of tools/call
 type MCPToolsCallResult struct {
-	Content []MCPToolContent `json:"content"`
+	Content           []MCPToolContent       `json:"content"`
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
 }
 
 // MCPToolContent represents tool execution result content
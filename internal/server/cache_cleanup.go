@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+)
+
+// DefaultCacheCleanupInterval is how often the server runs cache cleanup
+const DefaultCacheCleanupInterval = 10 * time.Minute
+
+// SetCacheCleanupInterval sets how often the cache is cleaned up in the background.
+// Zero or less restores the default. Must be called before Start.
+func (s *MCPServer) SetCacheCleanupInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCacheCleanupInterval
+	}
+	s.cacheCleanupInterval = interval
+}
+
+// SetCacheTTL sets how long a document stays cached before cleanup expires it.
+// Zero or less keeps documents until they are invalidated by a file change.
+func (s *MCPServer) SetCacheTTL(ttl time.Duration) {
+	s.cache.SetTTL(ttl)
+}
+
+// SetCacheHitRatioThreshold sets the cache hit ratio percentage, measured over each
+// cache.DefaultHitRatioWindow, below which a warning is logged. Zero or less
+// disables the warning.
+func (s *MCPServer) SetCacheHitRatioThreshold(threshold float64) {
+	s.cache.SetHitRatioWarning(threshold, cache.DefaultHitRatioWindow)
+}
+
+// SetCacheContentInterning makes the cache keep one copy of the content of documents
+// with identical content, such as the same file reached under several paths. Must be
+// called before Start so every loaded document is interned.
+func (s *MCPServer) SetCacheContentInterning(enabled bool) {
+	s.cache.SetContentInterning(enabled)
+}
+
+// cacheCleanupScheduler runs cache cleanup on every interval tick until the
+// context is cancelled or the server shuts down
+func (s *MCPServer) cacheCleanupScheduler(ctx context.Context) {
+	interval := s.cacheCleanupInterval
+	if interval <= 0 {
+		interval = DefaultCacheCleanupInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runCacheCleanup("scheduled")
+		case <-ctx.Done():
+			return
+		case <-s.shutdownChan:
+			return
+		}
+	}
+}
+
+// runCacheCleanup expires TTL entries, refreshes cache statistics and returns
+// the number of documents expired
+func (s *MCPServer) runCacheCleanup(trigger string) int {
+	start := time.Now()
+	expired := s.cache.Cleanup()
+
+	s.logger.WithContext("trigger", trigger).
+		WithContext("expired_documents", expired).
+		WithContext("total_documents", s.cache.Size()).
+		WithContext("duration_ms", time.Since(start).Milliseconds()).
+		Debug("Cache cleanup completed")
+
+	return expired
+}
+
+// handleCacheCleanup handles the server/cache-cleanup method
+func (s *MCPServer) handleCacheCleanup(message *models.MCPMessage) *models.MCPMessage {
+	expired := s.runCacheCleanup("manual")
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result: map[string]interface{}{
+			"expired": expired,
+			"stats":   s.cache.GetStats(),
+		},
+	}
+}
+
+// handleCacheStats handles the server/cache-stats method, reporting cache totals
+// alongside the per-category breakdown of documents and memory
+func (s *MCPServer) handleCacheStats(message *models.MCPMessage) *models.MCPMessage {
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result: map[string]interface{}{
+			"stats": s.cache.GetStats(),
+		},
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 // handleFileEvent processes file system events and queues them for cache refresh
 func (s *MCPServer) handleFileEvent(event models.FileEvent) {
-	if !strings.HasSuffix(strings.ToLower(event.Path), config.MarkdownExtension) {
+	if !config.IsMarkdownFile(event.Path) {
 		return
 	}
 
@@ -130,7 +130,7 @@ func (s *MCPServer) handleCreateOrModifyEvent(event models.FileEvent) {
 		return
 	}
 
-	if err := s.loadDocumentIntoCache(*metadata); err != nil {
+	if err := s.loadDocumentIntoCache(context.Background(), *metadata); err != nil {
 		s.logger.WithError(err).
 			WithContext("file_path", event.Path).
 			WithContext("event_type", event.Type).
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/tools"
+)
+
+// serverMethods lists the JSON-RPC methods routeMessage handles, reported by
+// server/capabilities
+var serverMethods = []string{
+	"initialize",
+	"notifications/initialized",
+	"notifications/cancelled",
+	"resources/list",
+	"resources/read",
+	"resources/history",
+	"prompts/list",
+	"prompts/get",
+	"prompts/preview",
+	"prompts/get-batch",
+	"tools/list",
+	"tools/schema",
+	"tools/call",
+	"completion/complete",
+	"server/performance",
+	"server/diagnostics",
+	"server/cache-cleanup",
+	"server/cache-stats",
+	"server/capabilities",
+}
+
+// documentCategories describes the documentation categories the server loads
+var documentCategories = []struct {
+	category   string
+	uriSegment string
+	directory  string
+}{
+	{config.CategoryGuideline, config.URIGuidelines, config.GuidelinesPath},
+	{config.CategoryPattern, config.URIPatterns, config.PatternsPath},
+	{config.CategoryADR, config.URIADR, config.ADRPath},
+}
+
+// handleServerCapabilities handles the server/capabilities method, a consolidated
+// view of the protocol, methods, tools, prompts, categories and limits in effect
+func (s *MCPServer) handleServerCapabilities(message *models.MCPMessage) *models.MCPMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	toolNames := []string{}
+	if s.toolManager != nil {
+		for _, tool := range s.toolManager.ListTools() {
+			toolNames = append(toolNames, tool.Name)
+		}
+	}
+
+	promptNames := []string{}
+	for _, prompt := range s.promptManager.ListPrompts() {
+		promptNames = append(promptNames, prompt.Name)
+	}
+
+	categories := make([]map[string]interface{}, 0, len(documentCategories))
+	for _, c := range documentCategories {
+		searchBoost := tools.DefaultCategoryBoost
+		if boost, ok := s.categoryBoosts[c.category]; ok {
+			searchBoost = boost
+		}
+		categories = append(categories, map[string]interface{}{
+			"name":        c.category,
+			"uriPrefix":   config.URIScheme + c.uriSegment + "/",
+			"directory":   c.directory,
+			"extensions":  s.scanner.LoadedExtensions(c.category),
+			"searchBoost": searchBoost,
+		})
+	}
+
+	minQueryLength := tools.DefaultMinQueryLength
+	if s.minQueryLength > 0 {
+		minQueryLength = s.minQueryLength
+	}
+	sectionLevels := tools.DefaultSectionLevels
+	if s.sectionLevels != nil {
+		sectionLevels = s.sectionLevels
+	}
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result: map[string]interface{}{
+			"serverInfo":       s.serverInfo,
+			"protocolVersions": []string{protocolVersion},
+			"capabilities":     s.capabilities,
+			"methods":          serverMethods,
+			"tools":            toolNames,
+			"prompts":          promptNames,
+			"categories":       categories,
+			"limits": map[string]interface{}{
+				"maxFileSize":        s.scanner.MaxFileSize(),
+				"maxListedResources": s.maxListedResources,
+				"maxListedPrompts":   s.maxListedPrompts,
+				"maxBatchPrompts":    s.maxBatchPrompts,
+				"maxSearchResults":   tools.MaxSearchResults,
+				"minQueryLength":     minQueryLength,
+				"relatedResources":   s.relatedResources,
+				"sectionLevels":      sectionLevels,
+				"toolTimeoutMs":      tools.DefaultToolTimeout.Milliseconds(),
+				"readOnlyDocs":       s.readOnlyDocs,
+			},
+		},
+	}
+}
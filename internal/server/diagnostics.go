@@ -0,0 +1,93 @@
+package server
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// recordDocumentationReport summarizes the initial documentation load so operators
+// can tell whether it loaded cleanly without reading through startup logs
+func (s *MCPServer) recordDocumentationReport(indexes map[string]*models.DocumentIndex, scanErrors []string, duration time.Duration) {
+	report := &models.DocumentationReport{
+		GeneratedAt:    time.Now(),
+		DurationMs:     duration.Milliseconds(),
+		CategoryCounts: make(map[string]int),
+		SkippedFiles:   []models.SkippedFile{},
+		ParseWarnings:  append([]string{}, scanErrors...),
+		DuplicateIDs:   []models.DuplicateDocumentID{},
+		EmptyDocuments: []string{},
+	}
+
+	for _, index := range indexes {
+		report.SkippedFiles = append(report.SkippedFiles, index.Skipped...)
+		report.ParseWarnings = append(report.ParseWarnings, index.Errors...)
+	}
+	sort.Strings(report.ParseWarnings)
+	sort.Slice(report.SkippedFiles, func(i, j int) bool {
+		return report.SkippedFiles[i].Path < report.SkippedFiles[j].Path
+	})
+
+	// Documents that generate the same URI shadow each other in resources/read
+	pathsByURI := make(map[string][]string)
+	for path, doc := range s.cache.GetAllDocuments() {
+		report.CategoryCounts[doc.Metadata.Category]++
+		report.TotalDocuments++
+
+		if strings.TrimSpace(doc.Content.RawContent) == "" {
+			report.EmptyDocuments = append(report.EmptyDocuments, path)
+		}
+
+		uri := s.generateResourceURI(doc.Metadata.Category, doc.Metadata.Path)
+		pathsByURI[uri] = append(pathsByURI[uri], path)
+	}
+	for uri, paths := range pathsByURI {
+		if len(paths) > 1 {
+			sort.Strings(paths)
+			report.DuplicateIDs = append(report.DuplicateIDs, models.DuplicateDocumentID{URI: uri, Paths: paths})
+		}
+	}
+	sort.Slice(report.DuplicateIDs, func(i, j int) bool {
+		return report.DuplicateIDs[i].URI < report.DuplicateIDs[j].URI
+	})
+	sort.Strings(report.EmptyDocuments)
+
+	reportLogger := s.logger.WithContext("total_documents", report.TotalDocuments).
+		WithContext("category_counts", report.CategoryCounts).
+		WithContext("skipped_files", len(report.SkippedFiles)).
+		WithContext("parse_warnings", len(report.ParseWarnings)).
+		WithContext("duplicate_ids", len(report.DuplicateIDs)).
+		WithContext("empty_documents", len(report.EmptyDocuments))
+	if len(report.ParseWarnings) > 0 || len(report.DuplicateIDs) > 0 || len(report.EmptyDocuments) > 0 {
+		reportLogger.Warn("Documentation loaded with issues")
+	} else {
+		reportLogger.Info("Documentation integrity report")
+	}
+
+	s.mu.Lock()
+	s.documentationReport = report
+	s.mu.Unlock()
+}
+
+// DocumentationReport returns the integrity report from the last documentation
+// load, or nil if documentation has not been initialized
+func (s *MCPServer) DocumentationReport() *models.DocumentationReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.documentationReport
+}
+
+// handleServerDiagnostics handles the server/diagnostics method
+func (s *MCPServer) handleServerDiagnostics(message *models.MCPMessage) *models.MCPMessage {
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result: map[string]interface{}{
+			"documentation": s.DocumentationReport(),
+			"cache":         s.cache.GetStats(),
+		},
+	}
+}
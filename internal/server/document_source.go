@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/scanner"
+)
+
+// DocumentSource supplies the documentation the server serves. The default reads
+// the documentation directories from disk; embedders can supply documents from
+// memory or an object store instead.
+type DocumentSource interface {
+	// List returns the metadata of every document, indexed by category
+	List(ctx context.Context) (map[string]*models.DocumentIndex, error)
+	// Read returns the content of the document listed under path
+	Read(ctx context.Context, path string) ([]byte, error)
+}
+
+// filesystemDocumentSource is the default DocumentSource, scanning the
+// documentation directories on disk
+type filesystemDocumentSource struct {
+	scanner *scanner.DocumentationScanner
+}
+
+// List scans the documentation directories
+func (f *filesystemDocumentSource) List(ctx context.Context) (map[string]*models.DocumentIndex, error) {
+	return f.scanner.BuildIndexContext(ctx, documentationDirs())
+}
+
+// Read reads the document file at path
+func (f *filesystemDocumentSource) Read(ctx context.Context, path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// SetDocumentSource configures where documentation is loaded from. Passing nil
+// restores the filesystem default. Only the filesystem is watched for changes;
+// other sources are picked up again by Reload. Must be called before Start.
+func (s *MCPServer) SetDocumentSource(source DocumentSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if source == nil {
+		source = &filesystemDocumentSource{scanner: s.scanner}
+	}
+	s.documentSource = source
+}
+
+// DocumentScanner returns the scanner that filters and parses documentation with
+// the server's settings, for document sources such as NewFSDocumentSource
+func (s *MCPServer) DocumentScanner() *scanner.DocumentationScanner {
+	return s.scanner
+}
+
+// documentsOnDisk reports whether documentation comes from the filesystem, so its
+// directories can be watched
+func (s *MCPServer) documentsOnDisk() bool {
+	_, ok := s.documentSource.(*filesystemDocumentSource)
+	return ok
+}
+
+// MemoryDocumentSource is a DocumentSource holding documents in memory, for tests
+// and for embedders that supply documentation programmatically. Documents are
+// added under paths inside the documentation directories, such as
+// "mcp/resources/patterns/cqrs.md", which decide their category.
+type MemoryDocumentSource struct {
+	scanner   *scanner.DocumentationScanner
+	mu        sync.RWMutex
+	documents map[string]memoryDocument // path -> document
+}
+
+// memoryDocument is a document held by a MemoryDocumentSource
+type memoryDocument struct {
+	metadata models.DocumentMetadata
+	content  []byte
+}
+
+// NewMemoryDocumentSource creates an empty MemoryDocumentSource
+func NewMemoryDocumentSource() *MemoryDocumentSource {
+	return &MemoryDocumentSource{
+		scanner:   scanner.NewDocumentationScanner("."),
+		documents: make(map[string]memoryDocument),
+	}
+}
+
+// SetChecksumAlgorithm changes how the checksums of documents added afterwards are
+// computed, as MCPServer.SetChecksumAlgorithm does for documents on disk. Must not
+// be called concurrently with Add.
+func (m *MemoryDocumentSource) SetChecksumAlgorithm(algorithm scanner.ChecksumAlgorithm) {
+	m.scanner.SetChecksumAlgorithm(algorithm)
+}
+
+// Add stores a document, replacing any already stored under path. Its metadata is
+// extracted like a file's, so content must be text the scanner accepts.
+func (m *MemoryDocumentSource) Add(docPath, content string) error {
+	docPath = path.Clean(docPath)
+
+	category := ""
+	for _, c := range documentCategories {
+		if strings.HasPrefix(docPath, c.directory+"/") {
+			category = c.category
+			break
+		}
+	}
+	if category == "" {
+		return fmt.Errorf("document path %s is not inside a documentation directory", docPath)
+	}
+
+	metadata, err := m.scanner.ParseContent(docPath, []byte(content))
+	if err != nil {
+		return err
+	}
+	metadata.Category = category
+	metadata.LastModified = time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.documents[docPath] = memoryDocument{metadata: *metadata, content: []byte(content)}
+	return nil
+}
+
+// Remove deletes the document stored under path, if any
+func (m *MemoryDocumentSource) Remove(docPath string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.documents, path.Clean(docPath))
+}
+
+// List returns the stored documents indexed by category, sorted by path
+func (m *MemoryDocumentSource) List(ctx context.Context) (map[string]*models.DocumentIndex, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	indexes := make(map[string]*models.DocumentIndex)
+	for _, c := range documentCategories {
+		indexes[c.category] = &models.DocumentIndex{Category: c.category, Documents: []models.DocumentMetadata{}}
+	}
+	for _, doc := range m.documents {
+		index := indexes[doc.metadata.Category]
+		index.Documents = append(index.Documents, doc.metadata)
+		index.Count++
+	}
+	for _, index := range indexes {
+		sort.Slice(index.Documents, func(i, j int) bool {
+			return index.Documents[i].Path < index.Documents[j].Path
+		})
+	}
+	return indexes, nil
+}
+
+// Read returns the content stored under path
+func (m *MemoryDocumentSource) Read(ctx context.Context, docPath string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	doc, ok := m.documents[docPath]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: docPath, Err: fs.ErrNotExist}
+	}
+	return doc.content, nil
+}
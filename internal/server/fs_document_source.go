@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/scanner"
+)
+
+// FSDocumentSource is a DocumentSource reading documentation from an fs.FS, such
+// as an embed.FS compiled into the binary or an adapter over an object store or a
+// zip archive. Documents are found by walking the documentation directories,
+// "mcp/resources/guidelines" and so on, from the root of the file system; use
+// fs.Sub when they sit under another prefix.
+type FSDocumentSource struct {
+	fsys    fs.FS
+	scanner *scanner.DocumentationScanner
+}
+
+// NewFSDocumentSource creates an FSDocumentSource reading from fsys. Documents are
+// filtered and parsed by docScanner; pass the server's DocumentScanner so its
+// settings, such as the checksum algorithm, apply to them as to documents on disk.
+func NewFSDocumentSource(fsys fs.FS, docScanner *scanner.DocumentationScanner) *FSDocumentSource {
+	return &FSDocumentSource{
+		fsys:    fsys,
+		scanner: docScanner,
+	}
+}
+
+// List walks the documentation directories in the file system. A directory that
+// does not exist yields an empty category; files the scanner would skip on disk,
+// by extension or size, are recorded as skipped.
+func (f *FSDocumentSource) List(ctx context.Context) (map[string]*models.DocumentIndex, error) {
+	indexes := make(map[string]*models.DocumentIndex)
+	for _, c := range documentCategories {
+		index, err := f.listCategory(ctx, c.category, c.directory)
+		if err != nil {
+			return nil, err
+		}
+		indexes[c.category] = index
+	}
+	return indexes, nil
+}
+
+// listCategory walks one documentation directory
+func (f *FSDocumentSource) listCategory(ctx context.Context, category, root string) (*models.DocumentIndex, error) {
+	index := &models.DocumentIndex{Category: category, Documents: []models.DocumentMetadata{}}
+
+	err := fs.WalkDir(f.fsys, root, func(path string, entry fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if !f.scanner.IsLoadedFile(category, path) {
+			index.Skipped = append(index.Skipped, models.SkippedFile{Path: path, Reason: "unsupported file extension"})
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if maxSize := f.scanner.MaxFileSize(); maxSize > 0 && info.Size() > maxSize {
+			index.Skipped = append(index.Skipped, models.SkippedFile{
+				Path:   path,
+				Reason: fmt.Sprintf("file size %d bytes exceeds limit of %d bytes", info.Size(), maxSize),
+			})
+			return nil
+		}
+
+		content, err := fs.ReadFile(f.fsys, path)
+		if err != nil {
+			return err
+		}
+		metadata, err := f.scanner.ParseContent(path, content)
+		if err != nil {
+			index.Errors = append(index.Errors, fmt.Sprintf("parse error for %s: %v", path, err))
+			return nil
+		}
+		metadata.Category = category
+		metadata.LastModified = info.ModTime()
+
+		index.Documents = append(index.Documents, *metadata)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(index.Documents, func(i, j int) bool {
+		return index.Documents[i].Path < index.Documents[j].Path
+	})
+	index.Count = len(index.Documents)
+	return index, nil
+}
+
+// Read reads the document file at path from the file system
+func (f *FSDocumentSource) Read(ctx context.Context, path string) ([]byte, error) {
+	return fs.ReadFile(f.fsys, path)
+}
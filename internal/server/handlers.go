@@ -4,10 +4,13 @@ import (
 	"mcp-architecture-service/internal/models"
 )
 
+// protocolVersion is the MCP protocol version the server implements
+const protocolVersion = "2024-11-05"
+
 // handleInitialize handles the MCP initialize method
 func (s *MCPServer) handleInitialize(message *models.MCPMessage) *models.MCPMessage {
 	result := models.MCPInitializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: protocolVersion,
 		Capabilities:    s.capabilities,
 		ServerInfo:      s.serverInfo,
 	}
@@ -21,7 +24,9 @@ func (s *MCPServer) handleInitialize(message *models.MCPMessage) *models.MCPMess
 
 // handleInitialized handles the notifications/initialized method
 func (s *MCPServer) handleInitialized(message *models.MCPMessage) *models.MCPMessage {
+	s.mu.Lock()
 	s.initialized = true
+	s.mu.Unlock()
 	s.logger.WithContext("request_id", message.ID).Info("MCP server initialized successfully")
 	return nil // No response for notifications
 }
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"mcp-architecture-service/internal/models"
@@ -131,7 +132,7 @@ func (s *MCPServer) generatePatternCompletions(prefix string) ([]models.MCPCompl
 		}
 
 		// Extract pattern name from path (e.g., "repository-pattern.md" → "repository pattern")
-		patternName := strings.TrimSuffix(doc.Metadata.Path, config.MarkdownExtension)
+		patternName := config.TrimMarkdownExtension(doc.Metadata.Path)
 		patternName = strings.TrimPrefix(patternName, config.PatternsPath+"/")
 		patternName = strings.ReplaceAll(patternName, "-", " ")
 
@@ -139,7 +140,7 @@ func (s *MCPServer) generatePatternCompletions(prefix string) ([]models.MCPCompl
 		if prefix == "" || strings.HasPrefix(strings.ToLower(patternName), lowerPrefix) {
 			completions = append(completions, models.MCPCompletionItem{
 				Value:       patternName,
-				Label:       patternName,
+				Label:       completionLabel(patternName, doc),
 				Description: doc.Metadata.Title,
 			})
 		}
@@ -161,7 +162,7 @@ func (s *MCPServer) generateGuidelineCompletions(prefix string) ([]models.MCPCom
 		}
 
 		// Extract guideline name from path (e.g., "api-design.md" → "api design")
-		guidelineName := strings.TrimSuffix(doc.Metadata.Path, config.MarkdownExtension)
+		guidelineName := config.TrimMarkdownExtension(doc.Metadata.Path)
 		guidelineName = strings.TrimPrefix(guidelineName, config.GuidelinesPath+"/")
 		guidelineName = strings.ReplaceAll(guidelineName, "-", " ")
 
@@ -169,7 +170,7 @@ func (s *MCPServer) generateGuidelineCompletions(prefix string) ([]models.MCPCom
 		if prefix == "" || strings.HasPrefix(strings.ToLower(guidelineName), lowerPrefix) {
 			completions = append(completions, models.MCPCompletionItem{
 				Value:       guidelineName,
-				Label:       guidelineName,
+				Label:       completionLabel(guidelineName, doc),
 				Description: doc.Metadata.Title,
 			})
 		}
@@ -191,7 +192,7 @@ func (s *MCPServer) generateADRCompletions(prefix string) ([]models.MCPCompletio
 		}
 
 		// Extract ADR ID from path using the same logic as generateResourceURI
-		cleanPath := strings.TrimSuffix(doc.Metadata.Path, config.MarkdownExtension)
+		cleanPath := config.TrimMarkdownExtension(doc.Metadata.Path)
 		cleanPath = strings.TrimPrefix(cleanPath, config.ADRPath+"/")
 		adrId := s.extractADRId(cleanPath)
 
@@ -199,7 +200,7 @@ func (s *MCPServer) generateADRCompletions(prefix string) ([]models.MCPCompletio
 		if prefix == "" || strings.HasPrefix(strings.ToLower(adrId), lowerPrefix) {
 			completions = append(completions, models.MCPCompletionItem{
 				Value:       adrId,
-				Label:       adrId,
+				Label:       completionLabel(adrId, doc),
 				Description: doc.Metadata.Title,
 			})
 		}
@@ -232,3 +233,12 @@ func (s *MCPServer) handleCompletionError(id interface{}, promptName, argumentNa
 
 	return s.createStructuredErrorResponse(id, structuredErr)
 }
+
+// completionLabel appends a document's declared status to a completion label so
+// drafts can be told apart from approved documents
+func completionLabel(name string, doc *models.Document) string {
+	if status := documentStatus(doc); status != "unknown" {
+		return fmt.Sprintf("%s (%s)", name, status)
+	}
+	return name
+}
@@ -116,6 +116,45 @@ func TestHandleCompletionComplete_ValidPatternName(t *testing.T) {
 	}
 }
 
+// TestHandleCompletionComplete_StatusInLabel tests that declared statuses appear in completion labels
+func TestHandleCompletionComplete_StatusInLabel(t *testing.T) {
+	server := NewMCPServer()
+	setupTestPrompt(t, server, "test-prompt", "guideline_name")
+
+	for path, status := range map[string]string{
+		"mcp/resources/guidelines/api-design.md": "draft",
+		"mcp/resources/guidelines/caching.md":    "",
+	} {
+		server.cache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Path: path, Category: "guideline", Status: status},
+		})
+	}
+
+	response := server.handleCompletionComplete(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "test-status",
+		Method:  "completion/complete",
+		Params: models.MCPCompletionCompleteParams{
+			Ref:      models.MCPCompletionRef{Type: "ref/prompt", Name: "test-prompt"},
+			Argument: models.MCPCompletionArgument{Name: "guideline_name", Value: ""},
+		},
+	})
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+
+	labels := make(map[string]string)
+	for _, item := range response.Result.(models.MCPCompletionResult).Completion.Values {
+		labels[item.Value] = item.Label
+	}
+	if labels["api design"] != "api design (draft)" {
+		t.Errorf("Expected draft status in label, got %q", labels["api design"])
+	}
+	if labels["caching"] != "caching" {
+		t.Errorf("Expected plain label without a status, got %q", labels["caching"])
+	}
+}
+
 // TestHandleCompletionComplete_InvalidRefType tests error handling for invalid ref type
 func TestHandleCompletionComplete_InvalidRefType(t *testing.T) {
 	server := NewMCPServer()
@@ -52,12 +52,19 @@ func (s *MCPServer) createErrorResponse(id interface{}, code int, message string
 	}
 }
 
+// SetErrorVerbosity sets how much internal detail structured error responses carry.
+// Terse, the default, keeps causes out of responses; verbose is meant for
+// development. Must be called before Start.
+func (s *MCPServer) SetErrorVerbosity(verbosity errors.ErrorVerbosity) {
+	s.errorVerbosity = verbosity
+}
+
 // createStructuredErrorResponse creates an MCP error response from a structured error
 func (s *MCPServer) createStructuredErrorResponse(id interface{}, structuredErr *errors.StructuredError) *models.MCPMessage {
 	return &models.MCPMessage{
 		JSONRPC: "2.0",
 		ID:      id,
-		Error:   structuredErr.ToMCPError(),
+		Error:   structuredErr.ToMCPErrorWithVerbosity(s.errorVerbosity),
 	}
 }
 
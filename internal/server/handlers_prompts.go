@@ -2,6 +2,8 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
 	"mcp-architecture-service/internal/models"
@@ -13,11 +15,38 @@ func (s *MCPServer) handlePromptsList(message *models.MCPMessage) *models.MCPMes
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Get all available prompts from the prompt manager
+	var params models.MCPPromptsListParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters")
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters format")
+		}
+	}
+
+	// Get all available prompts from the prompt manager. Cursors are offsets, so
+	// the order must not change between pages.
 	prompts := s.promptManager.ListPrompts()
+	sortPrompts(prompts)
+
+	start, end, nextCursor, err := pageBounds(len(prompts), params.Cursor, s.maxListedPrompts)
+	if err != nil {
+		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+			"Invalid cursor", err).WithContext("cursor", params.Cursor)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+	if nextCursor != "" {
+		s.logger.WithContext("listed", end-start).
+			WithContext("total", len(prompts)).
+			WithContext("max_listed_prompts", s.maxListedPrompts).
+			Info("prompts/list truncated; remaining prompts are on later pages")
+	}
 
 	result := models.MCPPromptsListResult{
-		Prompts: prompts,
+		Prompts:    prompts[start:end],
+		NextCursor: nextCursor,
 	}
 
 	return &models.MCPMessage{
@@ -27,6 +56,22 @@ func (s *MCPServer) handlePromptsList(message *models.MCPMessage) *models.MCPMes
 	}
 }
 
+// sortPrompts orders prompts alphabetically by name, ignoring case. Names that
+// differ only in case, such as prompts loaded from different directories, fall back
+// to byte order and then to their descriptions, so the order is always the same.
+func sortPrompts(prompts []models.MCPPrompt) {
+	sort.SliceStable(prompts, func(i, j int) bool {
+		a, b := prompts[i], prompts[j]
+		if folded, otherFolded := strings.ToLower(a.Name), strings.ToLower(b.Name); folded != otherFolded {
+			return folded < otherFolded
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Description < b.Description
+	})
+}
+
 // handlePromptsGet handles the prompts/get method
 func (s *MCPServer) handlePromptsGet(message *models.MCPMessage) *models.MCPMessage {
 	s.mu.RLock()
@@ -44,15 +89,111 @@ func (s *MCPServer) handlePromptsGet(message *models.MCPMessage) *models.MCPMess
 		}
 	}
 
+	return s.getPrompt(message.ID, params)
+}
+
+// getPrompt validates and renders one prompts/get request
+func (s *MCPServer) getPrompt(id interface{}, params models.MCPPromptsGetParams) *models.MCPMessage {
 	// Validate prompt name parameter
 	if params.Name == "" {
 		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
 			"Missing required parameter: name", nil)
-		return s.createStructuredErrorResponse(message.ID, structuredErr)
+		return s.createStructuredErrorResponse(id, structuredErr)
+	}
+
+	if structuredErr := s.checkPromptArguments(params.Name, params.Arguments); structuredErr != nil {
+		return s.createStructuredErrorResponse(id, structuredErr)
 	}
 
 	// Render the prompt with provided arguments
 	result, err := s.promptManager.RenderPrompt(params.Name, params.Arguments)
+	if err != nil {
+		return s.handlePromptRenderError(id, params.Name, err)
+	}
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	}
+}
+
+// handlePromptsGetBatch handles the prompts/get-batch method, which renders several
+// prompts in one request. Each prompt succeeds or fails on its own, so one bad
+// request does not fail the others.
+func (s *MCPServer) handlePromptsGetBatch(message *models.MCPMessage) *models.MCPMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var params models.MCPPromptsGetBatchParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters")
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters format")
+		}
+	}
+
+	if len(params.Prompts) == 0 {
+		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+			"Missing required parameter: prompts", nil)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+	if s.maxBatchPrompts > 0 && len(params.Prompts) > s.maxBatchPrompts {
+		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+			fmt.Sprintf("Too many prompts: %d exceeds the maximum of %d", len(params.Prompts), s.maxBatchPrompts), nil)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+
+	results := make([]models.MCPPromptsGetBatchItem, 0, len(params.Prompts))
+	for _, request := range params.Prompts {
+		item := models.MCPPromptsGetBatchItem{Name: request.Name}
+		response := s.getPrompt(nil, request)
+		if response.Error != nil {
+			item.Error = response.Error
+		} else {
+			item.Result = response.Result.(*models.MCPPromptsGetResult)
+		}
+		results = append(results, item)
+	}
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result:  models.MCPPromptsGetBatchResult{Results: results},
+	}
+}
+
+// handlePromptsPreview handles the prompts/preview method, which takes the same
+// parameters as prompts/get and returns the rendered prompt as a single text
+func (s *MCPServer) handlePromptsPreview(message *models.MCPMessage) *models.MCPMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var params models.MCPPromptsGetParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters")
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters format")
+		}
+	}
+
+	if params.Name == "" {
+		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+			"Missing required parameter: name", nil)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+
+	if structuredErr := s.checkPromptArguments(params.Name, params.Arguments); structuredErr != nil {
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+
+	result, err := s.promptManager.PreviewPrompt(params.Name, params.Arguments)
 	if err != nil {
 		return s.handlePromptRenderError(message.ID, params.Name, err)
 	}
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"sort"
 
 	"mcp-architecture-service/internal/models"
-	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/errors"
 )
 
@@ -13,18 +15,59 @@ func (s *MCPServer) handleResourcesList(message *models.MCPMessage) *models.MCPM
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var resources []models.MCPResource
+	var params models.MCPResourcesListParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters")
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters format")
+		}
+	}
 
-	// Get all cached documents and convert them to MCP resources
-	allDocuments := s.cache.GetAllDocuments()
+	resources := []models.MCPResource{}
+
+	// Convert cached documents to MCP resources, grouped by category and ordered by path
+	var categories []string
+	if params.Category != "" {
+		category, ok := resourceCategory(params.Category)
+		if !ok {
+			structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+				"Unknown resource category", nil).
+				WithContext("category", params.Category).
+				WithContext("valid_categories", resourceCategoryNames())
+			return s.createStructuredErrorResponse(message.ID, structuredErr)
+		}
+		categories = []string{category}
+	} else {
+		categories = s.cache.GetCategories()
+		sort.Strings(categories)
+	}
+
+	for _, category := range categories {
+		for _, doc := range s.cache.GetByCategorySorted(category, cache.SortByPath, 0) {
+			resource := s.createMCPResourceFromDocument(doc)
+			resources = append(resources, resource)
+		}
+	}
 
-	for _, doc := range allDocuments {
-		resource := s.createMCPResourceFromDocument(doc)
-		resources = append(resources, resource)
+	start, end, nextCursor, err := pageBounds(len(resources), params.Cursor, s.maxListedResources)
+	if err != nil {
+		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+			"Invalid cursor", err).WithContext("cursor", params.Cursor)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+	if nextCursor != "" {
+		s.logger.WithContext("listed", end-start).
+			WithContext("total", len(resources)).
+			WithContext("max_listed_resources", s.maxListedResources).
+			Info("resources/list truncated; remaining resources are on later pages")
 	}
 
 	result := models.MCPResourcesListResult{
-		Resources: resources,
+		Resources:  resources[start:end],
+		NextCursor: nextCursor,
 	}
 
 	return &models.MCPMessage{
@@ -79,6 +122,9 @@ func (s *MCPServer) handleResourcesRead(message *models.MCPMessage) *models.MCPM
 	err = circuitBreaker.Execute(func() error {
 		var findErr error
 		document, findErr = s.findDocumentByResourcePath(category, path)
+		if findErr != nil && s.readThrough {
+			document, findErr = s.readThroughDocument(context.Background(), category, path)
+		}
 		return findErr
 	})
 
@@ -98,12 +144,13 @@ func (s *MCPServer) handleResourcesRead(message *models.MCPMessage) *models.MCPM
 	// Create resource content response
 	content := models.MCPResourceContent{
 		URI:      params.URI,
-		MimeType: config.MimeTypeMarkdown,
+		MimeType: documentMimeType(document),
 		Text:     document.Content.RawContent,
 	}
 
 	result := models.MCPResourcesReadResult{
-		Contents: []models.MCPResourceContent{content},
+		Contents:         []models.MCPResourceContent{content},
+		RelatedResources: s.relatedResourceURIs(document),
 	}
 
 	return &models.MCPMessage{
@@ -112,3 +159,87 @@ func (s *MCPServer) handleResourcesRead(message *models.MCPMessage) *models.MCPM
 		Result:  result,
 	}
 }
+
+// handleResourcesHistory handles the resources/history method
+func (s *MCPServer) handleResourcesHistory(message *models.MCPMessage) *models.MCPMessage {
+	var params models.MCPResourcesHistoryParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters")
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters format")
+		}
+	}
+
+	if params.URI == "" {
+		structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+			"Missing required parameter: uri", nil)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+
+	// Resolve the document under the lock, but call the provider outside it since
+	// history lookups can be slow
+	s.mu.RLock()
+	provider := s.historyProvider
+	document, err := s.findDocumentByURI(params.URI)
+	s.mu.RUnlock()
+	if err != nil {
+		if structuredErr, ok := err.(*errors.StructuredError); ok {
+			return s.createStructuredErrorResponse(message.ID, structuredErr)
+		}
+		structuredErr := errors.NewMCPError(errors.ErrCodeResourceNotFound,
+			"Resource not found", err).WithContext("uri", params.URI)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyLookupTimeout)
+	defer cancel()
+
+	versions, err := provider.GetDocumentHistory(ctx, document)
+	if err != nil {
+		s.logger.WithError(err).
+			WithContext("uri", params.URI).
+			Error("History provider failed")
+		structuredErr := errors.NewSystemError(errors.ErrCodeHistoryUnavailable,
+			"Document history unavailable", err).WithContext("uri", params.URI)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+	if versions == nil {
+		versions = []models.MCPResourceVersion{}
+	}
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result: models.MCPResourcesHistoryResult{
+			URI:      params.URI,
+			Versions: versions,
+		},
+	}
+}
+
+// relatedResourceURIs returns the URIs of the documents most similar to document,
+// scored like the find-similar tool and cached until the documentation changes
+func (s *MCPServer) relatedResourceURIs(document *models.Document) []string {
+	if s.relatedResources <= 0 {
+		return nil
+	}
+
+	related := s.relatedFinder.RelatedDocuments(document.Metadata.Path, s.relatedResources)
+	uris := make([]string, 0, len(related))
+	for _, doc := range related {
+		uris = append(uris, s.generateResourceURI(doc.Metadata.Category, doc.Metadata.Path))
+	}
+	return uris
+}
+
+// findDocumentByURI resolves an architecture:// URI to a cached document
+func (s *MCPServer) findDocumentByURI(uri string) (*models.Document, error) {
+	category, path, err := s.parseResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return s.findDocumentByResourcePath(category, path)
+}
@@ -7,6 +7,7 @@ import (
 
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/errors"
+	"mcp-architecture-service/pkg/tools"
 )
 
 // handleToolsList handles the tools/list method
@@ -45,8 +46,53 @@ func (s *MCPServer) handleToolsList(message *models.MCPMessage) *models.MCPMessa
 	}
 }
 
+// handleToolsSchema handles the tools/schema method, returning the input JSON Schema
+// of one tool by name or of every registered tool
+func (s *MCPServer) handleToolsSchema(message *models.MCPMessage) *models.MCPMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.toolManager == nil {
+		structuredErr := errors.NewSystemError("TOOLS_NOT_INITIALIZED",
+			"Tools system not initialized", nil)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
+	}
+
+	var params models.MCPToolsSchemaParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters")
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return s.createErrorResponse(message.ID, -32602, "Invalid parameters format")
+		}
+	}
+
+	var schemas []models.MCPToolSchema
+	if params.Name != "" {
+		tool, err := s.toolManager.GetTool(params.Name)
+		if err != nil {
+			structuredErr := errors.NewValidationError(errors.ErrCodeInvalidParams,
+				"Tool not found", err).WithContext("tool_name", params.Name)
+			return s.createStructuredErrorResponse(message.ID, structuredErr)
+		}
+		schemas = append(schemas, models.MCPToolSchema{Name: tool.Name(), InputSchema: tool.InputSchema()})
+	} else {
+		for _, toolDef := range s.toolManager.ListTools() {
+			schemas = append(schemas, models.MCPToolSchema{Name: toolDef.Name, InputSchema: toolDef.InputSchema})
+		}
+	}
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      message.ID,
+		Result:  models.MCPToolsSchemaResult{Schemas: schemas},
+	}
+}
+
 // handleToolsCall handles the tools/call method
-func (s *MCPServer) handleToolsCall(message *models.MCPMessage) *models.MCPMessage {
+func (s *MCPServer) handleToolsCall(ctx context.Context, message *models.MCPMessage) *models.MCPMessage {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -85,9 +131,9 @@ func (s *MCPServer) handleToolsCall(message *models.MCPMessage) *models.MCPMessa
 
 	err := circuitBreaker.Execute(func() error {
 		var ctxErr error
-		// Create a context for tool execution
-		ctx := context.Background()
-		result, ctxErr = s.toolManager.ExecuteTool(ctx, params.Name, params.Arguments)
+		// Tool execution is audited under this connection's session
+		toolCtx := tools.ContextWithSessionID(ctx, s.sessionID)
+		result, ctxErr = s.toolManager.ExecuteTool(toolCtx, params.Name, params.Arguments)
 		return ctxErr
 	})
 
@@ -96,29 +142,17 @@ func (s *MCPServer) handleToolsCall(message *models.MCPMessage) *models.MCPMessa
 	}
 
 	// Convert result to MCP tool content format
-	// Result is expected to be a string or convertible to JSON
-	var contentText string
-	if strResult, ok := result.(string); ok {
-		contentText = strResult
-	} else {
-		// Convert to JSON for structured results
-		jsonBytes, err := json.Marshal(result)
-		if err != nil {
-			structuredErr := errors.NewSystemError("TOOL_RESULT_SERIALIZATION_FAILED",
-				"Failed to serialize tool result", err).
-				WithContext("tool_name", params.Name)
-			return s.createStructuredErrorResponse(message.ID, structuredErr)
-		}
-		contentText = string(jsonBytes)
+	tool, err := s.toolManager.GetTool(params.Name)
+	if err != nil {
+		return s.handleToolExecutionError(message.ID, params.Name, err)
 	}
 
-	toolResult := models.MCPToolsCallResult{
-		Content: []models.MCPToolContent{
-			{
-				Type: "text",
-				Text: contentText,
-			},
-		},
+	toolResult, err := tools.FormatResult(tool, result)
+	if err != nil {
+		structuredErr := errors.NewSystemError("TOOL_RESULT_SERIALIZATION_FAILED",
+			"Failed to serialize tool result", err).
+			WithContext("tool_name", params.Name)
+		return s.createStructuredErrorResponse(message.ID, structuredErr)
 	}
 
 	return &models.MCPMessage{
@@ -128,11 +162,19 @@ func (s *MCPServer) handleToolsCall(message *models.MCPMessage) *models.MCPMessa
 	}
 }
 
-// handleToolExecutionError creates appropriate error response based on tool execution error
+// handleToolExecutionError maps a tool execution error to a response. Unknown tools,
+// invalid arguments and an open circuit breaker are protocol errors reported as
+// JSON-RPC errors; tools report arguments they reject themselves as validation
+// errors too. Other failures raised while the tool ran are returned as a tools/call
+// result with isError set so the client can show the message to the model
 func (s *MCPServer) handleToolExecutionError(id interface{}, toolName string, err error) *models.MCPMessage {
 	// Check if it's already a structured error
 	if structuredErr, ok := err.(*errors.StructuredError); ok {
-		return s.createStructuredErrorResponse(id, structuredErr)
+		if structuredErr.Category == errors.ErrorCategoryValidation ||
+			structuredErr.Code == "CIRCUIT_BREAKER_OPEN" {
+			return s.createStructuredErrorResponse(id, structuredErr)
+		}
+		return s.createToolErrorResult(id, toolName, structuredErr.Message)
 	}
 
 	// Check for specific error types
@@ -152,13 +194,26 @@ func (s *MCPServer) handleToolExecutionError(id interface{}, toolName string, er
 
 	if strings.Contains(err.Error(), "timeout") ||
 		strings.Contains(err.Error(), "deadline exceeded") {
-		structuredErr := errors.NewSystemError("TOOL_EXECUTION_TIMEOUT",
-			"Tool execution timeout", err).WithContext("tool_name", toolName)
-		return s.createStructuredErrorResponse(id, structuredErr)
+		return s.createToolErrorResult(id, toolName, "Tool execution timeout: "+err.Error())
 	}
 
 	// Generic tool execution error
-	structuredErr := errors.NewSystemError("TOOL_EXECUTION_FAILED",
-		"Tool execution failed", err).WithContext("tool_name", toolName)
-	return s.createStructuredErrorResponse(id, structuredErr)
+	return s.createToolErrorResult(id, toolName, "Tool execution failed: "+err.Error())
+}
+
+// createToolErrorResult builds a tools/call result flagged with isError whose content
+// carries the failure message
+func (s *MCPServer) createToolErrorResult(id interface{}, toolName, message string) *models.MCPMessage {
+	s.logger.WithContext("tool_name", toolName).
+		WithContext("error", message).
+		Warn("Tool execution returned an error result")
+
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: models.MCPToolsCallResult{
+			Content: []models.MCPToolContent{{Type: "text", Text: message}},
+			IsError: true,
+		},
+	}
 }
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// historyLookupTimeout bounds a provider call, since providers typically shell out
+// to version control or call a remote API
+const historyLookupTimeout = 10 * time.Second
+
+// HistoryProvider supplies prior versions of a document for resources/history.
+// Implementations backed by version control receive the document's file path.
+type HistoryProvider interface {
+	GetDocumentHistory(ctx context.Context, doc *models.Document) ([]models.MCPResourceVersion, error)
+}
+
+// NoopHistoryProvider is the default provider; it reports no prior versions
+type NoopHistoryProvider struct{}
+
+// GetDocumentHistory returns an empty history
+func (NoopHistoryProvider) GetDocumentHistory(ctx context.Context, doc *models.Document) ([]models.MCPResourceVersion, error) {
+	return []models.MCPResourceVersion{}, nil
+}
+
+// SetHistoryProvider configures where resources/history gets prior versions from.
+// Passing nil restores the no-op default.
+func (s *MCPServer) SetHistoryProvider(provider HistoryProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if provider == nil {
+		provider = NoopHistoryProvider{}
+	}
+	s.historyProvider = provider
+}
@@ -2,28 +2,240 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"mcp-architecture-service/internal/models"
-	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
 	"mcp-architecture-service/pkg/prompts"
+	"mcp-architecture-service/pkg/scanner"
+	"mcp-architecture-service/pkg/tokens"
 	"mcp-architecture-service/pkg/tools"
 )
 
+// DefaultLoadWorkers is the default number of documents read into the cache
+// concurrently during the initial load. Hosts with fewer CPUs use one worker per CPU.
+const DefaultLoadWorkers = 4
+
+// SetLoadWorkers sets how many files are read and parsed concurrently during the
+// initial documentation load. Zero or less restores the defaults. Must be called
+// before Start.
+func (s *MCPServer) SetLoadWorkers(workers int) {
+	if workers <= 0 {
+		s.loadWorkers = 0
+		s.scanner.SetWorkerCount(0)
+		return
+	}
+
+	s.loadWorkers = workers
+	s.scanner.SetWorkerCount(workers)
+}
+
+// defaultLoadWorkers is DefaultLoadWorkers capped at the number of CPUs
+func defaultLoadWorkers() int {
+	return min(runtime.NumCPU(), DefaultLoadWorkers)
+}
+
+// DefaultRelatedResources is how many related resource URIs resources/read returns
+const DefaultRelatedResources = 3
+
+// SetRelatedResources sets how many URIs of related documents, ranked like the
+// find-similar tool, resources/read returns alongside a document. Zero or less
+// disables them. Defaults to DefaultRelatedResources.
+func (s *MCPServer) SetRelatedResources(count int) {
+	if count < 0 {
+		count = 0
+	}
+	s.relatedResources = count
+}
+
+// SetFollowSymlinks controls whether the documentation loader follows symbolic
+// links to files and directories. Off by default. Must be called before Start.
+func (s *MCPServer) SetFollowSymlinks(follow bool) {
+	s.scanner.SetFollowSymlinks(follow)
+}
+
+// SetChecksumAlgorithm changes how the checksums of documents loaded from disk are
+// computed; scanner.ChecksumNone skips computing them, and reloads then detect
+// changes by size and modification time. Must be called before Start.
+func (s *MCPServer) SetChecksumAlgorithm(algorithm scanner.ChecksumAlgorithm) {
+	s.scanner.SetChecksumAlgorithm(algorithm)
+}
+
+// SetCategoryExtensions restricts the file extensions loaded for a documentation
+// category, such as config.CategoryGuideline. An empty list restores the default
+// extensions. Must be called before Start.
+func (s *MCPServer) SetCategoryExtensions(category string, extensions []string) {
+	s.scanner.SetCategoryExtensions(category, extensions)
+}
+
+// SetStopWordLanguage selects the built-in stop-word set, by language code such as
+// "de", that search-architecture and check-adr-alignment leave out of queries. An
+// empty language keeps the tools' defaults: search-architecture drops no words and
+// check-adr-alignment drops English ones. Must be called before Start.
+func (s *MCPServer) SetStopWordLanguage(language string) error {
+	if language == "" {
+		s.stopWords = nil
+		return nil
+	}
+	stopWords, err := tools.StopWordsFor(language)
+	if err != nil {
+		return err
+	}
+	s.stopWords = stopWords
+	return nil
+}
+
+// SetMinQueryLength sets the shortest query, in characters after trimming surrounding
+// whitespace, that search-architecture accepts. Shorter and blank queries are rejected
+// as invalid params. Defaults to tools.DefaultMinQueryLength. Must be called before Start.
+func (s *MCPServer) SetMinQueryLength(chars int) error {
+	if chars < 1 {
+		return fmt.Errorf("minimum query length must be at least 1 character, got %d", chars)
+	}
+	s.minQueryLength = chars
+	return nil
+}
+
+// SetSectionLevels sets the heading levels of the named sections validate-against-pattern
+// and check-adr-alignment read, such as a pattern's "Best Practices" or an ADR's
+// "Decision", for documentation that titles them with # or ### rather than ##.
+// Defaults to tools.DefaultSectionLevels. Must be called before Start.
+func (s *MCPServer) SetSectionLevels(levels []int) error {
+	if err := tools.ValidateSectionLevels(levels); err != nil {
+		return err
+	}
+	s.sectionLevels = levels
+	return nil
+}
+
+// SetCategoryBoosts sets search-architecture's per-category score multipliers, such
+// as {"adr": 1.5} to rank ADRs above equally relevant guidelines. Keys are categories
+// or URI segments and multipliers must be positive; categories left out keep
+// tools.DefaultCategoryBoost. Must be called before Start.
+func (s *MCPServer) SetCategoryBoosts(boosts map[string]float64) error {
+	normalized, err := tools.NormalizeCategoryBoosts(boosts)
+	if err != nil {
+		return err
+	}
+	s.categoryBoosts = normalized
+	return nil
+}
+
+// SetCollapseCodeExcerpts makes search-architecture collapse each fenced code block
+// in its excerpts to "[code omitted]", for audiences that should not be shown code.
+// Terms inside code blocks still count toward relevance. Off by default. Must be
+// called before Start.
+func (s *MCPServer) SetCollapseCodeExcerpts(enabled bool) {
+	s.collapseCodeExcerpts = enabled
+}
+
+// SetEmbedFormat sets how prompts wrap each document embedded through
+// {{resource:...}}: the header and footer around its content and the separator
+// between documents. Defaults to prompts.DefaultEmbedFormat.
+func (s *MCPServer) SetEmbedFormat(format prompts.EmbedFormat) error {
+	return s.promptManager.SetEmbedFormat(format)
+}
+
+// SetEmbedBudget sets the token budget of the documents each prompt message embeds
+// through {{resource:...}}, and whether exceeding it fails prompts/get or truncates
+// the largest documents to fit. Defaults to prompts.DefaultEmbedBudget, which sets
+// no budget.
+func (s *MCPServer) SetEmbedBudget(budget prompts.EmbedBudget) error {
+	return s.promptManager.SetEmbedBudget(budget)
+}
+
+// SetTokenEstimator sets how the estimatedTokens annotation of resources and the
+// {{tokens}} placeholder of embedded documents are estimated, such as with a real
+// tokenizer wrapped in tokens.EstimatorFunc. Passing nil restores
+// tokens.DefaultEstimator. Must be called before Start.
+func (s *MCPServer) SetTokenEstimator(estimator tokens.Estimator) {
+	if estimator == nil {
+		estimator = tokens.DefaultEstimator
+	}
+	s.tokenEstimator = estimator
+	s.promptManager.SetTokenEstimator(estimator)
+}
+
+// SetPromptDelimiters sets the delimiters of prompt template placeholders, for
+// prompts that do not set their own. Defaults to prompts.DefaultDelimiters.
+func (s *MCPServer) SetPromptDelimiters(delimiters prompts.Delimiters) error {
+	return s.promptManager.SetDelimiters(delimiters)
+}
+
+// SetPromptArgumentNormalization sets how prompt argument values are cleaned up
+// before they are validated and substituted. Arguments a prompt flags preserve are
+// left alone. Defaults to prompts.DefaultArgumentNormalization.
+func (s *MCPServer) SetPromptArgumentNormalization(normalization prompts.ArgumentNormalization) {
+	s.promptManager.SetArgumentNormalization(normalization)
+}
+
+// SetPromptDirectories sets the directories prompts are loaded from, from lowest to
+// highest precedence: a prompt in a later directory, such as a project-local one,
+// overrides a prompt with the same name in an earlier, shared one. Defaults to
+// config.PromptsBasePath. Must be called before Start.
+func (s *MCPServer) SetPromptDirectories(dirs []string) error {
+	return s.promptManager.SetPromptDirectories(dirs)
+}
+
+// SetPromptNamePattern sets the regular expression prompt names must match when
+// they are loaded. Defaults to prompts.DefaultNamePattern. Must be called before
+// Start.
+func (s *MCPServer) SetPromptNamePattern(pattern string) error {
+	return s.promptManager.SetNamePattern(pattern)
+}
+
+// SetLenientPromptNames loads prompts whose names do not match the name pattern
+// with a warning instead of skipping them. Strict mode skips them regardless.
+// Disabled by default. Must be called before Start.
+func (s *MCPServer) SetLenientPromptNames(lenient bool) {
+	s.lenientPromptNames = lenient
+}
+
+// SetAuditLog enables a JSON audit record of every tool invocation, written to w
+// one per line, separate from the service logs. Records carry the tool, this
+// server's session ID, sanitized arguments, duration and outcome. Disabled by
+// default. Must be called before Start.
+func (s *MCPServer) SetAuditLog(w io.Writer) {
+	s.auditLog = w
+}
+
+// SetArgumentSanitization sets how tool arguments appear in logs and audit records:
+// strings longer than maxLength characters are truncated and the values of
+// redactedFields, matched ignoring case, are replaced with "[redacted]". Defaults to
+// tools.DefaultSanitizeLength and tools.DefaultRedactedFields. Must be called
+// before Start.
+func (s *MCPServer) SetArgumentSanitization(maxLength int, redactedFields []string) error {
+	if maxLength < 1 {
+		return fmt.Errorf("argument sanitize length must be at least 1 character, got %d", maxLength)
+	}
+	s.argSanitizeLength = maxLength
+	s.redactedFields = append([]string{}, redactedFields...)
+	return nil
+}
+
+// newSessionID returns a random ID for the client connection a server serves
+func newSessionID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("pid-%d-%d", os.Getpid(), time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
 // initializeDocumentationSystem sets up the documentation scanning and monitoring with concurrent processing
 func (s *MCPServer) initializeDocumentationSystem(ctx context.Context) error {
 	// Define documentation directories to scan
-	docDirs := []string{
-		config.GuidelinesPath,
-		config.PatternsPath,
-		config.ADRPath,
-	}
+	docDirs := documentationDirs()
 
 	// Populate initial cache using concurrent scanner
 	scanStart := time.Now()
@@ -46,7 +258,7 @@ func (s *MCPServer) initializeDocumentationSystemConcurrent(ctx context.Context,
 
 	// Start concurrent scanning
 	go func() {
-		indexes, err := s.scanner.BuildIndex(docDirs)
+		indexes, err := s.documentSource.List(ctx)
 		resultChan <- initResult{
 			operation: "scanning",
 			err:       err,
@@ -56,7 +268,10 @@ func (s *MCPServer) initializeDocumentationSystemConcurrent(ctx context.Context,
 
 	// Start concurrent monitoring setup
 	go func() {
-		err := s.setupFileSystemMonitoring(docDirs)
+		var err error
+		if s.documentsOnDisk() {
+			err = s.setupFileSystemMonitoring(docDirs)
+		}
 		resultChan <- initResult{
 			operation: "monitoring",
 			err:       err,
@@ -88,11 +303,23 @@ func (s *MCPServer) initializeDocumentationSystemConcurrent(ctx context.Context,
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		s.logger.WithContext("documents_loaded", s.cache.Size()).
+			Warn("Documentation initialization cancelled during scan")
+		return err
+	}
+
 	scanDuration := time.Since(scanStart)
 
 	// Process scan results and load documents concurrently
 	if scanIndexes != nil {
-		totalDocs := s.processScanResultsConcurrent(scanIndexes, &scanErrors)
+		totalDocs, err := s.processScanResultsConcurrent(ctx, scanIndexes, &scanErrors)
+		if err != nil {
+			s.logger.WithContext("documents_loaded", s.cache.Size()).
+				WithContext("documents_found", totalDocs).
+				Warn("Documentation initialization cancelled during load")
+			return err
+		}
 
 		// Log overall scan results
 		scanLogger := s.loggingManager.GetLogger("scanner").
@@ -112,6 +339,8 @@ func (s *MCPServer) initializeDocumentationSystemConcurrent(ctx context.Context,
 		}
 	}
 
+	s.recordDocumentationReport(scanIndexes, scanErrors, time.Since(scanStart))
+
 	// Handle monitoring setup result
 	if monitoringErr != nil {
 		s.logger.WithError(monitoringErr).Warn("File system monitoring setup had issues")
@@ -128,6 +357,7 @@ func (s *MCPServer) initializePromptsSystem() error {
 	s.logger.Info("Initializing prompts system")
 
 	// Load prompt definitions
+	s.promptManager.SetLenientNames(s.lenientPromptNames && !s.strict)
 	if err := s.promptManager.LoadPrompts(); err != nil {
 		s.logger.WithError(err).Warn("Failed to load prompts")
 		return err
@@ -150,38 +380,53 @@ func (s *MCPServer) initializePromptsSystem() error {
 	return nil
 }
 
-// processScanResultsConcurrent processes scan results and loads documents concurrently
-func (s *MCPServer) processScanResultsConcurrent(indexes map[string]*models.DocumentIndex, scanErrors *[]string) int {
+// processScanResultsConcurrent loads scanned documents concurrently and then caches
+// the category indexes. If ctx is cancelled mid-load it returns the context's error
+// without caching any index, leaving only fully loaded documents in the cache.
+func (s *MCPServer) processScanResultsConcurrent(ctx context.Context, indexes map[string]*models.DocumentIndex, scanErrors *[]string) (int, error) {
 	var totalDocs int
 
 	// Collect all documents to load
 	var allDocuments []models.DocumentMetadata
-	for category, index := range indexes {
-		s.cache.SetIndex(category, index)
+	for _, index := range indexes {
 		totalDocs += index.Count
-
-		s.logger.WithContext("category", category).
-			WithContext("document_count", index.Count).
-			Info("Cached documents for category")
-
 		allDocuments = append(allDocuments, index.Documents...)
 	}
 
 	// Load documents concurrently
 	if len(allDocuments) > 0 {
-		s.loadDocumentsConcurrent(allDocuments, scanErrors)
+		if err := s.loadDocumentsConcurrent(ctx, allDocuments, scanErrors); err != nil {
+			return totalDocs, err
+		}
 	}
 
-	return totalDocs
+	for category, index := range indexes {
+		s.cache.SetIndex(category, index)
+
+		s.logger.WithContext("category", category).
+			WithContext("document_count", index.Count).
+			Info("Cached documents for category")
+	}
+
+	return totalDocs, nil
 }
 
 // loadDocumentsConcurrent loads multiple documents into cache concurrently
-// Worker pool size is capped to balance parallelism with resource usage
-func (s *MCPServer) loadDocumentsConcurrent(documents []models.DocumentMetadata, scanErrors *[]string) {
-	numWorkers := min(runtime.NumCPU(), len(documents))
-	if numWorkers > 4 {
-		numWorkers = 4
+// Worker pool size is bounded by loadWorkers to balance parallelism with resource usage.
+// Workers stop reading files once ctx is done and the context's error is returned.
+func (s *MCPServer) loadDocumentsConcurrent(ctx context.Context, documents []models.DocumentMetadata, scanErrors *[]string) error {
+	return s.forEachDocumentConcurrent(ctx, documents, scanErrors, s.loadDocumentIntoCache)
+}
+
+// forEachDocumentConcurrent calls load for each document on the loadWorkers worker
+// pool, collecting the errors it returns into scanErrors. Workers stop once ctx is
+// done and the context's error is returned.
+func (s *MCPServer) forEachDocumentConcurrent(ctx context.Context, documents []models.DocumentMetadata, scanErrors *[]string, load func(context.Context, models.DocumentMetadata) error) error {
+	loadWorkers := s.loadWorkers
+	if loadWorkers <= 0 {
+		loadWorkers = defaultLoadWorkers()
 	}
+	numWorkers := min(loadWorkers, len(documents))
 
 	docChan := make(chan models.DocumentMetadata, len(documents))
 	errorChan := make(chan error, len(documents))
@@ -192,7 +437,10 @@ func (s *MCPServer) loadDocumentsConcurrent(documents []models.DocumentMetadata,
 		go func() {
 			defer wg.Done()
 			for doc := range docChan {
-				if err := s.loadDocumentIntoCache(doc); err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := load(ctx, doc); err != nil {
 					errorChan <- fmt.Errorf("failed to load %s: %v", doc.Path, err)
 				}
 			}
@@ -213,6 +461,8 @@ func (s *MCPServer) loadDocumentsConcurrent(documents []models.DocumentMetadata,
 		*scanErrors = append(*scanErrors, err.Error())
 		s.logger.WithError(err).Warn("Failed to load document into cache")
 	}
+
+	return ctx.Err()
 }
 
 // setupFileSystemMonitoring sets up file system monitoring for all directories
@@ -247,12 +497,30 @@ func (s *MCPServer) setupFileSystemMonitoring(docDirs []string) error {
 }
 
 // loadDocumentIntoCache loads a document's full content into the cache
-func (s *MCPServer) loadDocumentIntoCache(metadata models.DocumentMetadata) error {
-	// Read the file content
-	content, err := os.ReadFile(metadata.Path)
+func (s *MCPServer) loadDocumentIntoCache(ctx context.Context, metadata models.DocumentMetadata) error {
+	// Read the document content
+	content, err := s.documentSource.Read(ctx, metadata.Path)
 	if err != nil {
 		return err
 	}
+	return s.cacheDocument(metadata, content)
+}
+
+// cacheDocument stores a document's content in the cache under its metadata
+func (s *MCPServer) cacheDocument(metadata models.DocumentMetadata, content []byte) error {
+	// The file may have changed since it was scanned
+	if err := scanner.ValidateTextContent(content); err != nil {
+		return err
+	}
+
+	// Empty documents stay readable but search skips them, so flag them early
+	if strings.TrimSpace(string(content)) == "" {
+		s.logger.WithContext("path", metadata.Path).
+			Warn("Document has no content and will not appear in search results")
+	}
+
+	// Word count is computed once here so resources/list stays cheap
+	metadata.WordCount = countWords(string(content))
 
 	// Create document with content
 	doc := &models.Document{
@@ -268,6 +536,52 @@ func (s *MCPServer) loadDocumentIntoCache(metadata models.DocumentMetadata) erro
 	return nil
 }
 
+// SetNamingConventions overrides the filename patterns check-naming-conventions
+// enforces, as regular expressions keyed by category such as {"adr": `^ADR-\d+\.md$`}.
+// Categories left out keep the tools' defaults. Must be called before Start.
+func (s *MCPServer) SetNamingConventions(conventions map[string]string) error {
+	for category, pattern := range conventions {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid naming convention for %s: %w", category, err)
+		}
+	}
+	s.namingConventions = conventions
+	return nil
+}
+
+// SetADRProximityWindow sets how close, in characters, an opposing keyword such as
+// "avoid" must be to a decision keyword in an ADR for check-adr-alignment to report
+// a conflict. Defaults to tools.DefaultProximityWindow. Must be called before Start.
+func (s *MCPServer) SetADRProximityWindow(chars int) error {
+	if chars < 1 {
+		return fmt.Errorf("proximity window must be at least 1 character, got %d", chars)
+	}
+	s.adrProximityWindow = chars
+	return nil
+}
+
+// SetADRMinConfidence sets the confidence, between 0 and 1, check-adr-alignment needs
+// to report an ADR as supporting or conflicting; less confident matches are reported
+// as related. Defaults to tools.DefaultMinAlignmentConfidence. Must be called before Start.
+func (s *MCPServer) SetADRMinConfidence(confidence float64) error {
+	if confidence < 0 || confidence > 1 {
+		return fmt.Errorf("minimum confidence must be between 0 and 1, got %g", confidence)
+	}
+	s.adrMinConfidence = confidence
+	return nil
+}
+
+// SetStaleThresholdDays sets how many days a document may go unmodified before
+// find-stale-documents reports it when a call leaves out threshold_days. Defaults
+// to tools.DefaultStaleThresholdDays. Must be called before Start.
+func (s *MCPServer) SetStaleThresholdDays(days int) error {
+	if days < 1 {
+		return fmt.Errorf("stale threshold must be at least 1 day, got %d", days)
+	}
+	s.staleThresholdDays = days
+	return nil
+}
+
 // initializeToolsSystem sets up the tools system with registered tools
 func (s *MCPServer) initializeToolsSystem() error {
 	s.logger.Info("Initializing tools system")
@@ -276,12 +590,25 @@ func (s *MCPServer) initializeToolsSystem() error {
 	loggingManager := logging.NewLoggingManager()
 	toolLogger := loggingManager.GetLogger("tools")
 	s.toolManager = tools.NewToolManager(toolLogger)
+	if s.auditLog != nil {
+		s.toolManager.SetAuditLog(s.auditLog)
+	}
+	if s.argSanitizeLength > 0 {
+		if err := s.toolManager.SetArgumentSanitization(s.argSanitizeLength, s.redactedFields); err != nil {
+			return err
+		}
+	}
 
 	// Register built-in tools
 	var registrationErrors []error
 
 	// Register ValidatePatternTool
 	validateTool := tools.NewValidatePatternTool(s.cache, toolLogger)
+	if s.sectionLevels != nil {
+		if err := validateTool.SetSectionLevels(s.sectionLevels); err != nil {
+			return err
+		}
+	}
 	if err := s.toolManager.RegisterTool(validateTool); err != nil {
 		s.logger.WithError(err).
 			WithContext("tool", validateTool.Name()).
@@ -294,6 +621,18 @@ func (s *MCPServer) initializeToolsSystem() error {
 
 	// Register SearchArchitectureTool
 	searchTool := tools.NewSearchArchitectureTool(s.cache, toolLogger)
+	if s.stopWords != nil {
+		searchTool.SetStopWords(s.stopWords)
+	}
+	if s.minQueryLength > 0 {
+		if err := searchTool.SetMinQueryLength(s.minQueryLength); err != nil {
+			return err
+		}
+	}
+	if err := searchTool.SetCategoryBoosts(s.categoryBoosts); err != nil {
+		return err
+	}
+	searchTool.SetCollapseCodeExcerpts(s.collapseCodeExcerpts)
 	if err := s.toolManager.RegisterTool(searchTool); err != nil {
 		s.logger.WithError(err).
 			WithContext("tool", searchTool.Name()).
@@ -306,6 +645,22 @@ func (s *MCPServer) initializeToolsSystem() error {
 
 	// Register CheckADRAlignmentTool
 	adrTool := tools.NewCheckADRAlignmentTool(s.cache, toolLogger)
+	if s.adrProximityWindow > 0 {
+		if err := adrTool.SetProximityWindow(s.adrProximityWindow); err != nil {
+			return err
+		}
+	}
+	if err := adrTool.SetMinConfidence(s.adrMinConfidence); err != nil {
+		return err
+	}
+	if s.stopWords != nil {
+		adrTool.SetStopWords(s.stopWords)
+	}
+	if s.sectionLevels != nil {
+		if err := adrTool.SetSectionLevels(s.sectionLevels); err != nil {
+			return err
+		}
+	}
 	if err := s.toolManager.RegisterTool(adrTool); err != nil {
 		s.logger.WithError(err).
 			WithContext("tool", adrTool.Name()).
@@ -316,6 +671,64 @@ func (s *MCPServer) initializeToolsSystem() error {
 			Info("Registered tool successfully")
 	}
 
+	// Register CheckNamingConventionsTool
+	namingTool := tools.NewCheckNamingConventionsTool(s.cache, toolLogger)
+	for category, pattern := range s.namingConventions {
+		if err := namingTool.SetConvention(category, pattern); err != nil {
+			return err
+		}
+	}
+	if err := s.toolManager.RegisterTool(namingTool); err != nil {
+		s.logger.WithError(err).
+			WithContext("tool", namingTool.Name()).
+			Error("Failed to register CheckNamingConventionsTool")
+		registrationErrors = append(registrationErrors, fmt.Errorf("CheckNamingConventionsTool: %w", err))
+	} else {
+		s.logger.WithContext("tool", namingTool.Name()).
+			Info("Registered tool successfully")
+	}
+
+	// Register FindStaleDocumentsTool
+	staleTool := tools.NewFindStaleDocumentsTool(s.cache, toolLogger)
+	if s.staleThresholdDays > 0 {
+		if err := staleTool.SetThresholdDays(s.staleThresholdDays); err != nil {
+			return err
+		}
+	}
+	if err := s.toolManager.RegisterTool(staleTool); err != nil {
+		s.logger.WithError(err).
+			WithContext("tool", staleTool.Name()).
+			Error("Failed to register FindStaleDocumentsTool")
+		registrationErrors = append(registrationErrors, fmt.Errorf("FindStaleDocumentsTool: %w", err))
+	} else {
+		s.logger.WithContext("tool", staleTool.Name()).
+			Info("Registered tool successfully")
+	}
+
+	// Register FindSimilarTool
+	similarTool := tools.NewFindSimilarTool(s.cache, toolLogger)
+	if err := s.toolManager.RegisterTool(similarTool); err != nil {
+		s.logger.WithError(err).
+			WithContext("tool", similarTool.Name()).
+			Error("Failed to register FindSimilarTool")
+		registrationErrors = append(registrationErrors, fmt.Errorf("FindSimilarTool: %w", err))
+	} else {
+		s.logger.WithContext("tool", similarTool.Name()).
+			Info("Registered tool successfully")
+	}
+
+	// Register SummarizeCategoryTool
+	summaryTool := tools.NewSummarizeCategoryTool(s.cache, toolLogger)
+	if err := s.toolManager.RegisterTool(summaryTool); err != nil {
+		s.logger.WithError(err).
+			WithContext("tool", summaryTool.Name()).
+			Error("Failed to register SummarizeCategoryTool")
+		registrationErrors = append(registrationErrors, fmt.Errorf("SummarizeCategoryTool: %w", err))
+	} else {
+		s.logger.WithContext("tool", summaryTool.Name()).
+			Info("Registered tool successfully")
+	}
+
 	// Validate all registered tools
 	registeredTools := s.toolManager.ListTools()
 	s.logger.WithContext("tool_count", len(registeredTools)).
@@ -330,9 +743,9 @@ func (s *MCPServer) initializeToolsSystem() error {
 
 	// Inject tool manager into prompt manager for prompt-tool integration
 	if s.promptManager != nil {
-		// Create adapter to bridge tools.ToolManager and prompts.ToolManagerInterface
-		adapter := &toolManagerAdapter{tm: s.toolManager}
-		s.promptManager.SetToolManager(adapter)
+		s.promptManager.SetToolManager(s.toolManager)
+		// Query hints in {{resource:...}} rank documents as search-architecture does
+		s.promptManager.SetDocumentRanker(searchTool)
 		s.logger.Info("Tool manager injected into prompt manager for tool reference expansion")
 	}
 
@@ -340,15 +753,6 @@ func (s *MCPServer) initializeToolsSystem() error {
 	return nil
 }
 
-// toolManagerAdapter adapts tools.ToolManager to prompts.ToolManagerInterface
-type toolManagerAdapter struct {
-	tm *tools.ToolManager
-}
-
-func (a *toolManagerAdapter) GetTool(name string) (prompts.ToolInterface, error) {
-	return a.tm.GetTool(name)
-}
-
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
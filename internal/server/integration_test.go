@@ -1,16 +1,25 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+	"mcp-architecture-service/pkg/scanner"
+	"mcp-architecture-service/pkg/tools"
 )
 
 // Test fixtures and setup helpers
@@ -263,6 +272,57 @@ func TestResourcesReadMethod(t *testing.T) {
 	}
 }
 
+// Test: Non-markdown resources keep their detected MIME type through list and read
+func TestResourcesReadNonMarkdownMimeType(t *testing.T) {
+	env := setupTestEnv(t)
+	docs := standardTestDocs(env)
+	docs[filepath.Join(env.patternsDir, "service-catalog.json")] = `{"services": ["orders", "billing"]}`
+	docs[filepath.Join(env.guidelinesDir, "lint-rules.yaml")] = "rules:\n  max-line-length: 120\n"
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	tests := []struct {
+		name         string
+		uri          string
+		expectedMime string
+		expectedText string
+	}{
+		{"JSON", "architecture://patterns/service-catalog.json", config.MimeTypeJSON, `"orders"`},
+		{"YAML", "architecture://guidelines/lint-rules.yaml", config.MimeTypeYAML, "max-line-length"},
+		{"Markdown", "architecture://patterns/repository-pattern", config.MimeTypeMarkdown, "Repository Pattern"},
+	}
+
+	listResponse := env.server.handleResourcesList(&models.MCPMessage{JSONRPC: "2.0", ID: "list", Method: "resources/list"})
+	listed := make(map[string]string)
+	for _, resource := range listResponse.Result.(models.MCPResourcesListResult).Resources {
+		listed[resource.URI] = resource.MimeType
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if listed[tt.uri] != tt.expectedMime {
+				t.Errorf("resources/list mimeType for %s = %q, want %q", tt.uri, listed[tt.uri], tt.expectedMime)
+			}
+
+			response := env.server.handleResourcesRead(&models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "read",
+				Method:  "resources/read",
+				Params:  models.MCPResourcesReadParams{URI: tt.uri},
+			})
+			validateMCPResponse(t, response, false)
+
+			content := response.Result.(models.MCPResourcesReadResult).Contents[0]
+			if content.MimeType != tt.expectedMime {
+				t.Errorf("resources/read mimeType = %q, want %q", content.MimeType, tt.expectedMime)
+			}
+			if !strings.Contains(content.Text, tt.expectedText) {
+				t.Errorf("Content should contain %q", tt.expectedText)
+			}
+		})
+	}
+}
+
 // Test: Resource Error Scenarios - Table Driven
 func TestResourceErrorScenarios(t *testing.T) {
 	server := NewMCPServer()
@@ -321,6 +381,248 @@ func TestResourceErrorScenarios(t *testing.T) {
 	}
 }
 
+// Test: Documentation Diagnostics Report
+func TestServerDiagnosticsReport(t *testing.T) {
+	env := setupTestEnv(t)
+	docs := standardTestDocs(env)
+	docs[filepath.Join(env.adrDir, "001-event-sourcing.md")] = "# ADR-001: Use Event Sourcing\n\n## Status\nProposed"
+	docs[filepath.Join(env.patternsDir, "generated-catalog.md")] = "# Generated\n\n" + strings.Repeat("x", int(scanner.DefaultMaxFileSize))
+	docs[filepath.Join(env.guidelinesDir, "placeholder.md")] = "\n\n"
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	response := env.server.handleServerDiagnostics(&models.MCPMessage{JSONRPC: "2.0", ID: "diag", Method: "server/diagnostics"})
+	validateMCPResponse(t, response, false)
+
+	report, ok := response.Result.(map[string]interface{})["documentation"].(*models.DocumentationReport)
+	if !ok || report == nil {
+		t.Fatalf("Expected documentation report in result, got %+v", response.Result)
+	}
+
+	t.Run("CategoryCounts", func(t *testing.T) {
+		expected := map[string]int{config.CategoryGuideline: 2, config.CategoryPattern: 1, config.CategoryADR: 2}
+		for category, count := range expected {
+			if report.CategoryCounts[category] != count {
+				t.Errorf("Expected %d %s documents, got %d", count, category, report.CategoryCounts[category])
+			}
+		}
+		if report.TotalDocuments != 5 {
+			t.Errorf("Expected 5 total documents, got %d", report.TotalDocuments)
+		}
+	})
+
+	t.Run("EmptyDocument", func(t *testing.T) {
+		if len(report.EmptyDocuments) != 1 || filepath.Base(report.EmptyDocuments[0]) != "placeholder.md" {
+			t.Errorf("Expected placeholder.md as the only empty document, got %v", report.EmptyDocuments)
+		}
+	})
+
+	t.Run("SkippedOversizeFile", func(t *testing.T) {
+		if len(report.SkippedFiles) != 1 {
+			t.Fatalf("Expected 1 skipped file, got %+v", report.SkippedFiles)
+		}
+		skipped := report.SkippedFiles[0]
+		if filepath.Base(skipped.Path) != "generated-catalog.md" || !strings.Contains(skipped.Reason, "exceeds limit") {
+			t.Errorf("Unexpected skipped file %+v", skipped)
+		}
+	})
+
+	t.Run("DuplicateADRId", func(t *testing.T) {
+		if len(report.DuplicateIDs) != 1 {
+			t.Fatalf("Expected 1 duplicate ID, got %+v", report.DuplicateIDs)
+		}
+		duplicate := report.DuplicateIDs[0]
+		if duplicate.URI != "architecture://adr/001" {
+			t.Errorf("Expected duplicate URI architecture://adr/001, got %s", duplicate.URI)
+		}
+		if len(duplicate.Paths) != 2 {
+			t.Errorf("Expected 2 paths for duplicate ID, got %v", duplicate.Paths)
+		}
+	})
+
+	if env.server.DocumentationReport() != report {
+		t.Error("DocumentationReport should return the report served by server/diagnostics")
+	}
+}
+
+// Test: Initial Load With Configured Worker Counts
+func TestInitialLoadWorkerCounts(t *testing.T) {
+	const docCount = 60
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		t.Run(fmt.Sprintf("Workers_%d", workers), func(t *testing.T) {
+			env := setupTestEnv(t)
+			if err := createTestDocumentationFiles(env.tempDir, docCount); err != nil {
+				t.Fatalf("Failed to create test documents: %v", err)
+			}
+
+			originalDir, _ := os.Getwd()
+			os.Chdir(env.tempDir)
+			t.Cleanup(func() { env.cleanup(t, originalDir) })
+
+			server := newMCPServerWithOptions(false)
+			server.SetLoadWorkers(workers)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := server.initializeDocumentationSystem(ctx); err != nil {
+				t.Fatalf("Failed to initialize documentation system: %v", err)
+			}
+
+			if size := server.cache.Size(); size != docCount {
+				t.Errorf("Expected %d documents loaded, got %d", docCount, size)
+			}
+			if report := server.DocumentationReport(); report.TotalDocuments != docCount || len(report.ParseWarnings) != 0 {
+				t.Errorf("Expected clean load of %d documents, got %+v", docCount, report)
+			}
+		})
+	}
+}
+
+// Test: Initial Load Skips Binary Files - non-UTF-8 content never reaches the cache
+func TestInitialLoadSkipsBinaryFiles(t *testing.T) {
+	env := setupTestEnv(t)
+	docs := standardTestDocs(env)
+	docs[filepath.Join(env.patternsDir, "diagram.md")] = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\xff\xfe"
+	docs[filepath.Join(env.guidelinesDir, "legacy.md")] = "# Caf\xe9 Guidelines\n\nLatin-1 encoded."
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	if size := env.server.cache.Size(); size != len(standardTestDocs(env)) {
+		t.Errorf("Expected %d documents loaded, got %d", len(standardTestDocs(env)), size)
+	}
+
+	for path, doc := range env.server.cache.GetAllDocuments() {
+		if base := filepath.Base(path); base == "diagram.md" || base == "legacy.md" {
+			t.Errorf("Expected %s to be skipped", path)
+		}
+		if !utf8.ValidString(doc.Content.RawContent) {
+			t.Errorf("Document %s cached with invalid UTF-8 content", path)
+		}
+	}
+
+	warnings := strings.Join(env.server.DocumentationReport().ParseWarnings, "\n")
+	for _, name := range []string{"diagram.md", "legacy.md"} {
+		if !strings.Contains(warnings, name) {
+			t.Errorf("Expected a parse warning for %s, got:\n%s", name, warnings)
+		}
+	}
+}
+
+// Test: Initial Load Cancellation - stops mid-load leaving only complete documents
+func TestInitialLoadCancellation(t *testing.T) {
+	const docCount = 3000
+
+	env := setupTestEnv(t)
+	if err := createTestDocumentationFiles(env.tempDir, docCount); err != nil {
+		t.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	os.Chdir(env.tempDir)
+	t.Cleanup(func() { env.cleanup(t, originalDir) })
+
+	server := newMCPServerWithOptions(false)
+	server.SetLoadWorkers(1)
+
+	// Cancel once the load phase has started filling the cache
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for server.cache.Size() < 10 {
+			if ctx.Err() != nil {
+				return
+			}
+			runtime.Gosched()
+		}
+		cancel()
+	}()
+
+	err := server.initializeDocumentationSystem(ctx)
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	loaded := server.cache.GetAllDocuments()
+	if len(loaded) == 0 || len(loaded) >= docCount {
+		t.Errorf("Expected a partial load, got %d of %d documents", len(loaded), docCount)
+	}
+	for path, doc := range loaded {
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			t.Fatalf("Failed to read %s: %v", path, readErr)
+		}
+		if doc.Content.RawContent != string(content) {
+			t.Errorf("Document %s was cached with incomplete content", path)
+		}
+	}
+	if indexes := server.cache.GetAllIndexes(); len(indexes) != 0 {
+		t.Errorf("Expected no category indexes after a cancelled load, got %d", len(indexes))
+	}
+	if server.DocumentationReport() != nil {
+		t.Error("Expected no documentation report after a cancelled load")
+	}
+}
+
+// Test: Markdown Extension Variants - .markdown and .mdx documents load with clean URIs
+func TestMarkdownExtensionVariants(t *testing.T) {
+	env := setupTestEnv(t)
+	docs := standardTestDocs(env)
+	docs[filepath.Join(env.guidelinesDir, "deployment.markdown")] = "# Deployment Guidelines\n\nUse blue-green deployments."
+	docs[filepath.Join(env.patternsDir, "widgets.mdx")] = "# Widget Pattern\n\nCompose widgets."
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	t.Run("GenerateURI", func(t *testing.T) {
+		tests := []struct {
+			category string
+			path     string
+			expected string
+		}{
+			{config.CategoryGuideline, config.GuidelinesPath + "/deployment.markdown", "architecture://guidelines/deployment"},
+			{config.CategoryPattern, config.PatternsPath + "/widgets.mdx", "architecture://patterns/widgets"},
+			{config.CategoryADR, config.ADRPath + "/007-use-grpc.markdown", "architecture://adr/007"},
+			{config.CategoryGuideline, config.GuidelinesPath + "/notes.txt", "architecture://guidelines/notes.txt"},
+		}
+		for _, tt := range tests {
+			if uri := env.server.generateResourceURI(tt.category, tt.path); uri != tt.expected {
+				t.Errorf("generateResourceURI(%s) = %s, want %s", tt.path, uri, tt.expected)
+			}
+		}
+	})
+
+	t.Run("ListAndRead", func(t *testing.T) {
+		response := env.server.handleResourcesList(&models.MCPMessage{JSONRPC: "2.0", ID: "list", Method: "resources/list"})
+		validateMCPResponse(t, response, false)
+
+		uris := make(map[string]bool)
+		for _, resource := range response.Result.(models.MCPResourcesListResult).Resources {
+			uris[resource.URI] = true
+		}
+
+		for uri, content := range map[string]string{
+			"architecture://guidelines/deployment": "blue-green",
+			"architecture://patterns/widgets":      "Compose widgets",
+		} {
+			if !uris[uri] {
+				t.Errorf("Expected %s in resources/list", uri)
+				continue
+			}
+
+			read := env.server.handleResourcesRead(&models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "read",
+				Method:  "resources/read",
+				Params:  models.MCPResourcesReadParams{URI: uri},
+			})
+			validateMCPResponse(t, read, false)
+			if text := read.Result.(models.MCPResourcesReadResult).Contents[0].Text; !strings.Contains(text, content) {
+				t.Errorf("Expected %s to contain %q, got %q", uri, content, text)
+			}
+		}
+	})
+}
+
 // Test: URI Parsing - Table Driven
 func TestResourceURIParsing(t *testing.T) {
 	server := NewMCPServer()
@@ -407,9 +709,191 @@ func TestToolsSystemIntegration(t *testing.T) {
 	validateMCPResponse(t, response, false)
 
 	result := response.Result.(models.MCPToolsListResult)
-	if len(result.Tools) != 3 {
-		t.Errorf("Expected 3 tools, got %d", len(result.Tools))
+	if len(result.Tools) != 7 {
+		t.Errorf("Expected 7 tools, got %d", len(result.Tools))
+	}
+}
+
+// Test: create-adr embeds the check-adr-alignment result for its topic
+func TestCreateADRPromptSurfacesConflicts(t *testing.T) {
+	promptJSON, err := os.ReadFile(filepath.Join("..", "..", config.PromptsBasePath, "create-adr.json"))
+	if err != nil {
+		t.Fatalf("Failed to read create-adr prompt: %v", err)
+	}
+
+	env := setupTestEnv(t)
+	promptsDir := filepath.Join(env.tempDir, config.PromptsBasePath)
+	if err := os.MkdirAll(promptsDir, 0755); err != nil {
+		t.Fatalf("Failed to create prompts directory: %v", err)
+	}
+
+	docs := standardTestDocs(env)
+	docs[filepath.Join(env.adrDir, "002-shared-database.md")] = `# ADR-002: Shared Database Across Services
+
+## Status
+Superseded
+
+## Decision
+Services share a single relational database for reporting.`
+	docs[filepath.Join(promptsDir, "create-adr.json")] = string(promptJSON)
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+	if err := env.server.initializePromptsSystem(); err != nil {
+		t.Fatalf("Failed to initialize prompts system: %v", err)
+	}
+
+	promptResponse := env.server.handlePromptsGet(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "create-adr",
+		Method:  "prompts/get",
+		Params: models.MCPPromptsGetParams{
+			Name:      "create-adr",
+			Arguments: map[string]interface{}{"topic": "Services share a single database for reporting"},
+		},
+	})
+	result := validatePromptsGetResponse(t, promptResponse, "create-adr")
+	text := result.Messages[0].Content.Text
+	if !strings.Contains(text, "Tool: check-adr-alignment") {
+		t.Error("Expected create-adr to embed the check-adr-alignment tool reference")
+	}
+
+	// The alignment result for the topic is embedded when the prompt is rendered
+	_, embedded, found := strings.Cut(text, "Result of check-adr-alignment:\n")
+	if !found {
+		t.Fatalf("Expected create-adr to embed the check-adr-alignment result, got %s", text)
+	}
+	var alignment struct {
+		Conflicts []struct {
+			ADRURI string `json:"adr_uri"`
+		} `json:"conflicts"`
+	}
+	if err := json.NewDecoder(strings.NewReader(embedded)).Decode(&alignment); err != nil {
+		t.Fatalf("Failed to parse embedded alignment result: %v", err)
+	}
+
+	found = false
+	for _, conflict := range alignment.Conflicts {
+		found = found || conflict.ADRURI == "architecture://adr/002-shared-database"
+	}
+	if !found {
+		t.Errorf("Expected superseded ADR-002 among embedded conflicts, got %s", embedded)
+	}
+}
+
+// exportedSchemaTool validates arguments against a schema as a client would receive it
+type exportedSchemaTool struct {
+	name   string
+	schema map[string]interface{}
+}
+
+func (e *exportedSchemaTool) Name() string                        { return e.name }
+func (e *exportedSchemaTool) Description() string                 { return "" }
+func (e *exportedSchemaTool) InputSchema() map[string]interface{} { return e.schema }
+func (e *exportedSchemaTool) Execute(context.Context, map[string]interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// Test: Tools Schema Method
+func TestToolsSchemaMethod(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	response := env.server.handleToolsSchema(&models.MCPMessage{JSONRPC: "2.0", ID: "schema", Method: "tools/schema"})
+	validateMCPResponse(t, response, false)
+
+	// Round-trip through JSON so the test sees exactly what a client receives
+	payload, err := json.Marshal(response.Result)
+	if err != nil {
+		t.Fatalf("Failed to serialize schemas: %v", err)
+	}
+	var exported struct {
+		Schemas []struct {
+			Name        string                 `json:"name"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		} `json:"schemas"`
+	}
+	if err := json.Unmarshal(payload, &exported); err != nil {
+		t.Fatalf("Failed to parse serialized schemas: %v", err)
+	}
+
+	if len(exported.Schemas) != 7 {
+		t.Fatalf("Expected 7 tool schemas, got %d", len(exported.Schemas))
+	}
+
+	schemas := make(map[string]map[string]interface{})
+	for _, schema := range exported.Schemas {
+		rawRequired, hasRequired := schema.InputSchema["required"]
+		required, ok := rawRequired.([]interface{})
+		if hasRequired && !ok {
+			t.Errorf("%s: required should serialize as a JSON array, got %T", schema.Name, rawRequired)
+		}
+		for _, field := range required {
+			if _, ok := field.(string); !ok {
+				t.Errorf("%s: required entries should be strings, got %T", schema.Name, field)
+			}
+		}
+		schemas[schema.Name] = schema.InputSchema
+	}
+
+	executor := tools.NewToolExecutor(logging.NewStructuredLogger("test"))
+
+	tests := []struct {
+		name      string
+		toolName  string
+		args      map[string]interface{}
+		expectErr bool
+	}{
+		{"SearchValid", "search-architecture", map[string]interface{}{"query": "api design", "max_results": float64(5)}, false},
+		{"SearchMissingQuery", "search-architecture", map[string]interface{}{"max_results": float64(5)}, true},
+		{"ValidateValid", "validate-against-pattern", map[string]interface{}{"code": "type Repo struct{}", "pattern_name": "repository-pattern"}, false},
+		{"ValidateMissingCode", "validate-against-pattern", map[string]interface{}{"pattern_name": "repository-pattern"}, true},
+		{"AlignmentValid", "check-adr-alignment", map[string]interface{}{"decision_description": "Use GraphQL"}, false},
+		{"AlignmentTooLong", "check-adr-alignment", map[string]interface{}{"decision_description": strings.Repeat("x", 5001)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &exportedSchemaTool{name: tt.toolName, schema: schemas[tt.toolName]}
+			err := executor.ValidateArguments(tool, tt.args)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ValidateArguments() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
 	}
+
+	t.Run("SingleTool", func(t *testing.T) {
+		response := env.server.handleToolsSchema(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "schema-one",
+			Method:  "tools/schema",
+			Params:  models.MCPToolsSchemaParams{Name: "find-similar"},
+		})
+		validateMCPResponse(t, response, false)
+
+		result := response.Result.(models.MCPToolsSchemaResult)
+		if len(result.Schemas) != 1 || result.Schemas[0].Name != "find-similar" {
+			t.Errorf("Expected only the find-similar schema, got %+v", result.Schemas)
+		}
+	})
+
+	t.Run("UnknownTool", func(t *testing.T) {
+		response := env.server.handleToolsSchema(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "schema-unknown",
+			Method:  "tools/schema",
+			Params:  models.MCPToolsSchemaParams{Name: "nonexistent-tool"},
+		})
+		validateMCPResponse(t, response, true)
+	})
 }
 
 // Test: Tools Call Method - Table Driven
@@ -476,7 +960,7 @@ func TestToolsCallMethod(t *testing.T) {
 				},
 			}
 
-			response := env.server.handleToolsCall(msg)
+			response := env.server.handleToolsCall(context.Background(), msg)
 			validateMCPResponse(t, response, tt.expectErr)
 
 			if !tt.expectErr {
@@ -489,59 +973,312 @@ func TestToolsCallMethod(t *testing.T) {
 	}
 }
 
-// Test: Cache Refresh Coordinator
-func TestCacheRefreshCoordinator(t *testing.T) {
-	server := NewMCPServer()
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// failingTool is a tool whose execution always fails
+type failingTool struct {
+	err error
+}
 
-	go server.cacheRefreshCoordinator(ctx)
+func (f *failingTool) Name() string        { return "failing-tool" }
+func (f *failingTool) Description() string { return "Always fails" }
+func (f *failingTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"input": map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"input"},
+	}
+}
+func (f *failingTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	return nil, f.err
+}
 
-	testEvent := models.FileEvent{Type: "delete", Path: config.PatternsPath + "/test.md"}
+// blockingTool is a tool that runs until its context is done
+type blockingTool struct {
+	started chan struct{}
+	stopped chan error // Receives the context error the tool stopped on
+}
+
+func (b *blockingTool) Name() string        { return "blocking-tool" }
+func (b *blockingTool) Description() string { return "Runs until cancelled" }
+func (b *blockingTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (b *blockingTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	close(b.started)
+	<-ctx.Done()
+	b.stopped <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+// Test: notifications/cancelled aborts the matching in-flight request
+func TestCancelledNotificationAbortsInFlightRequest(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	if err := server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+	tool := &blockingTool{started: make(chan struct{}), stopped: make(chan error, 1)}
+	if err := server.toolManager.RegisterTool(tool); err != nil {
+		t.Fatalf("Failed to register blocking tool: %v", err)
+	}
+
+	input, inputWriter := io.Pipe()
+	output := &bytes.Buffer{}
+	processed := make(chan error, 1)
+	go func() {
+		processed <- server.processMessages(context.Background(), input, output)
+	}()
 
+	send := func(message string) {
+		if _, err := inputWriter.Write([]byte(message + "\n")); err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+	}
+
+	send(`{"jsonrpc":"2.0","id":42,"method":"tools/call","params":{"name":"blocking-tool","arguments":{}}}`)
 	select {
-	case server.refreshChan <- testEvent:
-		// Event sent successfully
-	case <-time.After(1 * time.Second):
-		t.Error("Failed to send event to refresh channel")
+	case <-tool.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Slow request did not start")
 	}
-}
 
-// Test: Category From Path - Table Driven
-func TestGetCategoryFromPath(t *testing.T) {
-	server := NewMCPServer()
+	// Other requests and unknown notifications are handled while it runs
+	send(`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1}}`)
+	send(`{"jsonrpc":"2.0","id":"list","method":"tools/list"}`)
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":42,"reason":"user aborted"}}`)
+	inputWriter.Close()
 
-	tests := []struct {
-		path     string
-		expected string
-	}{
-		{config.GuidelinesPath + "/api.md", "guideline"},
-		{config.PatternsPath + "/repository.md", "pattern"},
-		{config.ADRPath + "/adr-001.md", "adr"},
-		{"some/other/path.md", "unknown"},
-		{strings.ToUpper(config.GuidelinesPath) + "/API.MD", "guideline"},
+	select {
+	case err := <-tool.stopped:
+		if err != context.Canceled {
+			t.Errorf("Expected the tool context to be cancelled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Cancelled request kept running")
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.path, func(t *testing.T) {
-			result := server.getCategoryFromPath(tt.path)
-			if result != tt.expected {
-				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
-			}
-		})
+	select {
+	case err := <-processed:
+		if err != nil {
+			t.Fatalf("Expected nil error (EOF), got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Message processing did not finish after cancellation")
+	}
+
+	decoder := json.NewDecoder(output)
+	var ids []interface{}
+	for decoder.More() {
+		var response models.MCPMessage
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		ids = append(ids, response.ID)
+	}
+	if len(ids) != 1 || ids[0] != "list" {
+		t.Errorf("Expected only the tools/list response, got responses for %v", ids)
 	}
 }
 
-// Test: Concurrent Tool Invocations
-func TestConcurrentToolInvocations(t *testing.T) {
-	env := setupTestEnv(t)
-	env.writeTestDocs(t, standardTestDocs(env))
-	env.initServer(t)
+// renamedTool registers a blockingTool under another name
+type renamedTool struct {
+	*blockingTool
+	name string
+}
 
-	if err := env.server.initializeToolsSystem(); err != nil {
+func (r *renamedTool) Name() string { return r.name }
+
+// Test: requests beyond the concurrency limit wait for a running one to finish,
+// while initialize is handled before the notifications that follow it
+func TestProcessMessagesLimitsConcurrentRequests(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	server.SetMaxConcurrentRequests(1)
+	if err := server.initializeToolsSystem(); err != nil {
 		t.Fatalf("Failed to initialize tools system: %v", err)
 	}
-
+	first := &blockingTool{started: make(chan struct{}), stopped: make(chan error, 1)}
+	second := &renamedTool{
+		blockingTool: &blockingTool{started: make(chan struct{}), stopped: make(chan error, 1)},
+		name:         "second-blocking-tool",
+	}
+	for _, tool := range []tools.Tool{first, second} {
+		if err := server.toolManager.RegisterTool(tool); err != nil {
+			t.Fatalf("Failed to register %s: %v", tool.Name(), err)
+		}
+	}
+
+	input, inputWriter := io.Pipe()
+	output := &bytes.Buffer{}
+	processed := make(chan error, 1)
+	go func() {
+		processed <- server.processMessages(context.Background(), input, output)
+	}()
+
+	send := func(message string) {
+		if _, err := inputWriter.Write([]byte(message + "\n")); err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+	}
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"blocking-tool","arguments":{}}}`)
+	select {
+	case <-first.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("First request did not start")
+	}
+
+	// The write returns once the message is read, and reading waits for a free slot
+	sent := make(chan struct{})
+	go func() {
+		send(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"second-blocking-tool","arguments":{}}}`)
+		close(sent)
+	}()
+	select {
+	case <-second.started:
+		t.Fatal("Second request started while the first held the only slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	server.cancellable.cancel(2)
+	select {
+	case <-second.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Second request did not start after the first finished")
+	}
+	<-sent
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":3}}`)
+	inputWriter.Close()
+
+	select {
+	case err := <-processed:
+		if err != nil {
+			t.Fatalf("Expected nil error (EOF), got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Message processing did not finish")
+	}
+
+	if !server.initialized {
+		t.Error("Expected notifications/initialized to complete initialization")
+	}
+}
+
+// Test: Tool Call Error Semantics - protocol errors vs isError results
+func TestToolsCallErrorSemantics(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+	if err := env.server.toolManager.RegisterTool(&failingTool{err: fmt.Errorf("backend unavailable")}); err != nil {
+		t.Fatalf("Failed to register failing tool: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		toolName      string
+		args          map[string]interface{}
+		expectRPCErr  bool
+		expectIsError bool
+		expectText    string
+	}{
+		{"UnknownTool", "nonexistent-tool", map[string]interface{}{}, true, false, ""},
+		{"MissingArgument", "failing-tool", map[string]interface{}{}, true, false, ""},
+		{"InvalidArgumentValue", "search-architecture", map[string]interface{}{"query": "API", "max_results": 0}, true, false, ""},
+		{"ArgumentRejectedByTool", "validate-against-pattern", map[string]interface{}{"code": "x", "pattern_name": "../../etc/passwd"}, true, false, ""},
+		{"ExecutionFailure", "failing-tool", map[string]interface{}{"input": "x"}, false, true, "backend unavailable"},
+		{"NoMatchesIsNotAnError", "search-architecture", map[string]interface{}{"query": "zzzunmatchedzzz"}, false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "test-tool-error",
+				Method:  "tools/call",
+				Params: models.MCPToolsCallParams{
+					Name:      tt.toolName,
+					Arguments: tt.args,
+				},
+			}
+
+			response := env.server.handleToolsCall(context.Background(), msg)
+			validateMCPResponse(t, response, tt.expectRPCErr)
+			if tt.expectRPCErr {
+				return
+			}
+
+			result := response.Result.(models.MCPToolsCallResult)
+			if result.IsError != tt.expectIsError {
+				t.Errorf("Expected isError %v, got %v", tt.expectIsError, result.IsError)
+			}
+			if len(result.Content) == 0 {
+				t.Fatal("Expected at least one content item")
+			}
+			if tt.expectText != "" && !strings.Contains(result.Content[0].Text, tt.expectText) {
+				t.Errorf("Expected content to contain %q, got %q", tt.expectText, result.Content[0].Text)
+			}
+		})
+	}
+}
+
+// Test: Cache Refresh Coordinator
+func TestCacheRefreshCoordinator(t *testing.T) {
+	server := NewMCPServer()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go server.cacheRefreshCoordinator(ctx)
+
+	testEvent := models.FileEvent{Type: "delete", Path: config.PatternsPath + "/test.md"}
+
+	select {
+	case server.refreshChan <- testEvent:
+		// Event sent successfully
+	case <-time.After(1 * time.Second):
+		t.Error("Failed to send event to refresh channel")
+	}
+}
+
+// Test: Category From Path - Table Driven
+func TestGetCategoryFromPath(t *testing.T) {
+	server := NewMCPServer()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{config.GuidelinesPath + "/api.md", "guideline"},
+		{config.PatternsPath + "/repository.md", "pattern"},
+		{config.ADRPath + "/adr-001.md", "adr"},
+		{"some/other/path.md", "unknown"},
+		{strings.ToUpper(config.GuidelinesPath) + "/API.MD", "guideline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			result := server.getCategoryFromPath(tt.path)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+// Test: Concurrent Tool Invocations
+func TestConcurrentToolInvocations(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
 	const numConcurrent = 10
 	done := make(chan error, numConcurrent)
 
@@ -561,7 +1298,7 @@ func TestConcurrentToolInvocations(t *testing.T) {
 				},
 			}
 
-			response := env.server.handleToolsCall(msg)
+			response := env.server.handleToolsCall(context.Background(), msg)
 			if response.Error != nil {
 				done <- fmt.Errorf("concurrent call %d failed: %v", index, response.Error)
 			} else {
@@ -581,3 +1318,710 @@ func TestConcurrentToolInvocations(t *testing.T) {
 		}
 	}
 }
+
+// TestNamingConventions tests that check-naming-conventions enforces the filename
+// patterns set on the server and keeps the defaults for categories left out
+func TestNamingConventions(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	if err := env.server.SetNamingConventions(map[string]string{config.CategoryADR: `^(`}); err == nil {
+		t.Error("Expected an error for an invalid naming convention")
+	}
+	if err := env.server.SetNamingConventions(map[string]string{config.CategoryADR: `^ADR-\d{4}\.md$`}); err != nil {
+		t.Fatalf("SetNamingConventions failed: %v", err)
+	}
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "naming",
+		Method:  "tools/call",
+		Params: models.MCPToolsCallParams{
+			Name:      "check-naming-conventions",
+			Arguments: map[string]interface{}{},
+		},
+	})
+	validateMCPResponse(t, response, false)
+
+	var result struct {
+		Violations []struct {
+			Filename string `json:"filename"`
+		} `json:"violations"`
+		Conventions map[string]string `json:"conventions"`
+	}
+	text := response.Result.(models.MCPToolsCallResult).Content[0].Text
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to parse naming result: %v", err)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Filename != "001-microservices-architecture.md" {
+		t.Errorf("Expected only the ADR to break the configured convention, got %s", text)
+	}
+	if result.Conventions[config.CategoryPattern] != tools.DefaultKebabCaseNamePattern {
+		t.Errorf("Expected the pattern convention to keep its default, got %q", result.Conventions[config.CategoryPattern])
+	}
+}
+
+// TestADRProximityWindow tests that check-adr-alignment only reports an opposing
+// keyword as a conflict within the proximity window set on the server
+func TestADRProximityWindow(t *testing.T) {
+	adr := "# ADR-003: API Style\n\n## Status\nAccepted\n\n## Decision\nAvoid coupling clients to server internals. " +
+		strings.Repeat("Clients are versioned independently of the backend. ", 4) + "GraphQL is out of scope for now."
+
+	tests := []struct {
+		name         string
+		window       int
+		wantConflict bool
+	}{
+		{name: "default window", wantConflict: false},
+		{name: "wider window", window: 300, wantConflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEnv(t)
+			docs := standardTestDocs(env)
+			docs[filepath.Join(env.adrDir, "003-api-style.md")] = adr
+			env.writeTestDocs(t, docs)
+			env.initServer(t)
+
+			if err := env.server.SetADRProximityWindow(0); err == nil {
+				t.Error("Expected an error for a proximity window below 1")
+			}
+			if tt.window > 0 {
+				if err := env.server.SetADRProximityWindow(tt.window); err != nil {
+					t.Fatalf("SetADRProximityWindow failed: %v", err)
+				}
+			}
+			if err := env.server.initializeToolsSystem(); err != nil {
+				t.Fatalf("Failed to initialize tools system: %v", err)
+			}
+
+			response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "alignment",
+				Method:  "tools/call",
+				Params: models.MCPToolsCallParams{
+					Name:      "check-adr-alignment",
+					Arguments: map[string]interface{}{"decision_description": "Adopt GraphQL"},
+				},
+			})
+			validateMCPResponse(t, response, false)
+
+			var alignment struct {
+				Conflicts []struct {
+					ADRURI string `json:"adr_uri"`
+				} `json:"conflicts"`
+			}
+			text := response.Result.(models.MCPToolsCallResult).Content[0].Text
+			if err := json.Unmarshal([]byte(text), &alignment); err != nil {
+				t.Fatalf("Failed to parse alignment result: %v", err)
+			}
+			conflict := false
+			for _, c := range alignment.Conflicts {
+				conflict = conflict || c.ADRURI == "architecture://adr/003-api-style"
+			}
+			if conflict != tt.wantConflict {
+				t.Errorf("Expected conflict %v for ADR-003, got %s", tt.wantConflict, text)
+			}
+		})
+	}
+}
+
+// TestADRMinConfidence tests that check-adr-alignment reports ADRs below the minimum
+// confidence set on the server as related
+func TestADRMinConfidence(t *testing.T) {
+	adr := "# ADR-003: Event Driven Messaging\n\n## Status\nAccepted\n\n## Decision\n" +
+		"We recommend asynchronous event driven messaging between services using a message broker."
+
+	tests := []struct {
+		name          string
+		confidence    float64
+		wantAlignment string
+	}{
+		{name: "default minimum", confidence: tools.DefaultMinAlignmentConfidence, wantAlignment: "supports"},
+		{name: "minimum above every match", confidence: 1, wantAlignment: "related"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEnv(t)
+			docs := standardTestDocs(env)
+			docs[filepath.Join(env.adrDir, "003-event-driven-messaging.md")] = adr
+			env.writeTestDocs(t, docs)
+			env.initServer(t)
+
+			if err := env.server.SetADRMinConfidence(1.5); err == nil {
+				t.Error("Expected an error for a minimum confidence above 1")
+			}
+			if err := env.server.SetADRMinConfidence(tt.confidence); err != nil {
+				t.Fatalf("SetADRMinConfidence failed: %v", err)
+			}
+			if err := env.server.initializeToolsSystem(); err != nil {
+				t.Fatalf("Failed to initialize tools system: %v", err)
+			}
+
+			response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "alignment",
+				Method:  "tools/call",
+				Params: models.MCPToolsCallParams{
+					Name: "check-adr-alignment",
+					Arguments: map[string]interface{}{
+						"decision_description": "Use asynchronous event driven messaging between services with a message broker",
+					},
+				},
+			})
+			validateMCPResponse(t, response, false)
+
+			var alignment struct {
+				RelatedADRs []struct {
+					ADRID     string `json:"adr_id"`
+					Alignment string `json:"alignment"`
+				} `json:"related_adrs"`
+			}
+			text := response.Result.(models.MCPToolsCallResult).Content[0].Text
+			if err := json.Unmarshal([]byte(text), &alignment); err != nil {
+				t.Fatalf("Failed to parse alignment result: %v", err)
+			}
+			got := ""
+			for _, related := range alignment.RelatedADRs {
+				if related.ADRID == "003" {
+					got = related.Alignment
+				}
+			}
+			if got != tt.wantAlignment {
+				t.Errorf("Expected ADR-003 alignment %q, got %q in %s", tt.wantAlignment, got, text)
+			}
+		})
+	}
+}
+
+// Test: Configured Stop-Word Language Reaches The Search Tool
+func TestStopWordLanguage(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, map[string]string{
+		filepath.Join(env.guidelinesDir, "datenbank.md"): "# Datenbank\n\nJeder Dienst besitzt seine eigene Datenbank.",
+		filepath.Join(env.guidelinesDir, "teams.md"):     "# Teams\n\nDie Teams planen die Arbeit und die Releases.",
+	})
+	env.initServer(t)
+
+	if err := env.server.SetStopWordLanguage("xx"); err == nil {
+		t.Error("Expected an error for a language without stop words")
+	}
+	if err := env.server.SetStopWordLanguage(""); err != nil || env.server.stopWords != nil {
+		t.Errorf("Expected an empty language to keep the tools' defaults, got %v", err)
+	}
+	if err := env.server.SetStopWordLanguage("de"); err != nil {
+		t.Fatalf("SetStopWordLanguage failed: %v", err)
+	}
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "search",
+		Method:  "tools/call",
+		Params: models.MCPToolsCallParams{
+			Name:      "search-architecture",
+			Arguments: map[string]interface{}{"query": "die Datenbank"},
+		},
+	})
+	validateMCPResponse(t, response, false)
+
+	var search struct {
+		Results []struct {
+			URI string `json:"uri"`
+		} `json:"results"`
+	}
+	text := response.Result.(models.MCPToolsCallResult).Content[0].Text
+	if err := json.Unmarshal([]byte(text), &search); err != nil {
+		t.Fatalf("Failed to parse search result: %v", err)
+	}
+	if len(search.Results) != 1 || search.Results[0].URI != "architecture://guidelines/datenbank" {
+		t.Errorf("Expected only the Datenbank guideline once \"die\" is a stop word, got %s", text)
+	}
+}
+
+// TestSearchQueryLength tests that blank and too-short search queries are rejected
+// with invalid params, and that the minimum length is configurable
+func TestSearchQueryLength(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	if err := env.server.SetMinQueryLength(0); err == nil {
+		t.Error("Expected an error for a minimum query length below 1")
+	}
+	if err := env.server.SetMinQueryLength(3); err != nil {
+		t.Fatalf("SetMinQueryLength failed: %v", err)
+	}
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantError string
+	}{
+		{name: "empty query", query: "", wantError: "query must not be empty or whitespace"},
+		{name: "whitespace-only query", query: "  \t ", wantError: "query must not be empty or whitespace"},
+		{name: "single character", query: " a ", wantError: "query must be at least 3 characters, got 1"},
+		{name: "below configured minimum", query: "db", wantError: "query must be at least 3 characters, got 2"},
+		{name: "configured minimum", query: "api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "search",
+				Method:  "tools/call",
+				Params: models.MCPToolsCallParams{
+					Name:      "search-architecture",
+					Arguments: map[string]interface{}{"query": tt.query},
+				},
+			})
+
+			if tt.wantError == "" {
+				validateMCPResponse(t, response, false)
+				return
+			}
+
+			validateMCPResponse(t, response, true)
+			if response.Error.Code != -32602 {
+				t.Errorf("Expected error code -32602, got %d", response.Error.Code)
+			}
+			if response.Error.Message != tt.wantError {
+				t.Errorf("Expected error message %q, got %q", tt.wantError, response.Error.Message)
+			}
+		})
+	}
+}
+
+// TestToolAuditLog tests that tools/call writes an audit record under the server's
+// session when an audit log is configured
+func TestToolAuditLog(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	var auditLog bytes.Buffer
+	env.server.SetAuditLog(&auditLog)
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "audited",
+		Method:  "tools/call",
+		Params: models.MCPToolsCallParams{
+			Name:      "search-architecture",
+			Arguments: map[string]interface{}{"query": "api design"},
+		},
+	})
+	validateMCPResponse(t, response, false)
+
+	var record tools.AuditRecord
+	if err := json.Unmarshal(auditLog.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a single JSON audit record, got %q: %v", auditLog.String(), err)
+	}
+	if record.Tool != "search-architecture" || !record.Success || record.Arguments["query"] != "api design" {
+		t.Errorf("Unexpected audit record: %+v", record)
+	}
+	if record.SessionID == "" || record.SessionID != env.server.sessionID {
+		t.Errorf("Expected the server session ID %q, got %q", env.server.sessionID, record.SessionID)
+	}
+}
+
+// snapshotTree records the size and modification time of every entry under root
+func snapshotTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	snapshot := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		snapshot[path] = fmt.Sprintf("%d %s", info.Size(), info.ModTime().Format(time.RFC3339Nano))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to snapshot %s: %v", root, err)
+	}
+	return snapshot
+}
+
+// Test: Read-Only Docs Mode Leaves The Docs Root Untouched
+func TestReadOnlyDocsNoWrites(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	outputDir := t.TempDir()
+	env.server.SetReadOnlyDocs(true)
+	env.server.SetOutputDir(outputDir)
+	if err := env.server.validateOutputDir(); err != nil {
+		t.Fatalf("Output directory outside the docs root rejected: %v", err)
+	}
+
+	auditLog, err := env.server.OpenOutputFile("audit.log")
+	if err != nil {
+		t.Fatalf("Failed to open audit log in the output directory: %v", err)
+	}
+	defer auditLog.Close()
+	env.server.SetAuditLog(auditLog)
+
+	docsRoot := filepath.Join(env.tempDir, "mcp")
+	before := snapshotTree(t, docsRoot)
+
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+	requests := []*models.MCPMessage{
+		{JSONRPC: "2.0", ID: "list", Method: "resources/list"},
+		{JSONRPC: "2.0", ID: "read", Method: "resources/read", Params: models.MCPResourcesReadParams{URI: "architecture://guidelines/api-design"}},
+		{JSONRPC: "2.0", ID: "tools", Method: "tools/list"},
+		{JSONRPC: "2.0", ID: "search", Method: "tools/call", Params: models.MCPToolsCallParams{
+			Name: "search-architecture", Arguments: map[string]interface{}{"query": "repository"},
+		}},
+		{JSONRPC: "2.0", ID: "stats", Method: "server/cache-stats"},
+		{JSONRPC: "2.0", ID: "cleanup", Method: "server/cache-cleanup"},
+	}
+	for _, request := range requests {
+		if response := env.server.routeMessage(request); response.Error != nil {
+			t.Fatalf("%s failed: %+v", request.Method, response.Error)
+		}
+	}
+
+	after := snapshotTree(t, docsRoot)
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("Docs root changed during normal operation:\nbefore %v\nafter  %v", before, after)
+	}
+	if info, err := os.Stat(filepath.Join(outputDir, "audit.log")); err != nil || info.Size() == 0 {
+		t.Errorf("Expected the audit log to be written to the output directory: %v", err)
+	}
+}
+
+// Test: Read-Only Docs Mode Refuses Output Inside The Docs Root
+func TestReadOnlyDocsRefusesDocsRootWrites(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	if err := os.MkdirAll(filepath.Join(env.tempDir, config.PromptsBasePath), 0755); err != nil {
+		t.Fatalf("Failed to create prompts directory: %v", err)
+	}
+	if err := os.Symlink(env.guidelinesDir, filepath.Join(env.tempDir, "docs-link")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	outsideDir := t.TempDir()
+
+	tests := []struct {
+		name      string
+		readOnly  bool
+		outputDir string
+		file      string
+		wantErr   bool
+	}{
+		{name: "resources root", readOnly: true, file: filepath.Join(config.GuidelinesPath, "audit.log"), wantErr: true},
+		{name: "prompts root", readOnly: true, file: filepath.Join(config.PromptsBasePath, "audit.log"), wantErr: true},
+		{name: "missing subdirectory", readOnly: true, file: filepath.Join(config.ADRPath, "logs", "audit.log"), wantErr: true},
+		{name: "absolute path", readOnly: true, file: filepath.Join(env.patternsDir, "audit.log"), wantErr: true},
+		{name: "through symlink", readOnly: true, file: filepath.Join("docs-link", "audit.log"), wantErr: true},
+		{name: "output dir in docs root", readOnly: true, outputDir: config.ResourcesBasePath, file: "audit.log", wantErr: true},
+		{name: "sibling of docs root", readOnly: true, file: filepath.Join("mcp", "audit.log")},
+		{name: "outside output dir", readOnly: true, outputDir: outsideDir, file: "audit.log"},
+		{name: "safe mode off", readOnly: false, file: filepath.Join(config.GuidelinesPath, "audit.log")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.server.SetReadOnlyDocs(tt.readOnly)
+			env.server.SetOutputDir(tt.outputDir)
+
+			file, err := env.server.OpenOutputFile(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenOutputFile(%q) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "read-only docs mode") {
+					t.Errorf("Expected a read-only docs mode error, got %v", err)
+				}
+				if _, statErr := os.Stat(filepath.Join(tt.outputDir, tt.file)); !os.IsNotExist(statErr) {
+					t.Errorf("Refused file %s was created", tt.file)
+				}
+				return
+			}
+			file.Close()
+			os.Remove(file.Name())
+		})
+	}
+
+	t.Run("start fails fast", func(t *testing.T) {
+		env.server.SetReadOnlyDocs(true)
+		env.server.SetOutputDir(config.PromptsBasePath)
+		if err := env.server.validateOutputDir(); err == nil {
+			t.Error("Expected an output directory inside the docs root to be rejected")
+		}
+	})
+}
+
+// Test: Related Resources In resources/read
+func TestResourcesReadRelatedResources(t *testing.T) {
+	env := setupTestEnv(t)
+	docs := standardTestDocs(env)
+	docs[filepath.Join(env.patternsDir, "unit-of-work.md")] = "# Unit of Work\n\nTracks changes and commits them through each repository. " +
+		"Pairs with the repository pattern and its repository interfaces."
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	const sourceURI = "architecture://patterns/repository-pattern"
+	read := func(uri string) models.MCPResourcesReadResult {
+		t.Helper()
+		response := env.server.handleResourcesRead(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "read",
+			Method:  "resources/read",
+			Params:  models.MCPResourcesReadParams{URI: uri},
+		})
+		validateMCPResponse(t, response, false)
+		return response.Result.(models.MCPResourcesReadResult)
+	}
+
+	tests := []struct {
+		name    string
+		count   int
+		wantMax int
+	}{
+		{name: "default", count: DefaultRelatedResources, wantMax: DefaultRelatedResources},
+		{name: "configured", count: 1, wantMax: 1},
+		{name: "disabled", count: 0, wantMax: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.server.SetRelatedResources(tt.count)
+			related := read(sourceURI).RelatedResources
+
+			if len(related) > tt.wantMax {
+				t.Errorf("Expected at most %d related resources, got %v", tt.wantMax, related)
+			}
+			if tt.wantMax == 0 {
+				return
+			}
+			if len(related) == 0 || related[0] != "architecture://patterns/unit-of-work" {
+				t.Errorf("Expected unit-of-work as the most related resource, got %v", related)
+			}
+			for _, uri := range related {
+				if uri == sourceURI {
+					t.Error("Related resources must not include the document read")
+				}
+				// Related URIs are readable resources
+				read(uri)
+			}
+		})
+	}
+}
+
+// Test: Server Capabilities Introspection
+func TestServerCapabilities(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	env.initServer(t)
+
+	env.server.SetCategoryExtensions(config.CategoryADR, []string{"md", ".markdown"})
+	env.server.SetMaxListedPrompts(20)
+	if err := env.server.SetMinQueryLength(3); err != nil {
+		t.Fatalf("SetMinQueryLength failed: %v", err)
+	}
+	if err := env.server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	response := env.server.routeMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "caps", Method: "server/capabilities"})
+	validateMCPResponse(t, response, false)
+	result := response.Result.(map[string]interface{})
+
+	t.Run("Tools", func(t *testing.T) {
+		var registered []string
+		for _, tool := range env.server.toolManager.ListTools() {
+			registered = append(registered, tool.Name)
+		}
+		if !reflect.DeepEqual(result["tools"], registered) {
+			t.Errorf("Expected tools %v, got %v", registered, result["tools"])
+		}
+		if !strings.Contains(strings.Join(registered, ","), "search-architecture") {
+			t.Errorf("Expected search-architecture among registered tools, got %v", registered)
+		}
+	})
+
+	t.Run("Categories", func(t *testing.T) {
+		categories := result["categories"].([]map[string]interface{})
+		extensions := make(map[string][]string)
+		for _, category := range categories {
+			extensions[category["name"].(string)] = category["extensions"].([]string)
+		}
+		if len(extensions) != 3 {
+			t.Fatalf("Expected guideline, pattern and adr categories, got %v", categories)
+		}
+		if !reflect.DeepEqual(extensions[config.CategoryADR], []string{".markdown", ".md"}) {
+			t.Errorf("Expected the configured ADR extensions, got %v", extensions[config.CategoryADR])
+		}
+		if !strings.Contains(strings.Join(extensions[config.CategoryGuideline], ","), ".yaml") {
+			t.Errorf("Expected guidelines to load every supported extension, got %v", extensions[config.CategoryGuideline])
+		}
+	})
+
+	t.Run("ProtocolAndLimits", func(t *testing.T) {
+		if !reflect.DeepEqual(result["protocolVersions"], []string{protocolVersion}) {
+			t.Errorf("Unexpected protocol versions %v", result["protocolVersions"])
+		}
+		limits := result["limits"].(map[string]interface{})
+		if limits["minQueryLength"] != 3 || limits["maxListedPrompts"] != 20 || limits["maxFileSize"] != int64(scanner.DefaultMaxFileSize) {
+			t.Errorf("Expected the configured limits, got %v", limits)
+		}
+	})
+}
+
+func TestReloadChanged(t *testing.T) {
+	promptDefinition, err := os.ReadFile(filepath.Join("..", "..", "mcp", "prompts", "create-adr.json"))
+	if err != nil {
+		t.Fatalf("Failed to read prompt definition: %v", err)
+	}
+
+	env := setupTestEnv(t)
+	docs := standardTestDocs(env)
+	env.writeTestDocs(t, docs)
+	env.initServer(t)
+
+	guidelinePath := filepath.Join(env.guidelinesDir, "api-design.md")
+	env.writeTestDocs(t, map[string]string{
+		guidelinePath: docs[guidelinePath] + "\n## Versioning\n- Version APIs in the URL path\n",
+		filepath.Join(env.patternsDir, "circuit-breaker.md"): "# Circuit Breaker\n\nStop calling a failing dependency.\n",
+	})
+	if err := os.Remove(filepath.Join(env.adrDir, "001-microservices-architecture.md")); err != nil {
+		t.Fatalf("Failed to remove ADR: %v", err)
+	}
+
+	promptsDir := filepath.Join(env.tempDir, "mcp", "prompts")
+	if err := os.MkdirAll(promptsDir, 0755); err != nil {
+		t.Fatalf("Failed to create prompts dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(promptsDir, "create-adr.json"), promptDefinition, 0644); err != nil {
+		t.Fatalf("Failed to write prompt: %v", err)
+	}
+
+	summary, err := env.server.ReloadChanged(env.ctx)
+	if err != nil {
+		t.Fatalf("ReloadChanged failed: %v", err)
+	}
+	if summary.Added != 1 || summary.Updated != 1 || summary.Removed != 1 || len(summary.Failed) != 0 {
+		t.Errorf("Expected 1 added, 1 updated and 1 removed, got %+v", summary)
+	}
+
+	read := func(uri string) *models.MCPMessage {
+		return env.server.routeMessage(&models.MCPMessage{
+			JSONRPC: "2.0", ID: uri, Method: "resources/read", Params: models.MCPResourcesReadParams{URI: uri},
+		})
+	}
+	if response := read("architecture://patterns/circuit-breaker"); response.Error != nil {
+		t.Errorf("Expected the added document to be readable, got %+v", response.Error)
+	}
+	if response := read("architecture://adr/001-microservices-architecture"); response.Error == nil {
+		t.Error("Expected the removed document to be gone")
+	}
+	response := read("architecture://guidelines/api-design")
+	if response.Error != nil {
+		t.Fatalf("Expected the updated document to be readable, got %+v", response.Error)
+	}
+	result := response.Result.(models.MCPResourcesReadResult)
+	if !strings.Contains(result.Contents[0].Text, "Versioning") {
+		t.Error("Expected the updated document to have its new content")
+	}
+	if count := env.server.cache.GetIndex(config.CategoryADR).Count; count != 0 {
+		t.Errorf("Expected the ADR index to be rebuilt without the removed document, got %d", count)
+	}
+
+	// Nothing changed since, so a second reload leaves the cache alone
+	summary, err = env.server.ReloadChanged(env.ctx)
+	if err != nil {
+		t.Fatalf("Second ReloadChanged failed: %v", err)
+	}
+	if summary.Added != 0 || summary.Updated != 0 || summary.Removed != 0 {
+		t.Errorf("Expected no changes on the second reload, got %+v", summary)
+	}
+
+	// Reload also re-reads the prompt definitions
+	if err := env.server.Reload(env.ctx); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if _, err := env.server.promptManager.GetPrompt("create-adr"); err != nil {
+		t.Errorf("Expected the new prompt to be loaded after Reload: %v", err)
+	}
+}
+
+// TestStaleThresholdDays tests that find-stale-documents uses the threshold set on
+// the server when a call leaves out threshold_days
+func TestStaleThresholdDays(t *testing.T) {
+	tests := []struct {
+		name      string
+		days      int
+		wantStale int
+	}{
+		{name: "default threshold", wantStale: 0},
+		{name: "shorter threshold", days: 30, wantStale: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEnv(t)
+			env.writeTestDocs(t, standardTestDocs(env))
+			old := time.Now().AddDate(0, 0, -60)
+			if err := os.Chtimes(filepath.Join(env.guidelinesDir, "api-design.md"), old, old); err != nil {
+				t.Fatalf("Failed to age test document: %v", err)
+			}
+			env.initServer(t)
+
+			if err := env.server.SetStaleThresholdDays(0); err == nil {
+				t.Error("Expected an error for a stale threshold below 1 day")
+			}
+			if tt.days > 0 {
+				if err := env.server.SetStaleThresholdDays(tt.days); err != nil {
+					t.Fatalf("SetStaleThresholdDays failed: %v", err)
+				}
+			}
+			if err := env.server.initializeToolsSystem(); err != nil {
+				t.Fatalf("Failed to initialize tools system: %v", err)
+			}
+
+			response := env.server.handleToolsCall(context.Background(), &models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "stale",
+				Method:  "tools/call",
+				Params: models.MCPToolsCallParams{
+					Name:      "find-stale-documents",
+					Arguments: map[string]interface{}{},
+				},
+			})
+			validateMCPResponse(t, response, false)
+
+			var result struct {
+				StaleCount int `json:"stale_count"`
+			}
+			text := response.Result.(models.MCPToolsCallResult).Content[0].Text
+			if err := json.Unmarshal([]byte(text), &result); err != nil {
+				t.Fatalf("Failed to parse stale documents result: %v", err)
+			}
+			if result.StaleCount != tt.wantStale {
+				t.Errorf("Expected %d stale documents, got %s", tt.wantStale, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// cancellableRequests tracks the contexts of requests being handled so
+// notifications/cancelled can abort them
+type cancellableRequests struct {
+	mu       sync.Mutex
+	requests map[string]*cancellableRequest // request ID key -> request
+}
+
+// cancellableRequest is a request in flight that may be cancelled
+type cancellableRequest struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// newCancellableRequests creates an empty cancellableRequests
+func newCancellableRequests() *cancellableRequests {
+	return &cancellableRequests{requests: make(map[string]*cancellableRequest)}
+}
+
+// requestKey identifies a request ID, keeping the string "1" apart from the number 1
+func requestKey(id interface{}) string {
+	key, _ := json.Marshal(id)
+	return string(key)
+}
+
+// begin registers a request and returns its context, along with the function that
+// ends it and reports whether it was cancelled meanwhile
+func (cr *cancellableRequests) begin(id interface{}) (context.Context, func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	request := &cancellableRequest{cancel: cancel}
+	key := requestKey(id)
+
+	cr.mu.Lock()
+	cr.requests[key] = request
+	cr.mu.Unlock()
+
+	return ctx, func() bool {
+		cancel()
+
+		cr.mu.Lock()
+		defer cr.mu.Unlock()
+
+		// A later request reusing the ID may have replaced this one
+		if cr.requests[key] == request {
+			delete(cr.requests, key)
+		}
+		return request.cancelled
+	}
+}
+
+// cancel cancels the context of the request with the given ID, returning false if
+// no such request is in flight
+func (cr *cancellableRequests) cancel(id interface{}) bool {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	request, ok := cr.requests[requestKey(id)]
+	if !ok {
+		return false
+	}
+	request.cancelled = true
+	request.cancel()
+	return true
+}
+
+// handleNotification dispatches a notification. Notifications are never answered,
+// so unknown ones are logged and ignored rather than rejected.
+func (s *MCPServer) handleNotification(message *models.MCPMessage) *models.MCPMessage {
+	switch message.Method {
+	case "notifications/initialized":
+		return s.handleInitialized(message)
+	case "notifications/cancelled":
+		s.handleCancelled(message)
+	default:
+		s.logger.WithContext("method", message.Method).Debug("Ignored unknown notification")
+	}
+	return nil
+}
+
+// handleCancelled handles the notifications/cancelled method by cancelling the
+// context of the in-flight request it names. The request's response is dropped.
+func (s *MCPServer) handleCancelled(message *models.MCPMessage) {
+	var params models.MCPCancelledParams
+	if message.Params != nil {
+		paramsBytes, err := json.Marshal(message.Params)
+		if err == nil {
+			err = json.Unmarshal(paramsBytes, &params)
+		}
+		if err != nil {
+			s.logger.WithError(err).Warn("Ignored cancellation with invalid parameters")
+			return
+		}
+	}
+	if params.RequestID == nil {
+		s.logger.Warn("Ignored cancellation without a requestId")
+		return
+	}
+
+	logger := s.logger.WithContext("request_id", params.RequestID).
+		WithContext("reason", params.Reason)
+	if s.cancellable.cancel(params.RequestID) {
+		logger.Info("Cancelled in-flight request")
+	} else {
+		logger.Debug("Ignored cancellation for a request not in flight")
+	}
+}
+
+// notifyPromptsListChanged tells the client the prompts changed on disk and were
+// reloaded, so it should list them again
+func (s *MCPServer) notifyPromptsListChanged() {
+	s.sendNotification("notifications/prompts/list_changed", nil)
+}
+
+// sendNotification sends a notification to the client. It is dropped when no
+// client is connected or the client has not finished initializing.
+func (s *MCPServer) sendNotification(method string, params interface{}) {
+	s.mu.RLock()
+	notify, initialized := s.notify, s.initialized
+	s.mu.RUnlock()
+
+	if notify == nil || !initialized {
+		s.logger.WithContext("method", method).Debug("Dropped notification, no initialized client")
+		return
+	}
+	notify(&models.MCPMessage{JSONRPC: "2.0", Method: method, Params: params})
+	s.logger.WithContext("method", method).Debug("Sent notification")
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// inFlightCount returns how many requests cr is tracking
+func inFlightCount(cr *cancellableRequests) int {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return len(cr.requests)
+}
+
+func TestCancellableRequests_CancelByID(t *testing.T) {
+	cr := newCancellableRequests()
+
+	ctx, end := cr.begin(float64(1))
+	if !cr.cancel(float64(1)) {
+		t.Fatal("Expected the in-flight request to be cancelled")
+	}
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("Expected the request context to be cancelled")
+	}
+	if !end() {
+		t.Error("Expected end to report the cancellation")
+	}
+
+	if n := inFlightCount(cr); n != 0 {
+		t.Errorf("Expected no tracked requests after end, got %d", n)
+	}
+	if cr.cancel(float64(1)) {
+		t.Error("Expected cancelling a finished request to report false")
+	}
+}
+
+func TestCancellableRequests_EndWithoutCancel(t *testing.T) {
+	cr := newCancellableRequests()
+
+	ctx, end := cr.begin("req-1")
+	if end() {
+		t.Error("Expected end to report no cancellation")
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected the request context to be released on end")
+	}
+	if n := inFlightCount(cr); n != 0 {
+		t.Errorf("Expected no tracked requests after end, got %d", n)
+	}
+}
+
+func TestCancellableRequests_IDTypesAreDistinct(t *testing.T) {
+	cr := newCancellableRequests()
+
+	_, end := cr.begin("1")
+	defer end()
+
+	if cr.cancel(float64(1)) {
+		t.Error("Cancelling numeric ID 1 must not cancel string ID \"1\"")
+	}
+	if !cr.cancel("1") {
+		t.Error("Expected string ID \"1\" to be cancelled")
+	}
+}
+
+func TestCancellableRequests_ReusedID(t *testing.T) {
+	cr := newCancellableRequests()
+
+	firstCtx, endFirst := cr.begin("dup")
+	secondCtx, endSecond := cr.begin("dup")
+
+	// Ending the first request must not drop the one that reused its ID
+	endFirst()
+	if !cr.cancel("dup") {
+		t.Fatal("Expected the request reusing the ID to still be tracked")
+	}
+	if secondCtx.Err() == nil {
+		t.Error("Expected the latest request with the ID to be cancelled")
+	}
+	if !endSecond() {
+		t.Error("Expected end to report the cancellation")
+	}
+	if firstCtx.Err() != context.Canceled {
+		t.Errorf("Expected the first request context released on end, got %v", firstCtx.Err())
+	}
+	if n := inFlightCount(cr); n != 0 {
+		t.Errorf("Expected no tracked requests, got %d", n)
+	}
+}
+
+func TestHandleMessage_CancelledNotification(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	if err := server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+	tool := &blockingTool{started: make(chan struct{}), stopped: make(chan error, 1)}
+	if err := server.toolManager.RegisterTool(tool); err != nil {
+		t.Fatalf("Failed to register blocking tool: %v", err)
+	}
+
+	responses := make(chan *models.MCPMessage, 1)
+	go func() {
+		responses <- server.HandleMessage(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "long-request",
+			Method:  "tools/call",
+			Params:  models.MCPToolsCallParams{Name: "blocking-tool", Arguments: map[string]interface{}{}},
+		})
+	}()
+
+	select {
+	case <-tool.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Long request did not start")
+	}
+	if n := inFlightCount(server.cancellable); n != 1 {
+		t.Fatalf("Expected the long request to be tracked, got %d tracked", n)
+	}
+
+	notification := server.HandleMessage(&models.MCPMessage{
+		JSONRPC: "2.0",
+		Method:  "notifications/cancelled",
+		Params:  map[string]interface{}{"requestId": "long-request"},
+	})
+	if notification != nil {
+		t.Errorf("Expected no response to the notification, got %+v", notification)
+	}
+
+	select {
+	case response := <-responses:
+		if response != nil {
+			t.Errorf("Expected no response to the cancelled request, got %+v", response)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Cancelled request did not finish")
+	}
+	if n := inFlightCount(server.cancellable); n != 0 {
+		t.Errorf("Expected no tracked requests after cancellation, got %d", n)
+	}
+}
+
+func TestPromptFileChangeSendsListChangedNotification(t *testing.T) {
+	server := newMCPServerWithOptions(true)
+	if server.monitor == nil {
+		t.Skip("File system monitor unavailable")
+	}
+	t.Cleanup(func() { server.monitor.StopWatching() })
+	if !server.capabilities.Prompts.ListChanged {
+		t.Error("Expected prompts listChanged to be advertised with a file monitor")
+	}
+
+	promptsDir := t.TempDir()
+	if err := server.SetPromptDirectories([]string{promptsDir}); err != nil {
+		t.Fatalf("SetPromptDirectories() unexpected error: %v", err)
+	}
+	if err := server.initializePromptsSystem(); err != nil {
+		t.Fatalf("Failed to initialize prompts system: %v", err)
+	}
+
+	input, inputWriter := io.Pipe()
+	output, outputWriter := io.Pipe()
+	processed := make(chan error, 1)
+	go func() {
+		processed <- server.processMessages(context.Background(), input, outputWriter)
+	}()
+	messages := make(chan models.MCPMessage, 10)
+	go func() {
+		decoder := json.NewDecoder(output)
+		for {
+			var message models.MCPMessage
+			if decoder.Decode(&message) != nil {
+				close(messages)
+				return
+			}
+			messages <- message
+		}
+	}()
+	t.Cleanup(func() {
+		inputWriter.Close()
+		<-processed
+		outputWriter.Close()
+	})
+
+	next := func() models.MCPMessage {
+		t.Helper()
+		select {
+		case message := <-messages:
+			return message
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a message from the server")
+			return models.MCPMessage{}
+		}
+	}
+
+	// The list response shows the initialized notification, handled in order, took effect
+	inputWriter.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"))
+	inputWriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}` + "\n"))
+	if response := next(); response.ID != float64(1) {
+		t.Fatalf("Expected the prompts/list response, got %+v", response)
+	}
+
+	// An invalid file is reported and skipped without stopping the watcher
+	if err := os.WriteFile(filepath.Join(promptsDir, "broken.json"), []byte(`{"name": `), 0644); err != nil {
+		t.Fatalf("Failed to write prompt file: %v", err)
+	}
+	prompt := `{"name": "new-prompt", "messages": [{"role": "user", "content": {"type": "text", "text": "Hello"}}]}`
+	if err := os.WriteFile(filepath.Join(promptsDir, "new-prompt.json"), []byte(prompt), 0644); err != nil {
+		t.Fatalf("Failed to write prompt file: %v", err)
+	}
+
+	for {
+		notification := next()
+		if notification.Method != "notifications/prompts/list_changed" || notification.ID != nil {
+			t.Fatalf("Expected a prompts list_changed notification, got %+v", notification)
+		}
+		if _, err := server.promptManager.GetPrompt("new-prompt"); err == nil {
+			break
+		}
+	}
+}
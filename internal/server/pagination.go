@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix marks list cursors issued by this server
+const cursorPrefix = "offset:"
+
+// SetMaxListedResources caps how many resources one resources/list response
+// advertises. The rest are returned on later pages through nextCursor, and every
+// resource can still be read directly. Zero or less lists everything at once.
+func (s *MCPServer) SetMaxListedResources(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	s.maxListedResources = limit
+}
+
+// SetMaxListedPrompts caps how many prompts one prompts/list response advertises.
+// The rest are returned on later pages through nextCursor, and every prompt can
+// still be fetched directly with prompts/get. Zero or less lists everything at once.
+func (s *MCPServer) SetMaxListedPrompts(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	s.maxListedPrompts = limit
+}
+
+// pageBounds returns the slice bounds of the page of total items starting at
+// cursor and holding at most limit items (all remaining items for a zero limit),
+// and the cursor of the following page, empty on the last page
+func pageBounds(total int, cursor string, limit int) (start, end int, nextCursor string, err error) {
+	if cursor != "" {
+		if start, err = decodeCursor(cursor); err != nil {
+			return 0, 0, "", err
+		}
+		if start > total {
+			start = total
+		}
+	}
+
+	end = total
+	if limit > 0 && start+limit < total {
+		end = start + limit
+		nextCursor = encodeCursor(end)
+	}
+	return start, end, nextCursor, nil
+}
+
+// encodeCursor returns the opaque cursor for the page starting at offset
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset a cursor from encodeCursor points at
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return offset, nil
+}
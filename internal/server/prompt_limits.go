@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"mcp-architecture-service/pkg/errors"
+)
+
+// Default limits on the arguments one prompts/get or prompts/preview request passes
+const (
+	DefaultMaxPromptArguments     = 64
+	DefaultMaxPromptArgumentsSize = 256 * 1024 // bytes of JSON
+)
+
+// DefaultMaxBatchPrompts is how many prompts one prompts/get-batch request may render
+const DefaultMaxBatchPrompts = 16
+
+// SetPromptArgumentLimits caps how many arguments a prompts/get or prompts/preview
+// request may pass and their total size in bytes, serialized as JSON. Requests over
+// either limit are rejected as invalid params before the prompt is rendered. Zero
+// or less disables a limit.
+func (s *MCPServer) SetPromptArgumentLimits(maxCount, maxSize int) {
+	if maxCount < 0 {
+		maxCount = 0
+	}
+	if maxSize < 0 {
+		maxSize = 0
+	}
+	s.maxPromptArguments = maxCount
+	s.maxPromptArgumentsSize = maxSize
+}
+
+// SetMaxBatchPrompts caps how many prompts one prompts/get-batch request may render;
+// larger batches are rejected as invalid params. Zero or less disables the limit.
+func (s *MCPServer) SetMaxBatchPrompts(count int) {
+	if count < 0 {
+		count = 0
+	}
+	s.maxBatchPrompts = count
+}
+
+// checkPromptArguments returns a validation error if arguments exceed the prompt
+// argument limits
+func (s *MCPServer) checkPromptArguments(promptName string, arguments map[string]interface{}) *errors.StructuredError {
+	if s.maxPromptArguments > 0 && len(arguments) > s.maxPromptArguments {
+		return errors.NewValidationError(errors.ErrCodeInvalidParams,
+			fmt.Sprintf("Too many prompt arguments: %d exceeds the maximum of %d", len(arguments), s.maxPromptArguments), nil).
+			WithContext("prompt_name", promptName)
+	}
+
+	if s.maxPromptArgumentsSize > 0 && len(arguments) > 0 {
+		encoded, err := json.Marshal(arguments)
+		if err != nil {
+			return errors.NewValidationError(errors.ErrCodeInvalidParams,
+				"Invalid prompt arguments", err).WithContext("prompt_name", promptName)
+		}
+		if len(encoded) > s.maxPromptArgumentsSize {
+			return errors.NewValidationError(errors.ErrCodeInvalidParams,
+				fmt.Sprintf("Prompt arguments too large: %d bytes exceeds the maximum of %d", len(encoded), s.maxPromptArgumentsSize), nil).
+				WithContext("prompt_name", promptName)
+		}
+	}
+
+	return nil
+}
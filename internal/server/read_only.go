@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/scanner"
+)
+
+// SetReadOnlyDocs turns on read-only docs mode for deployments where the
+// documentation is a read-only mount. The server never writes into the docs roots
+// (config.ResourcesBasePath and config.PromptsBasePath) during normal operation; in
+// this mode any attempt to open a file there for writing fails immediately instead
+// of surfacing later as a filesystem error. Off by default. Must be called before
+// Start.
+func (s *MCPServer) SetReadOnlyDocs(enabled bool) {
+	s.readOnlyDocs = enabled
+}
+
+// SetOutputDir sets the directory relative paths passed to OpenOutputFile are
+// resolved against, such as the audit log. Empty uses the working directory. Must
+// be called before Start.
+func (s *MCPServer) SetOutputDir(dir string) {
+	s.outputDir = dir
+}
+
+// OpenOutputFile opens name for appending, creating it if needed, for files the
+// server writes such as the audit log. Relative names are resolved against the
+// output directory. In read-only docs mode a file inside the docs roots is refused.
+func (s *MCPServer) OpenOutputFile(name string) (*os.File, error) {
+	path := name
+	if !filepath.IsAbs(path) && s.outputDir != "" {
+		path = filepath.Join(s.outputDir, path)
+	}
+	if err := s.checkWritable(path); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+}
+
+// checkWritable returns an error when read-only docs mode is on and path, with
+// symbolic links resolved, is inside one of the docs roots
+func (s *MCPServer) checkWritable(path string) error {
+	if !s.readOnlyDocs {
+		return nil
+	}
+
+	resolved, err := resolveExistingPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path %s: %w", path, err)
+	}
+
+	for _, root := range []string{config.ResourcesBasePath, config.PromptsBasePath} {
+		resolvedRoot, err := resolveExistingPath(root)
+		if err != nil {
+			return fmt.Errorf("failed to resolve documentation root %s: %w", root, err)
+		}
+		if scanner.IsWithinDir(resolved, resolvedRoot) {
+			return fmt.Errorf("read-only docs mode: refusing to write %s inside documentation root %s", path, root)
+		}
+	}
+	return nil
+}
+
+// validateOutputDir returns an error when read-only docs mode is on and the output
+// directory is inside one of the docs roots
+func (s *MCPServer) validateOutputDir() error {
+	if s.outputDir == "" {
+		return nil
+	}
+	return s.checkWritable(s.outputDir)
+}
+
+// resolveExistingPath returns the absolute form of path with symbolic links in its
+// longest existing prefix resolved, so paths that do not exist yet still resolve
+// through a symlinked parent directory
+func resolveExistingPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	existing := absPath
+	var missing []string
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return absPath, nil
+		}
+		missing = append([]string{filepath.Base(existing)}, missing...)
+		existing = parent
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/errors"
+)
+
+// SetReadThrough makes resources/read load a document missing from the cache from
+// the document source, cache it and serve it, so documents the cache has not been
+// warmed with or has evicted are still readable. Only files the initial load would
+// accept are read through. Off by default. Must be called before Start.
+func (s *MCPServer) SetReadThrough(enabled bool) {
+	s.readThrough = enabled
+}
+
+// readThroughDocument loads the document a resource path refers to from the
+// document source into the cache. It returns a not-found error when no candidate
+// file exists.
+func (s *MCPServer) readThroughDocument(ctx context.Context, category, resourcePath string) (*models.Document, error) {
+	var readErr error
+	for _, path := range s.readThroughPaths(category, resourcePath) {
+		if !s.scanner.IsLoadedFile(category, path) {
+			continue
+		}
+
+		content, err := s.documentSource.Read(ctx, path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				readErr = err
+			}
+			continue
+		}
+		if maxSize := s.scanner.MaxFileSize(); maxSize > 0 && int64(len(content)) > maxSize {
+			return nil, fmt.Errorf("file size %d bytes exceeds limit of %d bytes", len(content), maxSize)
+		}
+
+		metadata, err := s.scanner.ParseContent(path, content)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Category = category
+		metadata.LastModified = time.Now()
+		if s.documentsOnDisk() {
+			if info, err := os.Stat(path); err == nil {
+				metadata.LastModified = info.ModTime()
+			}
+		}
+
+		if err := s.cacheDocument(*metadata, content); err != nil {
+			return nil, err
+		}
+		s.updateCategoryIndex(category)
+
+		s.logger.WithContext("path", path).
+			WithContext("category", category).
+			Info("Loaded uncached document on read")
+
+		return s.cache.Get(path)
+	}
+
+	if readErr != nil {
+		return nil, readErr
+	}
+	return nil, errors.NewFileSystemError(errors.ErrCodeFileNotFound,
+		"Resource not found", nil).
+		WithContext("category", category).
+		WithContext("resourcePath", resourcePath)
+}
+
+// readThroughPaths returns the files a resource path may refer to: the markdown
+// candidates resources/read already looks up, and the path itself for resources
+// whose URI keeps the file extension
+func (s *MCPServer) readThroughPaths(category, resourcePath string) []string {
+	paths := s.generatePossibleFilePaths(category, resourcePath)
+	if mimeType, ok := s.scanner.MimeTypeFor(resourcePath); ok && mimeType != config.MimeTypeMarkdown {
+		for _, c := range documentCategories {
+			if c.category == category {
+				paths = append(paths, filepath.Join(c.directory, resourcePath))
+			}
+		}
+	}
+	return paths
+}
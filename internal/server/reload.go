@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
+)
+
+// ReloadSummary describes what a documentation reload changed
+type ReloadSummary struct {
+	Added   int      // Documents found on disk that were not cached
+	Updated int      // Cached documents whose content changed on disk
+	Removed int      // Cached documents no longer listed
+	Failed  []string // Errors for documents that could not be loaded
+}
+
+// documentationDirs returns the directories documentation is loaded from
+func documentationDirs() []string {
+	return []string{
+		config.GuidelinesPath,
+		config.PatternsPath,
+		config.ADRPath,
+	}
+}
+
+// ReloadChanged relists the document source and brings the cache in line with it:
+// new and changed documents are loaded, documents no longer listed are removed and
+// the category indexes are rebuilt. Documents are read before the server lock is
+// taken to swap them in, so requests keep being served from the cache while it
+// runs. Reloads run one at a time.
+func (s *MCPServer) ReloadChanged(ctx context.Context) (ReloadSummary, error) {
+	var summary ReloadSummary
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	indexes, err := s.documentSource.List(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("failed to scan documentation: %w", err)
+	}
+
+	cached := make(map[string]models.DocumentMetadata) // path -> cached metadata
+	for _, doc := range s.cache.GetAllDocuments() {
+		cached[doc.Metadata.Path] = doc.Metadata
+	}
+
+	var changed []models.DocumentMetadata
+	for _, index := range indexes {
+		for _, metadata := range index.Documents {
+			previous, exists := cached[metadata.Path]
+			delete(cached, metadata.Path)
+
+			switch {
+			case !exists:
+				summary.Added++
+			case documentChanged(previous, metadata):
+				summary.Updated++
+			default:
+				continue
+			}
+			changed = append(changed, metadata)
+		}
+	}
+
+	var loadedMu sync.Mutex
+	loaded := make(map[string][]byte, len(changed)) // path -> content read
+	err = s.forEachDocumentConcurrent(ctx, changed, &summary.Failed, func(ctx context.Context, metadata models.DocumentMetadata) error {
+		content, err := s.documentSource.Read(ctx, metadata.Path)
+		if err != nil {
+			return err
+		}
+		loadedMu.Lock()
+		loaded[metadata.Path] = content
+		loadedMu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Whatever is left in cached was not found by the scan
+	for path := range cached {
+		s.cache.Invalidate(path)
+		summary.Removed++
+	}
+
+	for _, metadata := range changed {
+		content, ok := loaded[metadata.Path]
+		if !ok {
+			continue
+		}
+		if err := s.cacheDocument(metadata, content); err != nil {
+			summary.Failed = append(summary.Failed, fmt.Sprintf("failed to load %s: %v", metadata.Path, err))
+			s.logger.WithError(err).Warn("Failed to load document into cache")
+		}
+	}
+
+	for category, index := range indexes {
+		s.cache.SetIndex(category, index)
+	}
+
+	return summary, nil
+}
+
+// Reload reloads changed documentation and re-reads the prompt definitions without
+// interrupting connections, logging what changed. It backs SIGHUP in mcp-server.
+func (s *MCPServer) Reload(ctx context.Context) error {
+	reloadStart := time.Now()
+	reloadLogger := s.loggingManager.GetLogger("reload")
+
+	summary, docsErr := s.ReloadChanged(ctx)
+	if docsErr != nil {
+		reloadLogger.WithError(docsErr).Error("Documentation reload failed")
+	}
+
+	promptsErr := s.promptManager.ReloadPrompts()
+
+	resultLogger := reloadLogger.WithContext("documents_added", summary.Added).
+		WithContext("documents_updated", summary.Updated).
+		WithContext("documents_removed", summary.Removed).
+		WithContext("documents_failed", len(summary.Failed)).
+		WithContext("total_documents", s.cache.Size()).
+		WithContext("duration_ms", time.Since(reloadStart).Milliseconds())
+
+	if docsErr != nil || promptsErr != nil || len(summary.Failed) > 0 {
+		resultLogger.Warn("Reload completed with errors")
+	} else {
+		resultLogger.Info("Reload completed")
+	}
+
+	if docsErr != nil {
+		return docsErr
+	}
+	if promptsErr != nil {
+		return fmt.Errorf("failed to reload prompts: %w", promptsErr)
+	}
+	return nil
+}
+
+// documentChanged reports whether a listed document differs from its cached
+// version. Without checksums, as with scanner.ChecksumNone, a change in size or
+// modification time counts as a change.
+func documentChanged(cached, listed models.DocumentMetadata) bool {
+	if cached.Checksum != "" && listed.Checksum != "" {
+		return cached.Checksum != listed.Checksum
+	}
+	return cached.Size != listed.Size || !cached.LastModified.Equal(listed.LastModified)
+}
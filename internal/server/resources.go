@@ -3,7 +3,8 @@ package server
 import (
 	"fmt"
 	"path/filepath"
-	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,60 +30,104 @@ func (s *MCPServer) createMCPResourceFromDocument(doc *models.Document) models.M
 		"path":         doc.Metadata.Path,
 		"lastModified": doc.Metadata.LastModified.Format(time.RFC3339),
 		"size":         fmt.Sprintf("%d", doc.Metadata.Size),
-		"checksum":     doc.Metadata.Checksum,
 	}
+	if doc.Metadata.Checksum != "" {
+		annotations["checksum"] = doc.Metadata.Checksum
+	}
+
+	wordCount := documentWordCount(doc)
+	annotations["wordCount"] = strconv.Itoa(wordCount)
+	annotations["readingMinutes"] = strconv.Itoa(readingMinutes(wordCount))
+	annotations["status"] = documentStatus(doc)
+	annotations["estimatedTokens"] = strconv.Itoa(s.tokenEstimator.Estimate(doc.Content.RawContent))
 
 	return models.MCPResource{
 		URI:         uri,
 		Name:        doc.Metadata.Title,
 		Description: description,
-		MimeType:    config.MimeTypeMarkdown,
+		MimeType:    documentMimeType(doc),
 		Annotations: annotations,
 	}
 }
 
-// generateResourceURI creates an MCP resource URI based on category and path
-// Normalizes filesystem paths to consistent URI format for MCP protocol
-func (s *MCPServer) generateResourceURI(category, path string) string {
-	cleanPath := strings.TrimSuffix(path, config.MarkdownExtension)
-	cleanPath = filepath.ToSlash(cleanPath)
+// documentMimeType returns the MIME type detected at load time, defaulting to markdown
+// for documents cached before detection existed or without going through the loader
+func documentMimeType(doc *models.Document) string {
+	if doc.Metadata.MimeType == "" {
+		return config.MimeTypeMarkdown
+	}
+	return doc.Metadata.MimeType
+}
 
-	switch category {
-	case config.CategoryGuideline:
-		cleanPath = strings.TrimPrefix(cleanPath, config.GuidelinesPath+"/")
-		return fmt.Sprintf("%s%s/%s", config.URIScheme, config.URIGuidelines, cleanPath)
-	case config.CategoryPattern:
-		cleanPath = strings.TrimPrefix(cleanPath, config.PatternsPath+"/")
-		return fmt.Sprintf("%s%s/%s", config.URIScheme, config.URIPatterns, cleanPath)
-	case config.CategoryADR:
-		cleanPath = strings.TrimPrefix(cleanPath, config.ADRPath+"/")
-		// ADRs use numeric IDs for cleaner URIs (e.g., "001" instead of "001-api-design")
-		adrId := s.extractADRId(cleanPath)
-		return fmt.Sprintf("%s%s/%s", config.URIScheme, config.URIADR, adrId)
-	default:
-		return fmt.Sprintf("%s%s/%s", config.URIScheme, config.URIUnknown, cleanPath)
+// documentWordCount returns the word count recorded at load time, falling back to
+// counting the content for documents cached without going through the loader
+func documentWordCount(doc *models.Document) int {
+	if doc.Metadata.WordCount > 0 {
+		return doc.Metadata.WordCount
 	}
+	return countWords(doc.Content.RawContent)
 }
 
-// extractADRId extracts ADR ID from filename or path
-// Supports multiple ADR naming conventions for flexibility
-func (s *MCPServer) extractADRId(path string) string {
-	filename := filepath.Base(path)
+// documentStatus returns the status recorded at load time, or "unknown" when the
+// document declares none. Content is not re-parsed here since resources/list runs
+// on every document.
+func documentStatus(doc *models.Document) string {
+	if doc.Metadata.Status != "" {
+		return doc.Metadata.Status
+	}
+	return "unknown"
+}
 
-	patterns := []string{
-		`^(\d+)-`,    // "001-api-design"
-		`^adr-(\d+)`, // "adr-001"
-		`^ADR-(\d+)`, // "ADR-001"
+// countWords counts whitespace-separated words, so markdown syntax tokens such as
+// heading markers are counted too; close enough for a reading estimate
+func countWords(content string) int {
+	return len(strings.Fields(content))
+}
+
+// readingMinutes estimates reading time, rounding up so short documents never read as zero
+func readingMinutes(wordCount int) int {
+	if wordCount == 0 {
+		return 0
 	}
+	return (wordCount + config.ReadingWordsPerMinute - 1) / config.ReadingWordsPerMinute
+}
 
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		if matches := re.FindStringSubmatch(filename); len(matches) > 1 {
-			return matches[1]
-		}
+// resourceCategories maps the names accepted for a resource category to the
+// category. URI segments ("patterns") are accepted alongside category names.
+var resourceCategories = map[string]string{
+	config.CategoryGuideline: config.CategoryGuideline,
+	config.URIGuidelines:     config.CategoryGuideline,
+	config.CategoryPattern:   config.CategoryPattern,
+	config.URIPatterns:       config.CategoryPattern,
+	config.CategoryADR:       config.CategoryADR,
+}
+
+// resourceCategory resolves a category name or URI segment to a category
+func resourceCategory(name string) (string, bool) {
+	category, ok := resourceCategories[strings.ToLower(name)]
+	return category, ok
+}
+
+// resourceCategoryNames returns the accepted category names in sorted order
+func resourceCategoryNames() []string {
+	names := make([]string, 0, len(resourceCategories))
+	for name := range resourceCategories {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	return filename
+// generateResourceURI creates an MCP resource URI based on category and path
+// Normalizes filesystem paths to consistent URI format for MCP protocol
+func (s *MCPServer) generateResourceURI(category, path string) string {
+	return config.ResourceURI(category, path)
+}
+
+// extractADRId extracts ADR ID from filename or path
+// Supports multiple ADR naming conventions for flexibility
+func (s *MCPServer) extractADRId(path string) string {
+	return config.ADRID(path)
 }
 
 // parseResourceURI parses an MCP resource URI and returns category and path
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponseFormat controls how messages sent to the client are marshaled
+type ResponseFormat string
+
+const (
+	// ResponseFormatCompact writes each message without insignificant whitespace,
+	// the default
+	ResponseFormatCompact ResponseFormat = "compact"
+	// ResponseFormatIndented spaces each message out for reading while debugging.
+	// Messages are framed one per line, so it stays on a single line: members and
+	// elements are separated by ", " and keys from values by ": ".
+	ResponseFormatIndented ResponseFormat = "indented"
+)
+
+// ParseResponseFormat converts a response format name, case-insensitively
+func ParseResponseFormat(name string) (ResponseFormat, error) {
+	switch format := ResponseFormat(strings.ToLower(name)); format {
+	case ResponseFormatCompact, ResponseFormatIndented:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown response format %q, expected %q or %q", name, ResponseFormatCompact, ResponseFormatIndented)
+	}
+}
+
+// SetResponseFormat sets how responses and notifications are marshaled. Compact,
+// the default, is the most efficient; indented is meant for reading the traffic
+// through the bridge. Must be called before Start.
+func (s *MCPServer) SetResponseFormat(format ResponseFormat) {
+	s.responseFormat = format
+}
+
+// marshalMessage marshals message in the given format as one newline-terminated
+// line
+func marshalMessage(format ResponseFormat, message interface{}) ([]byte, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	if format == ResponseFormatIndented {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", ""); err != nil {
+			return nil, err
+		}
+		// Newlines inside strings are escaped, so every newline left separates
+		// tokens and can become a space
+		data = bytes.ReplaceAll(indented.Bytes(), []byte("\n"), []byte(" "))
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseResponseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ResponseFormat
+		wantErr bool
+	}{
+		{"compact", ResponseFormatCompact, false},
+		{"Indented", ResponseFormatIndented, false},
+		{"pretty", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseResponseFormat(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseResponseFormat(%q) = %q, %v; want %q, wantErr %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMarshalMessage(t *testing.T) {
+	message := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result":  map[string]interface{}{"text": "line one\nline two", "tags": []string{"a", "b"}},
+	}
+
+	tests := []struct {
+		format ResponseFormat
+		want   string
+	}{
+		{ResponseFormatCompact, `{"id":1,"jsonrpc":"2.0","result":{"tags":["a","b"],"text":"line one\nline two"}}` + "\n"},
+		{ResponseFormatIndented, `{ "id": 1, "jsonrpc": "2.0", "result": { "tags": [ "a", "b" ], "text": "line one\nline two" } }` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := marshalMessage(tt.format, message)
+			if err != nil {
+				t.Fatalf("marshalMessage() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("marshalMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessMessages_ResponseFormatRoundTrips(t *testing.T) {
+	requests := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"no/such-method"}`,
+	}
+
+	responses := make(map[ResponseFormat][]map[string]interface{})
+	for _, format := range []ResponseFormat{ResponseFormatCompact, ResponseFormatIndented} {
+		t.Run(string(format), func(t *testing.T) {
+			server := newMCPServerWithOptions(false)
+			if err := server.initializeToolsSystem(); err != nil {
+				t.Fatalf("Failed to initialize tools system: %v", err)
+			}
+			server.SetResponseFormat(format)
+
+			output := &bytes.Buffer{}
+			input := strings.NewReader(strings.Join(requests, "\n") + "\n")
+			if err := server.processMessages(context.Background(), input, output); err != nil {
+				t.Fatalf("processMessages() error = %v", err)
+			}
+
+			// Every response must be a complete JSON value on its own line
+			scanner := bufio.NewScanner(output)
+			scanner.Buffer(nil, 1<<20)
+			var decoded []map[string]interface{}
+			for scanner.Scan() {
+				var response map[string]interface{}
+				if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+					t.Fatalf("Response line is not a JSON value: %v\n%s", err, scanner.Text())
+				}
+				if format == ResponseFormatIndented && !strings.Contains(scanner.Text(), `"jsonrpc": "2.0"`) {
+					t.Errorf("Expected an indented response, got %s", scanner.Text())
+				}
+				decoded = append(decoded, response)
+			}
+			if len(decoded) != len(requests) {
+				t.Fatalf("Expected %d response lines, got %d", len(requests), len(decoded))
+			}
+			responses[format] = decoded
+		})
+	}
+
+	// Both formats carry the same messages, whatever order requests finished in
+	byID := func(decoded []map[string]interface{}) map[interface{}]map[string]interface{} {
+		indexed := make(map[interface{}]map[string]interface{})
+		for _, response := range decoded {
+			indexed[response["id"]] = response
+		}
+		return indexed
+	}
+	compact, indented := byID(responses[ResponseFormatCompact]), byID(responses[ResponseFormatIndented])
+	for id, response := range compact {
+		if !reflect.DeepEqual(response, indented[id]) {
+			t.Errorf("Response %v differs between formats:\ncompact:  %v\nindented: %v", id, response, indented[id])
+		}
+	}
+}
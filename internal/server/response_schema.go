@@ -0,0 +1,237 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// mcpResponseSchemaJSON holds the MCP schema definitions of the messages the server
+// sends, checked in -validate-responses mode
+//
+//go:embed schemas/mcp-responses.json
+var mcpResponseSchemaJSON []byte
+
+// resultSchemas names the schema definition of the result of each standard method.
+// Results of this server's extension methods, such as server/diagnostics, are only
+// checked as far as the JSON-RPC envelope.
+var resultSchemas = map[string]string{
+	"initialize":          "InitializeResult",
+	"resources/list":      "ListResourcesResult",
+	"resources/read":      "ReadResourceResult",
+	"prompts/list":        "ListPromptsResult",
+	"prompts/get":         "GetPromptResult",
+	"tools/list":          "ListToolsResult",
+	"tools/call":          "CallToolResult",
+	"completion/complete": "CompleteResult",
+}
+
+// jsonSchema is the subset of JSON Schema the embedded MCP schema uses
+type jsonSchema struct {
+	Ref        string                 `json:"$ref"`
+	Type       schemaTypes            `json:"type"`
+	Const      json.RawMessage        `json:"const"`
+	Enum       []interface{}          `json:"enum"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+	OneOf      []*jsonSchema          `json:"oneOf"`
+}
+
+// schemaTypes is a schema's type, given as one name or a list of names
+type schemaTypes []string
+
+func (st *schemaTypes) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*st = schemaTypes{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("schema type must be a name or a list of names: %w", err)
+	}
+	*st = names
+	return nil
+}
+
+// schemaDocument is a set of named schema definitions
+type schemaDocument struct {
+	Definitions map[string]*jsonSchema `json:"definitions"`
+}
+
+// mcpResponseSchema parses the embedded schema the first time it is needed
+var mcpResponseSchema = sync.OnceValues(func() (*schemaDocument, error) {
+	var document schemaDocument
+	if err := json.Unmarshal(mcpResponseSchemaJSON, &document); err != nil {
+		return nil, fmt.Errorf("invalid embedded MCP schema: %w", err)
+	}
+	return &document, nil
+})
+
+// SetValidateResponses sets whether every message sent is first checked against the
+// embedded MCP schema, logging each violation as an error. Messages are sent either
+// way. This is a debugging aid for catching protocol regressions; it costs an extra
+// decode of every message, so it is off by default. Must be called before Start.
+func (s *MCPServer) SetValidateResponses(enabled bool) {
+	s.validateResponses = enabled
+}
+
+// checkResponseSchema logs the ways data, the marshaled message sent in reply to a
+// request or notification for method, violates the MCP schema
+func (s *MCPServer) checkResponseSchema(method string, message *models.MCPMessage, data []byte) {
+	violations, err := validateResponseSchema(method, message, data)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to validate response against the MCP schema")
+		return
+	}
+	if len(violations) == 0 {
+		return
+	}
+	s.logger.WithContext("method", method).
+		WithContext("request_id", message.ID).
+		WithContext("violations", violations).
+		Error("Response violates the MCP schema")
+}
+
+// validateResponseSchema returns the ways data, a marshaled message, violates the MCP
+// schema. Notifications are checked as notifications; anything else is a response to
+// method, whose result is checked when method is a standard one.
+func validateResponseSchema(method string, message *models.MCPMessage, data []byte) ([]string, error) {
+	document, err := mcpResponseSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	var violations []string
+	if message.Method != "" && message.ID == nil {
+		document.validate(value, document.Definitions["JSONRPCNotification"], "message", &violations)
+		return violations, nil
+	}
+
+	document.validate(value, document.Definitions["JSONRPCResponseMessage"], "message", &violations)
+	if name, ok := resultSchemas[method]; ok && message.Error == nil {
+		if fields, ok := value.(map[string]interface{}); ok && fields["result"] != nil {
+			document.validate(fields["result"], document.Definitions[name], "result", &violations)
+		}
+	}
+	return violations, nil
+}
+
+// validate appends to violations the ways value, found at path, violates schema
+func (sd *schemaDocument) validate(value interface{}, schema *jsonSchema, path string, violations *[]string) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		sd.validate(value, sd.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")], path, violations)
+		return
+	}
+
+	if len(schema.Type) > 0 && !schemaTypeMatches(schema.Type, value) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(schema.Type, " or "), jsonTypeName(value)))
+		return
+	}
+	if schema.Const != nil {
+		var expected interface{}
+		if err := json.Unmarshal(schema.Const, &expected); err == nil && !reflect.DeepEqual(value, expected) {
+			*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %v", path, schema.Const, value))
+		}
+	}
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		*violations = append(*violations, fmt.Sprintf("%s: %v is not one of %v", path, value, schema.Enum))
+	}
+
+	if fields, ok := value.(map[string]interface{}); ok {
+		for _, name := range schema.Required {
+			if _, present := fields[name]; !present {
+				*violations = append(*violations, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if field, present := fields[name]; present {
+				sd.validate(field, schema.Properties[name], path+"."+name, violations)
+			}
+		}
+	}
+	if items, ok := value.([]interface{}); ok && schema.Items != nil {
+		for i, item := range items {
+			sd.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	}
+
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, option := range schema.OneOf {
+			var optionViolations []string
+			sd.validate(value, option, path, &optionViolations)
+			if len(optionViolations) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			*violations = append(*violations, fmt.Sprintf("%s: matches %d of %d alternatives, expected exactly 1", path, matches, len(schema.OneOf)))
+		}
+	}
+}
+
+// schemaTypeMatches reports whether value, as decoded by encoding/json, has one of
+// the JSON Schema types
+func schemaTypeMatches(types schemaTypes, value interface{}) bool {
+	actual := jsonTypeName(value)
+	for _, expected := range types {
+		if expected == actual || (expected == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type of value, as decoded by encoding/json
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// enumContains reports whether value equals one of the enum values
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
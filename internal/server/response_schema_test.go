@@ -0,0 +1,273 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/logging"
+)
+
+func TestValidateResponseSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		message string
+		want    []string // substrings of the expected violations; none for a valid message
+	}{
+		{
+			name:    "valid resources/list",
+			method:  "resources/list",
+			message: `{"jsonrpc":"2.0","id":1,"result":{"resources":[{"uri":"architecture://patterns/cqrs","name":"CQRS","mimeType":"text/markdown"}]}}`,
+		},
+		{
+			name:    "valid error",
+			method:  "resources/read",
+			message: `{"jsonrpc":"2.0","id":"a","error":{"code":-32602,"message":"Invalid params"}}`,
+		},
+		{
+			name:    "valid prompts/get",
+			method:  "prompts/get",
+			message: `{"jsonrpc":"2.0","id":2,"result":{"messages":[{"role":"user","content":{"type":"text","text":"Review this"}}]}}`,
+		},
+		{
+			name:    "valid notification",
+			message: `{"jsonrpc":"2.0","method":"notifications/resources/list_changed"}`,
+		},
+		{
+			name:    "extension method checks only the envelope",
+			method:  "server/diagnostics",
+			message: `{"jsonrpc":"2.0","id":3,"result":{"anything":true}}`,
+		},
+		{
+			name:    "resources of the wrong type",
+			method:  "resources/list",
+			message: `{"jsonrpc":"2.0","id":1,"result":{"resources":"none"}}`,
+			want:    []string{"result.resources: expected array, got string"},
+		},
+		{
+			name:    "resource missing its uri",
+			method:  "resources/list",
+			message: `{"jsonrpc":"2.0","id":1,"result":{"resources":[{"name":"CQRS"}]}}`,
+			want:    []string{`result.resources[0]: missing required property "uri"`},
+		},
+		{
+			name:    "neither result nor error",
+			method:  "tools/list",
+			message: `{"jsonrpc":"2.0","id":1}`,
+			want:    []string{"message: matches 0 of 2 alternatives"},
+		},
+		{
+			name:    "wrong jsonrpc version",
+			method:  "tools/list",
+			message: `{"jsonrpc":"1.0","id":1,"result":{"tools":[]}}`,
+			want:    []string{"message: matches 0 of 2 alternatives"},
+		},
+		{
+			name:    "error without a message",
+			method:  "tools/call",
+			message: `{"jsonrpc":"2.0","id":1,"error":{"code":-32603}}`,
+			want:    []string{"message: matches 0 of 2 alternatives"},
+		},
+		{
+			name:    "prompt content without text",
+			method:  "prompts/get",
+			message: `{"jsonrpc":"2.0","id":2,"result":{"messages":[{"role":"system","content":{"type":"text"}}]}}`,
+			want: []string{
+				"result.messages[0].content: matches 0 of 3 alternatives",
+				"result.messages[0].role: system is not one of [user assistant]",
+			},
+		},
+		{
+			name:    "completion values that are not strings",
+			method:  "completion/complete",
+			message: `{"jsonrpc":"2.0","id":4,"result":{"completion":{"values":[{"value":"cqrs"}]}}}`,
+			want:    []string{"result.completion.values[0]: expected string, got object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message models.MCPMessage
+			if err := json.Unmarshal([]byte(tt.message), &message); err != nil {
+				t.Fatalf("Invalid test message: %v", err)
+			}
+			if message.ID == nil {
+				message.Method = "notifications/resources/list_changed"
+			}
+
+			violations, err := validateResponseSchema(tt.method, &message, []byte(tt.message))
+			if err != nil {
+				t.Fatalf("validateResponseSchema() error = %v", err)
+			}
+			if len(violations) != len(tt.want) {
+				t.Fatalf("validateResponseSchema() = %q, want %d violations", violations, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(strings.Join(violations, "\n"), want) {
+					t.Errorf("Expected a violation containing %q, got %q", want, violations)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMCPSchemaResolves(t *testing.T) {
+	document, err := mcpResponseSchema()
+	if err != nil {
+		t.Fatalf("mcpResponseSchema() error = %v", err)
+	}
+
+	var check func(name string, schema *jsonSchema)
+	check = func(name string, schema *jsonSchema) {
+		if schema == nil {
+			return
+		}
+		if schema.Ref != "" {
+			if _, ok := document.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]; !ok {
+				t.Errorf("%s: unresolved $ref %q", name, schema.Ref)
+			}
+		}
+		for _, property := range schema.Properties {
+			check(name, property)
+		}
+		check(name, schema.Items)
+		for _, option := range schema.OneOf {
+			check(name, option)
+		}
+	}
+	for name, schema := range document.Definitions {
+		check(name, schema)
+	}
+	for method, name := range resultSchemas {
+		if _, ok := document.Definitions[name]; !ok {
+			t.Errorf("Result schema %s of %s is not defined", name, method)
+		}
+	}
+}
+
+// newCapturedServerLogger returns a server logger that writes into a pipe instead of
+// the real stderr, plus a function that returns the decoded log entries
+func newCapturedServerLogger(t *testing.T) (*logging.StructuredLogger, func() []map[string]interface{}) {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	// The structured logger binds to os.Stderr at creation time
+	originalStderr := os.Stderr
+	os.Stderr = writer
+	logger := logging.NewStructuredLogger("server")
+	os.Stderr = originalStderr
+
+	collect := func() []map[string]interface{} {
+		writer.Close()
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+
+		var entries []map[string]interface{}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var entry map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				t.Fatalf("Server emitted non-JSON log line: %s", scanner.Text())
+			}
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+	return logger, collect
+}
+
+func TestCheckResponseSchema_LogsViolations(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	logger, collect := newCapturedServerLogger(t)
+	server.logger = logger
+
+	valid := &models.MCPMessage{JSONRPC: "2.0", ID: 1, Result: map[string]interface{}{"tools": []interface{}{}}}
+	malformed := &models.MCPMessage{JSONRPC: "2.0", ID: 2, Result: map[string]interface{}{"tools": "none"}}
+	for _, response := range []*models.MCPMessage{valid, malformed} {
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.Fatalf("Failed to marshal response: %v", err)
+		}
+		server.checkResponseSchema("tools/list", response, data)
+	}
+
+	entries := collect()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one log entry for the malformed response, got %d: %v", len(entries), entries)
+	}
+	entry := entries[0]
+	if entry["level"] != "ERROR" || entry["message"] != "Response violates the MCP schema" {
+		t.Errorf("Unexpected log entry %v", entry)
+	}
+	if entry["method"] != "tools/list" || entry["request_id"] != float64(2) {
+		t.Errorf("Expected the method and request ID in the log entry, got %v", entry)
+	}
+	violations, _ := entry["violations"].([]interface{})
+	if len(violations) != 1 || violations[0] != "result.tools: expected array, got string" {
+		t.Errorf("Unexpected violations %v", entry["violations"])
+	}
+}
+
+func TestProcessMessages_ResponsesMatchSchema(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	if err := server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+	server.SetValidateResponses(true)
+
+	requests := map[int]string{
+		1: "initialize",
+		2: "resources/list",
+		3: "prompts/list",
+		4: "tools/list",
+		5: "no/such-method",
+	}
+	var input strings.Builder
+	for id, method := range requests {
+		params := "{}"
+		if method == "initialize" {
+			params = `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}`
+		}
+		fmt.Fprintf(&input, `{"jsonrpc":"2.0","id":%d,"method":%q,"params":%s}`+"\n", id, method, params)
+	}
+
+	output := &bytes.Buffer{}
+	if err := server.processMessages(context.Background(), strings.NewReader(input.String()), output); err != nil {
+		t.Fatalf("processMessages() error = %v", err)
+	}
+
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(nil, 1<<20)
+	count := 0
+	for scanner.Scan() {
+		var response models.MCPMessage
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			t.Fatalf("Response line is not a JSON value: %v", err)
+		}
+		id, _ := response.ID.(float64)
+		method := requests[int(id)]
+		violations, err := validateResponseSchema(method, &response, scanner.Bytes())
+		if err != nil {
+			t.Fatalf("validateResponseSchema() error = %v", err)
+		}
+		if len(violations) != 0 {
+			t.Errorf("%s response violates the MCP schema: %q", method, violations)
+		}
+		count++
+	}
+	if count != len(requests) {
+		t.Errorf("Expected %d responses, got %d", len(requests), count)
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// SelfTestReport summarizes a self-test run: what loaded, and anything that would
+// make the server unfit to deploy
+type SelfTestReport struct {
+	Documentation *models.DocumentationReport `json:"documentation"`
+	Prompts       []string                    `json:"prompts"`
+	Tools         []string                    `json:"tools"`
+	// Warnings are issues worth fixing that leave the server usable
+	Warnings []string `json:"warnings"`
+	// Failures are fatal issues; the self-test passes only when there are none
+	Failures []string `json:"failures"`
+}
+
+// Passed reports whether the self-test found no fatal issues
+func (r *SelfTestReport) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// recordStartupError remembers that a system failed to initialize, for SelfTest
+func (s *MCPServer) recordStartupError(system string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.startupErrors == nil {
+		s.startupErrors = make(map[string]error)
+	}
+	s.startupErrors[system] = err
+}
+
+// SelfTest initializes the server as Start does, without processing messages, and
+// reports whether documentation loaded, prompts parsed and tools registered.
+// Failing to initialize a system, loading no documents, skipping an invalid prompt
+// file and registering no tools are fatal; documentation integrity issues are
+// warnings. Shutdown must still be called afterwards.
+func (s *MCPServer) SelfTest(ctx context.Context) *SelfTestReport {
+	report := &SelfTestReport{Prompts: []string{}, Tools: []string{}, Warnings: []string{}, Failures: []string{}}
+
+	// Startup failing, as in strict mode, still leaves what loaded to report on
+	if err := s.startup(ctx); err != nil {
+		report.Failures = append(report.Failures, fmt.Sprintf("startup: %v", err))
+	}
+
+	s.mu.RLock()
+	for _, system := range []string{"documentation", "prompts", "tools"} {
+		if err := s.startupErrors[system]; err != nil {
+			report.Failures = append(report.Failures, fmt.Sprintf("%s failed to initialize: %v", system, err))
+		}
+	}
+	s.mu.RUnlock()
+
+	report.Documentation = s.DocumentationReport()
+	if doc := report.Documentation; doc != nil {
+		if doc.TotalDocuments == 0 {
+			report.Failures = append(report.Failures, "no documentation loaded")
+		}
+		for _, warning := range doc.ParseWarnings {
+			report.Warnings = append(report.Warnings, "documentation: "+warning)
+		}
+		for _, skipped := range doc.SkippedFiles {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("skipped %s: %s", skipped.Path, skipped.Reason))
+		}
+		for _, duplicate := range doc.DuplicateIDs {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("duplicate resource %s: %v", duplicate.URI, duplicate.Paths))
+		}
+		for _, path := range doc.EmptyDocuments {
+			report.Warnings = append(report.Warnings, "empty document: "+path)
+		}
+	}
+
+	for _, prompt := range s.promptManager.ListPrompts() {
+		report.Prompts = append(report.Prompts, prompt.Name)
+	}
+	sort.Strings(report.Prompts)
+	for _, loadErr := range s.promptManager.LoadErrors() {
+		report.Failures = append(report.Failures, "invalid prompt "+loadErr)
+	}
+
+	if s.toolManager != nil {
+		for _, tool := range s.toolManager.ListTools() {
+			report.Tools = append(report.Tools, tool.Name)
+		}
+	}
+	sort.Strings(report.Tools)
+	if len(report.Tools) == 0 {
+		report.Failures = append(report.Failures, "no tools registered")
+	}
+
+	return report
+}
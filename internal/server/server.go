@@ -3,8 +3,10 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"mcp-architecture-service/pkg/monitor"
 	"mcp-architecture-service/pkg/prompts"
 	"mcp-architecture-service/pkg/scanner"
+	"mcp-architecture-service/pkg/tokens"
 	"mcp-architecture-service/pkg/tools"
 )
 
@@ -30,16 +33,106 @@ type MCPServer struct {
 	scanner *scanner.DocumentationScanner
 	monitor *monitor.FileSystemMonitor
 
+	// documentSource supplies the documents loaded into the cache; with readThrough,
+	// resources/read also loads documents missing from the cache from it
+	documentSource DocumentSource
+	readThrough    bool
+
+	// loadWorkers bounds how many documents are read into the cache at once; zero
+	// uses defaultLoadWorkers
+	loadWorkers int
+
+	// cacheCleanupInterval is how often cached documents are cleaned up
+	cacheCleanupInterval time.Duration
+
 	// Prompts system
 	promptManager *prompts.PromptManager
 
 	// Tools system
 	toolManager *tools.ToolManager
 
+	// namingConventions overrides check-naming-conventions' filename pattern per category
+	namingConventions map[string]string
+	// adrProximityWindow overrides check-adr-alignment's conflict proximity window when set
+	adrProximityWindow int
+	// adrMinConfidence is the confidence check-adr-alignment needs to report an ADR as
+	// supporting or conflicting rather than related
+	adrMinConfidence float64
+	// staleThresholdDays overrides find-stale-documents' default threshold when set
+	staleThresholdDays int
+	// stopWords overrides the tools' default stop words when set
+	stopWords map[string]bool
+
+	// minQueryLength overrides search-architecture's minimum query length when set
+	minQueryLength int
+
+	// sectionLevels overrides the heading levels of the sections tools read when set
+	sectionLevels []int
+	// categoryBoosts holds search-architecture's per-category score multipliers
+	categoryBoosts map[string]float64
+	// collapseCodeExcerpts collapses fenced code blocks in search excerpts
+	collapseCodeExcerpts bool
+
+	// Tool invocation audit log; nil disables it. sessionID identifies this server's
+	// client connection in audit records.
+	auditLog  io.Writer
+	sessionID string
+
+	// Tool argument sanitization for logs and audit records; zero and nil keep the
+	// tools' defaults
+	argSanitizeLength int
+	redactedFields    []string
+
+	// Read-only docs mode refuses writes into the docs roots; outputDir is where
+	// relative output files such as the audit log are created
+	readOnlyDocs bool
+	outputDir    string
+
+	// relatedResources is how many related resource URIs resources/read returns,
+	// found by relatedFinder; zero disables them
+	relatedResources int
+	relatedFinder    *tools.FindSimilarTool
+
+	// tokenEstimator fills the estimatedTokens annotation of resources
+	tokenEstimator tokens.Estimator
+
+	// validateResponses checks every message sent against the embedded MCP schema
+	validateResponses bool
+
+	// Page sizes for resources/list and prompts/list; zero lists everything at once
+	maxListedResources int
+	maxListedPrompts   int
+
+	// Limits on the arguments of prompts/get and prompts/preview; zero disables one
+	maxPromptArguments     int
+	maxPromptArgumentsSize int
+
+	// maxBatchPrompts caps the prompts one prompts/get-batch renders; zero disables it
+	maxBatchPrompts int
+
+	// Document version history
+	historyProvider HistoryProvider
+
+	// Integrity report from the last documentation load
+	documentationReport *models.DocumentationReport
+
+	// Systems that failed to initialize during startup, by name
+	startupErrors map[string]error
+
+	// strict fails startup on any documentation or prompt issue
+	strict bool
+
+	// lenientPromptNames loads prompts with non-conforming names, outside strict mode
+	lenientPromptNames bool
+
 	// Error handling and degradation
+	errorVerbosity        errors.ErrorVerbosity
 	circuitBreakerManager *errors.CircuitBreakerManager
 	degradationManager    *errors.GracefulDegradationManager
 
+	// Response encoding
+	responseFormat ResponseFormat
+
 	// Logging
 	loggingManager *logging.LoggingManager
 	logger         *logging.StructuredLogger
@@ -48,8 +141,27 @@ type MCPServer struct {
 	refreshChan  chan models.FileEvent
 	shutdownChan chan struct{}
 
+	// Shutdown
+	shutdownTimeout time.Duration
+	requests        *requestTracker
+
+	// maxConcurrentRequests bounds how many requests processMessages handles at once;
+	// zero disables the limit
+	maxConcurrentRequests int
+
+	// Background components started by Start and stopped by Shutdown
+	subsystems *subsystemRegistry
+
+	// Requests notifications/cancelled can abort
+	cancellable *cancellableRequests
+
+	// notify sends a notification to the client; nil while no client is connected
+	notify func(notification *models.MCPMessage)
+
 	// Synchronization
 	mu sync.RWMutex
+	// reloadMu runs documentation reloads one at a time
+	reloadMu sync.Mutex
 }
 
 // NewMCPServer creates a new MCP server instance
@@ -116,7 +228,8 @@ func newMCPServerWithOptions(enableMonitor bool) *MCPServer {
 				ListChanged: false,
 			},
 			Prompts: &models.MCPPromptCapabilities{
-				ListChanged: false,
+				// Prompt files are only watched, and changes announced, with a monitor
+				ListChanged: fileMonitor != nil,
 			},
 			Tools: &models.MCPToolCapabilities{
 				ListChanged: false,
@@ -132,13 +245,37 @@ func newMCPServerWithOptions(enableMonitor bool) *MCPServer {
 		scanner: docScanner,
 		monitor: fileMonitor,
 
+		documentSource: &filesystemDocumentSource{scanner: docScanner},
+
+		cacheCleanupInterval: DefaultCacheCleanupInterval,
+		relatedResources:     DefaultRelatedResources,
+		tokenEstimator:       tokens.DefaultEstimator,
+		relatedFinder:        tools.NewFindSimilarTool(docCache, loggingManager.GetLogger("tools")),
+
+		// Tools system
+		adrMinConfidence: tools.DefaultMinAlignmentConfidence,
+
 		// Prompts system
-		promptManager: promptManager,
+		promptManager:          promptManager,
+		maxPromptArguments:     DefaultMaxPromptArguments,
+		maxPromptArgumentsSize: DefaultMaxPromptArgumentsSize,
+		maxBatchPrompts:        DefaultMaxBatchPrompts,
+
+		maxConcurrentRequests: DefaultMaxConcurrentRequests,
+
+		sessionID: newSessionID(),
+
+		// Document version history
+		historyProvider: NoopHistoryProvider{},
 
 		// Error handling
+		errorVerbosity:        errors.ErrorVerbosityTerse,
 		circuitBreakerManager: circuitBreakerManager,
 		degradationManager:    degradationManager,
 
+		// Response encoding
+		responseFormat: ResponseFormatCompact,
+
 		// Logging
 		loggingManager: loggingManager,
 		logger:         logger,
@@ -146,11 +283,31 @@ func newMCPServerWithOptions(enableMonitor bool) *MCPServer {
 		// Coordination channels
 		refreshChan:  make(chan models.FileEvent, 100), // Buffered channel for file events
 		shutdownChan: make(chan struct{}),
+
+		// Shutdown
+		shutdownTimeout: DefaultShutdownTimeout,
+		requests:        newRequestTracker(),
+		subsystems:      newSubsystemRegistry(),
+
+		cancellable: newCancellableRequests(),
 	}
 
 	// Set up degradation state change callback
 	degradationManager.SetStateChangeCallback(server.onDegradationStateChange)
 
+	// Announce prompt changes picked up by the prompts directory watcher
+	promptManager.SetChangeCallback(server.notifyPromptsListChanged)
+
+	// The cache and monitor run from creation; the monitor stops before the cache
+	server.subsystems.register("document_cache", nil, func() error {
+		docCache.Close() // Stop cleanup goroutines
+		docCache.Clear()
+		return nil
+	})
+	if fileMonitor != nil {
+		server.subsystems.register("file_monitor", nil, fileMonitor.StopWatching)
+	}
+
 	// Set up circuit breaker callbacks
 	server.setupCircuitBreakerCallbacks()
 
@@ -159,16 +316,36 @@ func newMCPServerWithOptions(enableMonitor bool) *MCPServer {
 
 // Start begins the MCP server operation
 func (s *MCPServer) Start(ctx context.Context) error {
+	if err := s.startup(ctx); err != nil {
+		return err
+	}
+
+	// Start JSON-RPC message processing loop
+	return s.processMessages(ctx, os.Stdin, os.Stdout)
+}
+
+// startup loads documentation, prompts and tools and starts the background
+// subsystems, leaving the server ready to process messages
+func (s *MCPServer) startup(ctx context.Context) error {
 	startTime := time.Now()
 	startupLogger := s.loggingManager.GetLogger("startup")
 
 	startupLogger.WithContext("phase", "initialization").Info("Server start")
 
+	if err := s.validateOutputDir(); err != nil {
+		startupLogger.WithError(err).Error("Invalid output directory")
+		return err
+	}
+	if s.readOnlyDocs {
+		s.logger.Info("Read-only docs mode enabled")
+	}
+
 	// Initialize documentation system
 	docInitStart := time.Now()
 	if err := s.initializeDocumentationSystem(ctx); err != nil {
 		startupLogger.WithContext("duration_ms", time.Since(docInitStart).Milliseconds()).
 			WithError(err).Error("Documentation init failed")
+		s.recordStartupError("documentation", err)
 		s.logger.WithError(err).Warn("Failed to initialize documentation system")
 	} else {
 		startupLogger.WithContext("duration_ms", time.Since(docInitStart).Milliseconds()).
@@ -180,6 +357,7 @@ func (s *MCPServer) Start(ctx context.Context) error {
 	if err := s.initializePromptsSystem(); err != nil {
 		startupLogger.WithContext("duration_ms", time.Since(promptInitStart).Milliseconds()).
 			WithError(err).Error("Prompts init failed")
+		s.recordStartupError("prompts", err)
 		s.logger.WithError(err).Warn("Failed to initialize prompts system")
 	} else {
 		startupLogger.WithContext("duration_ms", time.Since(promptInitStart).Milliseconds()).
@@ -191,22 +369,44 @@ func (s *MCPServer) Start(ctx context.Context) error {
 	if err := s.initializeToolsSystem(); err != nil {
 		startupLogger.WithContext("duration_ms", time.Since(toolsInitStart).Milliseconds()).
 			WithError(err).Error("Tools init failed")
+		s.recordStartupError("tools", err)
 		s.logger.WithError(err).Warn("Failed to initialize tools system")
 	} else {
 		startupLogger.WithContext("duration_ms", time.Since(toolsInitStart).Milliseconds()).
 			Info("Tools init completed")
 	}
 
-	// Start cache refresh coordinator
-	go s.cacheRefreshCoordinator(ctx)
+	// Register the components started during initialization, then start the
+	// background loops; Shutdown stops them in reverse order
+	if s.toolManager != nil {
+		toolManager := s.toolManager
+		s.subsystems.register("tool_executor", nil, func() error {
+			toolManager.Close()
+			return nil
+		})
+	}
+	s.subsystems.register("prompt_watcher", nil, func() error {
+		s.promptManager.StopWatching()
+		return nil
+	})
+
+	// Strict mode fails only once the watchers are registered for Shutdown to stop
+	if s.strict {
+		if err := s.checkStrict(); err != nil {
+			startupLogger.WithError(err).Error("Strict mode validation failed")
+			return err
+		}
+	}
+
+	s.subsystems.registerLoop("cache_refresh", s.cacheRefreshCoordinator)
+	s.subsystems.registerLoop("cache_cleanup", s.cacheCleanupScheduler)
+	s.subsystems.startAll(ctx, startupLogger)
 
 	startupLogger.WithContext("total_startup_time_ms", time.Since(startTime).Milliseconds()).
 		Info("Server ready")
 
 	s.logger.Info("MCP Architecture Service started successfully")
-
-	// Start JSON-RPC message processing loop
-	return s.processMessages(ctx, os.Stdin, os.Stdout)
+	return nil
 }
 
 // Shutdown gracefully shuts down the MCP server
@@ -219,39 +419,96 @@ func (s *MCPServer) Shutdown(ctx context.Context) error {
 	// Signal shutdown to background goroutines
 	close(s.shutdownChan)
 
-	// Stop file system monitoring
-	monitorShutdownStart := time.Now()
-	if s.monitor != nil {
-		if err := s.monitor.StopWatching(); err != nil {
-			shutdownLogger.WithContext("duration_ms", time.Since(monitorShutdownStart).Milliseconds()).
-				WithError(err).Error("Monitor stop failed")
-			s.logger.WithError(err).Error("Error stopping file monitor")
-		} else {
-			shutdownLogger.WithContext("duration_ms", time.Since(monitorShutdownStart).Milliseconds()).
-				Info("Monitor stopped")
-		}
+	var shutdownErr error
+
+	// Wait for in-flight requests, abandoning any that outlive the timeout
+	if abandoned := s.requests.wait(s.shutdownTimeout); len(abandoned) > 0 {
+		shutdownLogger.WithContext("requests", abandoned).
+			WithContext("timeout_ms", s.shutdownTimeout.Milliseconds()).
+			Error("Abandoned in-flight requests after shutdown timeout")
+		shutdownErr = fmt.Errorf("shutdown timed out with %d in-flight requests: %s",
+			len(abandoned), strings.Join(abandoned, ", "))
 	}
 
-	// Clear cache and stop cleanup goroutines
-	cacheShutdownStart := time.Now()
-	s.cache.Close() // Stop cleanup goroutines
-	s.cache.Clear()
-	shutdownLogger.WithContext("duration_ms", time.Since(cacheShutdownStart).Milliseconds()).
-		Info("Cache cleared")
+	// Stop the subsystems in reverse start order, abandoning any that outlive the timeout
+	if abandoned := s.subsystems.stopAll(s.shutdownTimeout, shutdownLogger); len(abandoned) > 0 {
+		shutdownLogger.WithContext("subsystems", abandoned).
+			WithContext("timeout_ms", s.shutdownTimeout.Milliseconds()).
+			Error("Abandoned subsystems after shutdown timeout")
+		if shutdownErr == nil {
+			shutdownErr = fmt.Errorf("shutdown timed out stopping subsystems: %s", strings.Join(abandoned, ", "))
+		}
+	}
 
 	shutdownLogger.WithContext("total_shutdown_time_ms", time.Since(shutdownStart).Milliseconds()).
 		Info("Shutdown complete")
 
 	s.logger.Info("MCP Architecture Service shutdown completed")
 
-	return nil
+	return shutdownErr
+}
+
+// DefaultMaxConcurrentRequests is how many requests one client may have handled at once
+const DefaultMaxConcurrentRequests = 16
+
+// SetMaxConcurrentRequests caps how many requests from the client are handled at
+// once. Further requests wait to be read until one finishes, while initialize and
+// notifications are always handled in order. Zero or less disables the limit.
+func (s *MCPServer) SetMaxConcurrentRequests(count int) {
+	if count < 0 {
+		count = 0
+	}
+	s.maxConcurrentRequests = count
 }
 
-// processMessages handles the JSON-RPC message processing loop
+// processMessages handles the JSON-RPC message processing loop. Requests are
+// handled concurrently so that reading continues while they run, which is what lets
+// notifications/cancelled reach a request in flight; at most maxConcurrentRequests
+// run at once, and reading waits for a free slot beyond that. initialize and
+// notifications are handled in order as they are read, so notifications/initialized
+// never overtakes the initialize it follows. At end of input it waits for the
+// requests still running. While it runs, notifications from the server are sent to
+// writer too.
 func (s *MCPServer) processMessages(ctx context.Context, reader io.Reader, writer io.Writer) error {
 	decoder := json.NewDecoder(reader)
-	encoder := json.NewEncoder(writer)
 
+	var writeMu sync.Mutex
+	send := func(message, response *models.MCPMessage) {
+		if response == nil {
+			return
+		}
+		data, err := marshalMessage(s.responseFormat, response)
+		if err == nil && s.validateResponses {
+			s.checkResponseSchema(message.Method, response, data)
+		}
+		if err == nil {
+			writeMu.Lock()
+			_, err = writer.Write(data)
+			writeMu.Unlock()
+		}
+		if err != nil {
+			s.logger.WithError(err).
+				WithContext("method", message.Method).
+				WithContext("request_id", message.ID).
+				Error("Error encoding response")
+		}
+	}
+
+	s.mu.Lock()
+	s.notify = func(notification *models.MCPMessage) { send(notification, notification) }
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.notify = nil
+		s.mu.Unlock()
+	}()
+
+	var slots chan struct{}
+	if s.maxConcurrentRequests > 0 {
+		slots = make(chan struct{}, s.maxConcurrentRequests)
+	}
+
+	var inFlight sync.WaitGroup
 	for {
 		select {
 		case <-ctx.Done():
@@ -260,43 +517,107 @@ func (s *MCPServer) processMessages(ctx context.Context, reader io.Reader, write
 			var message models.MCPMessage
 			if err := decoder.Decode(&message); err != nil {
 				if err == io.EOF {
+					inFlight.Wait()
 					return nil
 				}
 				s.logger.WithError(err).Error("Error decoding message")
 				continue
 			}
 
-			response := s.handleMessage(&message)
-			if response != nil {
-				if err := encoder.Encode(response); err != nil {
-					s.logger.WithError(err).
-						WithContext("method", message.Method).
-						WithContext("request_id", message.ID).
-						Error("Error encoding response")
+			if response, valid := s.validateMessage(&message); !valid {
+				send(&message, response)
+				continue
+			}
+
+			if message.ID == nil || message.Method == "initialize" {
+				send(&message, s.handleMessage(&message))
+				continue
+			}
+
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
 			}
+			inFlight.Add(1)
+			go func() {
+				defer inFlight.Done()
+				if slots != nil {
+					defer func() { <-slots }()
+				}
+				send(&message, s.handleMessage(&message))
+			}()
 		}
 	}
 }
 
+// validateMessage checks that a decoded message is a JSON-RPC 2.0 request or
+// notification the server should handle. Otherwise it returns false with the error
+// response to send, or nil for a response from the client, which is ignored since
+// the server sends no requests that could be answered.
+func (s *MCPServer) validateMessage(message *models.MCPMessage) (*models.MCPMessage, bool) {
+	logger := s.logger.WithContext("request_id", message.ID)
+
+	if message.JSONRPC != "2.0" {
+		logger.WithContext("jsonrpc", message.JSONRPC).Warn("Rejected message with invalid jsonrpc version")
+		return s.createErrorResponse(message.ID, -32600,
+			fmt.Sprintf("Invalid Request: jsonrpc must be \"2.0\", got %q", message.JSONRPC)), false
+	}
+
+	if message.Method == "" {
+		if message.Result != nil || message.Error != nil {
+			logger.Warn("Ignored JSON-RPC response from client")
+			return nil, false
+		}
+		logger.Warn("Rejected request without a method")
+		return s.createErrorResponse(message.ID, -32601, "Method not found: request has no method"), false
+	}
+
+	return nil, true
+}
+
 // HandleMessage processes individual MCP messages (exported for testing)
 func (s *MCPServer) HandleMessage(message *models.MCPMessage) *models.MCPMessage {
 	return s.handleMessage(message)
 }
 
-// handleMessage processes individual MCP messages
+// handleMessage processes individual MCP messages. Requests are tracked by ID
+// while they run so notifications/cancelled can abort them; the response to a
+// cancelled request is dropped, as the client no longer expects one.
 func (s *MCPServer) handleMessage(message *models.MCPMessage) *models.MCPMessage {
+	if message.ID == nil {
+		return s.handleMessageContext(context.Background(), message)
+	}
+
+	ctx, end := s.cancellable.begin(message.ID)
+	response := s.handleMessageContext(ctx, message)
+	if end() {
+		s.logger.WithContext("method", message.Method).
+			WithContext("request_id", message.ID).
+			Info("Dropped response to cancelled request")
+		return nil
+	}
+	return response
+}
+
+// handleMessageContext processes an MCP message, passing ctx to handlers that can
+// be cancelled
+func (s *MCPServer) handleMessageContext(ctx context.Context, message *models.MCPMessage) *models.MCPMessage {
 	startTime := time.Now()
 	var success bool = true
 	var errorMsg string
 
 	s.logIncomingMessage(message)
 
+	defer s.requests.begin(message.Method)()
+
 	defer func() {
 		s.logOutgoingMessage(message, startTime, success, errorMsg)
 	}()
 
-	response := s.routeMessage(message)
+	response := s.routeMessageContext(ctx, message)
 
 	// Check if response contains an error
 	if response != nil && response.Error != nil {
@@ -336,27 +657,50 @@ func (s *MCPServer) logOutgoingMessage(message *models.MCPMessage, startTime tim
 }
 
 func (s *MCPServer) routeMessage(message *models.MCPMessage) *models.MCPMessage {
+	return s.routeMessageContext(context.Background(), message)
+}
+
+// routeMessageContext dispatches a message to its handler
+func (s *MCPServer) routeMessageContext(ctx context.Context, message *models.MCPMessage) *models.MCPMessage {
+	if strings.HasPrefix(message.Method, "notifications/") {
+		return s.handleNotification(message)
+	}
+
 	switch message.Method {
 	case "initialize":
 		return s.handleInitialize(message)
-	case "notifications/initialized":
-		return s.handleInitialized(message)
 	case "resources/list":
 		return s.handleResourcesList(message)
 	case "resources/read":
 		return s.handleResourcesRead(message)
+	case "resources/history":
+		return s.handleResourcesHistory(message)
 	case "prompts/list":
 		return s.handlePromptsList(message)
 	case "prompts/get":
 		return s.handlePromptsGet(message)
+	case "prompts/preview":
+		return s.handlePromptsPreview(message)
+	case "prompts/get-batch":
+		return s.handlePromptsGetBatch(message)
 	case "tools/list":
 		return s.handleToolsList(message)
+	case "tools/schema":
+		return s.handleToolsSchema(message)
 	case "tools/call":
-		return s.handleToolsCall(message)
+		return s.handleToolsCall(ctx, message)
 	case "completion/complete":
 		return s.handleCompletionComplete(message)
 	case "server/performance":
 		return s.handlePerformanceMetrics(message)
+	case "server/diagnostics":
+		return s.handleServerDiagnostics(message)
+	case "server/cache-cleanup":
+		return s.handleCacheCleanup(message)
+	case "server/cache-stats":
+		return s.handleCacheStats(message)
+	case "server/capabilities":
+		return s.handleServerCapabilities(message)
 	default:
 		return s.createErrorResponse(message.ID, -32601, "Method not found")
 	}
@@ -288,6 +288,45 @@ func BenchmarkStartupTime(b *testing.B) {
 	}
 }
 
+// BenchmarkInitialLoadWorkers benchmarks the initial document load over many files
+// with different worker counts
+func BenchmarkInitialLoadWorkers(b *testing.B) {
+	tempDir, err := os.MkdirTemp("", "benchmark_load_workers")
+	if err != nil {
+		b.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	const docCount = 2000
+	if err := createTestDocumentationFiles(tempDir, docCount); err != nil {
+		b.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	for _, workers := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("Workers_%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				server := newMCPServerWithOptions(false) // Disable file monitor for benchmarks
+				server.SetLoadWorkers(workers)
+
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				err := server.initializeDocumentationSystem(ctx)
+				cancel()
+
+				if err != nil {
+					b.Fatalf("Failed to initialize documentation system: %v", err)
+				}
+				if server.cache.Size() != docCount {
+					b.Fatalf("Expected %d documents loaded, got %d", docCount, server.cache.Size())
+				}
+			}
+		})
+	}
+}
+
 // LoadTestConcurrentRequests performs load testing with concurrent MCP requests
 func LoadTestConcurrentRequests(b *testing.B) {
 	server := newMCPServerWithOptions(false) // Disable file monitor for benchmarks
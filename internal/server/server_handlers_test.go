@@ -79,3 +79,16 @@ func TestCompletionCapabilityAdvertised(t *testing.T) {
 		t.Error("ArgumentCompletions should be true")
 	}
 }
+
+func TestServerMethodsAreRouted(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+
+	for _, method := range serverMethods {
+		t.Run(method, func(t *testing.T) {
+			response := server.routeMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "routed", Method: method})
+			if response != nil && response.Error != nil && response.Error.Code == -32601 {
+				t.Errorf("%s is listed by server/capabilities but not routed", method)
+			}
+		})
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
 )
 
 func TestNewMCPServer(t *testing.T) {
@@ -251,6 +254,80 @@ func TestJSONRPCMessageParsing(t *testing.T) {
 	}
 }
 
+func TestJSONRPCMessageValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantResponse bool
+		wantCode     int
+		wantID       interface{}
+	}{
+		{
+			name:         "jsonrpc 1.0 rejected as invalid request",
+			input:        `{"jsonrpc":"1.0","id":"v1","method":"resources/list"}`,
+			wantResponse: true,
+			wantCode:     -32600,
+			wantID:       "v1",
+		},
+		{
+			name:         "missing jsonrpc rejected as invalid request",
+			input:        `{"id":2,"method":"resources/list"}`,
+			wantResponse: true,
+			wantCode:     -32600,
+			wantID:       float64(2),
+		},
+		{
+			name:         "request without method rejected as method not found",
+			input:        `{"jsonrpc":"2.0","id":"no-method","params":{}}`,
+			wantResponse: true,
+			wantCode:     -32601,
+			wantID:       "no-method",
+		},
+		{
+			name:  "response from client ignored",
+			input: `{"jsonrpc":"2.0","id":7,"result":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMCPServerWithOptions(false)
+			writer := &bytes.Buffer{}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			if err := server.processMessages(ctx, strings.NewReader(tt.input), writer); err != nil {
+				t.Fatalf("Expected nil error (EOF), got %v", err)
+			}
+
+			if !tt.wantResponse {
+				if writer.Len() > 0 {
+					t.Errorf("Expected no response, got %s", writer.String())
+				}
+				return
+			}
+
+			var response models.MCPMessage
+			if err := json.Unmarshal(writer.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Error == nil {
+				t.Fatalf("Expected error response, got %s", writer.String())
+			}
+			if response.Error.Code != tt.wantCode {
+				t.Errorf("Expected error code %d, got %d (%s)", tt.wantCode, response.Error.Code, response.Error.Message)
+			}
+			if response.ID != tt.wantID {
+				t.Errorf("Expected ID %v, got %v", tt.wantID, response.ID)
+			}
+			if response.JSONRPC != "2.0" {
+				t.Errorf("Expected JSONRPC '2.0', got '%s'", response.JSONRPC)
+			}
+		})
+	}
+}
+
 func TestPromptsCapabilityInInitialize(t *testing.T) {
 	server := NewMCPServer()
 
@@ -459,3 +536,241 @@ func TestPerformanceMetricsIncludesTools(t *testing.T) {
 		t.Error("Expected prompt_metrics to be present")
 	}
 }
+
+func TestScheduledCacheCleanup(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	server.SetCacheCleanupInterval(20 * time.Millisecond)
+	server.SetCacheTTL(10 * time.Millisecond)
+
+	doc := &models.Document{Metadata: models.DocumentMetadata{Path: "mcp/resources/guidelines/api.md", Category: "guideline"}}
+	server.cache.Set(doc.Metadata.Path, doc)
+
+	done := make(chan struct{})
+	go func() {
+		server.cacheCleanupScheduler(context.Background())
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for server.cache.Size() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if server.cache.Size() != 0 {
+		t.Error("Scheduled cleanup should have expired the cached document")
+	}
+	if stats := server.cache.GetStats(); stats.Expirations != 1 {
+		t.Errorf("Expected 1 expiration, got %d", stats.Expirations)
+	}
+
+	// Shutdown stops the scheduler
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error during shutdown: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Error("Cache cleanup scheduler did not stop on shutdown")
+	}
+}
+
+func TestCacheContentInterning(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		wantInterned int
+	}{
+		{name: "disabled by default", wantInterned: 0},
+		{name: "enabled", enabled: true, wantInterned: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMCPServerWithOptions(false)
+			defer server.Shutdown(context.Background())
+			if tt.enabled {
+				server.SetCacheContentInterning(true)
+			}
+
+			for _, path := range []string{"mcp/resources/guidelines/api.md", "mcp/resources/guidelines/api-copy.md"} {
+				server.cache.Set(path, &models.Document{
+					Metadata: models.DocumentMetadata{Path: path, Category: "guideline"},
+					Content:  models.DocumentContent{RawContent: "# API\n\nVersion every endpoint."},
+				})
+			}
+
+			metrics := server.cache.GetPerformanceMetrics()
+			if metrics["content_interning"] != tt.enabled {
+				t.Errorf("Expected content_interning %v, got %v", tt.enabled, metrics["content_interning"])
+			}
+			if metrics["interned_contents"] != tt.wantInterned {
+				t.Errorf("Expected %d interned contents, got %v", tt.wantInterned, metrics["interned_contents"])
+			}
+		})
+	}
+}
+
+func TestCacheCleanupMethod(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	defer server.Shutdown(context.Background())
+
+	// Interval long enough that only the manual trigger runs
+	server.SetCacheCleanupInterval(time.Hour)
+	server.SetCacheTTL(10 * time.Millisecond)
+
+	doc := &models.Document{Metadata: models.DocumentMetadata{Path: "mcp/resources/patterns/repository.md", Category: "pattern"}}
+	server.cache.Set(doc.Metadata.Path, doc)
+	time.Sleep(20 * time.Millisecond)
+
+	response := server.routeMessage(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "cleanup-1",
+		Method:  "server/cache-cleanup",
+	})
+	if response.Error != nil {
+		t.Fatalf("Unexpected error: %v", response.Error)
+	}
+
+	result, ok := response.Result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected map result, got %T", response.Result)
+	}
+	if result["expired"] != 1 {
+		t.Errorf("Expected 1 expired document, got %v", result["expired"])
+	}
+	if server.cache.Size() != 0 {
+		t.Error("Manual cleanup should have expired the cached document")
+	}
+}
+
+func TestCacheStatsMethod(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	defer server.Shutdown(context.Background())
+
+	for _, doc := range []*models.Document{
+		{Metadata: models.DocumentMetadata{Path: "mcp/resources/patterns/repository.md", Category: "pattern"}},
+		{Metadata: models.DocumentMetadata{Path: "mcp/resources/patterns/factory.md", Category: "pattern"}},
+		{Metadata: models.DocumentMetadata{Path: "mcp/resources/adr/001-api.md", Category: "adr"}},
+	} {
+		server.cache.Set(doc.Metadata.Path, doc)
+	}
+
+	for _, method := range []string{"server/cache-stats", "server/diagnostics"} {
+		t.Run(method, func(t *testing.T) {
+			response := server.routeMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "stats-1", Method: method})
+			if response.Error != nil {
+				t.Fatalf("Unexpected error: %v", response.Error)
+			}
+
+			result, ok := response.Result.(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected map result, got %T", response.Result)
+			}
+			key := "stats"
+			if method == "server/diagnostics" {
+				key = "cache"
+			}
+			stats, ok := result[key].(cache.CacheStats)
+			if !ok {
+				t.Fatalf("Expected cache stats under %q, got %T", key, result[key])
+			}
+			if stats.Categories["pattern"].Documents != 2 || stats.Categories["adr"].Documents != 1 {
+				t.Errorf("Unexpected category breakdown %+v", stats.Categories)
+			}
+		})
+	}
+}
+
+// waitForInFlight waits until server is handling count requests
+func waitForInFlight(t *testing.T, server *MCPServer, count int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		server.requests.mu.Lock()
+		inFlight := len(server.requests.requests)
+		server.requests.mu.Unlock()
+		if inFlight == count {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d in-flight requests", count)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	t.Run("stuck request is abandoned after the timeout", func(t *testing.T) {
+		server := newMCPServerWithOptions(false)
+		server.SetShutdownTimeout(100 * time.Millisecond)
+
+		// Hold the server lock so resources/list blocks until released
+		server.mu.Lock()
+		handled := make(chan struct{})
+		go func() {
+			defer close(handled)
+			server.HandleMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "stuck", Method: "resources/list"})
+		}()
+		waitForInFlight(t, server, 1)
+
+		start := time.Now()
+		err := server.Shutdown(context.Background())
+		elapsed := time.Since(start)
+
+		if err == nil {
+			t.Fatal("Expected an error for the abandoned request")
+		}
+		if !strings.Contains(err.Error(), "resources/list") {
+			t.Errorf("Expected error to name the abandoned request, got %v", err)
+		}
+		if elapsed < 100*time.Millisecond || elapsed > time.Second {
+			t.Errorf("Expected Shutdown to return after the timeout, took %v", elapsed)
+		}
+
+		server.mu.Unlock()
+		<-handled
+	})
+
+	t.Run("request finishing within the timeout is waited for", func(t *testing.T) {
+		server := newMCPServerWithOptions(false)
+		server.SetShutdownTimeout(2 * time.Second)
+
+		server.mu.Lock()
+		handled := make(chan struct{})
+		go func() {
+			defer close(handled)
+			server.HandleMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "slow", Method: "resources/list"})
+		}()
+		waitForInFlight(t, server, 1)
+
+		start := time.Now()
+		time.AfterFunc(50*time.Millisecond, server.mu.Unlock)
+		if err := server.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Expected a clean shutdown, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Expected Shutdown to wait for the request, returned after %v", elapsed)
+		}
+		<-handled
+	})
+}
+
+func TestRequestTrackerWait(t *testing.T) {
+	tracker := newRequestTracker()
+	if abandoned := tracker.wait(time.Second); abandoned != nil {
+		t.Fatalf("Expected nothing to wait for, got %v", abandoned)
+	}
+
+	end := tracker.begin("tools/call")
+	baseline := runtime.NumGoroutine()
+	abandoned := tracker.wait(20 * time.Millisecond)
+	if !reflect.DeepEqual(abandoned, []string{"tools/call"}) {
+		t.Errorf("Abandoned %v, want [tools/call]", abandoned)
+	}
+	// A wait that times out leaves nothing behind blocked on the stuck request
+	if n := runtime.NumGoroutine(); n > baseline {
+		t.Errorf("Expected at most %d goroutines after the wait timed out, got %d", baseline, n)
+	}
+
+	time.AfterFunc(20*time.Millisecond, end)
+	if abandoned := tracker.wait(time.Second); abandoned != nil {
+		t.Errorf("Expected the request to finish within the timeout, got %v", abandoned)
+	}
+}
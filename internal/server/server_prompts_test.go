@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/logging"
 	"mcp-architecture-service/pkg/prompts"
 )
@@ -185,6 +187,151 @@ func TestHandlePromptsList(t *testing.T) {
 	}
 }
 
+func TestHandlePromptsList_MaxListed(t *testing.T) {
+	server := NewMCPServer()
+
+	tmpDir := t.TempDir()
+	names := []string{"alpha-prompt", "beta-prompt", "gamma-prompt"}
+	for _, name := range names {
+		content := `{
+			"name": "` + name + `",
+			"description": "Paging test prompt",
+			"messages": [{"role": "user", "content": {"type": "text", "text": "Prompt ` + name + `"}}]
+		}`
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".json"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test prompt file: %v", err)
+		}
+	}
+	server.promptManager = prompts.NewPromptManager(tmpDir, server.cache, server.monitor, logging.NewStructuredLogger("test"))
+	if err := server.promptManager.LoadPrompts(); err != nil {
+		t.Fatalf("Failed to load prompts: %v", err)
+	}
+	server.SetMaxListedPrompts(2)
+
+	response := server.handlePromptsList(&models.MCPMessage{JSONRPC: "2.0", ID: "page-1", Method: "prompts/list"})
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got %v", response.Error)
+	}
+	first := response.Result.(models.MCPPromptsListResult)
+	if len(first.Prompts) != 2 || first.Prompts[0].Name != "alpha-prompt" || first.Prompts[1].Name != "beta-prompt" {
+		t.Fatalf("Expected the first two prompts, got %+v", first.Prompts)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("Expected a nextCursor for the truncated list")
+	}
+
+	response = server.handlePromptsList(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "page-2",
+		Method:  "prompts/list",
+		Params:  models.MCPPromptsListParams{Cursor: first.NextCursor},
+	})
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got %v", response.Error)
+	}
+	second := response.Result.(models.MCPPromptsListResult)
+	if len(second.Prompts) != 1 || second.Prompts[0].Name != "gamma-prompt" || second.NextCursor != "" {
+		t.Fatalf("Expected only gamma-prompt on the last page, got %+v", second)
+	}
+
+	// Prompts beyond the first page can still be fetched directly
+	response = server.handlePromptsGet(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "get-unlisted",
+		Method:  "prompts/get",
+		Params:  models.MCPPromptsGetParams{Name: "gamma-prompt"},
+	})
+	result := validatePromptsGetResponse(t, response, "get-unlisted")
+	validateMessageStructure(t, result.Messages)
+}
+
+func TestSortPrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		prompts []models.MCPPrompt
+		want    []string
+	}{
+		{
+			name:    "alphabetical ignoring case",
+			prompts: []models.MCPPrompt{{Name: "beta"}, {Name: "Gamma"}, {Name: "alpha"}, {Name: "Delta"}},
+			want:    []string{"alpha", "beta", "Delta", "Gamma"},
+		},
+		{
+			name:    "names differing only in case fall back to byte order",
+			prompts: []models.MCPPrompt{{Name: "review"}, {Name: "Review"}, {Name: "REVIEW"}},
+			want:    []string{"REVIEW", "Review", "review"},
+		},
+		{
+			name:    "hyphens and digits sort by character",
+			prompts: []models.MCPPrompt{{Name: "a2"}, {Name: "b-prompt"}, {Name: "a10"}, {Name: "a-prompt"}},
+			want:    []string{"a-prompt", "a10", "a2", "b-prompt"},
+		},
+		{
+			name:    "identical names ordered by description",
+			prompts: []models.MCPPrompt{{Name: "review", Description: "second"}, {Name: "review", Description: "first"}},
+			want:    []string{"review:first", "review:second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortPrompts(tt.prompts)
+			var got []string
+			for _, prompt := range tt.prompts {
+				if prompt.Description != "" {
+					got = append(got, prompt.Name+":"+prompt.Description)
+				} else {
+					got = append(got, prompt.Name)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("sortPrompts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePromptsList_OrderAcrossDirectories(t *testing.T) {
+	server := NewMCPServer()
+
+	dirs := []string{t.TempDir(), t.TempDir()}
+	for i, names := range [][]string{{"b-prompt", "a2"}, {"a10", "a-prompt"}} {
+		for _, name := range names {
+			content := `{
+				"name": "` + name + `",
+				"description": "Ordering test prompt",
+				"messages": [{"role": "user", "content": {"type": "text", "text": "Prompt ` + name + `"}}]
+			}`
+			if err := os.WriteFile(filepath.Join(dirs[i], name+".json"), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create test prompt file: %v", err)
+			}
+		}
+	}
+	server.promptManager = prompts.NewPromptManager(dirs[0], server.cache, server.monitor, logging.NewStructuredLogger("test"))
+	if err := server.promptManager.SetPromptDirectories(dirs); err != nil {
+		t.Fatalf("Failed to set prompt directories: %v", err)
+	}
+	if err := server.promptManager.LoadPrompts(); err != nil {
+		t.Fatalf("Failed to load prompts: %v", err)
+	}
+
+	// Every listing returns the same order, whichever directory a prompt came from
+	want := "a-prompt,a10,a2,b-prompt"
+	for i := 0; i < 5; i++ {
+		response := server.handlePromptsList(&models.MCPMessage{JSONRPC: "2.0", ID: i, Method: "prompts/list"})
+		if response.Error != nil {
+			t.Fatalf("Expected no error, got %v", response.Error)
+		}
+		var names []string
+		for _, prompt := range response.Result.(models.MCPPromptsListResult).Prompts {
+			names = append(names, prompt.Name)
+		}
+		if got := strings.Join(names, ","); got != want {
+			t.Fatalf("Listing %d ordered prompts %s, want %s", i, got, want)
+		}
+	}
+}
+
 func TestHandlePromptsGet(t *testing.T) {
 	server := NewMCPServer()
 
@@ -327,6 +474,80 @@ func TestHandlePromptsGetErrors(t *testing.T) {
 	}
 }
 
+func TestPromptArgumentLimits(t *testing.T) {
+	server := NewMCPServer()
+	server.SetPromptArgumentLimits(3, 200)
+
+	setupTestPromptFromJSON(t, server, "test-limits-prompt", `{
+		"name": "test-limits-prompt",
+		"description": "A test prompt for argument limits",
+		"arguments": [{"name": "input", "description": "Test input", "required": true}],
+		"messages": [{"role": "user", "content": {"type": "text", "text": "Test: {{input}}"}}]
+	}`)
+
+	tooMany := map[string]interface{}{"input": "a"}
+	for i := 0; i < 4; i++ {
+		tooMany[fmt.Sprintf("extra%d", i)] = "x"
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		arguments    map[string]interface{}
+		wantContains string // Expected error message fragment; empty expects success
+	}{
+		{
+			name:      "within limits",
+			method:    "prompts/get",
+			arguments: map[string]interface{}{"input": "short"},
+		},
+		{
+			name:         "too many arguments",
+			method:       "prompts/get",
+			arguments:    tooMany,
+			wantContains: "Too many prompt arguments: 5 exceeds the maximum of 3",
+		},
+		{
+			name:         "oversized arguments payload",
+			method:       "prompts/get",
+			arguments:    map[string]interface{}{"input": strings.Repeat("a", 500)},
+			wantContains: "Prompt arguments too large",
+		},
+		{
+			name:         "preview shares the limits",
+			method:       "prompts/preview",
+			arguments:    tooMany,
+			wantContains: "Too many prompt arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := server.routeMessage(&models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "limits",
+				Method:  tt.method,
+				Params:  models.MCPPromptsGetParams{Name: "test-limits-prompt", Arguments: tt.arguments},
+			})
+
+			if tt.wantContains == "" {
+				if response.Error != nil {
+					t.Fatalf("Expected success, got error: %s", response.Error.Message)
+				}
+				return
+			}
+			if response.Error == nil {
+				t.Fatal("Expected an error response")
+			}
+			if response.Error.Code != -32602 {
+				t.Errorf("Expected error code -32602, got %d", response.Error.Code)
+			}
+			if !strings.Contains(response.Error.Message, tt.wantContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantContains, response.Error.Message)
+			}
+		})
+	}
+}
 func TestPromptsIntegrationFlow(t *testing.T) {
 	server := NewMCPServer()
 
@@ -410,3 +631,152 @@ func TestPromptsIntegrationFlow(t *testing.T) {
 		t.Error("Expected messages array in result")
 	}
 }
+
+func TestHandlePromptsPreview(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+	if err := server.initializeToolsSystem(); err != nil {
+		t.Fatalf("Failed to initialize tools system: %v", err)
+	}
+
+	adrPath := config.ADRPath + "/007-transactional-outbox.md"
+	server.cache.Set(adrPath, &models.Document{
+		Metadata: models.DocumentMetadata{Path: adrPath, Category: config.CategoryADR, Title: "ADR-007: Transactional Outbox"},
+		Content:  models.DocumentContent{RawContent: "Write events to an outbox table in the same transaction."},
+	})
+
+	testPromptContent := `{
+		"name": "preview-prompt",
+		"description": "A prompt for preview testing",
+		"arguments": [
+			{"name": "service", "description": "Service name", "required": true, "maxLength": 100}
+		],
+		"messages": [
+			{"role": "user", "content": {"type": "text", "text": "Review {{service}} against:\n{{resource:architecture://adr/007-transactional-outbox}}"}},
+			{"role": "assistant", "content": {"type": "text", "text": "I will check it with:\n{{tool:search-architecture}}"}}
+		]
+	}`
+	setupTestPromptFromJSON(t, server, "preview-prompt", testPromptContent)
+	server.promptManager.SetToolManager(server.toolManager)
+
+	response := server.routeMessage(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "preview",
+		Method:  "prompts/preview",
+		Params: models.MCPPromptsGetParams{
+			Name:      "preview-prompt",
+			Arguments: map[string]interface{}{"service": "billing-service"},
+		},
+	})
+	if response.Error != nil {
+		t.Fatalf("prompts/preview failed: %+v", response.Error)
+	}
+
+	result, ok := response.Result.(*models.MCPPromptsPreviewResult)
+	if !ok {
+		t.Fatalf("Expected *models.MCPPromptsPreviewResult, got %T", response.Result)
+	}
+	if result.Name != "preview-prompt" || result.MessageCount != 2 {
+		t.Errorf("Expected preview-prompt with 2 messages, got %s with %d", result.Name, result.MessageCount)
+	}
+
+	searchTool, err := server.toolManager.GetTool("search-architecture")
+	if err != nil {
+		t.Fatalf("search-architecture not registered: %v", err)
+	}
+	for _, want := range []string{
+		"[user]\nReview billing-service against:",
+		"Write events to an outbox table in the same transaction.",
+		"[assistant]\nI will check it with:",
+		"Tool: search-architecture",
+		"Description: " + searchTool.Description(),
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "{{") {
+		t.Errorf("Expected every placeholder to be rendered, got:\n%s", result.Text)
+	}
+
+	t.Run("errors match prompts/get", func(t *testing.T) {
+		for _, params := range []models.MCPPromptsGetParams{
+			{Name: ""},
+			{Name: "missing-prompt"},
+			{Name: "preview-prompt"}, // required argument missing
+		} {
+			response := server.routeMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "err", Method: "prompts/preview", Params: params})
+			if response.Error == nil || response.Error.Code != -32602 {
+				t.Errorf("Expected an invalid params error for %+v, got %+v", params, response.Error)
+			}
+		}
+	})
+}
+
+func TestHandlePromptsGetBatch(t *testing.T) {
+	server := newMCPServerWithOptions(false)
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"first-prompt", "second-prompt"} {
+		content := `{
+			"name": "` + name + `",
+			"description": "A prompt for batch testing",
+			"arguments": [{"name": "topic", "description": "Topic", "required": true}],
+			"messages": [{"role": "user", "content": {"type": "text", "text": "` + name + ` about {{topic}}"}}]
+		}`
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".json"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test prompt file: %v", err)
+		}
+	}
+	server.promptManager = prompts.NewPromptManager(tmpDir, server.cache, server.monitor, logging.NewStructuredLogger("test"))
+	if err := server.promptManager.LoadPrompts(); err != nil {
+		t.Fatalf("Failed to load prompts: %v", err)
+	}
+
+	batch := func(requests ...models.MCPPromptsGetParams) *models.MCPMessage {
+		return server.routeMessage(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "batch",
+			Method:  "prompts/get-batch",
+			Params:  models.MCPPromptsGetBatchParams{Prompts: requests},
+		})
+	}
+
+	response := batch(
+		models.MCPPromptsGetParams{Name: "first-prompt", Arguments: map[string]interface{}{"topic": "caching"}},
+		models.MCPPromptsGetParams{Name: "second-prompt"},
+	)
+	if response.Error != nil {
+		t.Fatalf("Expected partial success, got error %+v", response.Error)
+	}
+	results := response.Result.(models.MCPPromptsGetBatchResult).Results
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Name != "first-prompt" || results[0].Error != nil || results[0].Result == nil {
+		t.Fatalf("Expected first-prompt to render, got %+v", results[0])
+	}
+	if text := results[0].Result.Messages[0].Content.Text; text != "first-prompt about caching" {
+		t.Errorf("Unexpected rendered text %q", text)
+	}
+
+	if results[1].Name != "second-prompt" || results[1].Result != nil || results[1].Error == nil {
+		t.Fatalf("Expected second-prompt to fail, got %+v", results[1])
+	}
+	if results[1].Error.Code != -32602 || !strings.Contains(results[1].Error.Message, "topic") {
+		t.Errorf("Expected an invalid params error naming the missing argument, got %+v", results[1].Error)
+	}
+
+	if response := batch(); response.Error == nil {
+		t.Error("Expected an error for an empty batch")
+	}
+
+	server.SetMaxBatchPrompts(1)
+	response = batch(
+		models.MCPPromptsGetParams{Name: "first-prompt", Arguments: map[string]interface{}{"topic": "caching"}},
+		models.MCPPromptsGetParams{Name: "second-prompt", Arguments: map[string]interface{}{"topic": "queues"}},
+	)
+	if response.Error == nil || response.Error.Code != -32602 {
+		t.Errorf("Expected an invalid params error over the batch limit, got %+v", response.Error)
+	}
+}
@@ -1,12 +1,23 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/errors"
+	"mcp-architecture-service/pkg/scanner"
+	"mcp-architecture-service/pkg/tokens"
 )
 
 // setupTestCacheDocuments prepares test documents and adds them to the server cache
@@ -140,6 +151,173 @@ func TestHandleResourcesList(t *testing.T) {
 	validateResourceURIs(t, result.Resources)
 }
 
+func TestHandleResourcesList_DeterministicOrder(t *testing.T) {
+	server := NewMCPServer()
+	for _, path := range []string{
+		config.PatternsPath + "/repository.md",
+		config.GuidelinesPath + "/testing.md",
+		config.ADRPath + "/002-use-postgres.md",
+		config.GuidelinesPath + "/api-design.md",
+		config.ADRPath + "/001-use-go.md",
+	} {
+		server.cache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{
+				Title:    path,
+				Category: server.getCategoryFromPath(path),
+				Path:     path,
+			},
+		})
+	}
+
+	response := server.handleResourcesList(&models.MCPMessage{JSONRPC: "2.0", ID: "test-order", Method: "resources/list"})
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got %v", response.Error)
+	}
+	result := response.Result.(models.MCPResourcesListResult)
+
+	expected := []string{
+		"architecture://adr/001",
+		"architecture://adr/002",
+		"architecture://guidelines/api-design",
+		"architecture://guidelines/testing",
+		"architecture://patterns/repository",
+	}
+	if len(result.Resources) != len(expected) {
+		t.Fatalf("Expected %d resources, got %d", len(expected), len(result.Resources))
+	}
+	for i, uri := range expected {
+		if result.Resources[i].URI != uri {
+			t.Errorf("Position %d: expected %s, got %s", i, uri, result.Resources[i].URI)
+		}
+	}
+}
+
+func TestHandleResourcesList_MaxListed(t *testing.T) {
+	server := NewMCPServer()
+	for _, path := range []string{
+		config.PatternsPath + "/repository.md",
+		config.GuidelinesPath + "/testing.md",
+		config.ADRPath + "/002-use-postgres.md",
+		config.GuidelinesPath + "/api-design.md",
+		config.ADRPath + "/001-use-go.md",
+	} {
+		server.cache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{
+				Title:    path,
+				Category: server.getCategoryFromPath(path),
+				Path:     path,
+			},
+			Content: models.DocumentContent{RawContent: "# " + path},
+		})
+	}
+	server.SetMaxListedResources(2)
+
+	expectedPages := [][]string{
+		{"architecture://adr/001", "architecture://adr/002"},
+		{"architecture://guidelines/api-design", "architecture://guidelines/testing"},
+		{"architecture://patterns/repository"},
+	}
+
+	cursor := ""
+	for i, expected := range expectedPages {
+		response := server.handleResourcesList(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "test-page",
+			Method:  "resources/list",
+			Params:  models.MCPResourcesListParams{Cursor: cursor},
+		})
+		if response.Error != nil {
+			t.Fatalf("Page %d: expected no error, got %v", i, response.Error)
+		}
+		result := response.Result.(models.MCPResourcesListResult)
+
+		var uris []string
+		for _, resource := range result.Resources {
+			uris = append(uris, resource.URI)
+		}
+		if !reflect.DeepEqual(uris, expected) {
+			t.Errorf("Page %d: expected %v, got %v", i, expected, uris)
+		}
+
+		lastPage := i == len(expectedPages)-1
+		if (result.NextCursor == "") != lastPage {
+			t.Fatalf("Page %d: unexpected nextCursor %q", i, result.NextCursor)
+		}
+		cursor = result.NextCursor
+	}
+
+	// Resources beyond the first page can still be read directly
+	response := server.handleResourcesRead(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "test-read-unlisted",
+		Method:  "resources/read",
+		Params:  models.MCPResourcesReadParams{URI: "architecture://patterns/repository"},
+	})
+	validateResourceReadResponse(t, response, "test-read-unlisted", "architecture://patterns/repository", "# "+config.PatternsPath+"/repository.md")
+
+	response = server.handleResourcesList(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "test-bad-cursor",
+		Method:  "resources/list",
+		Params:  models.MCPResourcesListParams{Cursor: "not-a-cursor"},
+	})
+	if response.Error == nil || response.Error.Code != -32602 {
+		t.Errorf("Expected -32602 for an invalid cursor, got %+v", response.Error)
+	}
+}
+
+func TestHandleResourcesList_CategoryFilter(t *testing.T) {
+	server := NewMCPServer()
+	setupTestCacheDocuments(t, server)
+
+	tests := []struct {
+		name         string
+		category     string
+		expectedURIs []string
+		expectError  bool
+	}{
+		{"all categories", "", []string{"architecture://guidelines/api-design", "architecture://patterns/repository"}, false},
+		{"patterns only", "pattern", []string{"architecture://patterns/repository"}, false},
+		{"uri segment alias", "Patterns", []string{"architecture://patterns/repository"}, false},
+		{"known category without documents", "adr", nil, false},
+		{"unknown category", "runbooks", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := server.handleResourcesList(&models.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      "test-filter",
+				Method:  "resources/list",
+				Params:  models.MCPResourcesListParams{Category: tt.category},
+			})
+
+			if tt.expectError {
+				if response.Error == nil || response.Error.Code != -32602 {
+					t.Fatalf("Expected invalid params error, got %+v", response.Error)
+				}
+				if !strings.Contains(fmt.Sprint(response.Error.Data), "valid_categories") {
+					t.Errorf("Expected valid categories in error data, got %v", response.Error.Data)
+				}
+				return
+			}
+
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got %v", response.Error)
+			}
+			resources := response.Result.(models.MCPResourcesListResult).Resources
+			if len(resources) != len(tt.expectedURIs) {
+				t.Fatalf("Expected %d resources, got %d", len(tt.expectedURIs), len(resources))
+			}
+			for i, uri := range tt.expectedURIs {
+				if resources[i].URI != uri {
+					t.Errorf("Position %d: expected %s, got %s", i, uri, resources[i].URI)
+				}
+			}
+		})
+	}
+}
+
 // validateResourceReadResponse validates a successful resource read response
 func validateResourceReadResponse(t *testing.T, response *models.MCPMessage, expectedID, expectedURI, expectedContent string) {
 	t.Helper()
@@ -266,3 +444,602 @@ func TestHandleResourcesReadErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleResourcesList_ReadingAnnotations(t *testing.T) {
+	server := NewMCPServer()
+	setupTestCacheDocuments(t, server)
+
+	response := server.handleResourcesList(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "test-list",
+		Method:  "resources/list",
+	})
+	result := validateResourceListBasics(t, response, "test-list")
+
+	for _, resource := range result.Resources {
+		if resource.Annotations["path"] != config.GuidelinesPath+"/api-design.md" {
+			continue
+		}
+		// "# API Design Guidelines\nThis is a test document." splits into 9 words
+		if resource.Annotations["wordCount"] != "9" {
+			t.Errorf("Expected wordCount 9, got %s", resource.Annotations["wordCount"])
+		}
+		if resource.Annotations["readingMinutes"] != "1" {
+			t.Errorf("Expected readingMinutes 1, got %s", resource.Annotations["readingMinutes"])
+		}
+		return
+	}
+	t.Fatal("Guideline resource not found")
+}
+
+func TestCreateMCPResourceFromDocument_EstimatedTokens(t *testing.T) {
+	server := NewMCPServer()
+
+	estimate := func(content string) int {
+		doc := &models.Document{
+			Metadata: models.DocumentMetadata{Path: config.GuidelinesPath + "/doc.md", Category: config.CategoryGuideline},
+			Content:  models.DocumentContent{RawContent: content},
+		}
+		value := server.createMCPResourceFromDocument(doc).Annotations["estimatedTokens"]
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			t.Fatalf("Expected a numeric estimatedTokens annotation, got %q", value)
+		}
+		return count
+	}
+
+	if got := estimate(""); got != 0 {
+		t.Errorf("Expected 0 estimated tokens for an empty document, got %d", got)
+	}
+	if got := estimate("# API Design"); got != 3 {
+		t.Errorf("Expected 3 estimated tokens for 12 characters, got %d", got)
+	}
+
+	previous := 0
+	for _, length := range []int{1, 10, 100, 1000, 10000} {
+		got := estimate(strings.Repeat("a", length))
+		if got < previous {
+			t.Errorf("Expected estimate to grow with content length, got %d for %d characters after %d", got, length, previous)
+		}
+		previous = got
+	}
+	if previous <= estimate("short") {
+		t.Errorf("Expected a long document to estimate more tokens than a short one")
+	}
+
+	server.SetTokenEstimator(tokens.EstimatorFunc(func(text string) int { return len(strings.Fields(text)) }))
+	if got := estimate("one two three"); got != 3 {
+		t.Errorf("Expected the custom estimator to count 3 tokens, got %d", got)
+	}
+
+	server.SetTokenEstimator(nil)
+	if got := estimate("one two three"); got != 4 {
+		t.Errorf("Expected nil to restore the default estimator (4 tokens), got %d", got)
+	}
+}
+
+func TestHandleResourcesList_StatusAnnotation(t *testing.T) {
+	server := NewMCPServer()
+
+	tempDir := t.TempDir()
+	docScanner := scanner.NewDocumentationScanner(tempDir)
+
+	docs := []struct {
+		path     string
+		content  string
+		expected string
+	}{
+		{config.ADRPath + "/001-use-go.md", "# ADR-001: Use Go\n\n## Status\nAccepted", "accepted"},
+		{config.GuidelinesPath + "/logging.md", "---\nstatus: draft\n---\n# Logging", "draft"},
+		{config.GuidelinesPath + "/testing.md", "# Testing\n\n**Status**: Approved", "approved"},
+		{config.PatternsPath + "/repository.md", "# Repository Pattern", "unknown"},
+	}
+	for _, doc := range docs {
+		fullPath := filepath.Join(tempDir, doc.path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(doc.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", doc.path, err)
+		}
+
+		// Parse through the scanner so the status is recorded as at load time
+		metadata, err := docScanner.ParseMarkdownFile(fullPath)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", doc.path, err)
+		}
+		metadata.Category = server.getCategoryFromPath(doc.path)
+		server.cache.Set(doc.path, &models.Document{
+			Metadata: *metadata,
+			Content:  models.DocumentContent{RawContent: doc.content},
+		})
+	}
+
+	response := server.handleResourcesList(&models.MCPMessage{JSONRPC: "2.0", ID: "test-status", Method: "resources/list"})
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got %v", response.Error)
+	}
+
+	statuses := make(map[string]string)
+	for _, resource := range response.Result.(models.MCPResourcesListResult).Resources {
+		statuses[resource.Annotations["path"]] = resource.Annotations["status"]
+	}
+	for _, doc := range docs {
+		if statuses[doc.path] != doc.expected {
+			t.Errorf("Expected status %q for %s, got %q", doc.expected, doc.path, statuses[doc.path])
+		}
+	}
+}
+
+func TestLoadDocumentIntoCache_RecordsWordCount(t *testing.T) {
+	server := NewMCPServer()
+	path := filepath.Join(t.TempDir(), "long-guideline.md")
+	content := "# Long Guideline\n" + strings.Repeat("word ", 448)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test document: %v", err)
+	}
+
+	if err := server.loadDocumentIntoCache(context.Background(), models.DocumentMetadata{Path: path, Category: config.CategoryGuideline}); err != nil {
+		t.Fatalf("loadDocumentIntoCache failed: %v", err)
+	}
+
+	doc, err := server.cache.Get(path)
+	if err != nil {
+		t.Fatalf("Document not cached: %v", err)
+	}
+	if doc.Metadata.WordCount != 451 {
+		t.Errorf("Expected WordCount 451, got %d", doc.Metadata.WordCount)
+	}
+
+	resource := server.createMCPResourceFromDocument(doc)
+	if resource.Annotations["readingMinutes"] != "3" {
+		t.Errorf("Expected readingMinutes 3, got %s", resource.Annotations["readingMinutes"])
+	}
+}
+
+func TestReadingMinutes(t *testing.T) {
+	tests := []struct {
+		name      string
+		wordCount int
+		expected  int
+	}{
+		{"empty document", 0, 0},
+		{"single word", 1, 1},
+		{"exactly one minute", config.ReadingWordsPerMinute, 1},
+		{"just over one minute", config.ReadingWordsPerMinute + 1, 2},
+		{"ten minutes", config.ReadingWordsPerMinute * 10, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readingMinutes(tt.wordCount); got != tt.expected {
+				t.Errorf("readingMinutes(%d) = %d, want %d", tt.wordCount, got, tt.expected)
+			}
+		})
+	}
+}
+
+// fakeHistoryProvider returns canned versions and records the document it was asked about
+type fakeHistoryProvider struct {
+	versions  []models.MCPResourceVersion
+	err       error
+	requested string
+}
+
+func (f *fakeHistoryProvider) GetDocumentHistory(ctx context.Context, doc *models.Document) ([]models.MCPResourceVersion, error) {
+	f.requested = doc.Metadata.Path
+	return f.versions, f.err
+}
+
+// historyRequest builds a resources/history message for a URI
+func historyRequest(uri string) *models.MCPMessage {
+	return &models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "test-history",
+		Method:  "resources/history",
+		Params:  models.MCPResourcesHistoryParams{URI: uri},
+	}
+}
+
+func TestHandleResourcesHistory_DelegatesToProvider(t *testing.T) {
+	server := NewMCPServer()
+	setupTestCacheDocuments(t, server)
+
+	provider := &fakeHistoryProvider{
+		versions: []models.MCPResourceVersion{
+			{Timestamp: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), Author: "alice", Summary: "Add pagination rules", Revision: "b2c3d4"},
+			{Timestamp: time.Date(2023, 11, 20, 14, 30, 0, 0, time.UTC), Author: "bob", Summary: "Initial version", Revision: "a1b2c3"},
+		},
+	}
+	server.SetHistoryProvider(provider)
+
+	response := server.HandleMessage(historyRequest("architecture://guidelines/api-design"))
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got %v", response.Error)
+	}
+
+	if provider.requested != config.GuidelinesPath+"/api-design.md" {
+		t.Errorf("Provider asked about %q, want the guideline path", provider.requested)
+	}
+
+	result, ok := response.Result.(models.MCPResourcesHistoryResult)
+	if !ok {
+		t.Fatal("Expected result to be MCPResourcesHistoryResult")
+	}
+	if result.URI != "architecture://guidelines/api-design" {
+		t.Errorf("Expected URI echoed back, got %s", result.URI)
+	}
+	if len(result.Versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(result.Versions))
+	}
+	if result.Versions[0].Author != "alice" || result.Versions[1].Summary != "Initial version" {
+		t.Errorf("Versions not returned as provided: %+v", result.Versions)
+	}
+}
+
+func TestHandleResourcesHistory_DefaultProviderReturnsEmptyHistory(t *testing.T) {
+	server := NewMCPServer()
+	setupTestCacheDocuments(t, server)
+
+	response := server.HandleMessage(historyRequest("architecture://patterns/repository"))
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got %v", response.Error)
+	}
+
+	result := response.Result.(models.MCPResourcesHistoryResult)
+	if result.Versions == nil || len(result.Versions) != 0 {
+		t.Errorf("Expected empty non-nil versions, got %v", result.Versions)
+	}
+}
+
+func TestHandleResourcesHistory_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		uri          string
+		providerErr  error
+		expectedCode int
+	}{
+		{"missing uri", "", nil, -32602},
+		{"invalid uri", "http://example.com/doc", nil, -32602},
+		{"unknown resource", "architecture://guidelines/missing", nil, -32603},
+		{"provider failure", "architecture://guidelines/api-design", fmt.Errorf("git not available"), -32603},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer()
+			setupTestCacheDocuments(t, server)
+			server.SetHistoryProvider(&fakeHistoryProvider{err: tt.providerErr})
+
+			response := server.HandleMessage(historyRequest(tt.uri))
+			if response.Error == nil {
+				t.Fatal("Expected error response")
+			}
+			if response.Error.Code != tt.expectedCode {
+				t.Errorf("Expected error code %d, got %d", tt.expectedCode, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestHandleResourcesHistory_ErrorVerbosity(t *testing.T) {
+	cause := fmt.Errorf("git log failed: %w", os.ErrPermission)
+
+	tests := []struct {
+		name        string
+		verbosity   errors.ErrorVerbosity
+		expectCause bool
+	}{
+		{"terse by default", "", false},
+		{"terse", errors.ErrorVerbosityTerse, false},
+		{"verbose", errors.ErrorVerbosityVerbose, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer()
+			setupTestCacheDocuments(t, server)
+			server.SetHistoryProvider(&fakeHistoryProvider{err: cause})
+			if tt.verbosity != "" {
+				server.SetErrorVerbosity(tt.verbosity)
+			}
+
+			response := server.HandleMessage(historyRequest("architecture://guidelines/api-design"))
+			if response.Error == nil {
+				t.Fatal("Expected error response")
+			}
+
+			// The JSON-RPC code, message and error code stay stable across verbosities
+			if response.Error.Code != -32603 || response.Error.Message != "Document history unavailable" {
+				t.Errorf("Unexpected error %d %q", response.Error.Code, response.Error.Message)
+			}
+			data := response.Error.Data.(map[string]interface{})
+			if data["code"] != errors.ErrCodeHistoryUnavailable {
+				t.Errorf("Expected code %s, got %v", errors.ErrCodeHistoryUnavailable, data["code"])
+			}
+
+			payload, _ := json.Marshal(response.Error)
+			leaked := strings.Contains(string(payload), "git log failed")
+			if leaked != tt.expectCause {
+				t.Errorf("Expected cause in payload: %v, payload: %s", tt.expectCause, payload)
+			}
+			if tt.expectCause {
+				causes, _ := data["causes"].([]string)
+				if len(causes) != 2 || !strings.Contains(causes[1], "permission denied") {
+					t.Errorf("Expected the full cause chain, got %v", data["causes"])
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryDocumentSource_PopulatesCache(t *testing.T) {
+	// Run from an empty directory so nothing can come from disk
+	originalDir, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	t.Cleanup(func() { os.Chdir(originalDir) })
+
+	source := NewMemoryDocumentSource()
+	docs := map[string]string{
+		config.GuidelinesPath + "/api-design.md": "# API Design\n\nUse nouns for resource names.",
+		config.PatternsPath + "/repository.md":   "# Repository Pattern\n\nMediates between domain and data mapping.",
+		config.ADRPath + "/001-use-go.md":        "# ADR 001: Use Go\n\n## Status\n\nAccepted",
+	}
+	for path, content := range docs {
+		if err := source.Add(path, content); err != nil {
+			t.Fatalf("Add(%s) unexpected error: %v", path, err)
+		}
+	}
+	if err := source.Add("docs/elsewhere.md", "# Elsewhere"); err == nil {
+		t.Error("Expected an error adding a document outside the documentation directories")
+	}
+
+	server := newMCPServerWithOptions(false)
+	server.SetDocumentSource(source)
+	if err := server.initializeDocumentationSystem(context.Background()); err != nil {
+		t.Fatalf("initializeDocumentationSystem() unexpected error: %v", err)
+	}
+
+	response := server.HandleMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "list", Method: "resources/list"})
+	if response.Error != nil {
+		t.Fatalf("resources/list error: %v", response.Error)
+	}
+	result := response.Result.(models.MCPResourcesListResult)
+	var uris []string
+	for _, resource := range result.Resources {
+		uris = append(uris, resource.URI)
+	}
+	expected := []string{
+		"architecture://adr/001",
+		"architecture://guidelines/api-design",
+		"architecture://patterns/repository",
+	}
+	if !reflect.DeepEqual(uris, expected) {
+		t.Errorf("Listed %v, want %v", uris, expected)
+	}
+
+	response = server.HandleMessage(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "read",
+		Method:  "resources/read",
+		Params:  models.MCPResourcesReadParams{URI: "architecture://patterns/repository"},
+	})
+	validateResourceReadResponse(t, response, "read", "architecture://patterns/repository", docs[config.PatternsPath+"/repository.md"])
+
+	// Removed documents drop out on reload
+	source.Remove(config.PatternsPath + "/repository.md")
+	summary, err := server.ReloadChanged(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadChanged() unexpected error: %v", err)
+	}
+	if summary.Removed != 1 || server.cache.Size() != 2 {
+		t.Errorf("Expected one document removed leaving 2, got %+v with %d cached", summary, server.cache.Size())
+	}
+}
+
+// blockingReadSource is a MemoryDocumentSource whose reads wait until released
+type blockingReadSource struct {
+	*MemoryDocumentSource
+	reading chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingReadSource) Read(ctx context.Context, path string) ([]byte, error) {
+	b.reading <- struct{}{}
+	<-b.release
+	return b.MemoryDocumentSource.Read(ctx, path)
+}
+
+func TestReloadChanged_ServesRequestsWhileReading(t *testing.T) {
+	memory := NewMemoryDocumentSource()
+	if err := memory.Add(config.GuidelinesPath+"/api-design.md", "# API Design\n\nUse nouns."); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	server := newMCPServerWithOptions(false)
+	server.SetDocumentSource(memory)
+	if err := server.initializeDocumentationSystem(context.Background()); err != nil {
+		t.Fatalf("initializeDocumentationSystem() unexpected error: %v", err)
+	}
+
+	source := &blockingReadSource{MemoryDocumentSource: memory, reading: make(chan struct{}), release: make(chan struct{})}
+	server.SetDocumentSource(source)
+	if err := memory.Add(config.PatternsPath+"/repository.md", "# Repository Pattern\n\nMediates."); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+
+	reloaded := make(chan error, 1)
+	go func() {
+		_, err := server.ReloadChanged(context.Background())
+		reloaded <- err
+	}()
+	<-source.reading
+
+	// The new document is being read; listing must not wait for it
+	listed := make(chan *models.MCPMessage, 1)
+	go func() {
+		listed <- server.HandleMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "list", Method: "resources/list"})
+	}()
+	select {
+	case response := <-listed:
+		if response.Error != nil {
+			t.Errorf("resources/list error: %v", response.Error)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resources/list blocked while the reload read documents")
+	}
+
+	close(source.release)
+	if err := <-reloaded; err != nil {
+		t.Fatalf("ReloadChanged() unexpected error: %v", err)
+	}
+	if server.cache.Size() != 2 {
+		t.Errorf("Expected 2 cached documents after the reload, got %d", server.cache.Size())
+	}
+}
+
+func TestFSDocumentSource_ListsResources(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		config.GuidelinesPath + "/api-design.md":      {Data: []byte("# API Design\n\nUse nouns for resource names."), ModTime: modified},
+		config.GuidelinesPath + "/nested/security.md": {Data: []byte("# Security\n\nValidate every input.")},
+		config.GuidelinesPath + "/diagram.png":        {Data: []byte{0x89, 'P', 'N', 'G'}},
+		config.PatternsPath + "/repository.md":        {Data: []byte("# Repository Pattern\n\nMediates between domain and data mapping.")},
+		"unrelated/readme.md":                         {Data: []byte("# Not documentation")},
+	}
+
+	// Documents are parsed with the server's settings
+	server := newMCPServerWithOptions(false)
+	server.SetChecksumAlgorithm(scanner.ChecksumNone)
+	source := NewFSDocumentSource(fsys, server.DocumentScanner())
+	indexes, err := source.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if indexes[config.CategoryADR] == nil || indexes[config.CategoryADR].Count != 0 {
+		t.Errorf("Expected an empty ADR index for the missing directory, got %+v", indexes[config.CategoryADR])
+	}
+	guidelines := indexes[config.CategoryGuideline]
+	if guidelines.Count != 2 || len(guidelines.Skipped) != 1 {
+		t.Errorf("Expected 2 guidelines and 1 skipped file, got %d and %v", guidelines.Count, guidelines.Skipped)
+	}
+	if !guidelines.Documents[0].LastModified.Equal(modified) {
+		t.Errorf("Expected LastModified from the file system, got %v", guidelines.Documents[0].LastModified)
+	}
+	if checksum := guidelines.Documents[0].Checksum; checksum != "" {
+		t.Errorf("Expected no checksum with the server's checksums disabled, got %q", checksum)
+	}
+
+	server.SetDocumentSource(source)
+	if err := server.initializeDocumentationSystem(context.Background()); err != nil {
+		t.Fatalf("initializeDocumentationSystem() unexpected error: %v", err)
+	}
+
+	response := server.HandleMessage(&models.MCPMessage{JSONRPC: "2.0", ID: "list", Method: "resources/list"})
+	if response.Error != nil {
+		t.Fatalf("resources/list error: %v", response.Error)
+	}
+	var uris []string
+	for _, resource := range response.Result.(models.MCPResourcesListResult).Resources {
+		uris = append(uris, resource.URI)
+	}
+	expected := []string{
+		"architecture://guidelines/api-design",
+		"architecture://guidelines/nested/security",
+		"architecture://patterns/repository",
+	}
+	if !reflect.DeepEqual(uris, expected) {
+		t.Errorf("Listed %v, want %v", uris, expected)
+	}
+
+	response = server.HandleMessage(&models.MCPMessage{
+		JSONRPC: "2.0",
+		ID:      "read",
+		Method:  "resources/read",
+		Params:  models.MCPResourcesReadParams{URI: "architecture://guidelines/nested/security"},
+	})
+	validateResourceReadResponse(t, response, "read", "architecture://guidelines/nested/security", "# Security\n\nValidate every input.")
+}
+
+func TestHandleResourcesRead_ReadThrough(t *testing.T) {
+	source := NewMemoryDocumentSource()
+	repository := "# Repository Pattern\n\nMediates between domain and data mapping."
+	if err := source.Add(config.PatternsPath+"/repository.md", repository); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	if err := source.Add(config.PatternsPath+"/service-catalog.json", `{"services": []}`); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+
+	read := func(server *MCPServer, uri string) *models.MCPMessage {
+		return server.HandleMessage(&models.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      "read",
+			Method:  "resources/read",
+			Params:  models.MCPResourcesReadParams{URI: uri},
+		})
+	}
+
+	// The cache is never warmed, so only read-through can find the documents
+	server := newMCPServerWithOptions(false)
+	server.SetDocumentSource(source)
+	if response := read(server, "architecture://patterns/repository"); response.Error == nil {
+		t.Fatal("Expected not found without read-through")
+	}
+
+	server.SetReadThrough(true)
+	validateResourceReadResponse(t, read(server, "architecture://patterns/repository"), "read", "architecture://patterns/repository", repository)
+	if _, err := server.cache.Get(config.PatternsPath + "/repository.md"); err != nil {
+		t.Errorf("Expected the document to be cached after the first read: %v", err)
+	}
+	response := read(server, "architecture://patterns/service-catalog.json")
+	if response.Error != nil {
+		t.Fatalf("Expected the JSON resource to be read through, got %v", response.Error)
+	}
+	if content := response.Result.(models.MCPResourcesReadResult).Contents[0]; content.MimeType != config.MimeTypeJSON || content.Text != `{"services": []}` {
+		t.Errorf("Unexpected JSON resource content: %+v", content)
+	}
+
+	if response := read(server, "architecture://patterns/missing"); response.Error == nil {
+		t.Error("Expected not found for a document the source does not have")
+	}
+}
+
+func TestReloadChanged_WithoutChecksums(t *testing.T) {
+	source := NewMemoryDocumentSource()
+	source.SetChecksumAlgorithm(scanner.ChecksumNone)
+	path := config.GuidelinesPath + "/api-design.md"
+	if err := source.Add(path, "# API Design\n\nUse nouns for resource names."); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+
+	server := newMCPServerWithOptions(false)
+	server.SetDocumentSource(source)
+	if err := server.initializeDocumentationSystem(context.Background()); err != nil {
+		t.Fatalf("initializeDocumentationSystem() unexpected error: %v", err)
+	}
+
+	response := server.handleResourcesList(&models.MCPMessage{JSONRPC: "2.0", ID: "list", Method: "resources/list"})
+	resources := response.Result.(models.MCPResourcesListResult).Resources
+	if _, ok := resources[0].Annotations["checksum"]; ok {
+		t.Errorf("Expected no checksum annotation when checksums are disabled, got %v", resources[0].Annotations)
+	}
+
+	summary, err := server.ReloadChanged(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadChanged() unexpected error: %v", err)
+	}
+	if summary.Updated != 0 {
+		t.Errorf("Expected an unchanged document not to be reloaded, got %+v", summary)
+	}
+
+	if err := source.Add(path, "# API Design\n\nUse nouns for resource names and version in the path."); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	summary, err = server.ReloadChanged(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadChanged() unexpected error: %v", err)
+	}
+	if summary.Updated != 1 {
+		t.Errorf("Expected the changed document to be detected by size, got %+v", summary)
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds how long Shutdown waits for in-flight requests and
+// cleanup before giving up on them
+const DefaultShutdownTimeout = 10 * time.Second
+
+// requestTracker records the requests being handled so Shutdown can wait for them
+// and report the ones it had to abandon
+type requestTracker struct {
+	mu       sync.Mutex
+	nextID   uint64
+	requests map[uint64]string // request sequence number -> method
+	idle     chan struct{}     // closed once the requests in flight have all ended
+}
+
+// newRequestTracker creates an empty requestTracker
+func newRequestTracker() *requestTracker {
+	return &requestTracker{requests: make(map[uint64]string)}
+}
+
+// begin records a request for method and returns the function that ends it
+func (rt *requestTracker) begin(method string) func() {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	id := rt.nextID
+	rt.nextID++
+	if len(rt.requests) == 0 {
+		rt.idle = make(chan struct{})
+	}
+	rt.requests[id] = method
+
+	return func() {
+		rt.mu.Lock()
+		defer rt.mu.Unlock()
+
+		delete(rt.requests, id)
+		if len(rt.requests) == 0 {
+			close(rt.idle)
+		}
+	}
+}
+
+// wait blocks until no requests are in flight or timeout passes, returning the
+// methods of the requests still running, sorted
+func (rt *requestTracker) wait(timeout time.Duration) []string {
+	rt.mu.Lock()
+	if len(rt.requests) == 0 {
+		rt.mu.Unlock()
+		return nil
+	}
+	idle := rt.idle
+	rt.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-idle:
+		return nil
+	case <-timer.C:
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	methods := make([]string, 0, len(rt.requests))
+	for _, method := range rt.requests {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// SetShutdownTimeout bounds how long Shutdown waits for in-flight requests to finish,
+// and then for cleanup to complete. Work still running when it passes is abandoned
+// and logged, and Shutdown returns an error. Zero or less abandons in-flight
+// requests at once and leaves cleanup unbounded. Defaults to DefaultShutdownTimeout.
+func (s *MCPServer) SetShutdownTimeout(timeout time.Duration) {
+	s.shutdownTimeout = timeout
+}
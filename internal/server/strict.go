@@ -0,0 +1,51 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetStrict makes startup fail, rather than load best-effort, when documentation
+// or prompts have issues: a document file skipped or failing to parse, a prompt
+// file failing to load, or documents sharing a resource URI, such as duplicate ADR
+// IDs. Meant for CI and staging. Must be called before Start.
+func (s *MCPServer) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// strictViolations lists the issues strict mode refuses to start with
+func (s *MCPServer) strictViolations() []string {
+	var violations []string
+
+	s.mu.RLock()
+	for _, system := range []string{"documentation", "prompts"} {
+		if err := s.startupErrors[system]; err != nil {
+			violations = append(violations, fmt.Sprintf("%s failed to initialize: %v", system, err))
+		}
+	}
+	s.mu.RUnlock()
+
+	if report := s.DocumentationReport(); report != nil {
+		for _, skipped := range report.SkippedFiles {
+			violations = append(violations, fmt.Sprintf("skipped %s: %s", skipped.Path, skipped.Reason))
+		}
+		violations = append(violations, report.ParseWarnings...)
+		for _, duplicate := range report.DuplicateIDs {
+			violations = append(violations, fmt.Sprintf("duplicate resource %s: %s", duplicate.URI, strings.Join(duplicate.Paths, ", ")))
+		}
+	}
+
+	for _, loadErr := range s.promptManager.LoadErrors() {
+		violations = append(violations, "invalid prompt "+loadErr)
+	}
+	return violations
+}
+
+// checkStrict returns an error describing every strict mode violation, if any
+func (s *MCPServer) checkStrict() error {
+	violations := s.strictViolations()
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict mode: %d documentation and prompt issues: %s", len(violations), strings.Join(violations, "; "))
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStartup_Strict(t *testing.T) {
+	validPrompt := `{"name": "review", "description": "Review code", "messages": [{"role": "user", "content": {"type": "text", "text": "Review this"}}]}`
+
+	tests := []struct {
+		name    string
+		docs    func(env *testEnv) map[string]string
+		prompt  string
+		strict  bool
+		lenient bool
+		wantErr string
+	}{
+		{
+			name:   "clean documentation passes strict mode",
+			docs:   standardTestDocs,
+			prompt: validPrompt,
+			strict: true,
+		},
+		{
+			name: "malformed document fails strict mode",
+			docs: func(env *testEnv) map[string]string {
+				docs := standardTestDocs(env)
+				docs[filepath.Join(env.patternsDir, "empty.md")] = ""
+				return docs
+			},
+			prompt:  validPrompt,
+			strict:  true,
+			wantErr: "empty.md",
+		},
+		{
+			name: "malformed document is skipped without strict mode",
+			docs: func(env *testEnv) map[string]string {
+				docs := standardTestDocs(env)
+				docs[filepath.Join(env.patternsDir, "empty.md")] = ""
+				return docs
+			},
+			prompt: validPrompt,
+		},
+		{
+			name:    "invalid prompt fails strict mode",
+			docs:    standardTestDocs,
+			prompt:  `{"name": "review"`,
+			strict:  true,
+			wantErr: "invalid prompt",
+		},
+		{
+			name:    "non-conforming prompt name loads when lenient",
+			docs:    standardTestDocs,
+			prompt:  `{"name": "Review_Code", "messages": [{"role": "user", "content": {"type": "text", "text": "Review this"}}]}`,
+			lenient: true,
+		},
+		{
+			name:    "strict mode rejects non-conforming prompt names even when lenient",
+			docs:    standardTestDocs,
+			prompt:  `{"name": "Review_Code", "messages": [{"role": "user", "content": {"type": "text", "text": "Review this"}}]}`,
+			strict:  true,
+			lenient: true,
+			wantErr: "prompt name must match pattern",
+		},
+		{
+			name: "duplicate ADR IDs fail strict mode",
+			docs: func(env *testEnv) map[string]string {
+				docs := standardTestDocs(env)
+				docs[filepath.Join(env.adrDir, "001-microservices-architecture.markdown")] = "# ADR-001: Duplicate\n\nSame ID as another ADR.\n"
+				return docs
+			},
+			prompt:  validPrompt,
+			strict:  true,
+			wantErr: "duplicate resource",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEnv(t)
+			env.writeTestDocs(t, tt.docs(env))
+			promptsDir := filepath.Join(env.tempDir, "mcp", "prompts")
+			if err := os.MkdirAll(promptsDir, 0755); err != nil {
+				t.Fatalf("Failed to create prompts directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(promptsDir, "review.json"), []byte(tt.prompt), 0644); err != nil {
+				t.Fatalf("Failed to write prompt: %v", err)
+			}
+			originalDir, _ := os.Getwd()
+			os.Chdir(env.tempDir)
+			t.Cleanup(func() { env.cleanup(t, originalDir) })
+
+			server := NewMCPServer()
+			server.SetStrict(tt.strict)
+			server.SetLenientPromptNames(tt.lenient)
+			err := server.startup(context.Background())
+			defer server.Shutdown(context.Background())
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("startup() unexpected error: %v", err)
+				}
+				if tt.lenient {
+					if _, err := server.promptManager.GetPrompt("Review_Code"); err != nil {
+						t.Errorf("Expected the leniently named prompt to load: %v", err)
+					}
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("startup() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mcp-architecture-service/pkg/logging"
+)
+
+// subsystem is a background component of the server, such as a watcher or a
+// cleanup ticker, that Start starts and Shutdown stops
+type subsystem struct {
+	name  string
+	start func(ctx context.Context) error // nil for a component already running
+	stop  func() error
+}
+
+// subsystemRegistry starts subsystems in the order they were registered and stops
+// the running ones in reverse order
+type subsystemRegistry struct {
+	mu      sync.Mutex
+	pending []subsystem // Registered but not started yet
+	running []subsystem // In start order
+}
+
+// newSubsystemRegistry creates an empty subsystemRegistry
+func newSubsystemRegistry() *subsystemRegistry {
+	return &subsystemRegistry{}
+}
+
+// register adds a subsystem, started by the next startAll. A nil start registers a
+// component that is already running, so it is stopped even if startAll never runs.
+func (r *subsystemRegistry) register(name string, start func(ctx context.Context) error, stop func() error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub := subsystem{name: name, start: start, stop: stop}
+	if start == nil {
+		r.running = append(r.running, sub)
+		return
+	}
+	r.pending = append(r.pending, sub)
+}
+
+// registerLoop registers a subsystem running loop in its own goroutine. loop must
+// return once ctx is done or the server shuts down; stopping waits for it to.
+func (r *subsystemRegistry) registerLoop(name string, loop func(ctx context.Context)) {
+	done := make(chan struct{})
+	r.register(name, func(ctx context.Context) error {
+		go func() {
+			defer close(done)
+			loop(ctx)
+		}()
+		return nil
+	}, func() error {
+		<-done
+		return nil
+	})
+}
+
+// startAll starts the registered subsystems in order. One that fails to start is
+// logged and left out, so the server runs degraded rather than not at all.
+func (r *subsystemRegistry) startAll(ctx context.Context, logger *logging.StructuredLogger) {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	for _, sub := range pending {
+		if err := sub.start(ctx); err != nil {
+			logger.WithError(err).WithContext("subsystem", sub.name).Error("Subsystem start failed")
+			continue
+		}
+		r.mu.Lock()
+		r.running = append(r.running, sub)
+		r.mu.Unlock()
+		logger.WithContext("subsystem", sub.name).Debug("Subsystem started")
+	}
+}
+
+// stopAll stops the running subsystems in reverse start order, returning the names
+// of those still stopping when timeout passes. Without a positive timeout it waits
+// for all of them.
+func (r *subsystemRegistry) stopAll(timeout time.Duration, logger *logging.StructuredLogger) []string {
+	r.mu.Lock()
+	running := r.running
+	r.running = nil
+	r.mu.Unlock()
+
+	var mu sync.Mutex
+	remaining := len(running) // running[:remaining] have not finished stopping
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(running) - 1; i >= 0; i-- {
+			sub := running[i]
+			stopStart := time.Now()
+			subLogger := logger.WithContext("subsystem", sub.name)
+			if err := sub.stop(); err != nil {
+				subLogger.WithContext("duration_ms", time.Since(stopStart).Milliseconds()).
+					WithError(err).Error("Subsystem stop failed")
+			} else {
+				subLogger.WithContext("duration_ms", time.Since(stopStart).Milliseconds()).
+					Info("Subsystem stopped")
+			}
+
+			mu.Lock()
+			remaining = i
+			mu.Unlock()
+		}
+	}()
+
+	if timeout <= 0 {
+		<-done
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, remaining)
+	for i := remaining - 1; i >= 0; i-- {
+		names = append(names, running[i].name)
+	}
+	return names
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-architecture-service/pkg/logging"
+)
+
+func TestSubsystemRegistry_StopsInReverseOrder(t *testing.T) {
+	registry := newSubsystemRegistry()
+	logger := logging.NewStructuredLogger("test")
+
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		registry.register(name, func(ctx context.Context) error {
+			record("start " + name)
+			return nil
+		}, func() error {
+			record("stop " + name)
+			return nil
+		})
+	}
+	registry.register("running", nil, func() error {
+		record("stop running")
+		return nil
+	})
+
+	registry.startAll(context.Background(), logger)
+	if abandoned := registry.stopAll(time.Second, logger); len(abandoned) != 0 {
+		t.Errorf("Expected no abandoned subsystems, got %v", abandoned)
+	}
+
+	want := []string{
+		"start first", "start second", "start third",
+		"stop third", "stop second", "stop first", "stop running",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got %v, want %v", events, want)
+	}
+	if abandoned := registry.stopAll(time.Second, logger); len(abandoned) != 0 || len(events) != len(want) {
+		t.Error("Expected a second stopAll to stop nothing")
+	}
+}
+
+func TestSubsystemRegistry_FailedStartIsNotStopped(t *testing.T) {
+	registry := newSubsystemRegistry()
+	logger := logging.NewStructuredLogger("test")
+
+	stopped := false
+	registry.register("broken", func(ctx context.Context) error {
+		return io.ErrUnexpectedEOF
+	}, func() error {
+		stopped = true
+		return nil
+	})
+
+	registry.startAll(context.Background(), logger)
+	registry.stopAll(time.Second, logger)
+	if stopped {
+		t.Error("Expected a subsystem that failed to start not to be stopped")
+	}
+}
+
+func TestSubsystemRegistry_StopTimeout(t *testing.T) {
+	registry := newSubsystemRegistry()
+	logger := logging.NewStructuredLogger("test")
+
+	release := make(chan struct{})
+	defer close(release)
+	registry.register("slow", nil, func() error {
+		<-release
+		return nil
+	})
+	registry.register("stuck", nil, func() error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	abandoned := registry.stopAll(50*time.Millisecond, logger)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected stopAll to return after the timeout, took %v", elapsed)
+	}
+	if want := []string{"stuck", "slow"}; !reflect.DeepEqual(abandoned, want) {
+		t.Errorf("Abandoned %v, want %v", abandoned, want)
+	}
+}
+
+func TestStartupShutdown_NoGoroutineLeak(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeTestDocs(t, standardTestDocs(env))
+	if err := os.MkdirAll(filepath.Join(env.tempDir, "mcp", "prompts"), 0755); err != nil {
+		t.Fatalf("Failed to create prompts directory: %v", err)
+	}
+	originalDir, _ := os.Getwd()
+	os.Chdir(env.tempDir)
+	t.Cleanup(func() { env.cleanup(t, originalDir) })
+
+	baseline := runtime.NumGoroutine()
+
+	server := NewMCPServer()
+	if server.monitor == nil {
+		t.Skip("File system monitor unavailable")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := server.startup(ctx); err != nil {
+		t.Fatalf("startup() unexpected error: %v", err)
+	}
+
+	input, inputWriter := io.Pipe()
+	processed := make(chan error, 1)
+	go func() {
+		processed <- server.processMessages(ctx, input, io.Discard)
+	}()
+	inputWriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}` + "\n"))
+	inputWriter.Close()
+	if err := <-processed; err != nil {
+		t.Fatalf("processMessages() unexpected error: %v", err)
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	// Some goroutines, such as the file watcher's reader, exit just after Shutdown
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > baseline {
+		buf := make([]byte, 1<<16)
+		t.Errorf("Expected at most %d goroutines after shutdown, got %d:\n%s", baseline, n, buf[:runtime.Stack(buf, true)])
+	}
+}
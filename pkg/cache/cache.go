@@ -1,16 +1,28 @@
 package cache
 
 import (
+	"crypto/md5"
+	"fmt"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
 )
 
-// DocumentCache provides in-memory caching for documentation with optimized memory usage
+// DocumentCache provides in-memory caching for documentation with optimized memory usage.
+//
+// Documents are shared, not copied: Get, GetAllDocuments and GetByCategory return
+// the pointers the cache holds, so every caller sees the same document. Cached
+// documents must be treated as immutable; a caller that needs to change one should
+// build a new document and Set it. Mutating a cached document in place changes it
+// for all readers and throws off memory accounting and content interning.
 type DocumentCache struct {
 	documents      map[string]*models.Document
 	indexes        map[string]*models.DocumentIndex
@@ -24,17 +36,89 @@ type DocumentCache struct {
 	cleanupTicker  *time.Ticker
 	stopCleanup    chan struct{}
 	logger         *logging.StructuredLogger
+
+	// Content interning lets documents with identical content share one string
+	interning       bool
+	internedContent map[string]*internedContent // content checksum -> shared content
+	contentKeys     map[string]string           // document key -> content checksum
+
+	// Documents are stored under their cleaned filesystem path. uriKeys resolves the
+	// resource URI uriFunc derives for each document back to that path; a nil
+	// uriFunc disables URI keys.
+	uriFunc URIFunc
+	uriKeys map[string]string
+
+	// Per-category document counts and memory, kept up to date as documents are
+	// stored and removed. charged records the memory each document was counted with.
+	categoryStats map[string]*CategoryStats
+	charged       map[string]int64
+
+	// version changes whenever the set of cached documents changes, so callers can
+	// tell whether results derived from the cache are still current
+	version uint64
+
+	// Entries older than ttl are expired by Cleanup; zero disables expiry
+	ttl      time.Duration
+	storedAt map[string]time.Time
+
+	// Low hit ratio warnings; a zero threshold disables them. The ratio is measured
+	// over the lookups of each window rather than since the cache was created.
+	hitRatioThreshold float64       // percentage, as returned by GetCacheHitRatio
+	hitRatioWindow    time.Duration // how long each measured window lasts
+	windowStart       time.Time
+	windowHits        int64
+	windowMisses      int64
+}
+
+// DefaultHitRatioThreshold is the hit ratio percentage below which the cache warns
+const DefaultHitRatioThreshold = 50.0
+
+// DefaultHitRatioWindow is how long the hit ratio is measured over before it is
+// compared with the threshold, and so the minimum time between warnings
+const DefaultHitRatioWindow = time.Minute
+
+// minHitRatioLookups is the number of lookups a window needs before its hit ratio
+// is considered meaningful
+const minHitRatioLookups = 20
+
+// URIFunc derives the resource URI a document is addressed by from its category
+// and path
+type URIFunc func(category, path string) string
+
+// internedContent is a RawContent string shared by every document that holds it
+type internedContent struct {
+	content string
+	keys    map[string]bool // Keys of the documents holding the content
 }
 
 // CacheStats tracks cache performance metrics
 type CacheStats struct {
-	Hits          int64     `json:"hits"`
-	Misses        int64     `json:"misses"`
-	Invalidations int64     `json:"invalidations"`
-	LastCleanup   time.Time `json:"lastCleanup"`
-	MemoryUsage   int64     `json:"memoryUsage"` // Approximate memory usage in bytes
+	Hits                int64     `json:"hits"`
+	Misses              int64     `json:"misses"`
+	Invalidations       int64     `json:"invalidations"`
+	Expirations         int64     `json:"expirations"`
+	LowHitRatioWarnings int64     `json:"lowHitRatioWarnings"` // Warnings logged for a sustained low hit ratio
+	LastCleanup         time.Time `json:"lastCleanup"`
+	MemoryUsage         int64     `json:"memoryUsage"` // Approximate memory usage in bytes
+
+	// Categories breaks the documents and memory down by category. Interned content
+	// held by more than one document is counted once in SharedContentMemory rather
+	// than in any category; content only one document holds counts toward that
+	// document's category. The category memory plus SharedContentMemory is MemoryUsage.
+	Categories          map[string]CategoryStats `json:"categories"`
+	SharedContentMemory int64                    `json:"sharedContentMemory"`
+}
+
+// CategoryStats is the share of the cache held by one document category
+type CategoryStats struct {
+	Documents   int   `json:"documents"`
+	MemoryUsage int64 `json:"memoryUsage"` // Approximate memory usage in bytes
 }
 
+// documentOverhead approximates the memory a cached document takes besides its
+// content: metadata, entry overhead and the document and category map entries
+const documentOverhead = 200 + 100 + 50 + 100
+
 // NewDocumentCache creates a new document cache with memory optimizations
 func NewDocumentCache() *DocumentCache {
 	// Create a default logger for the cache
@@ -42,13 +126,23 @@ func NewDocumentCache() *DocumentCache {
 	logger := loggingManager.GetLogger("cache")
 
 	cache := &DocumentCache{
-		documents:      make(map[string]*models.Document),
-		indexes:        make(map[string]*models.DocumentIndex),
-		pathToCategory: make(map[string]string),
-		stats:          CacheStats{LastCleanup: time.Now()},
-		maxMemoryUsage: 256 * 1024 * 1024, // 256MB default limit
-		stopCleanup:    make(chan struct{}),
-		logger:         logger,
+		documents:       make(map[string]*models.Document),
+		indexes:         make(map[string]*models.DocumentIndex),
+		pathToCategory:  make(map[string]string),
+		stats:           CacheStats{LastCleanup: time.Now()},
+		maxMemoryUsage:  256 * 1024 * 1024, // 256MB default limit
+		stopCleanup:     make(chan struct{}),
+		logger:          logger,
+		internedContent: make(map[string]*internedContent),
+		contentKeys:     make(map[string]string),
+		storedAt:        make(map[string]time.Time),
+		uriFunc:         config.ResourceURI,
+		uriKeys:         make(map[string]string),
+		categoryStats:   make(map[string]*CategoryStats),
+		charged:         make(map[string]int64),
+
+		hitRatioThreshold: DefaultHitRatioThreshold,
+		hitRatioWindow:    DefaultHitRatioWindow,
 	}
 
 	// Initialize memory pool for document reuse
@@ -97,15 +191,18 @@ func (dc *DocumentCache) performMemoryCleanup() {
 	}
 }
 
-// Get retrieves a document from the cache by key (path)
+// Get retrieves a document from the cache by key, either its path or its resource
+// URI. The document is shared and must not be modified.
 func (dc *DocumentCache) Get(key string) (*models.Document, error) {
 	dc.mutex.RLock()
-	document, exists := dc.documents[key]
+	document, exists := dc.documents[dc.canonicalKey(key)]
 	dc.mutex.RUnlock()
 
 	if !exists {
 		dc.mutex.Lock()
 		dc.stats.Misses++
+		dc.windowMisses++
+		dc.checkHitRatio()
 		dc.mutex.Unlock()
 		return nil, errors.NewCacheError(errors.ErrCodeCacheMiss,
 			"Document not found in cache", nil).
@@ -114,25 +211,343 @@ func (dc *DocumentCache) Get(key string) (*models.Document, error) {
 
 	dc.mutex.Lock()
 	dc.stats.Hits++
+	dc.windowHits++
+	dc.checkHitRatio()
 	dc.mutex.Unlock()
 	return document, nil
 }
 
-// Set stores a document in the cache with memory optimization
+// SetHitRatioWarning configures the low hit ratio warning. The hit ratio (a
+// percentage) is measured over consecutive windows of the given length, and a WARN
+// with the current stats is logged for each window whose ratio fell below threshold.
+// A threshold of zero or less disables the warning; a window of zero or less uses
+// DefaultHitRatioWindow.
+func (dc *DocumentCache) SetHitRatioWarning(threshold float64, window time.Duration) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+
+	if window <= 0 {
+		window = DefaultHitRatioWindow
+	}
+	dc.hitRatioThreshold = threshold
+	dc.hitRatioWindow = window
+	dc.resetHitRatioWindow(time.Now())
+}
+
+// resetHitRatioWindow starts a new hit ratio window (must be called with lock held)
+func (dc *DocumentCache) resetHitRatioWindow(now time.Time) {
+	dc.windowStart = now
+	dc.windowHits = 0
+	dc.windowMisses = 0
+}
+
+// checkHitRatio logs a warning when a hit ratio window has ended with a ratio below
+// the threshold, then starts the next window (must be called with lock held)
+func (dc *DocumentCache) checkHitRatio() {
+	now := time.Now()
+	if dc.windowStart.IsZero() {
+		dc.windowStart = now
+	}
+	if now.Sub(dc.windowStart) < dc.hitRatioWindow {
+		return
+	}
+
+	hits, misses := dc.windowHits, dc.windowMisses
+	dc.resetHitRatioWindow(now)
+	if dc.hitRatioThreshold <= 0 || hits+misses < minHitRatioLookups {
+		return
+	}
+
+	ratio := float64(hits) / float64(hits+misses) * 100.0
+	if ratio >= dc.hitRatioThreshold {
+		return
+	}
+
+	dc.stats.LowHitRatioWarnings++
+	dc.logger.WithContext("cache_hit_ratio", ratio).
+		WithContext("threshold", dc.hitRatioThreshold).
+		WithContext("window_hits", hits).
+		WithContext("window_misses", misses).
+		WithContext("cache_hits", dc.stats.Hits).
+		WithContext("cache_misses", dc.stats.Misses).
+		WithContext("total_documents", len(dc.documents)).
+		WithContext("memory_usage_bytes", dc.stats.MemoryUsage).
+		Warn("Cache hit ratio below threshold")
+}
+
+// Set stores a document in the cache with memory optimization. The key may be the
+// document's path or its resource URI; a URI key stores the document under its
+// Metadata.Path when it has one. The cache takes ownership of document; the caller
+// must not modify it afterwards.
 func (dc *DocumentCache) Set(key string, document *models.Document) {
 	dc.mutex.Lock()
 	defer dc.mutex.Unlock()
 
+	if isURIKey(key) && document.Metadata.Path != "" {
+		key = cleanKey(document.Metadata.Path)
+	} else {
+		key = dc.canonicalKey(key)
+	}
+
 	// Check if we need to perform cleanup before adding new document
 	if dc.stats.MemoryUsage > dc.maxMemoryUsage*80/100 { // 80% threshold
 		dc.performLRUCleanup()
 	}
 
+	// Release the replaced document's content before possibly interning the new one
+	dc.unchargeDocument(key)
+	dc.releaseContent(key)
+	if dc.interning {
+		dc.internContent(key, document)
+	}
+
+	dc.unindexURI(key)
 	dc.documents[key] = document
 	dc.pathToCategory[key] = document.Metadata.Category
+	dc.storedAt[key] = time.Now()
+	dc.indexURI(key)
+	dc.chargeDocument(key)
+	dc.version++
 	dc.updateMemoryUsage()
 }
 
+// SetURIFunc sets how resource URIs are derived for URI keys and rebuilds the URI
+// index. The default is config.ResourceURI; nil disables URI keys.
+func (dc *DocumentCache) SetURIFunc(uriFunc URIFunc) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+
+	dc.uriFunc = uriFunc
+	dc.uriKeys = make(map[string]string)
+	for key := range dc.documents {
+		dc.indexURI(key)
+	}
+}
+
+// isURIKey reports whether key is a resource URI rather than a path
+func isURIKey(key string) bool {
+	return strings.HasPrefix(key, config.URIScheme)
+}
+
+// cleanKey normalizes a path key to its cleaned, slash-separated form
+func cleanKey(key string) string {
+	return filepath.ToSlash(filepath.Clean(key))
+}
+
+// canonicalKey maps a path or resource URI to the key its document is stored
+// under. Unknown URIs are returned unchanged (must be called with lock held).
+func (dc *DocumentCache) canonicalKey(key string) string {
+	if isURIKey(key) {
+		if path, exists := dc.uriKeys[key]; exists {
+			return path
+		}
+		return key
+	}
+	return cleanKey(key)
+}
+
+// uriFor returns the resource URI of the document stored under key, or "" when URI
+// keys are disabled (must be called with lock held)
+func (dc *DocumentCache) uriFor(key string) string {
+	document, exists := dc.documents[key]
+	if dc.uriFunc == nil || !exists || isURIKey(key) {
+		return ""
+	}
+	return dc.uriFunc(document.Metadata.Category, key)
+}
+
+// indexURI makes the document stored under key reachable by its resource URI
+// (must be called with lock held)
+func (dc *DocumentCache) indexURI(key string) {
+	if uri := dc.uriFor(key); uri != "" {
+		dc.uriKeys[uri] = key
+	}
+}
+
+// unindexURI removes the URI entry of the document stored under key, unless the
+// URI has since been claimed by another document (must be called with lock held)
+func (dc *DocumentCache) unindexURI(key string) {
+	if uri := dc.uriFor(key); uri != "" && dc.uriKeys[uri] == key {
+		delete(dc.uriKeys, uri)
+	}
+}
+
+// removeDocument deletes the document stored under key along with its
+// bookkeeping (must be called with lock held)
+func (dc *DocumentCache) removeDocument(key string) {
+	dc.unchargeDocument(key)
+	dc.releaseContent(key)
+	dc.unindexURI(key)
+	delete(dc.documents, key)
+	delete(dc.pathToCategory, key)
+	delete(dc.storedAt, key)
+	dc.version++
+}
+
+// documentMemory estimates the memory taken by the document stored under key.
+// Content shared with other documents is left out; it is counted once in
+// SharedContentMemory (must be called with lock held).
+func (dc *DocumentCache) documentMemory(key string) int64 {
+	memory := int64(documentOverhead)
+	if !dc.sharesContent(key) {
+		memory += int64(len(dc.documents[key].Content.RawContent))
+	}
+	return memory
+}
+
+// sharesContent reports whether the document stored under key holds interned
+// content that other documents hold too (must be called with lock held)
+func (dc *DocumentCache) sharesContent(key string) bool {
+	checksum, interned := dc.contentKeys[key]
+	if !interned {
+		return false
+	}
+	entry := dc.internedContent[checksum]
+	return entry != nil && len(entry.keys) > 1
+}
+
+// rechargeDocument recounts the document stored under key after the memory
+// attributed to it changed (must be called with lock held)
+func (dc *DocumentCache) rechargeDocument(key string) {
+	dc.unchargeDocument(key)
+	dc.chargeDocument(key)
+}
+
+// chargeDocument adds the document stored under key to its category's stats
+// (must be called with lock held)
+func (dc *DocumentCache) chargeDocument(key string) {
+	category := dc.pathToCategory[key]
+	stats := dc.categoryStats[category]
+	if stats == nil {
+		stats = &CategoryStats{}
+		dc.categoryStats[category] = stats
+	}
+
+	memory := dc.documentMemory(key)
+	stats.Documents++
+	stats.MemoryUsage += memory
+	dc.charged[key] = memory
+}
+
+// unchargeDocument removes the document stored under key from its category's
+// stats (must be called with lock held, before the document is removed)
+func (dc *DocumentCache) unchargeDocument(key string) {
+	memory, ok := dc.charged[key]
+	if !ok {
+		return
+	}
+	delete(dc.charged, key)
+
+	category := dc.pathToCategory[key]
+	if stats := dc.categoryStats[category]; stats != nil {
+		stats.Documents--
+		stats.MemoryUsage -= memory
+		if stats.Documents <= 0 {
+			delete(dc.categoryStats, category)
+		}
+	}
+}
+
+// rebuildCategoryStats recounts every category from scratch, for when the memory
+// attributed to documents changes without them being stored again (must be called
+// with lock held)
+func (dc *DocumentCache) rebuildCategoryStats() {
+	dc.categoryStats = make(map[string]*CategoryStats)
+	dc.charged = make(map[string]int64)
+	for key := range dc.documents {
+		dc.chargeDocument(key)
+	}
+}
+
+// SetTTL sets how long a document stays cached before Cleanup expires it.
+// Zero or less disables expiry.
+func (dc *DocumentCache) SetTTL(ttl time.Duration) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+
+	if ttl < 0 {
+		ttl = 0
+	}
+	dc.ttl = ttl
+}
+
+// SetContentInterning enables or disables content interning. When enabled, Set
+// shares one RawContent string between documents with identical content. Only
+// documents stored after enabling are interned.
+func (dc *DocumentCache) SetContentInterning(enabled bool) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+
+	dc.interning = enabled
+	if !enabled {
+		// Documents keep their (possibly shared) strings; only the bookkeeping goes
+		dc.internedContent = make(map[string]*internedContent)
+		dc.contentKeys = make(map[string]string)
+		dc.rebuildCategoryStats()
+		dc.updateMemoryUsage()
+	}
+}
+
+// internContent points the document at the shared copy of its content, registering
+// the content if it is new (must be called with lock held)
+func (dc *DocumentCache) internContent(key string, document *models.Document) {
+	if document == nil || document.Content.RawContent == "" {
+		return
+	}
+
+	checksum := fmt.Sprintf("%x", md5.Sum([]byte(document.Content.RawContent)))
+	entry, exists := dc.internedContent[checksum]
+	if !exists {
+		dc.internedContent[checksum] = &internedContent{
+			content: document.Content.RawContent,
+			keys:    map[string]bool{key: true},
+		}
+		dc.contentKeys[key] = checksum
+		return
+	}
+
+	// A checksum collision must not make two different documents share content
+	if entry.content != document.Content.RawContent {
+		return
+	}
+
+	document.Content.RawContent = entry.content
+	entry.keys[key] = true
+	dc.contentKeys[key] = checksum
+
+	// The first holder's content just became shared, so it leaves its category
+	if len(entry.keys) == 2 {
+		for holder := range entry.keys {
+			if holder != key {
+				dc.rechargeDocument(holder)
+			}
+		}
+	}
+}
+
+// releaseContent drops a document's reference to interned content, freeing the
+// shared copy when the last reference goes (must be called with lock held)
+func (dc *DocumentCache) releaseContent(key string) {
+	checksum, exists := dc.contentKeys[key]
+	if !exists {
+		return
+	}
+	delete(dc.contentKeys, key)
+
+	if entry := dc.internedContent[checksum]; entry != nil {
+		delete(entry.keys, key)
+		switch len(entry.keys) {
+		case 0:
+			delete(dc.internedContent, checksum)
+		case 1:
+			// The last holder no longer shares the content, so it counts toward its category
+			for holder := range entry.keys {
+				dc.rechargeDocument(holder)
+			}
+		}
+	}
+}
+
 // performLRUCleanup removes least recently used documents to free memory
 func (dc *DocumentCache) performLRUCleanup() {
 	// Simple cleanup strategy: remove 10% of documents
@@ -149,8 +564,7 @@ func (dc *DocumentCache) performLRUCleanup() {
 		if count >= len(dc.documents)-targetSize {
 			break
 		}
-		delete(dc.documents, key)
-		delete(dc.pathToCategory, key)
+		dc.removeDocument(key)
 		count++
 	}
 
@@ -164,8 +578,7 @@ func (dc *DocumentCache) Invalidate(key string) {
 	dc.mutex.Lock()
 	defer dc.mutex.Unlock()
 
-	delete(dc.documents, key)
-	delete(dc.pathToCategory, key)
+	dc.removeDocument(dc.canonicalKey(key))
 	dc.stats.Invalidations++
 	dc.updateMemoryUsage()
 }
@@ -178,10 +591,26 @@ func (dc *DocumentCache) Clear() {
 	dc.documents = make(map[string]*models.Document)
 	dc.indexes = make(map[string]*models.DocumentIndex)
 	dc.pathToCategory = make(map[string]string)
+	dc.internedContent = make(map[string]*internedContent)
+	dc.contentKeys = make(map[string]string)
+	dc.storedAt = make(map[string]time.Time)
+	dc.uriKeys = make(map[string]string)
+	dc.categoryStats = make(map[string]*CategoryStats)
+	dc.charged = make(map[string]int64)
+	dc.version++
 	dc.stats.LastCleanup = time.Now()
 	dc.updateMemoryUsage()
 }
 
+// Version returns a counter that changes whenever a document is stored, removed or
+// expired, or the cache is cleared. Results computed from the cache at one version
+// are stale once it returns a different value.
+func (dc *DocumentCache) Version() uint64 {
+	dc.mutex.RLock()
+	defer dc.mutex.RUnlock()
+	return dc.version
+}
+
 // Close stops the cache cleanup goroutine and releases resources
 func (dc *DocumentCache) Close() {
 	close(dc.stopCleanup)
@@ -203,15 +632,36 @@ func (dc *DocumentCache) SetIndex(category string, index *models.DocumentIndex)
 	dc.indexes[category] = index
 }
 
-// GetAllDocuments returns all cached documents
+// getAllDocumentsBatch is how many documents GetAllDocuments copies per read lock
+const getAllDocumentsBatch = 256
+
+// GetAllDocuments returns all cached documents. The keys are snapshotted under a
+// short read lock and the documents are then copied in batches, releasing the lock
+// between batches so writers are not blocked for the whole copy on large caches.
+// Documents invalidated during the copy are left out and documents added after the
+// key snapshot are not included. The documents are shared and must not be modified.
 func (dc *DocumentCache) GetAllDocuments() map[string]*models.Document {
 	dc.mutex.RLock()
-	defer dc.mutex.RUnlock()
+	keys := make([]string, 0, len(dc.documents))
+	for key := range dc.documents {
+		keys = append(keys, key)
+	}
+	dc.mutex.RUnlock()
 
-	// Create a copy to avoid concurrent access issues
-	result := make(map[string]*models.Document)
-	for key, doc := range dc.documents {
-		result[key] = doc
+	result := make(map[string]*models.Document, len(keys))
+	for start := 0; start < len(keys); start += getAllDocumentsBatch {
+		end := start + getAllDocumentsBatch
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		dc.mutex.RLock()
+		for _, key := range keys[start:end] {
+			if doc, exists := dc.documents[key]; exists {
+				result[key] = doc
+			}
+		}
+		dc.mutex.RUnlock()
 	}
 
 	return result
@@ -239,7 +689,8 @@ func (dc *DocumentCache) Size() int {
 	return len(dc.documents)
 }
 
-// GetByCategory retrieves all documents for a specific category
+// GetByCategory retrieves all documents for a specific category. The documents
+// are shared and must not be modified.
 func (dc *DocumentCache) GetByCategory(category string) []*models.Document {
 	dc.mutex.RLock()
 	defer dc.mutex.RUnlock()
@@ -256,6 +707,57 @@ func (dc *DocumentCache) GetByCategory(category string) []*models.Document {
 	return documents
 }
 
+// SortBy selects the order of documents returned by GetByCategorySorted
+type SortBy string
+
+const (
+	// SortByPath orders documents by path
+	SortByPath SortBy = "path"
+	// SortByTitle orders documents by title, then path
+	SortByTitle SortBy = "title"
+	// SortByRecency orders documents newest first by last modification, then path
+	SortByRecency SortBy = "recency"
+)
+
+// GetByCategorySorted returns the documents of a category in a deterministic order,
+// keeping at most limit of them when limit is positive. An unrecognized sortBy
+// orders by path.
+func (dc *DocumentCache) GetByCategorySorted(category string, sortBy SortBy, limit int) []*models.Document {
+	documents := dc.GetByCategory(category)
+
+	var less func(a, b *models.Document) bool
+	switch sortBy {
+	case SortByTitle:
+		less = func(a, b *models.Document) bool {
+			if a.Metadata.Title != b.Metadata.Title {
+				return a.Metadata.Title < b.Metadata.Title
+			}
+			return a.Metadata.Path < b.Metadata.Path
+		}
+	case SortByRecency:
+		less = func(a, b *models.Document) bool {
+			if !a.Metadata.LastModified.Equal(b.Metadata.LastModified) {
+				return a.Metadata.LastModified.After(b.Metadata.LastModified)
+			}
+			return a.Metadata.Path < b.Metadata.Path
+		}
+	default:
+		less = func(a, b *models.Document) bool {
+			return a.Metadata.Path < b.Metadata.Path
+		}
+	}
+
+	sort.Slice(documents, func(i, j int) bool {
+		return less(documents[i], documents[j])
+	})
+
+	if limit > 0 && len(documents) > limit {
+		documents = documents[:limit]
+	}
+
+	return documents
+}
+
 // InvalidateByCategory removes all documents of a specific category from the cache
 func (dc *DocumentCache) InvalidateByCategory(category string) int {
 	dc.mutex.Lock()
@@ -273,8 +775,7 @@ func (dc *DocumentCache) InvalidateByCategory(category string) int {
 
 	// Delete collected paths
 	for _, path := range pathsToDelete {
-		delete(dc.documents, path)
-		delete(dc.pathToCategory, path)
+		dc.removeDocument(path)
 		invalidatedCount++
 	}
 
@@ -291,9 +792,9 @@ func (dc *DocumentCache) InvalidateByPaths(paths []string) int {
 
 	var invalidatedCount int
 	for _, path := range paths {
+		path = dc.canonicalKey(path)
 		if _, exists := dc.documents[path]; exists {
-			delete(dc.documents, path)
-			delete(dc.pathToCategory, path)
+			dc.removeDocument(path)
 			invalidatedCount++
 		}
 	}
@@ -309,19 +810,40 @@ func (dc *DocumentCache) GetStats() CacheStats {
 	dc.mutex.RLock()
 	defer dc.mutex.RUnlock()
 
-	return dc.stats
+	stats := dc.stats
+	stats.Categories = make(map[string]CategoryStats, len(dc.categoryStats))
+	for category, categoryStats := range dc.categoryStats {
+		stats.Categories[category] = *categoryStats
+	}
+	return stats
 }
 
 // Cleanup performs memory cleanup and garbage collection
-func (dc *DocumentCache) Cleanup() {
+// and returns the number of documents expired because they outlived the TTL
+func (dc *DocumentCache) Cleanup() int {
 	dc.mutex.Lock()
 	defer dc.mutex.Unlock()
 
+	expired := 0
+	if dc.ttl > 0 {
+		cutoff := time.Now().Add(-dc.ttl)
+		for key, storedAt := range dc.storedAt {
+			if storedAt.After(cutoff) {
+				continue
+			}
+			dc.removeDocument(key)
+			expired++
+		}
+		dc.stats.Expirations += int64(expired)
+	}
+
 	// Force garbage collection to free up memory
 	runtime.GC()
 
 	dc.stats.LastCleanup = time.Now()
 	dc.updateMemoryUsage()
+
+	return expired
 }
 
 // updateMemoryUsage estimates the memory usage of the cache (must be called with lock held)
@@ -330,16 +852,20 @@ func (dc *DocumentCache) updateMemoryUsage() {
 
 	// Estimate memory usage based on document count and average size
 	// This is an approximation since exact memory measurement is complex in Go
-	for _, doc := range dc.documents {
-		// Estimate: metadata (~200 bytes) + content size + overhead
-		memUsage += 200 + int64(len(doc.Content.RawContent)) + 100
+	for key := range dc.documents {
+		memUsage += dc.documentMemory(key)
 	}
 
-	// Add overhead for maps and indexes
-	memUsage += int64(len(dc.documents) * 50)       // Map overhead
-	memUsage += int64(len(dc.pathToCategory) * 100) // Path mapping overhead
+	// Shared content is stored once no matter how many documents hold it
+	var shared int64
+	for _, entry := range dc.internedContent {
+		if len(entry.keys) > 1 {
+			shared += int64(len(entry.content))
+		}
+	}
 
-	dc.stats.MemoryUsage = memUsage
+	dc.stats.MemoryUsage = memUsage + shared
+	dc.stats.SharedContentMemory = shared
 }
 
 // GetCacheHitRatio returns the cache hit ratio as a percentage
@@ -347,6 +873,11 @@ func (dc *DocumentCache) GetCacheHitRatio() float64 {
 	dc.mutex.RLock()
 	defer dc.mutex.RUnlock()
 
+	return dc.hitRatio()
+}
+
+// hitRatio computes the hit ratio percentage (must be called with lock held)
+func (dc *DocumentCache) hitRatio() float64 {
 	total := dc.stats.Hits + dc.stats.Misses
 	if total == 0 {
 		return 0.0
@@ -361,16 +892,20 @@ func (dc *DocumentCache) GetPerformanceMetrics() map[string]interface{} {
 	defer dc.mutex.RUnlock()
 
 	return map[string]interface{}{
-		"total_documents":    len(dc.documents),
-		"total_categories":   len(dc.indexes),
-		"memory_usage_bytes": dc.stats.MemoryUsage,
-		"memory_limit_bytes": dc.maxMemoryUsage,
-		"memory_usage_pct":   float64(dc.stats.MemoryUsage) / float64(dc.maxMemoryUsage) * 100.0,
-		"cache_hits":         dc.stats.Hits,
-		"cache_misses":       dc.stats.Misses,
-		"cache_hit_ratio":    dc.GetCacheHitRatio(),
-		"invalidations":      dc.stats.Invalidations,
-		"last_cleanup":       dc.stats.LastCleanup,
+		"total_documents":        len(dc.documents),
+		"total_categories":       len(dc.indexes),
+		"memory_usage_bytes":     dc.stats.MemoryUsage,
+		"memory_limit_bytes":     dc.maxMemoryUsage,
+		"memory_usage_pct":       float64(dc.stats.MemoryUsage) / float64(dc.maxMemoryUsage) * 100.0,
+		"cache_hits":             dc.stats.Hits,
+		"cache_misses":           dc.stats.Misses,
+		"cache_hit_ratio":        dc.hitRatio(),
+		"invalidations":          dc.stats.Invalidations,
+		"expirations":            dc.stats.Expirations,
+		"low_hit_ratio_warnings": dc.stats.LowHitRatioWarnings,
+		"last_cleanup":           dc.stats.LastCleanup,
+		"content_interning":      dc.interning,
+		"interned_contents":      len(dc.internedContent),
 	}
 }
 
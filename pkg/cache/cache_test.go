@@ -2,9 +2,11 @@ package cache
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+	"unsafe"
 
 	"mcp-architecture-service/internal/models"
 )
@@ -129,6 +131,55 @@ func TestDocumentCache_GetByCategory(t *testing.T) {
 	}
 }
 
+func TestDocumentCache_GetByCategorySorted(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	docs := []models.DocumentMetadata{
+		{Title: "Caching", Category: "guideline", Path: "/mcp/resources/guidelines/c.md", LastModified: base.Add(2 * time.Hour)},
+		{Title: "API Design", Category: "guideline", Path: "/mcp/resources/guidelines/b.md", LastModified: base},
+		{Title: "Backups", Category: "guideline", Path: "/mcp/resources/guidelines/a.md", LastModified: base.Add(time.Hour)},
+		{Title: "API Design", Category: "guideline", Path: "/mcp/resources/guidelines/d.md", LastModified: base.Add(time.Hour)},
+		{Title: "Repository", Category: "pattern", Path: "/mcp/resources/patterns/repository.md", LastModified: base},
+	}
+	for _, metadata := range docs {
+		cache.Set(metadata.Path, &models.Document{Metadata: metadata})
+	}
+
+	tests := []struct {
+		name     string
+		sortBy   SortBy
+		limit    int
+		expected []string
+	}{
+		{"by path", SortByPath, 0, []string{"a.md", "b.md", "c.md", "d.md"}},
+		{"by title with path tie-break", SortByTitle, 0, []string{"b.md", "d.md", "a.md", "c.md"}},
+		{"by recency newest first", SortByRecency, 0, []string{"c.md", "a.md", "d.md", "b.md"}},
+		{"unknown sort falls back to path", SortBy("size"), 0, []string{"a.md", "b.md", "c.md", "d.md"}},
+		{"limited", SortByRecency, 2, []string{"c.md", "a.md"}},
+		{"limit above count", SortByPath, 10, []string{"a.md", "b.md", "c.md", "d.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := cache.GetByCategorySorted("guideline", tt.sortBy, tt.limit)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d documents, got %d", len(tt.expected), len(result))
+			}
+			for i, name := range tt.expected {
+				if !strings.HasSuffix(result[i].Metadata.Path, "/"+name) {
+					t.Errorf("Position %d: expected %s, got %s", i, name, result[i].Metadata.Path)
+				}
+			}
+		})
+	}
+
+	if result := cache.GetByCategorySorted("nonexistent", SortByPath, 5); len(result) != 0 {
+		t.Errorf("Expected no documents for nonexistent category, got %d", len(result))
+	}
+}
+
 func TestDocumentCache_Invalidate(t *testing.T) {
 	cache := NewDocumentCache()
 
@@ -391,6 +442,143 @@ func TestDocumentCache_Cleanup(t *testing.T) {
 	}
 }
 
+func TestDocumentCache_CleanupExpiresTTLEntries(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+	cache.SetTTL(50 * time.Millisecond)
+
+	stale := &models.Document{Metadata: models.DocumentMetadata{Path: "/mcp/resources/stale.md", Category: "guideline"}}
+	cache.Set(stale.Metadata.Path, stale)
+	time.Sleep(60 * time.Millisecond)
+
+	fresh := &models.Document{Metadata: models.DocumentMetadata{Path: "/mcp/resources/fresh.md", Category: "guideline"}}
+	cache.Set(fresh.Metadata.Path, fresh)
+
+	if expired := cache.Cleanup(); expired != 1 {
+		t.Errorf("Expected 1 expired document, got %d", expired)
+	}
+	if _, err := cache.Get(stale.Metadata.Path); err == nil {
+		t.Error("Stale document should have expired")
+	}
+	if _, err := cache.Get(fresh.Metadata.Path); err != nil {
+		t.Error("Fresh document should still be cached")
+	}
+	if stats := cache.GetStats(); stats.Expirations != 1 {
+		t.Errorf("Expected 1 expiration in stats, got %d", stats.Expirations)
+	}
+
+	// Without a TTL nothing expires
+	cache.SetTTL(0)
+	time.Sleep(60 * time.Millisecond)
+	if expired := cache.Cleanup(); expired != 0 {
+		t.Errorf("Expected no expirations with TTL disabled, got %d", expired)
+	}
+}
+
+func TestDocumentCache_LowHitRatioWarning(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	const window = 50 * time.Millisecond
+	cache.SetHitRatioWarning(50.0, window)
+
+	doc := &models.Document{Metadata: models.DocumentMetadata{Path: "/mcp/resources/test.md", Category: "guideline"}}
+	cache.Set(doc.Metadata.Path, doc)
+
+	miss := func(n int) {
+		for i := 0; i < n; i++ {
+			cache.Get(fmt.Sprintf("/mcp/resources/missing-%d.md", i))
+		}
+	}
+	warnings := func() int64 {
+		return cache.GetStats().LowHitRatioWarnings
+	}
+
+	// The ratio has only just dropped, so no warning yet
+	miss(minHitRatioLookups)
+	if got := warnings(); got != 0 {
+		t.Fatalf("Expected no warning before the window elapses, got %d", got)
+	}
+
+	time.Sleep(window + 10*time.Millisecond)
+	miss(1)
+	if got := warnings(); got != 1 {
+		t.Fatalf("Expected 1 warning after a sustained low ratio, got %d", got)
+	}
+
+	// Further misses within the window are rate-limited
+	miss(50)
+	if got := warnings(); got != 1 {
+		t.Errorf("Expected warnings to be rate-limited, got %d", got)
+	}
+
+	time.Sleep(window + 10*time.Millisecond)
+	miss(1)
+	if got := warnings(); got != 2 {
+		t.Errorf("Expected a second warning after the window, got %d", got)
+	}
+
+	// A disabled threshold never warns
+	cache.SetHitRatioWarning(0, window)
+	time.Sleep(window + 10*time.Millisecond)
+	miss(1)
+	if got := warnings(); got != 2 {
+		t.Errorf("Expected no warning with the threshold disabled, got %d", got)
+	}
+}
+
+func TestDocumentCache_HealthyHitRatioDoesNotWarn(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+	cache.SetHitRatioWarning(50.0, time.Millisecond)
+
+	doc := &models.Document{Metadata: models.DocumentMetadata{Path: "/mcp/resources/test.md", Category: "guideline"}}
+	cache.Set(doc.Metadata.Path, doc)
+
+	for i := 0; i < 3*minHitRatioLookups; i++ {
+		cache.Get(doc.Metadata.Path)
+		if i%4 == 0 {
+			cache.Get("/mcp/resources/missing.md")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := cache.GetStats().LowHitRatioWarnings; got != 0 {
+		t.Errorf("Expected no warnings with a healthy hit ratio, got %d", got)
+	}
+}
+
+func TestDocumentCache_HitRatioMeasuredPerWindow(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	const window = 50 * time.Millisecond
+	cache.SetHitRatioWarning(50.0, window)
+
+	doc := &models.Document{Metadata: models.DocumentMetadata{Path: "/mcp/resources/test.md", Category: "guideline"}}
+	cache.Set(doc.Metadata.Path, doc)
+
+	// A long healthy history keeps the cumulative ratio high
+	for i := 0; i < 50*minHitRatioLookups; i++ {
+		cache.Get(doc.Metadata.Path)
+	}
+	time.Sleep(window + 10*time.Millisecond)
+
+	// A window of nothing but misses still warns
+	for i := 0; i < minHitRatioLookups; i++ {
+		cache.Get("/mcp/resources/missing.md")
+	}
+	time.Sleep(window + 10*time.Millisecond)
+	cache.Get("/mcp/resources/missing.md")
+
+	if ratio := cache.GetCacheHitRatio(); ratio < 50.0 {
+		t.Fatalf("Expected the cumulative hit ratio to stay above the threshold, got %.1f", ratio)
+	}
+	if got := cache.GetStats().LowHitRatioWarnings; got != 1 {
+		t.Errorf("Expected 1 warning for the window of misses, got %d", got)
+	}
+}
+
 // TestDocumentCache_IndexOperations tests index storage and retrieval
 func TestDocumentCache_IndexOperations(t *testing.T) {
 	cache := NewDocumentCache()
@@ -947,3 +1135,522 @@ func TestDocumentCache_StatsAccuracy(t *testing.T) {
 		t.Errorf("Expected 7 total invalidations, got %d", finalStats.Invalidations)
 	}
 }
+
+// newContentDoc creates a document with the given path and raw content
+func newContentDoc(path, content string) *models.Document {
+	return &models.Document{
+		Metadata: models.DocumentMetadata{Path: path, Category: "guideline"},
+		Content:  models.DocumentContent{RawContent: content},
+	}
+}
+
+func TestDocumentCache_ContentInterningSharesStorage(t *testing.T) {
+	content := strings.Repeat("Shared guideline content. ", 400)
+
+	// Baseline: the same two documents without interning count content twice
+	plain := NewDocumentCache()
+	defer plain.Close()
+	plain.Set("/a.md", newContentDoc("/a.md", content))
+	plain.Set("/b.md", newContentDoc("/b.md", strings.Clone(content)))
+
+	cache := NewDocumentCache()
+	defer cache.Close()
+	cache.SetContentInterning(true)
+	cache.Set("/a.md", newContentDoc("/a.md", content))
+	cache.Set("/b.md", newContentDoc("/b.md", strings.Clone(content)))
+
+	docA, _ := cache.Get("/a.md")
+	docB, _ := cache.Get("/b.md")
+	if unsafe.StringData(docA.Content.RawContent) != unsafe.StringData(docB.Content.RawContent) {
+		t.Error("Expected documents with identical content to share the backing string")
+	}
+
+	saved := plain.GetStats().MemoryUsage - cache.GetStats().MemoryUsage
+	if saved != int64(len(content)) {
+		t.Errorf("Expected interning to save %d bytes, saved %d", len(content), saved)
+	}
+
+	metrics := cache.GetPerformanceMetrics()
+	if metrics["interned_contents"] != 1 {
+		t.Errorf("Expected 1 interned content, got %v", metrics["interned_contents"])
+	}
+}
+
+func TestDocumentCache_ContentInterningReferenceCounting(t *testing.T) {
+	content := strings.Repeat("x", 1000)
+
+	tests := []struct {
+		name             string
+		invalidate       func(c *DocumentCache)
+		expectedInterned int
+		expectedContent  int64
+	}{
+		{
+			name:             "one reference released",
+			invalidate:       func(c *DocumentCache) { c.Invalidate("/a.md") },
+			expectedInterned: 1,
+			expectedContent:  1000,
+		},
+		{
+			name:             "last reference released",
+			invalidate:       func(c *DocumentCache) { c.InvalidateByPaths([]string{"/a.md", "/b.md"}) },
+			expectedInterned: 0,
+			expectedContent:  0,
+		},
+		{
+			name:             "reference replaced with different content",
+			invalidate:       func(c *DocumentCache) { c.Set("/a.md", newContentDoc("/a.md", "changed")) },
+			expectedInterned: 2,
+			expectedContent:  1000 + int64(len("changed")),
+		},
+		{
+			name:             "category invalidated",
+			invalidate:       func(c *DocumentCache) { c.InvalidateByCategory("guideline") },
+			expectedInterned: 0,
+			expectedContent:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewDocumentCache()
+			defer cache.Close()
+			cache.SetContentInterning(true)
+			cache.Set("/a.md", newContentDoc("/a.md", content))
+			cache.Set("/b.md", newContentDoc("/b.md", content))
+
+			tt.invalidate(cache)
+
+			cache.mutex.RLock()
+			interned := len(cache.internedContent)
+			var contentBytes int64
+			for _, entry := range cache.internedContent {
+				contentBytes += int64(len(entry.content))
+			}
+			cache.mutex.RUnlock()
+
+			if interned != tt.expectedInterned {
+				t.Errorf("Expected %d interned contents, got %d", tt.expectedInterned, interned)
+			}
+			if contentBytes != tt.expectedContent {
+				t.Errorf("Expected %d interned bytes, got %d", tt.expectedContent, contentBytes)
+			}
+		})
+	}
+}
+
+func TestDocumentCache_ContentInterningDisabledByDefault(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	content := strings.Repeat("y", 100)
+	cache.Set("/a.md", newContentDoc("/a.md", content))
+	cache.Set("/b.md", newContentDoc("/b.md", strings.Clone(content)))
+
+	docA, _ := cache.Get("/a.md")
+	docB, _ := cache.Get("/b.md")
+	if unsafe.StringData(docA.Content.RawContent) == unsafe.StringData(docB.Content.RawContent) {
+		t.Error("Expected content not to be shared when interning is disabled")
+	}
+	if metrics := cache.GetPerformanceMetrics(); metrics["interned_contents"] != 0 {
+		t.Errorf("Expected no interned contents, got %v", metrics["interned_contents"])
+	}
+}
+
+// TestDocumentCache_GetAllDocumentsWithConcurrentWriters tests that writers keep
+// progressing while large snapshots are taken and that snapshots stay consistent
+func TestDocumentCache_GetAllDocumentsWithConcurrentWriters(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	const numDocuments = 10000
+	for i := 0; i < numDocuments; i++ {
+		path := fmt.Sprintf("mcp/resources/patterns/doc-%d.md", i)
+		cache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Title: fmt.Sprintf("Doc %d", i), Category: "pattern", Path: path},
+		})
+	}
+
+	stop := make(chan struct{})
+	var writes int64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	// The writer invalidates even documents, so odd ones must appear in every snapshot
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			path := fmt.Sprintf("mcp/resources/guidelines/writer-%d.md", i)
+			cache.Set(path, &models.Document{
+				Metadata: models.DocumentMetadata{Title: "Writer", Category: "guideline", Path: path},
+			})
+			if i*2 < numDocuments {
+				cache.Invalidate(fmt.Sprintf("mcp/resources/patterns/doc-%d.md", i*2))
+			}
+
+			mu.Lock()
+			writes++
+			mu.Unlock()
+		}
+	}()
+
+	writesSoFar := func() int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return writes
+	}
+
+	// Keep taking snapshots until the writer has visibly progressed alongside them
+	const minWrites = 10
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for round := 0; round < 5 || writesSoFar() < minWrites; round++ {
+			snapshot := cache.GetAllDocuments()
+			for key, doc := range snapshot {
+				if doc == nil || doc.Metadata.Path != key {
+					t.Errorf("Snapshot entry %q holds the wrong document: %+v", key, doc)
+					return
+				}
+			}
+			for i := 1; i < numDocuments; i += 2 {
+				key := fmt.Sprintf("mcp/resources/patterns/doc-%d.md", i)
+				if _, ok := snapshot[key]; !ok {
+					t.Errorf("Snapshot is missing untouched document %q", key)
+					return
+				}
+			}
+
+			_ = cache.GetPerformanceMetrics()
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("Writer made %d writes while snapshots were taken, expected at least %d", writesSoFar(), minWrites)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+// TestDocumentCache_CanonicalKeys tests that paths and resource URIs address the same entry
+func TestDocumentCache_CanonicalKeys(t *testing.T) {
+	newDoc := func(category, path string) *models.Document {
+		return &models.Document{
+			Metadata: models.DocumentMetadata{Title: path, Category: category, Path: path},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		setKey   string
+		document *models.Document
+		getKeys  []string
+	}{
+		{
+			name:     "set by path",
+			setKey:   "mcp/resources/patterns/repository-pattern.md",
+			document: newDoc("pattern", "mcp/resources/patterns/repository-pattern.md"),
+			getKeys: []string{
+				"architecture://patterns/repository-pattern",
+				"./mcp/resources/patterns/repository-pattern.md",
+			},
+		},
+		{
+			name:     "set by URI",
+			setKey:   "architecture://guidelines/api-design",
+			document: newDoc("guideline", "mcp/resources/guidelines/api-design.md"),
+			getKeys: []string{
+				"mcp/resources/guidelines/api-design.md",
+				"architecture://guidelines/api-design",
+			},
+		},
+		{
+			name:     "ADR by numeric ID",
+			setKey:   "mcp/resources/adr/001-microservices-architecture.md",
+			document: newDoc("adr", "mcp/resources/adr/001-microservices-architecture.md"),
+			getKeys:  []string{"architecture://adr/001"},
+		},
+		{
+			name:     "non-default markdown extension",
+			setKey:   "mcp/resources/guidelines/deployment.markdown",
+			document: newDoc("guideline", "mcp/resources/guidelines/deployment.markdown"),
+			getKeys:  []string{"architecture://guidelines/deployment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewDocumentCache()
+			defer cache.Close()
+
+			cache.Set(tt.setKey, tt.document)
+
+			for _, key := range tt.getKeys {
+				doc, err := cache.Get(key)
+				if err != nil {
+					t.Fatalf("Get(%q) failed: %v", key, err)
+				}
+				if doc != tt.document {
+					t.Errorf("Get(%q) returned a different document", key)
+				}
+			}
+
+			all := cache.GetAllDocuments()
+			if _, ok := all[tt.document.Metadata.Path]; !ok || len(all) != 1 {
+				t.Errorf("Expected one entry keyed by path %q, got %v", tt.document.Metadata.Path, all)
+			}
+		})
+	}
+}
+
+// TestDocumentCache_CanonicalKeysSingleEntry tests that setting a document by path and
+// by URI, and invalidating it by either, works on a single entry
+func TestDocumentCache_CanonicalKeysSingleEntry(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	const path = "mcp/resources/patterns/circuit-breaker.md"
+	const uri = "architecture://patterns/circuit-breaker"
+
+	cache.Set(path, &models.Document{Metadata: models.DocumentMetadata{Title: "Old", Category: "pattern", Path: path}})
+	cache.Set(uri, &models.Document{Metadata: models.DocumentMetadata{Title: "New", Category: "pattern", Path: path}})
+
+	if cache.Size() != 1 {
+		t.Fatalf("Expected a single entry, got %d", cache.Size())
+	}
+	if doc, err := cache.Get(path); err != nil || doc.Metadata.Title != "New" {
+		t.Errorf("Expected the URI set to replace the path entry, got %v, %v", doc, err)
+	}
+
+	cache.Invalidate(uri)
+	if _, err := cache.Get(path); err == nil {
+		t.Error("Invalidating by URI should remove the path entry")
+	}
+	if _, err := cache.Get(uri); err == nil {
+		t.Error("URI should no longer resolve after invalidation")
+	}
+
+	cache.Set(path, &models.Document{Metadata: models.DocumentMetadata{Category: "pattern", Path: path}})
+	cache.SetURIFunc(nil)
+	if _, err := cache.Get(uri); err == nil {
+		t.Error("URI keys should not resolve once disabled")
+	}
+	if _, err := cache.Get(path); err != nil {
+		t.Errorf("Path keys should still resolve with URI keys disabled: %v", err)
+	}
+}
+
+// TestDocumentCache_Version tests that the version changes with every change to the
+// cached documents and stays put on reads
+func TestDocumentCache_Version(t *testing.T) {
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	const path = "mcp/resources/guidelines/api.md"
+	doc := &models.Document{Metadata: models.DocumentMetadata{Category: "guideline", Path: path}}
+
+	steps := []struct {
+		name        string
+		action      func()
+		wantChanged bool
+	}{
+		{name: "set", action: func() { cache.Set(path, doc) }, wantChanged: true},
+		{name: "get", action: func() { _, _ = cache.Get(path) }},
+		{name: "get all", action: func() { cache.GetAllDocuments() }},
+		{name: "replace", action: func() { cache.Set(path, &models.Document{Metadata: doc.Metadata}) }, wantChanged: true},
+		{name: "invalidate", action: func() { cache.Invalidate(path) }, wantChanged: true},
+		{name: "clear", action: func() { cache.Clear() }, wantChanged: true},
+	}
+
+	for _, step := range steps {
+		before := cache.Version()
+		step.action()
+		if changed := cache.Version() != before; changed != step.wantChanged {
+			t.Errorf("%s: expected version changed %v, got %v", step.name, step.wantChanged, changed)
+		}
+	}
+}
+
+// assertCategoryStatsReconcile checks that the per-category breakdown adds up to
+// the aggregate statistics of dc
+func assertCategoryStatsReconcile(t *testing.T, dc *DocumentCache, stats CacheStats) {
+	t.Helper()
+
+	documents := 0
+	memory := stats.SharedContentMemory
+	for _, category := range stats.Categories {
+		documents += category.Documents
+		memory += category.MemoryUsage
+	}
+	if documents != dc.Size() {
+		t.Errorf("Category document counts sum to %d, expected %d", documents, dc.Size())
+	}
+	if memory != stats.MemoryUsage {
+		t.Errorf("Category memory plus shared content is %d, expected %d", memory, stats.MemoryUsage)
+	}
+}
+
+func TestDocumentCache_CategoryStats(t *testing.T) {
+	newDoc := func(path, category, content string) *models.Document {
+		return &models.Document{
+			Metadata: models.DocumentMetadata{Path: path, Category: category},
+			Content:  models.DocumentContent{RawContent: content},
+		}
+	}
+
+	cache := NewDocumentCache()
+	defer cache.Close()
+
+	cache.Set("/guidelines/a.md", newDoc("/guidelines/a.md", "guideline", strings.Repeat("g", 1000)))
+	cache.Set("/guidelines/b.md", newDoc("/guidelines/b.md", "guideline", strings.Repeat("g", 500)))
+	cache.Set("/patterns/a.md", newDoc("/patterns/a.md", "pattern", strings.Repeat("p", 100)))
+	cache.Set("/adr/001.md", newDoc("/adr/001.md", "adr", ""))
+
+	tests := []struct {
+		name      string
+		mutate    func()
+		documents map[string]int
+	}{
+		{
+			name:      "initial",
+			mutate:    func() {},
+			documents: map[string]int{"guideline": 2, "pattern": 1, "adr": 1},
+		},
+		{
+			name: "replace with larger content",
+			mutate: func() {
+				cache.Set("/patterns/a.md", newDoc("/patterns/a.md", "pattern", strings.Repeat("p", 5000)))
+			},
+			documents: map[string]int{"guideline": 2, "pattern": 1, "adr": 1},
+		},
+		{
+			name: "replace into another category",
+			mutate: func() {
+				cache.Set("/adr/001.md", newDoc("/adr/001.md", "pattern", "moved"))
+			},
+			documents: map[string]int{"guideline": 2, "pattern": 2},
+		},
+		{
+			name:      "invalidate",
+			mutate:    func() { cache.Invalidate("/guidelines/b.md") },
+			documents: map[string]int{"guideline": 1, "pattern": 2},
+		},
+		{
+			name:      "invalidate category",
+			mutate:    func() { cache.InvalidateByCategory("pattern") },
+			documents: map[string]int{"guideline": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mutate()
+			stats := cache.GetStats()
+
+			if len(stats.Categories) != len(tt.documents) {
+				t.Errorf("Expected categories %v, got %+v", tt.documents, stats.Categories)
+			}
+			for category, count := range tt.documents {
+				if stats.Categories[category].Documents != count {
+					t.Errorf("Expected %d %s documents, got %d", count, category, stats.Categories[category].Documents)
+				}
+			}
+			assertCategoryStatsReconcile(t, cache, stats)
+		})
+	}
+
+	t.Run("memory follows content size", func(t *testing.T) {
+		cache.Set("/patterns/big.md", newDoc("/patterns/big.md", "pattern", strings.Repeat("p", 10000)))
+		stats := cache.GetStats()
+		if stats.Categories["pattern"].MemoryUsage <= stats.Categories["guideline"].MemoryUsage {
+			t.Errorf("Expected the pattern category to dominate memory, got %+v", stats.Categories)
+		}
+		assertCategoryStatsReconcile(t, cache, stats)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		cache.Clear()
+		stats := cache.GetStats()
+		if len(stats.Categories) != 0 || stats.SharedContentMemory != 0 {
+			t.Errorf("Expected no category stats after Clear, got %+v", stats.Categories)
+		}
+	})
+}
+
+func TestDocumentCache_CategoryStatsWithInterning(t *testing.T) {
+	content := strings.Repeat("Shared content. ", 100)
+
+	cache := NewDocumentCache()
+	defer cache.Close()
+	cache.SetContentInterning(true)
+
+	cache.Set("/guidelines/a.md", &models.Document{
+		Metadata: models.DocumentMetadata{Path: "/guidelines/a.md", Category: "guideline"},
+		Content:  models.DocumentContent{RawContent: content},
+	})
+	cache.Set("/patterns/a.md", &models.Document{
+		Metadata: models.DocumentMetadata{Path: "/patterns/a.md", Category: "pattern"},
+		Content:  models.DocumentContent{RawContent: strings.Clone(content)},
+	})
+
+	cache.Set("/adr/001-unique.md", &models.Document{
+		Metadata: models.DocumentMetadata{Path: "/adr/001-unique.md", Category: "adr"},
+		Content:  models.DocumentContent{RawContent: "Content no other document holds"},
+	})
+
+	stats := cache.GetStats()
+	if stats.SharedContentMemory != int64(len(content)) {
+		t.Errorf("Expected shared content counted once as %d bytes, got %d", len(content), stats.SharedContentMemory)
+	}
+	if got, want := stats.Categories["adr"].MemoryUsage, int64(documentOverhead+len("Content no other document holds")); got != want {
+		t.Errorf("Expected content interned by one document to count toward its category as %d bytes, got %d", want, got)
+	}
+	assertCategoryStatsReconcile(t, cache, stats)
+
+	// Once only one document holds the content it moves back into that document's category
+	cache.Invalidate("/patterns/a.md")
+	stats = cache.GetStats()
+	if stats.SharedContentMemory != 0 {
+		t.Errorf("Expected no shared content once a single document holds it, got %d", stats.SharedContentMemory)
+	}
+	if got, want := stats.Categories["guideline"].MemoryUsage, int64(documentOverhead+len(content)); got != want {
+		t.Errorf("Expected guideline memory to include its content as %d bytes, got %d", want, got)
+	}
+	assertCategoryStatsReconcile(t, cache, stats)
+
+	// Sharing it again moves it back out
+	cache.Set("/patterns/a.md", &models.Document{
+		Metadata: models.DocumentMetadata{Path: "/patterns/a.md", Category: "pattern"},
+		Content:  models.DocumentContent{RawContent: strings.Clone(content)},
+	})
+	stats = cache.GetStats()
+	if stats.SharedContentMemory != int64(len(content)) {
+		t.Errorf("Expected shared content counted once as %d bytes, got %d", len(content), stats.SharedContentMemory)
+	}
+	if got, want := stats.Categories["guideline"].MemoryUsage, int64(documentOverhead); got != want {
+		t.Errorf("Expected guideline memory without the shared content as %d bytes, got %d", want, got)
+	}
+	assertCategoryStatsReconcile(t, cache, stats)
+
+	// Turning interning off moves content back into the categories
+	cache.SetContentInterning(false)
+	stats = cache.GetStats()
+	if stats.SharedContentMemory != 0 {
+		t.Errorf("Expected no shared content with interning disabled, got %d", stats.SharedContentMemory)
+	}
+	for _, category := range []string{"guideline", "pattern"} {
+		if memory := stats.Categories[category].MemoryUsage; memory < int64(len(content)) {
+			t.Errorf("Expected %s memory to include its content, got %d", category, memory)
+		}
+	}
+	assertCategoryStatsReconcile(t, cache, stats)
+}
@@ -1,5 +1,12 @@
 package config
 
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
 // Path configuration constants for MCP resources and prompts
 const (
 	// Base paths for MCP assets
@@ -33,5 +40,80 @@ const (
 // File extension constants
 const (
 	MimeTypeMarkdown  = "text/markdown"
+	MimeTypePlainText = "text/plain"
+	MimeTypeJSON      = "application/json"
+	MimeTypeYAML      = "application/yaml"
 	MarkdownExtension = ".md"
 )
+
+// MarkdownExtensions lists the file extensions treated as markdown
+var MarkdownExtensions = []string{MarkdownExtension, ".markdown", ".mdx"}
+
+// IsMarkdownFile reports whether path has one of the MarkdownExtensions
+func IsMarkdownFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, markdownExt := range MarkdownExtensions {
+		if ext == markdownExt {
+			return true
+		}
+	}
+	return false
+}
+
+// TrimMarkdownExtension removes a markdown extension from path, leaving other
+// extensions in place
+func TrimMarkdownExtension(path string) string {
+	if IsMarkdownFile(path) {
+		return strings.TrimSuffix(path, filepath.Ext(path))
+	}
+	return path
+}
+
+// adrIDPatterns extract the numeric ID from ADR filenames such as "001-api-design",
+// "adr-001" and "ADR-001"
+var adrIDPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^(\d+)-`),
+	regexp.MustCompile(`^adr-(\d+)`),
+	regexp.MustCompile(`^ADR-(\d+)`),
+}
+
+// ADRID returns the numeric ID of an ADR path, or its filename when it has none
+func ADRID(path string) string {
+	filename := filepath.Base(path)
+
+	for _, re := range adrIDPatterns {
+		if matches := re.FindStringSubmatch(filename); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+
+	return filename
+}
+
+// ResourceURI derives the architecture:// URI of a document from its category and
+// path. Markdown extensions are dropped and ADRs are addressed by numeric ID.
+func ResourceURI(category, path string) string {
+	cleanPath := TrimMarkdownExtension(path)
+	cleanPath = filepath.ToSlash(cleanPath)
+
+	switch category {
+	case CategoryGuideline:
+		cleanPath = strings.TrimPrefix(cleanPath, GuidelinesPath+"/")
+		return fmt.Sprintf("%s%s/%s", URIScheme, URIGuidelines, cleanPath)
+	case CategoryPattern:
+		cleanPath = strings.TrimPrefix(cleanPath, PatternsPath+"/")
+		return fmt.Sprintf("%s%s/%s", URIScheme, URIPatterns, cleanPath)
+	case CategoryADR:
+		cleanPath = strings.TrimPrefix(cleanPath, ADRPath+"/")
+		// ADRs use numeric IDs for cleaner URIs (e.g., "001" instead of "001-api-design")
+		return fmt.Sprintf("%s%s/%s", URIScheme, URIADR, ADRID(cleanPath))
+	default:
+		return fmt.Sprintf("%s%s/%s", URIScheme, URIUnknown, cleanPath)
+	}
+}
+
+// Resource annotation constants
+const (
+	// ReadingWordsPerMinute is a typical adult reading speed for technical prose
+	ReadingWordsPerMinute = 200
+)
@@ -1,7 +1,9 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"mcp-architecture-service/internal/models"
@@ -99,6 +101,51 @@ func (se *StructuredError) ToMCPError() *models.MCPError {
 	}
 }
 
+// ErrorVerbosity controls how much of an error's internals are sent to clients
+type ErrorVerbosity string
+
+const (
+	// ErrorVerbosityTerse sends the stable code, category and context only
+	ErrorVerbosityTerse ErrorVerbosity = "terse"
+	// ErrorVerbosityVerbose also sends details and the underlying cause chain
+	ErrorVerbosityVerbose ErrorVerbosity = "verbose"
+)
+
+// ParseErrorVerbosity converts a verbosity name, case-insensitively
+func ParseErrorVerbosity(name string) (ErrorVerbosity, error) {
+	switch verbosity := ErrorVerbosity(strings.ToLower(name)); verbosity {
+	case ErrorVerbosityTerse, ErrorVerbosityVerbose:
+		return verbosity, nil
+	default:
+		return "", fmt.Errorf("unknown error verbosity %q, expected %q or %q", name, ErrorVerbosityTerse, ErrorVerbosityVerbose)
+	}
+}
+
+// ToMCPErrorWithVerbosity converts a StructuredError to an MCP protocol error. Terse
+// is the same as ToMCPError; verbose adds the details and, for each error in the
+// cause chain, its type and message so internal failures can be traced.
+func (se *StructuredError) ToMCPErrorWithVerbosity(verbosity ErrorVerbosity) *models.MCPError {
+	mcpErr := se.ToMCPError()
+	if verbosity != ErrorVerbosityVerbose {
+		return mcpErr
+	}
+
+	data := mcpErr.Data.(map[string]interface{})
+	if se.Details != "" {
+		data["details"] = se.Details
+	}
+
+	var causes []string
+	for cause := se.Cause; cause != nil; cause = stderrors.Unwrap(cause) {
+		causes = append(causes, fmt.Sprintf("%T: %v", cause, cause))
+	}
+	if len(causes) > 0 {
+		data["causes"] = causes
+	}
+
+	return mcpErr
+}
+
 // NewStructuredError creates a new structured error
 func NewStructuredError(category ErrorCategory, severity ErrorSeverity, code, message string) *StructuredError {
 	return &StructuredError{
@@ -224,4 +271,5 @@ const (
 	ErrCodeInitializationFailed = "INITIALIZATION_FAILED"
 	ErrCodeShutdownFailed       = "SHUTDOWN_FAILED"
 	ErrCodeUnexpectedPanic      = "UNEXPECTED_PANIC"
+	ErrCodeHistoryUnavailable   = "HISTORY_UNAVAILABLE"
 )
@@ -1,6 +1,9 @@
 package errors
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -213,3 +216,68 @@ func TestErrorTimestamp(t *testing.T) {
 		}
 	})
 }
+
+func TestParseErrorVerbosity(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  ErrorVerbosity
+		expectErr bool
+	}{
+		{"terse", "terse", ErrorVerbosityTerse, false},
+		{"verbose", "verbose", ErrorVerbosityVerbose, false},
+		{"case insensitive", "VERBOSE", ErrorVerbosityVerbose, false},
+		{"unknown", "debug", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verbosity, err := ParseErrorVerbosity(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseErrorVerbosity(%q) error = %v, expectErr %v", tt.input, err, tt.expectErr)
+			}
+			if verbosity != tt.expected {
+				t.Errorf("ParseErrorVerbosity(%q) = %q, want %q", tt.input, verbosity, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToMCPErrorWithVerbosity(t *testing.T) {
+	cause := fmt.Errorf("read index: %w", os.ErrNotExist)
+	err := NewSystemError("INDEX_FAILED", "Internal error", cause).
+		WithDetails("index rebuild aborted").
+		WithContext("category", "adr")
+
+	terse := err.ToMCPErrorWithVerbosity(ErrorVerbosityTerse)
+	verbose := err.ToMCPErrorWithVerbosity(ErrorVerbosityVerbose)
+
+	if terse.Code != verbose.Code || terse.Message != verbose.Message {
+		t.Errorf("Code and message should not depend on verbosity: %d %q vs %d %q",
+			terse.Code, terse.Message, verbose.Code, verbose.Message)
+	}
+
+	terseData := terse.Data.(map[string]interface{})
+	if terseData["code"] != "INDEX_FAILED" {
+		t.Errorf("Terse error should keep its stable code, got %v", terseData["code"])
+	}
+	if _, ok := terseData["details"]; ok {
+		t.Error("Terse error should not include details")
+	}
+	if _, ok := terseData["causes"]; ok {
+		t.Error("Terse error should not include causes")
+	}
+
+	verboseData := verbose.Data.(map[string]interface{})
+	if verboseData["details"] != "index rebuild aborted" {
+		t.Errorf("Verbose error should include details, got %v", verboseData["details"])
+	}
+	causes, _ := verboseData["causes"].([]string)
+	if len(causes) != 2 {
+		t.Fatalf("Expected 2 causes, got %v", verboseData["causes"])
+	}
+	if !strings.Contains(causes[0], "read index") || !strings.Contains(causes[1], "file does not exist") {
+		t.Errorf("Unexpected cause chain %v", causes)
+	}
+}
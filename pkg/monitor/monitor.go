@@ -1,11 +1,11 @@
 package monitor
 
 import (
-	"path/filepath"
 	"sync"
 	"time"
 
 	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
 
@@ -16,12 +16,18 @@ import (
 type FileSystemMonitor struct {
 	watcher        *fsnotify.Watcher
 	debounceDelay  time.Duration
-	callbacks      []func(models.FileEvent)
+	callbacks      []fileCallback
 	logger         *logging.StructuredLogger
 	debounceTimers map[string]*time.Timer
 	mu             sync.Mutex
 }
 
+// fileCallback is a callback registered for the files match accepts
+type fileCallback struct {
+	match    func(path string) bool
+	callback func(models.FileEvent)
+}
+
 // NewFileSystemMonitor creates a new file system monitor
 func NewFileSystemMonitor() (*FileSystemMonitor, error) {
 	watcher, err := fsnotify.NewWatcher()
@@ -37,16 +43,24 @@ func NewFileSystemMonitor() (*FileSystemMonitor, error) {
 	return &FileSystemMonitor{
 		watcher:        watcher,
 		debounceDelay:  500 * time.Millisecond, // 500ms debounce
-		callbacks:      make([]func(models.FileEvent), 0),
+		callbacks:      make([]fileCallback, 0),
 		logger:         logger,
 		debounceTimers: make(map[string]*time.Timer),
 	}, nil
 }
 
-// WatchDirectory starts watching a directory for changes
+// WatchDirectory starts watching a directory for changes to markdown files
 func (fsm *FileSystemMonitor) WatchDirectory(path string, callback func(models.FileEvent)) error {
+	return fsm.WatchDirectoryFiles(path, config.IsMarkdownFile, callback)
+}
+
+// WatchDirectoryFiles starts watching a directory for changes to the files match
+// accepts, such as prompt definitions. callback only sees events for those files.
+func (fsm *FileSystemMonitor) WatchDirectoryFiles(path string, match func(path string) bool, callback func(models.FileEvent)) error {
 	// Add callback to list
-	fsm.callbacks = append(fsm.callbacks, callback)
+	fsm.mu.Lock()
+	fsm.callbacks = append(fsm.callbacks, fileCallback{match: match, callback: callback})
+	fsm.mu.Unlock()
 
 	// Add directory to watcher
 	err := fsm.watcher.Add(path)
@@ -62,8 +76,16 @@ func (fsm *FileSystemMonitor) WatchDirectory(path string, callback func(models.F
 	return nil
 }
 
-// StopWatching stops the file system monitoring
+// StopWatching stops the file system monitoring. Debounced events not yet
+// delivered are dropped.
 func (fsm *FileSystemMonitor) StopWatching() error {
+	fsm.mu.Lock()
+	for path, timer := range fsm.debounceTimers {
+		timer.Stop()
+		delete(fsm.debounceTimers, path)
+	}
+	fsm.mu.Unlock()
+
 	if fsm.watcher != nil {
 		return fsm.watcher.Close()
 	}
@@ -79,8 +101,8 @@ func (fsm *FileSystemMonitor) monitorEvents() {
 				return
 			}
 
-			// Only process markdown files
-			if filepath.Ext(event.Name) != ".md" {
+			// Only process files a callback is interested in
+			if !fsm.matches(event.Name) {
 				continue
 			}
 
@@ -107,6 +129,19 @@ func (fsm *FileSystemMonitor) monitorEvents() {
 	}
 }
 
+// matches reports whether any callback is registered for path
+func (fsm *FileSystemMonitor) matches(path string) bool {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	for _, registered := range fsm.callbacks {
+		if registered.match(path) {
+			return true
+		}
+	}
+	return false
+}
+
 // processEvent converts fsnotify events to FileEvent and calls callbacks
 func (fsm *FileSystemMonitor) processEvent(event fsnotify.Event) {
 	var eventType string
@@ -129,9 +164,14 @@ func (fsm *FileSystemMonitor) processEvent(event fsnotify.Event) {
 		IsDir: false, // We only process files
 	}
 
-	// Call all registered callbacks
-	for _, callback := range fsm.callbacks {
-		callback(fileEvent)
+	// Call the callbacks registered for the file
+	fsm.mu.Lock()
+	callbacks := append([]fileCallback(nil), fsm.callbacks...)
+	fsm.mu.Unlock()
+	for _, registered := range callbacks {
+		if registered.match(event.Name) {
+			registered.callback(fileEvent)
+		}
 	}
 
 	fsm.logger.WithContext("event_type", eventType).
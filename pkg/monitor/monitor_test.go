@@ -343,3 +343,46 @@ func TestMultipleCallbacks(t *testing.T) {
 		t.Errorf("Expected both callbacks to have same count, got %d and %d", count1, count2)
 	}
 }
+
+func TestWatchDirectoryFiles(t *testing.T) {
+	tempDir, monitor := setupMonitorWithTempDir(t)
+	jsonEvents, _, _, jsonCallback := setupEventCollection(t)
+	markdownEvents, _, _, markdownCallback := setupEventCollection(t)
+
+	isJSON := func(path string) bool { return filepath.Ext(path) == ".json" }
+	if err := monitor.WatchDirectoryFiles(tempDir, isJSON, jsonCallback); err != nil {
+		t.Fatalf("Failed to start watching directory: %v", err)
+	}
+	if err := monitor.WatchDirectory(tempDir, markdownCallback); err != nil {
+		t.Fatalf("Failed to start watching directory: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	jsonFile := filepath.Join(tempDir, "prompt.json")
+	markdownFile := filepath.Join(tempDir, "doc.md")
+	if err := os.WriteFile(jsonFile, []byte(`{"name": "prompt"}`), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(markdownFile, []byte("# Doc"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Each callback only sees the files it asked for
+	for _, tt := range []struct {
+		events chan models.FileEvent
+		path   string
+	}{{jsonEvents, jsonFile}, {markdownEvents, markdownFile}} {
+		select {
+		case event := <-tt.events:
+			validateFileEvent(t, event, []string{"create", "modify"}, tt.path, false)
+		case <-time.After(2 * time.Second):
+			t.Errorf("Timeout waiting for event for %s", tt.path)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if len(jsonEvents) != 0 || len(markdownEvents) != 0 {
+		t.Errorf("Expected no events for unmatched files, got %d json and %d markdown", len(jsonEvents), len(markdownEvents))
+	}
+}
@@ -15,6 +15,12 @@ type PromptDefinition struct {
 	Description string               `json:"description,omitempty"`
 	Arguments   []ArgumentDefinition `json:"arguments,omitempty"`
 	Messages    []MessageTemplate    `json:"messages"`
+	// EmbedCategories lists the resource categories {{resource:...}} may embed,
+	// by category or URI segment ("pattern" or "patterns"); empty allows all
+	EmbedCategories []string `json:"embedCategories,omitempty"`
+	// Delimiters overrides the delimiters of this prompt's placeholders, for
+	// messages that contain literal {{ }}; nil uses the configured delimiters
+	Delimiters *Delimiters `json:"delimiters,omitempty"`
 }
 
 // ArgumentDefinition represents an argument that a prompt accepts
@@ -23,6 +29,9 @@ type ArgumentDefinition struct {
 	Description string `json:"description,omitempty"`
 	Required    bool   `json:"required"`
 	MaxLength   int    `json:"maxLength,omitempty"`
+	// Preserve passes the value through exactly as given, skipping argument
+	// normalization, for arguments such as code where whitespace matters
+	Preserve bool `json:"preserve,omitempty"`
 }
 
 // MessageTemplate represents a message template in the prompt
@@ -37,9 +46,13 @@ type ContentTemplate struct {
 	Text string `json:"text,omitempty"`
 }
 
+// DefaultNamePattern is the pattern prompt names must match unless configured
+// otherwise: lowercase letters, digits and hyphens only
+const DefaultNamePattern = `^[a-z0-9-]+$`
+
 var (
-	// promptNamePattern validates prompt names (lowercase alphanumeric and hyphens only)
-	promptNamePattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+	// promptNamePattern validates prompt names against DefaultNamePattern
+	promptNamePattern = regexp.MustCompile(DefaultNamePattern)
 )
 
 // LoadFromFile loads a prompt definition from a JSON file
@@ -75,14 +88,34 @@ func (pd *PromptDefinition) ToMCPPrompt() models.MCPPrompt {
 	}
 }
 
-// Validate checks the structural integrity of the prompt definition
+// Validate checks the structural integrity of the prompt definition, including
+// that its name matches DefaultNamePattern
 func (pd *PromptDefinition) Validate() error {
+	if err := pd.validateStructure(); err != nil {
+		return err
+	}
+	return pd.validateName(promptNamePattern)
+}
+
+// validateName checks the prompt name against pattern
+func (pd *PromptDefinition) validateName(pattern *regexp.Regexp) error {
+	if !pattern.MatchString(pd.Name) {
+		return fmt.Errorf("prompt name must match pattern %s, got: %s", pattern, pd.Name)
+	}
+	return nil
+}
+
+// validateStructure checks everything Validate does except the name pattern
+func (pd *PromptDefinition) validateStructure() error {
 	// Validate prompt name
 	if pd.Name == "" {
 		return fmt.Errorf("prompt name is required")
 	}
-	if !promptNamePattern.MatchString(pd.Name) {
-		return fmt.Errorf("prompt name must match pattern ^[a-z0-9-]+$, got: %s", pd.Name)
+
+	if pd.Delimiters != nil {
+		if err := pd.Delimiters.Validate(); err != nil {
+			return fmt.Errorf("invalid delimiters: %w", err)
+		}
 	}
 
 	// Validate messages
@@ -124,7 +157,7 @@ func (pd *PromptDefinition) Validate() error {
 		}
 	}
 
-	return nil
+	return pd.validateEmbedCategories()
 }
 
 // ValidateArguments validates user-provided arguments against the definition
@@ -0,0 +1,75 @@
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Delimiters mark the placeholders in prompt message templates: variables,
+// resource embeds and tool references. Prompts whose text legitimately contains
+// the default {{ }}, such as Go template or Vue examples, can use others, for
+// example << and >> to write <<topic>> and <<resource:architecture://adr/*>>.
+type Delimiters struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// DefaultDelimiters are the delimiters used unless configured otherwise
+var DefaultDelimiters = Delimiters{Open: "{{", Close: "}}"}
+
+// Validate returns an error if either delimiter is empty or contains whitespace
+func (d Delimiters) Validate() error {
+	for _, part := range []struct{ name, delimiter string }{{"open", d.Open}, {"close", d.Close}} {
+		if part.delimiter == "" {
+			return fmt.Errorf("%s delimiter is required", part.name)
+		}
+		if strings.ContainsAny(part.delimiter, " \t\r\n") {
+			return fmt.Errorf("%s delimiter %q must not contain whitespace", part.name, part.delimiter)
+		}
+	}
+	return nil
+}
+
+// ParseDelimiters converts an open and a close delimiter separated by whitespace,
+// such as "<< >>"
+func ParseDelimiters(value string) (Delimiters, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return Delimiters{}, fmt.Errorf("expected an open and a close delimiter separated by a space, got %q", value)
+	}
+	delimiters := Delimiters{Open: fields[0], Close: fields[1]}
+	return delimiters, delimiters.Validate()
+}
+
+// templateSyntax holds the placeholder patterns for one pair of delimiters
+type templateSyntax struct {
+	// variable matches {{variableName}} for substitution
+	variable *regexp.Regexp
+	// resource matches {{resource:uri}} for resource embedding
+	resource *regexp.Regexp
+	// tool matches {{tool:tool-name}} for tool reference embedding, and
+	// {{tool:tool-name?arg=value}} for embedding the tool's result; the name and
+	// arguments may hold masked argument values
+	tool *regexp.Regexp
+}
+
+// templateSyntaxes caches the compiled templateSyntax of each Delimiters
+var templateSyntaxes sync.Map // Delimiters -> *templateSyntax
+
+// syntax returns the placeholder patterns for the delimiters
+func (d Delimiters) syntax() *templateSyntax {
+	if cached, ok := templateSyntaxes.Load(d); ok {
+		return cached.(*templateSyntax)
+	}
+
+	open, close := regexp.QuoteMeta(d.Open), regexp.QuoteMeta(d.Close)
+	syntax := &templateSyntax{
+		variable: regexp.MustCompile(open + `([a-zA-Z0-9_-]+)` + close),
+		resource: regexp.MustCompile(open + `resource:(.+?)` + close),
+		tool:     regexp.MustCompile(open + `tool:([a-z0-9\x00-]+)(?:\?(.+?))?` + close),
+	}
+	cached, _ := templateSyntaxes.LoadOrStore(d, syntax)
+	return cached.(*templateSyntax)
+}
@@ -0,0 +1,149 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+func TestParseDelimiters(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  Delimiters
+		expectErr bool
+	}{
+		{"default", "{{ }}", DefaultDelimiters, false},
+		{"angle brackets", "<< >>", Delimiters{Open: "<<", Close: ">>"}, false},
+		{"extra whitespace", "  [% %]  ", Delimiters{Open: "[%", Close: "%]"}, false},
+		{"one delimiter", "<<", Delimiters{}, true},
+		{"three delimiters", "<< >> ||", Delimiters{}, true},
+		{"empty", "", Delimiters{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delimiters, err := ParseDelimiters(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseDelimiters(%q) error = %v, expectErr %v", tt.input, err, tt.expectErr)
+			}
+			if !tt.expectErr && delimiters != tt.expected {
+				t.Errorf("ParseDelimiters(%q) = %+v, want %+v", tt.input, delimiters, tt.expected)
+			}
+		})
+	}
+}
+
+// newDelimiterTestManager returns a prompt manager whose cache holds a pattern
+// with literal {{ }} in its content
+func newDelimiterTestManager(t *testing.T) *PromptManager {
+	t.Helper()
+
+	docCache := cache.NewDocumentCache()
+	t.Cleanup(docCache.Close)
+	docCache.Set(config.PatternsPath+"/templating.md", &models.Document{
+		Metadata: models.DocumentMetadata{Title: "Templating", Category: "patterns", Path: config.PatternsPath + "/templating.md"},
+		Content:  models.DocumentContent{RawContent: "Render {{ .Name }} or {{topic}} and never {{tool:missing-tool}}"},
+	})
+
+	pm := NewPromptManager("prompts", docCache, nil, logging.NewStructuredLogger("test"))
+	pm.SetToolManager(&mockToolManager{})
+	return pm
+}
+
+func TestRenderPrompt_CustomDelimiters(t *testing.T) {
+	pm := newDelimiterTestManager(t)
+	pm.registry["vue-review"] = &PromptDefinition{
+		Name:       "vue-review",
+		Arguments:  []ArgumentDefinition{{Name: "topic", Required: true}},
+		Delimiters: &Delimiters{Open: "<<", Close: ">>"},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "Review <<topic>> bound with {{ message }}:\n<<resource:architecture://patterns/templating>>"}},
+		},
+	}
+
+	result, err := pm.RenderPrompt("vue-review", map[string]interface{}{"topic": "forms"})
+	if err != nil {
+		t.Fatalf("RenderPrompt() unexpected error = %v", err)
+	}
+	text := result.Messages[0].Content.Text
+	for _, want := range []string{
+		"Review forms bound with {{ message }}:",
+		"Render {{ .Name }} or {{topic}} and never {{tool:missing-tool}}",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in rendered text, got %q", want, text)
+		}
+	}
+}
+
+func TestRenderPrompt_GlobalDelimiters(t *testing.T) {
+	pm := newDelimiterTestManager(t)
+	if err := pm.SetDelimiters(Delimiters{Open: "[%", Close: "%]"}); err != nil {
+		t.Fatalf("SetDelimiters() unexpected error = %v", err)
+	}
+	pm.registry["go-template"] = &PromptDefinition{
+		Name:      "go-template",
+		Arguments: []ArgumentDefinition{{Name: "topic", Required: true}},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "[%topic%] uses {{topic}}\n[%resource:architecture://patterns/templating%]"}},
+		},
+	}
+
+	result, err := pm.RenderPrompt("go-template", map[string]interface{}{"topic": "emails"})
+	if err != nil {
+		t.Fatalf("RenderPrompt() unexpected error = %v", err)
+	}
+	text := result.Messages[0].Content.Text
+	if !strings.HasPrefix(text, "emails uses {{topic}}\n") {
+		t.Errorf("Expected only the configured delimiters to be substituted, got %q", text)
+	}
+	if !strings.Contains(text, "Render {{ .Name }} or {{topic}}") {
+		t.Errorf("Expected the embedded document to be untouched, got %q", text)
+	}
+
+	if err := pm.SetDelimiters(Delimiters{Open: "<<"}); err == nil {
+		t.Error("SetDelimiters() expected error for a missing close delimiter, got nil")
+	}
+}
+
+func TestRenderPrompt_DefaultDelimitersLeaveEmbeddedContent(t *testing.T) {
+	pm := newDelimiterTestManager(t)
+	pm.registry["default"] = &PromptDefinition{
+		Name:      "default",
+		Arguments: []ArgumentDefinition{{Name: "topic", Required: true}},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "{{topic}}: {{resource:architecture://patterns/templating}}"}},
+		},
+	}
+
+	// The document references a tool that does not exist; it must not be expanded
+	result, err := pm.RenderPrompt("default", map[string]interface{}{"topic": "views"})
+	if err != nil {
+		t.Fatalf("RenderPrompt() unexpected error = %v", err)
+	}
+	if text := result.Messages[0].Content.Text; !strings.Contains(text, "never {{tool:missing-tool}}") {
+		t.Errorf("Expected the embedded document to be untouched, got %q", text)
+	}
+}
+
+func TestPromptDefinitionValidate_Delimiters(t *testing.T) {
+	def := &PromptDefinition{
+		Name:     "review",
+		Messages: []MessageTemplate{{Role: "user", Content: ContentTemplate{Type: "text", Text: "Review"}}},
+	}
+
+	def.Delimiters = &Delimiters{Open: "<<", Close: ">>"}
+	if err := def.Validate(); err != nil {
+		t.Errorf("Validate() unexpected error = %v", err)
+	}
+
+	def.Delimiters = &Delimiters{Open: "<< ", Close: ">>"}
+	if err := def.Validate(); err == nil {
+		t.Error("Validate() expected error for whitespace in a delimiter, got nil")
+	}
+}
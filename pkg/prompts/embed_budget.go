@@ -0,0 +1,136 @@
+package prompts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/tokens"
+)
+
+// EmbedOverflow is what happens when the documents one prompt message embeds are
+// estimated at more tokens than the EmbedBudget allows
+type EmbedOverflow string
+
+const (
+	// EmbedOverflowError fails the render, like exceeding MaxTotalContentSize
+	EmbedOverflowError EmbedOverflow = "error"
+	// EmbedOverflowTruncate cuts the largest documents down until the message fits,
+	// noting in each cut document how much of it was left out
+	EmbedOverflowTruncate EmbedOverflow = "truncate"
+)
+
+// ParseEmbedOverflow converts an overflow mode name, case-insensitively
+func ParseEmbedOverflow(name string) (EmbedOverflow, error) {
+	switch overflow := EmbedOverflow(strings.ToLower(name)); overflow {
+	case EmbedOverflowError, EmbedOverflowTruncate:
+		return overflow, nil
+	default:
+		return "", fmt.Errorf("unknown embed overflow %q, expected %q or %q", name, EmbedOverflowError, EmbedOverflowTruncate)
+	}
+}
+
+// EmbedBudget limits the estimated tokens of the document content one prompt
+// message embeds through {{resource:...}}. Headers, footers, separators and
+// truncation notes are not counted. MaxTotalContentSize still applies to the
+// content that is embedded.
+type EmbedBudget struct {
+	Tokens   int // Most estimated tokens of embedded content; 0 disables the budget
+	Overflow EmbedOverflow
+}
+
+// DefaultEmbedBudget sets no token budget
+var DefaultEmbedBudget = EmbedBudget{Overflow: EmbedOverflowError}
+
+// Validate returns an error if the budget is negative, the overflow mode is
+// unknown, or truncation is asked for without a budget to truncate to
+func (eb EmbedBudget) Validate() error {
+	if eb.Tokens < 0 {
+		return fmt.Errorf("embed token budget must not be negative, got %d", eb.Tokens)
+	}
+	if _, err := ParseEmbedOverflow(string(eb.Overflow)); err != nil {
+		return err
+	}
+	if eb.Overflow == EmbedOverflowTruncate && eb.Tokens == 0 {
+		return fmt.Errorf("embed overflow %q requires a token budget", EmbedOverflowTruncate)
+	}
+	return nil
+}
+
+// truncationNote follows the content kept of a document cut to fit the budget
+const truncationNote = "\n\n[Truncated to fit the embed token budget: %d of %d estimated tokens shown]"
+
+// fit returns the content to embed for each document, in order, and how many of
+// them were truncated. Within budget, or without one, every document is embedded
+// whole. Over budget, overflow error fails; truncate finds the largest per-document
+// allowance under which the documents fit and cuts every document above it, so
+// small documents stay whole and the largest lose the most.
+func (eb EmbedBudget) fit(documents []*models.Document, estimator tokens.Estimator) ([]string, int, error) {
+	contents := make([]string, len(documents))
+	sizes := make([]int, len(documents))
+	total := 0
+	for i, doc := range documents {
+		contents[i] = doc.Content.RawContent
+		sizes[i] = estimator.Estimate(contents[i])
+		total += sizes[i]
+	}
+	if eb.Tokens == 0 || total <= eb.Tokens {
+		return contents, 0, nil
+	}
+	if eb.Overflow != EmbedOverflowTruncate {
+		return nil, 0, fmt.Errorf("embed token budget exceeded: documents are estimated at %d tokens, budget is %d", total, eb.Tokens)
+	}
+
+	allowance := embedAllowance(sizes, eb.Tokens)
+	truncated := 0
+	for i, size := range sizes {
+		if size <= allowance {
+			continue
+		}
+		kept := truncateToTokens(contents[i], allowance, estimator)
+		contents[i] = kept + fmt.Sprintf(truncationNote, estimator.Estimate(kept), size)
+		truncated++
+	}
+	return contents, truncated, nil
+}
+
+// embedAllowance returns the most tokens each document may keep so that the sizes,
+// each capped at the allowance, add up to no more than budget
+func embedAllowance(sizes []int, budget int) int {
+	sorted := append([]int(nil), sizes...)
+	sort.Ints(sorted)
+
+	remaining := budget
+	for i, size := range sorted {
+		left := len(sorted) - i
+		if size*left > remaining {
+			return remaining / left
+		}
+		remaining -= size
+	}
+	return sorted[len(sorted)-1]
+}
+
+// truncateToTokens returns the longest prefix of content estimated at no more than
+// limit tokens, cut back to the end of a line when one ends in its second half
+func truncateToTokens(content string, limit int, estimator tokens.Estimator) string {
+	// Estimates do not decrease as text grows, so the cut point can be searched for
+	runes := []rune(content)
+	low, high := 0, len(runes)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if estimator.Estimate(string(runes[:mid])) <= limit {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	kept := string(runes[:low])
+	if newline := strings.LastIndexByte(kept, '\n'); newline > 0 && utf8.RuneCountInString(kept[:newline]) >= low/2 {
+		kept = kept[:newline]
+	}
+	return kept
+}
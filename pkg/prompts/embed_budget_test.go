@@ -0,0 +1,245 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+	"mcp-architecture-service/pkg/tokens"
+)
+
+// budgetSeparator splits the documents of newBudgetTestRenderer's output
+const budgetSeparator = "\n<<<>>>\n"
+
+// newBudgetTestRenderer returns a renderer over one short and two long guidelines
+// whose documents are embedded bare, so their content can be measured
+func newBudgetTestRenderer(t *testing.T) (*TemplateRenderer, *cache.DocumentCache) {
+	t.Helper()
+
+	docCache := cache.NewDocumentCache()
+	t.Cleanup(docCache.Close)
+
+	for _, doc := range []struct{ file, content string }{
+		{"short.md", strings.Repeat("s", 100)},                      // 25 tokens
+		{"long-a.md", strings.Repeat("a long line of text\n", 200)}, // 1000 tokens
+		{"long-b.md", strings.Repeat("b", 2000)},                    // 500 tokens
+	} {
+		path := config.GuidelinesPath + "/" + doc.file
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Title: doc.file, Category: config.URIGuidelines, Path: path},
+			Content:  models.DocumentContent{RawContent: doc.content},
+		})
+	}
+
+	renderer := NewTemplateRenderer(docCache)
+	if err := renderer.SetEmbedFormat(EmbedFormat{Separator: budgetSeparator}); err != nil {
+		t.Fatalf("SetEmbedFormat() error = %v", err)
+	}
+	return renderer, docCache
+}
+
+func TestEmbedResources_TokenBudget(t *testing.T) {
+	t.Run("truncates the largest documents under a tight budget", func(t *testing.T) {
+		renderer, _ := newBudgetTestRenderer(t)
+		if err := renderer.SetEmbedBudget(EmbedBudget{Tokens: 600, Overflow: EmbedOverflowTruncate}); err != nil {
+			t.Fatalf("SetEmbedBudget() error = %v", err)
+		}
+
+		result, count, truncated, err := renderer.embedResources(renderer.delimiters.syntax(), "{{resource:architecture://guidelines/*}}", nil, nil)
+		if err != nil {
+			t.Fatalf("embedResources() error = %v", err)
+		}
+		if count != 3 || truncated != 2 {
+			t.Errorf("embedResources() embedded %d and truncated %d documents, want 3 and 2", count, truncated)
+		}
+
+		total := 0
+		notes := 0
+		for _, content := range strings.Split(result, budgetSeparator) {
+			kept, note, cut := strings.Cut(content, "\n\n[Truncated to fit the embed token budget: ")
+			total += tokens.DefaultEstimator.Estimate(kept)
+			if !cut {
+				if content != strings.Repeat("s", 100) {
+					t.Errorf("Expected only the short document to be embedded whole, got %d characters", len(content))
+				}
+				continue
+			}
+			notes++
+			if !strings.HasSuffix(note, " of 1000 estimated tokens shown]") && !strings.HasSuffix(note, " of 500 estimated tokens shown]") {
+				t.Errorf("Expected the note to give the document's full estimate, got %q", note)
+			}
+		}
+		if notes != 2 {
+			t.Errorf("Expected 2 truncation notes, got %d", notes)
+		}
+		if total > 600 {
+			t.Errorf("Expected embedded content within the 600 token budget, got %d tokens", total)
+		}
+		if total < 500 {
+			t.Errorf("Expected truncation to use most of the budget, got %d tokens", total)
+		}
+	})
+
+	t.Run("budget spans every directive in the message", func(t *testing.T) {
+		renderer, _ := newBudgetTestRenderer(t)
+		if err := renderer.SetEmbedBudget(EmbedBudget{Tokens: 100, Overflow: EmbedOverflowTruncate}); err != nil {
+			t.Fatalf("SetEmbedBudget() error = %v", err)
+		}
+
+		result, err := renderer.EmbedResources("{{resource:architecture://guidelines/long-a}}"+budgetSeparator+"{{resource:architecture://guidelines/long-b}}", nil)
+		if err != nil {
+			t.Fatalf("EmbedResources() error = %v", err)
+		}
+		total := 0
+		for _, content := range strings.Split(result, budgetSeparator) {
+			kept, _, _ := strings.Cut(content, "\n\n[Truncated")
+			total += tokens.DefaultEstimator.Estimate(kept)
+		}
+		if total > 100 {
+			t.Errorf("Expected both directives within the 100 token budget, got %d tokens", total)
+		}
+	})
+
+	t.Run("within budget embeds documents whole", func(t *testing.T) {
+		renderer, _ := newBudgetTestRenderer(t)
+		want, err := renderer.EmbedResources("{{resource:architecture://guidelines/*}}", nil)
+		if err != nil {
+			t.Fatalf("EmbedResources() error = %v", err)
+		}
+
+		if err := renderer.SetEmbedBudget(EmbedBudget{Tokens: 1525, Overflow: EmbedOverflowTruncate}); err != nil {
+			t.Fatalf("SetEmbedBudget() error = %v", err)
+		}
+		got, err := renderer.EmbedResources("{{resource:architecture://guidelines/*}}", nil)
+		if err != nil {
+			t.Fatalf("EmbedResources() error = %v", err)
+		}
+		if strings.Contains(got, "[Truncated") || len(got) != len(want) {
+			t.Errorf("Expected no truncation within budget, got %d characters, want %d", len(got), len(want))
+		}
+	})
+
+	t.Run("error mode fails over budget", func(t *testing.T) {
+		renderer, _ := newBudgetTestRenderer(t)
+		if err := renderer.SetEmbedBudget(EmbedBudget{Tokens: 600, Overflow: EmbedOverflowError}); err != nil {
+			t.Fatalf("SetEmbedBudget() error = %v", err)
+		}
+
+		_, err := renderer.EmbedResources("{{resource:architecture://guidelines/*}}", nil)
+		if err == nil || !strings.Contains(err.Error(), "embed token budget exceeded: documents are estimated at 1525 tokens, budget is 600") {
+			t.Errorf("Expected a token budget error, got %v", err)
+		}
+	})
+
+	t.Run("truncation is counted in render metrics", func(t *testing.T) {
+		_, docCache := newBudgetTestRenderer(t)
+		pm := NewPromptManager("prompts", docCache, nil, logging.NewStructuredLogger("test"))
+		if err := pm.SetEmbedBudget(EmbedBudget{Tokens: 600, Overflow: EmbedOverflowTruncate}); err != nil {
+			t.Fatalf("SetEmbedBudget() error = %v", err)
+		}
+		pm.registry["digest"] = &PromptDefinition{
+			Name: "digest",
+			Messages: []MessageTemplate{
+				{Role: "user", Content: ContentTemplate{Type: "text", Text: "{{resource:architecture://guidelines/*}}"}},
+			},
+		}
+
+		_, metrics, err := pm.RenderPromptWithMetrics("digest", map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("RenderPromptWithMetrics() error = %v", err)
+		}
+		if metrics.ResourcesEmbedded != 3 || metrics.ResourcesTruncated != 2 {
+			t.Errorf("Expected 3 documents embedded and 2 truncated, got %d and %d", metrics.ResourcesEmbedded, metrics.ResourcesTruncated)
+		}
+	})
+}
+
+func TestEmbedAllowance(t *testing.T) {
+	tests := []struct {
+		name   string
+		sizes  []int
+		budget int
+		want   int
+	}{
+		{"equal sizes share the budget", []int{100, 100, 100}, 150, 50},
+		{"small documents stay whole", []int{10, 500, 1000}, 410, 200},
+		{"budget below one token each", []int{5, 5, 5}, 2, 0},
+		{"everything fits", []int{10, 20}, 100, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := embedAllowance(tt.sizes, tt.budget); got != tt.want {
+				t.Errorf("embedAllowance(%v, %d) = %d, want %d", tt.sizes, tt.budget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateToTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		limit   int
+		want    string
+	}{
+		{"fits whole", "short", 2, "short"},
+		{"cut mid-line", strings.Repeat("x", 40), 5, strings.Repeat("x", 20)},
+		{"cut back to a line end", "first line\nsecond line here", 5, "first line"},
+		{"early line end is not used", "a\n" + strings.Repeat("y", 40), 5, "a\n" + strings.Repeat("y", 18)},
+		{"multi-byte characters", strings.Repeat("é", 12), 2, strings.Repeat("é", 8)},
+		{"zero limit", "anything", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateToTokens(tt.content, tt.limit, tokens.DefaultEstimator); got != tt.want {
+				t.Errorf("truncateToTokens(%q, %d) = %q, want %q", tt.content, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbedBudget_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  EmbedBudget
+		wantErr string
+	}{
+		{"default", DefaultEmbedBudget, ""},
+		{"error mode with budget", EmbedBudget{Tokens: 1000, Overflow: EmbedOverflowError}, ""},
+		{"truncate mode with budget", EmbedBudget{Tokens: 1000, Overflow: EmbedOverflowTruncate}, ""},
+		{"negative budget", EmbedBudget{Tokens: -1, Overflow: EmbedOverflowError}, "must not be negative"},
+		{"unknown overflow", EmbedBudget{Tokens: 1000, Overflow: "drop"}, "unknown embed overflow"},
+		{"truncate without budget", EmbedBudget{Overflow: EmbedOverflowTruncate}, "requires a token budget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.budget.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseEmbedOverflow(t *testing.T) {
+	for _, name := range []string{"error", "truncate", "TRUNCATE"} {
+		if overflow, err := ParseEmbedOverflow(name); err != nil || string(overflow) != strings.ToLower(name) {
+			t.Errorf("ParseEmbedOverflow(%q) = %q, %v", name, overflow, err)
+		}
+	}
+	if _, err := ParseEmbedOverflow("drop"); err == nil {
+		t.Error("ParseEmbedOverflow(\"drop\") expected an error")
+	}
+}
@@ -0,0 +1,85 @@
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/tokens"
+)
+
+// EmbedFormat controls how each document embedded through {{resource:...}} is
+// wrapped. Header and Footer surround every document's content and may use the
+// placeholders {{title}}, {{path}}, {{uri}}, {{category}} and {{tokens}}, the
+// estimated token count of the content; Separator goes between consecutive
+// documents.
+type EmbedFormat struct {
+	Header    string
+	Footer    string
+	Separator string
+}
+
+// DefaultEmbedFormat heads each document with its title, source path and estimated
+// token count and separates documents with a horizontal rule
+var DefaultEmbedFormat = EmbedFormat{
+	Header:    "# {{title}}\nSource: {{path}}\nEstimated tokens: {{tokens}}\n\n",
+	Separator: "\n\n---\n\n",
+}
+
+// embedPlaceholderPattern matches the placeholders of an embed header or footer
+var embedPlaceholderPattern = regexp.MustCompile(`\{\{([a-zA-Z0-9_-]+)\}\}`)
+
+// embeddedDocument is what an embed header or footer is rendered for
+type embeddedDocument struct {
+	doc       *models.Document
+	content   string
+	estimator tokens.Estimator
+}
+
+// embedPlaceholders lists the placeholders headers and footers may use
+var embedPlaceholders = map[string]func(embeddedDocument) string{
+	"title": func(e embeddedDocument) string { return e.doc.Metadata.Title },
+	"path":  func(e embeddedDocument) string { return e.doc.Metadata.Path },
+	"uri": func(e embeddedDocument) string {
+		return config.ResourceURI(e.doc.Metadata.Category, e.doc.Metadata.Path)
+	},
+	"category": func(e embeddedDocument) string { return e.doc.Metadata.Category },
+	"tokens":   func(e embeddedDocument) string { return strconv.Itoa(e.estimator.Estimate(e.content)) },
+}
+
+// Validate returns an error if the header or footer uses an unknown placeholder
+func (ef EmbedFormat) Validate() error {
+	for _, part := range []struct{ name, template string }{{"header", ef.Header}, {"footer", ef.Footer}} {
+		for _, match := range embedPlaceholderPattern.FindAllStringSubmatch(part.template, -1) {
+			if _, ok := embedPlaceholders[match[1]]; !ok {
+				return fmt.Errorf("unknown placeholder {{%s}} in embed %s, expected one of {{title}}, {{path}}, {{uri}}, {{category}}, {{tokens}}", match[1], part.name)
+			}
+		}
+	}
+	return nil
+}
+
+// wrap returns content with the header and footer rendered for doc around it,
+// estimating its tokens with estimator
+func (ef EmbedFormat) wrap(doc *models.Document, content string, estimator tokens.Estimator) string {
+	embedded := embeddedDocument{doc: doc, content: content, estimator: estimator}
+	render := func(template string) string {
+		return embedPlaceholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+			return embedPlaceholders[strings.Trim(placeholder, "{}")](embedded)
+		})
+	}
+	return render(ef.Header) + content + render(ef.Footer)
+}
+
+// UnescapeEmbedTemplate expands Go escape sequences such as \n and \t in a header,
+// footer or separator given on the command line
+func UnescapeEmbedTemplate(value string) (string, error) {
+	unescaped, err := strconv.Unquote(`"` + strings.ReplaceAll(value, `"`, `\"`) + `"`)
+	if err != nil {
+		return "", fmt.Errorf("invalid escape sequence in %q", value)
+	}
+	return unescaped, nil
+}
@@ -0,0 +1,173 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/tokens"
+)
+
+// newEmbedTestRenderer returns a renderer over two cached ADRs
+func newEmbedTestRenderer(t *testing.T) *TemplateRenderer {
+	t.Helper()
+
+	docCache := cache.NewDocumentCache()
+	t.Cleanup(docCache.Close)
+
+	for _, doc := range []struct{ file, title, content string }{
+		{"001-event-sourcing.md", "ADR-001: Event Sourcing", "Store changes as events."},
+		{"002-cqrs.md", "ADR-002: CQRS", "Separate reads from writes."},
+	} {
+		path := config.ADRPath + "/" + doc.file
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Title: doc.title, Category: config.CategoryADR, Path: path},
+			Content:  models.DocumentContent{RawContent: doc.content},
+		})
+	}
+
+	return NewTemplateRenderer(docCache)
+}
+
+func TestEmbedResources_DefaultFormat(t *testing.T) {
+	renderer := newEmbedTestRenderer(t)
+
+	got, err := renderer.EmbedResources("{{resource:architecture://adr/001-event-sourcing}}", nil)
+	if err != nil {
+		t.Fatalf("EmbedResources() error = %v", err)
+	}
+
+	// "Store changes as events." is 24 characters, about 6 tokens
+	want := "# ADR-001: Event Sourcing\nSource: " + config.ADRPath + "/001-event-sourcing.md\nEstimated tokens: 6\n\nStore changes as events."
+	if got != want {
+		t.Errorf("EmbedResources() = %q, want %q", got, want)
+	}
+}
+
+func TestEmbedResources_CustomFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format EmbedFormat
+		want   []string // each document's rendering, in either order
+		sep    string
+	}{
+		{
+			name:   "custom header without source path",
+			format: EmbedFormat{Header: "<doc uri=\"{{uri}}\" category=\"{{category}}\">\n", Footer: "\n</doc>", Separator: "\n"},
+			want: []string{
+				"<doc uri=\"architecture://adr/001\" category=\"adr\">\nStore changes as events.\n</doc>",
+				"<doc uri=\"architecture://adr/002\" category=\"adr\">\nSeparate reads from writes.\n</doc>",
+			},
+			sep: "\n",
+		},
+		{
+			name:   "custom separator",
+			format: EmbedFormat{Header: "## {{title}}\n", Separator: "\n\n=====\n\n"},
+			want: []string{
+				"## ADR-001: Event Sourcing\nStore changes as events.",
+				"## ADR-002: CQRS\nSeparate reads from writes.",
+			},
+			sep: "\n\n=====\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := newEmbedTestRenderer(t)
+			if err := renderer.SetEmbedFormat(tt.format); err != nil {
+				t.Fatalf("SetEmbedFormat() error = %v", err)
+			}
+
+			got, err := renderer.EmbedResources("{{resource:architecture://adr/*}}", nil)
+			if err != nil {
+				t.Fatalf("EmbedResources() error = %v", err)
+			}
+
+			// Wildcard matches come back in no particular order
+			forward := tt.want[0] + tt.sep + tt.want[1]
+			reverse := tt.want[1] + tt.sep + tt.want[0]
+			if got != forward && got != reverse {
+				t.Errorf("EmbedResources() = %q, want %q in either order", got, forward)
+			}
+			if strings.Contains(got, "Source:") {
+				t.Error("Expected the default source line to be gone")
+			}
+		})
+	}
+}
+
+func TestEmbedResources_TokenEstimate(t *testing.T) {
+	renderer := newEmbedTestRenderer(t)
+	if err := renderer.SetEmbedFormat(EmbedFormat{Header: "[{{tokens}}] "}); err != nil {
+		t.Fatalf("SetEmbedFormat() error = %v", err)
+	}
+
+	// A pluggable estimator replaces the character heuristic
+	renderer.SetTokenEstimator(tokens.EstimatorFunc(func(text string) int { return len(strings.Fields(text)) }))
+	got, err := renderer.EmbedResources("{{resource:architecture://adr/002-cqrs}}", nil)
+	if err != nil {
+		t.Fatalf("EmbedResources() error = %v", err)
+	}
+	if want := "[4] Separate reads from writes."; got != want {
+		t.Errorf("EmbedResources() = %q, want %q", got, want)
+	}
+
+	// nil restores the default, one token per four characters
+	renderer.SetTokenEstimator(nil)
+	got, err = renderer.EmbedResources("{{resource:architecture://adr/002-cqrs}}", nil)
+	if err != nil {
+		t.Fatalf("EmbedResources() error = %v", err)
+	}
+	if want := "[7] Separate reads from writes."; got != want {
+		t.Errorf("EmbedResources() = %q, want %q", got, want)
+	}
+}
+
+func TestEmbedFormat_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  EmbedFormat
+		wantErr bool
+	}{
+		{"default", DefaultEmbedFormat, false},
+		{"all placeholders", EmbedFormat{Header: "{{title}} {{path}} {{tokens}}", Footer: "{{uri}} {{category}}"}, false},
+		{"empty", EmbedFormat{}, false},
+		{"unknown header placeholder", EmbedFormat{Header: "{{author}}"}, true},
+		{"unknown footer placeholder", EmbedFormat{Footer: "{{date}}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.format.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnescapeEmbedTemplate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{`# {{title}}\n`, "# {{title}}\n", false},
+		{`\n\n---\n\n`, "\n\n---\n\n", false},
+		{`say "hi"\t`, "say \"hi\"\t", false},
+		{`bad \q escape`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := UnescapeEmbedTemplate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnescapeEmbedTemplate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnescapeEmbedTemplate(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
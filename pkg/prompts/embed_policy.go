@@ -0,0 +1,45 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"mcp-architecture-service/pkg/config"
+)
+
+// embedCategorySegments maps the names an embedCategories entry may use, the
+// category or its URI segment, to the URI segment {{resource:...}} patterns use
+var embedCategorySegments = map[string]string{
+	config.CategoryGuideline: config.URIGuidelines,
+	config.URIGuidelines:     config.URIGuidelines,
+	config.CategoryPattern:   config.URIPatterns,
+	config.URIPatterns:       config.URIPatterns,
+	config.CategoryADR:       config.URIADR,
+}
+
+// validateEmbedCategories returns an error if embedCategories names an unknown
+// category
+func (pd *PromptDefinition) validateEmbedCategories() error {
+	for _, category := range pd.EmbedCategories {
+		if _, ok := embedCategorySegments[category]; !ok {
+			return fmt.Errorf("embedCategories: unknown category %q, expected one of %s, %s, %s",
+				category, config.URIGuidelines, config.URIPatterns, config.URIADR)
+		}
+	}
+	return nil
+}
+
+// checkEmbedCategory returns an error unless a resource URI whose category segment
+// is segment may be embedded under allowed. An empty allow-list allows everything.
+func checkEmbedCategory(segment string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, category := range allowed {
+		if embedCategorySegments[category] == segment {
+			return nil
+		}
+	}
+	return fmt.Errorf("resource category %q is not embeddable by this prompt, allowed: %s",
+		segment, strings.Join(allowed, ", "))
+}
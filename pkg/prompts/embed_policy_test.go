@@ -0,0 +1,106 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// newEmbedPolicyCache returns a cache holding one guideline and one pattern
+func newEmbedPolicyCache(t *testing.T) *cache.DocumentCache {
+	t.Helper()
+
+	docCache := cache.NewDocumentCache()
+	t.Cleanup(docCache.Close)
+
+	docCache.Set(config.GuidelinesPath+"/api-design.md", &models.Document{
+		Metadata: models.DocumentMetadata{Title: "API Design", Category: "guidelines", Path: config.GuidelinesPath + "/api-design.md"},
+		Content:  models.DocumentContent{RawContent: "API content"},
+	})
+	docCache.Set(config.PatternsPath+"/repository-pattern.md", &models.Document{
+		Metadata: models.DocumentMetadata{Title: "Repository Pattern", Category: "patterns", Path: config.PatternsPath + "/repository-pattern.md"},
+		Content:  models.DocumentContent{RawContent: "Repository content"},
+	})
+	return docCache
+}
+
+func TestResolveResourcePattern_EmbedCategories(t *testing.T) {
+	renderer := NewTemplateRenderer(newEmbedPolicyCache(t))
+
+	tests := []struct {
+		name    string
+		pattern string
+		allowed []string
+		wantErr bool
+	}{
+		{name: "no restriction", pattern: "architecture://guidelines/api-design"},
+		{name: "allowed by segment", pattern: "architecture://patterns/*", allowed: []string{"patterns"}},
+		{name: "allowed by category", pattern: "architecture://patterns/repository-pattern", allowed: []string{"pattern"}},
+		{name: "guideline restricted to patterns", pattern: "architecture://guidelines/api-design", allowed: []string{"patterns"}, wantErr: true},
+		{name: "guideline wildcard restricted to patterns", pattern: "architecture://guidelines/*", allowed: []string{"pattern"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := renderer.ResolveResourcePattern(tt.pattern, tt.allowed)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "not embeddable") {
+					t.Errorf("ResolveResourcePattern() error = %v, want category rejection", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ResolveResourcePattern() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderPrompt_EmbedCategories(t *testing.T) {
+	pm := NewPromptManager("prompts", newEmbedPolicyCache(t), nil, logging.NewStructuredLogger("test"))
+	pm.registry["patterns-only"] = &PromptDefinition{
+		Name:            "patterns-only",
+		EmbedCategories: []string{"patterns"},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "{{resource:architecture://guidelines/api-design}}"}},
+		},
+	}
+
+	_, err := pm.RenderPrompt("patterns-only", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), `resource category "guidelines" is not embeddable`) {
+		t.Errorf("RenderPrompt() error = %v, want guideline rejected", err)
+	}
+
+	pm.registry["patterns-only"].Messages[0].Content.Text = "{{resource:architecture://patterns/repository-pattern}}"
+	result, err := pm.RenderPrompt("patterns-only", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("RenderPrompt() unexpected error = %v", err)
+	}
+	if !strings.Contains(result.Messages[0].Content.Text, "Repository content") {
+		t.Errorf("Expected the pattern to be embedded, got %q", result.Messages[0].Content.Text)
+	}
+}
+
+func TestPromptDefinitionValidate_EmbedCategories(t *testing.T) {
+	def := &PromptDefinition{
+		Name:        "review",
+		Description: "Review code",
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "Review"}},
+		},
+	}
+
+	def.EmbedCategories = []string{"pattern", "guidelines", "adr"}
+	if err := def.Validate(); err != nil {
+		t.Errorf("Validate() unexpected error = %v", err)
+	}
+
+	def.EmbedCategories = []string{"recipes"}
+	if err := def.Validate(); err == nil {
+		t.Error("Validate() expected error for unknown category, got nil")
+	}
+}
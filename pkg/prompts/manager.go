@@ -1,12 +1,14 @@
 package prompts
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,18 +16,30 @@ import (
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/logging"
 	"mcp-architecture-service/pkg/monitor"
+	"mcp-architecture-service/pkg/tokens"
+	"mcp-architecture-service/pkg/tools"
 )
 
 // PromptManager manages the lifecycle of prompt definitions
 type PromptManager struct {
 	registry      map[string]*PromptDefinition
-	promptsDir    string
+	promptsDirs   []string // Lowest to highest precedence
 	cache         *cache.DocumentCache
 	monitor       *monitor.FileSystemMonitor
 	renderer      *TemplateRenderer
+	normalization ArgumentNormalization
+	namePattern   *regexp.Regexp
+	// lenientNames loads prompts whose names do not match namePattern with a
+	// warning instead of skipping them
+	lenientNames  bool
 	mu            sync.RWMutex
 	logger        *logging.StructuredLogger
 	debounceTimer *time.Timer
+	watchStopped  bool // Set by StopWatching; no reloads are scheduled after it
+	// loadErrors describes the prompt files the last load skipped as invalid
+	loadErrors []string
+	// changeCallback is called after prompt files are reloaded on a change
+	changeCallback func()
 
 	// Performance metrics
 	stats PromptStats
@@ -40,19 +54,32 @@ type PromptStats struct {
 	RenderTimeByName      map[string]int64
 	ResourceEmbeddings    int64
 	ResourceEmbedCacheHit int64
+	ToolEmbeddings        int64
+	TotalRenderedBytes    int64
 	mu                    sync.RWMutex
 }
 
+// RenderMetrics describes the cost of one successful prompt render
+type RenderMetrics struct {
+	ResourcesEmbedded  int           // Documents embedded through {{resource:...}}
+	ResourcesTruncated int           // Embedded documents cut to fit the embed budget
+	ToolsEmbedded      int           // Tool references expanded through {{tool:...}}
+	RenderedBytes      int           // Total size of the rendered message texts
+	Duration           time.Duration // Time taken to render, including validation
+}
+
 // NewPromptManager creates a new prompt manager
 func NewPromptManager(promptsDir string, cache *cache.DocumentCache, monitor *monitor.FileSystemMonitor, logger *logging.StructuredLogger) *PromptManager {
 	renderer := NewTemplateRenderer(cache)
 	pm := &PromptManager{
-		registry:   make(map[string]*PromptDefinition),
-		promptsDir: promptsDir,
-		cache:      cache,
-		monitor:    monitor,
-		renderer:   renderer,
-		logger:     logger,
+		registry:      make(map[string]*PromptDefinition),
+		promptsDirs:   []string{promptsDir},
+		cache:         cache,
+		monitor:       monitor,
+		renderer:      renderer,
+		normalization: DefaultArgumentNormalization,
+		namePattern:   promptNamePattern,
+		logger:        logger,
 		stats: PromptStats{
 			InvocationsByName: make(map[string]int64),
 			RenderTimeByName:  make(map[string]int64),
@@ -69,41 +96,159 @@ func (pm *PromptManager) SetToolManager(toolManager ToolManagerInterface) {
 	pm.logger.Info("Tool manager configured for prompt-tool integration")
 }
 
-// ToolManagerInterface is an interface for accessing tool definitions
-type ToolManagerInterface interface {
-	GetTool(name string) (ToolInterface, error)
+// SetDocumentRanker sets how documents are ordered for {{resource:...}} patterns
+// with a query hint, such as architecture://patterns/*?q=caching. Without a ranker,
+// query hints fail the render.
+func (pm *PromptManager) SetDocumentRanker(ranker DocumentRanker) {
+	pm.renderer.SetDocumentRanker(ranker)
+}
+
+// SetEmbedFormat sets how documents embedded through {{resource:...}} are wrapped,
+// such as leaving out the source path or using a custom separator. Defaults to
+// DefaultEmbedFormat.
+func (pm *PromptManager) SetEmbedFormat(format EmbedFormat) error {
+	return pm.renderer.SetEmbedFormat(format)
+}
+
+// SetTokenEstimator sets how the {{tokens}} placeholder of embedded documents is
+// estimated. Passing nil restores tokens.DefaultEstimator. Must be called before
+// prompts are rendered.
+func (pm *PromptManager) SetTokenEstimator(estimator tokens.Estimator) {
+	pm.renderer.SetTokenEstimator(estimator)
+}
+
+// SetEmbedBudget sets the token budget of the documents each prompt message embeds
+// and whether exceeding it fails the render or truncates the largest documents.
+// Defaults to DefaultEmbedBudget, which sets no budget.
+func (pm *PromptManager) SetEmbedBudget(budget EmbedBudget) error {
+	return pm.renderer.SetEmbedBudget(budget)
+}
+
+// SetDelimiters sets the delimiters of template placeholders for prompts that do
+// not set their own in their definition. Defaults to DefaultDelimiters.
+func (pm *PromptManager) SetDelimiters(delimiters Delimiters) error {
+	return pm.renderer.SetDelimiters(delimiters)
 }
 
-// ToolInterface represents a tool with its metadata
-type ToolInterface interface {
-	Name() string
-	Description() string
-	InputSchema() map[string]interface{}
+// SetArgumentNormalization sets how string argument values are cleaned up before
+// they are validated and substituted. Defaults to DefaultArgumentNormalization.
+// Must be called before prompts are rendered.
+func (pm *PromptManager) SetArgumentNormalization(normalization ArgumentNormalization) {
+	pm.normalization = normalization
 }
 
-// LoadPrompts scans the prompts directory and loads all JSON prompt definitions
+// SetPromptDirectories sets the directories prompts are loaded from, replacing the
+// one NewPromptManager was given. They are listed from lowest to highest
+// precedence, such as a shared directory followed by a project-local one: a prompt
+// in a later directory overrides one with the same name in an earlier directory.
+// Must be called before LoadPrompts.
+func (pm *PromptManager) SetPromptDirectories(dirs []string) error {
+	if len(dirs) == 0 {
+		return fmt.Errorf("at least one prompts directory is required")
+	}
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			return fmt.Errorf("prompts directory must not be empty")
+		}
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.promptsDirs = append([]string(nil), dirs...)
+	return nil
+}
+
+// SetNamePattern sets the regular expression prompt names must match, replacing
+// DefaultNamePattern. Anchor it with ^ and $ to match whole names. A prompt whose
+// name does not match is skipped and reported by LoadErrors, unless lenient names
+// are enabled. Must be called before LoadPrompts.
+func (pm *PromptManager) SetNamePattern(pattern string) error {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid prompt name pattern: %w", err)
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.namePattern = compiled
+	return nil
+}
+
+// SetLenientNames sets whether a prompt whose name does not match the name pattern
+// is loaded with a warning instead of skipped. Disabled by default. Must be called
+// before LoadPrompts.
+func (pm *PromptManager) SetLenientNames(lenient bool) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.lenientNames = lenient
+}
+
+// SetChangeCallback sets a function called after the watched prompt directories
+// change and the prompts are reloaded, so clients can be told to list them again.
+// Must be called before StartWatching.
+func (pm *PromptManager) SetChangeCallback(callback func()) {
+	pm.changeCallback = callback
+}
+
+// ToolManagerInterface is an interface for accessing and running tools. It is
+// satisfied by tools.ToolManager, so prompts resolve tool references against the
+// same registry tools/call executes from.
+type ToolManagerInterface interface {
+	GetTool(name string) (tools.Tool, error)
+	ExecuteTool(ctx context.Context, name string, arguments map[string]interface{}) (interface{}, error)
+}
+
+// LoadPrompts scans the prompts directories and loads all JSON prompt definitions.
+// On a name collision the prompt from the higher-precedence directory wins.
 func (pm *PromptManager) LoadPrompts() error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	// Check if prompts directory exists
-	if _, err := os.Stat(pm.promptsDir); os.IsNotExist(err) {
-		pm.logger.WithContext("prompts_dir", pm.promptsDir).
-			Warn("Prompts directory does not exist, starting with empty registry")
-		return nil
+	registry := make(map[string]*PromptDefinition)
+	sources := make(map[string]string) // prompt name -> file it was loaded from
+	loadedCount := 0
+	var loadErrors []string
+
+	for _, dir := range pm.promptsDirs {
+		loaded, failed, err := pm.loadPromptDir(dir, registry, sources)
+		if err != nil {
+			return err
+		}
+		loadedCount += loaded
+		loadErrors = append(loadErrors, failed...)
 	}
 
-	// Clear existing registry
-	pm.registry = make(map[string]*PromptDefinition)
+	pm.registry = registry
+	pm.loadErrors = loadErrors
+
+	pm.logger.WithContext("loaded", loadedCount).
+		WithContext("errors", len(loadErrors)).
+		WithContext("total", len(pm.registry)).
+		WithContext("prompts_dirs", pm.promptsDirs).
+		Info("Prompt definitions loaded")
+
+	return nil
+}
+
+// loadPromptDir loads the JSON prompt definitions in dir into registry, overriding
+// any already loaded from a lower-precedence directory, and returns how many
+// files loaded and why the others failed
+func (pm *PromptManager) loadPromptDir(dir string, registry map[string]*PromptDefinition, sources map[string]string) (int, []string, error) {
+	// Check if prompts directory exists
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		pm.logger.WithContext("prompts_dir", dir).
+			Warn("Prompts directory does not exist, skipping")
+		return 0, nil, nil
+	}
 
 	// Read all files in prompts directory
-	entries, err := os.ReadDir(pm.promptsDir)
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to read prompts directory: %w", err)
+		return 0, nil, fmt.Errorf("failed to read prompts directory %s: %w", dir, err)
 	}
 
 	loadedCount := 0
-	errorCount := 0
+	var failures []string
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -111,47 +256,60 @@ func (pm *PromptManager) LoadPrompts() error {
 		}
 
 		// Only process JSON files
-		if filepath.Ext(entry.Name()) != ".json" {
+		if !isPromptFile(entry.Name()) {
 			continue
 		}
 
-		filePath := filepath.Join(pm.promptsDir, entry.Name())
-		if err := pm.loadPromptFile(filePath); err != nil {
+		filePath := filepath.Join(dir, entry.Name())
+		def, err := pm.loadPromptFile(filePath)
+		if err != nil {
 			pm.logger.WithError(err).
 				WithContext("file", filePath).
 				Error("Failed to load prompt definition, skipping")
-			errorCount++
+			failures = append(failures, fmt.Sprintf("%s: %v", filePath, err))
 			continue
 		}
 
+		if overridden, ok := sources[def.Name]; ok {
+			pm.logger.WithContext("prompt_name", def.Name).
+				WithContext("file", filePath).
+				WithContext("overridden_file", overridden).
+				Info("Prompt definition overrides one from a lower-precedence directory")
+		}
+		registry[def.Name] = def
+		sources[def.Name] = filePath
 		loadedCount++
 	}
 
-	pm.logger.WithContext("loaded", loadedCount).
-		WithContext("errors", errorCount).
-		WithContext("total", len(pm.registry)).
-		Info("Prompt definitions loaded")
-
-	return nil
+	return loadedCount, failures, nil
 }
 
-// loadPromptFile loads a single prompt definition file
-func (pm *PromptManager) loadPromptFile(filePath string) error {
+// loadPromptFile loads and validates a single prompt definition file
+func (pm *PromptManager) loadPromptFile(filePath string) (*PromptDefinition, error) {
 	// Read file
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Parse JSON
 	var def PromptDefinition
 	if err := json.Unmarshal(data, &def); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
 	// Validate definition
-	if err := def.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+	if err := def.validateStructure(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := def.validateName(pm.namePattern); err != nil {
+		if !pm.lenientNames {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		pm.logger.WithError(err).
+			WithContext("prompt_name", def.Name).
+			WithContext("file", filepath.Base(filePath)).
+			Warn("Prompt name does not match the name pattern, loading it anyway")
 	}
 
 	// Validate tool references if tool manager is available
@@ -165,22 +323,19 @@ func (pm *PromptManager) loadPromptFile(filePath string) error {
 		}
 	}
 
-	// Add to registry
-	pm.registry[def.Name] = &def
-
 	pm.logger.WithContext("prompt_name", def.Name).
 		WithContext("file", filepath.Base(filePath)).
 		Debug("Prompt definition loaded")
 
-	return nil
+	return &def, nil
 }
 
 // validateToolReferences checks if all tool references in a prompt are valid
 func (pm *PromptManager) validateToolReferences(def *PromptDefinition) error {
-	toolPattern := regexp.MustCompile(`\{\{tool:([a-z0-9-]+)\}\}`)
+	syntax := pm.templateSyntax(def)
 
 	for i, msg := range def.Messages {
-		matches := toolPattern.FindAllStringSubmatch(msg.Content.Text, -1)
+		matches := syntax.tool.FindAllStringSubmatch(msg.Content.Text, -1)
 		for _, match := range matches {
 			if len(match) < 2 {
 				continue
@@ -196,6 +351,24 @@ func (pm *PromptManager) validateToolReferences(def *PromptDefinition) error {
 	return nil
 }
 
+// templateSyntax returns the placeholder patterns of a prompt: its own delimiters
+// if it sets them, otherwise the renderer's
+func (pm *PromptManager) templateSyntax(def *PromptDefinition) *templateSyntax {
+	if def.Delimiters != nil {
+		return def.Delimiters.syntax()
+	}
+	return pm.renderer.delimiters.syntax()
+}
+
+// LoadErrors describes the prompt files the last load or reload skipped because
+// they could not be read, parsed or validated
+func (pm *PromptManager) LoadErrors() []string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	return append([]string(nil), pm.loadErrors...)
+}
+
 // GetPrompt retrieves a prompt definition by name
 func (pm *PromptManager) GetPrompt(name string) (*PromptDefinition, error) {
 	pm.mu.RLock()
@@ -230,7 +403,15 @@ func (pm *PromptManager) ListPrompts() []models.MCPPrompt {
 
 // RenderPrompt validates arguments, renders templates, and embeds resources
 func (pm *PromptManager) RenderPrompt(name string, arguments map[string]interface{}) (*models.MCPPromptsGetResult, error) {
+	result, _, err := pm.RenderPromptWithMetrics(name, arguments)
+	return result, err
+}
+
+// RenderPromptWithMetrics renders a prompt like RenderPrompt and also reports what
+// the render embedded and how long it took
+func (pm *PromptManager) RenderPromptWithMetrics(name string, arguments map[string]interface{}) (*models.MCPPromptsGetResult, RenderMetrics, error) {
 	startTime := time.Now()
+	var metrics RenderMetrics
 
 	// Track invocation
 	pm.recordInvocation(name)
@@ -251,10 +432,11 @@ func (pm *PromptManager) RenderPrompt(name string, arguments map[string]interfac
 			WithContext("prompt_name", name).
 			WithContext("duration_ms", duration.Milliseconds()).
 			Error("Failed to get prompt definition")
-		return nil, err
+		return nil, metrics, err
 	}
 
-	// Validate arguments
+	// Normalize and validate arguments
+	arguments = pm.normalization.normalize(prompt, arguments)
 	if err := prompt.ValidateArguments(arguments); err != nil {
 		duration := time.Since(startTime)
 		pm.recordFailedInvocation(name, duration)
@@ -263,15 +445,21 @@ func (pm *PromptManager) RenderPrompt(name string, arguments map[string]interfac
 			WithContext("duration_ms", duration.Milliseconds()).
 			WithContext("arguments", sanitizedArgs).
 			Error("Prompt argument validation failed")
-		return nil, fmt.Errorf("argument validation failed: %w", err)
+		return nil, metrics, fmt.Errorf("argument validation failed: %w", err)
 	}
 
 	// Render messages
 	messages := make([]models.MCPPromptMessage, 0, len(prompt.Messages))
+	syntax := pm.templateSyntax(prompt)
 
 	for i, msgTemplate := range prompt.Messages {
-		// Render template with arguments
-		renderedText, err := pm.renderer.RenderTemplate(msgTemplate.Content.Text, arguments)
+		// Argument values are held back until the author's directives are expanded,
+		// so a value cannot smuggle in a resource or tool directive of its own
+		renderedText, values := pm.renderer.maskArguments(syntax, msgTemplate.Content.Text, arguments)
+
+		// Embed tools before resources, so placeholder-like text in embedded
+		// documents is never expanded
+		withTools, values, toolCount, err := pm.renderer.embedTools(syntax, renderedText, values)
 		if err != nil {
 			duration := time.Since(startTime)
 			pm.recordFailedInvocation(name, duration)
@@ -279,12 +467,12 @@ func (pm *PromptManager) RenderPrompt(name string, arguments map[string]interfac
 				WithContext("prompt_name", name).
 				WithContext("message_index", i).
 				WithContext("duration_ms", duration.Milliseconds()).
-				Error("Failed to render prompt template")
-			return nil, fmt.Errorf("failed to render message %d: %w", i, err)
+				Error("Failed to embed tools in prompt")
+			return nil, metrics, fmt.Errorf("failed to embed tools in message %d: %w", i, err)
 		}
 
 		// Embed resources
-		withResources, err := pm.renderer.EmbedResources(renderedText)
+		finalText, resourceCount, truncatedCount, err := pm.renderer.embedResources(syntax, withTools, prompt.EmbedCategories, values)
 		if err != nil {
 			duration := time.Since(startTime)
 			pm.recordFailedInvocation(name, duration)
@@ -292,23 +480,17 @@ func (pm *PromptManager) RenderPrompt(name string, arguments map[string]interfac
 				WithContext("prompt_name", name).
 				WithContext("message_index", i).
 				WithContext("duration_ms", duration.Milliseconds()).
-				WithContext("rendered_template_preview", truncateString(renderedText, 200)).
+				WithContext("rendered_template_preview", truncateString(values.unmask(renderedText), 200)).
 				Error("Failed to embed resources in prompt")
-			return nil, fmt.Errorf("failed to embed resources in message %d: %w", i, err)
+			return nil, metrics, fmt.Errorf("failed to embed resources in message %d: %w", i, err)
 		}
 
-		// Embed tools
-		finalText, err := pm.renderer.EmbedTools(withResources)
-		if err != nil {
-			duration := time.Since(startTime)
-			pm.recordFailedInvocation(name, duration)
-			pm.logger.WithError(err).
-				WithContext("prompt_name", name).
-				WithContext("message_index", i).
-				WithContext("duration_ms", duration.Milliseconds()).
-				Error("Failed to embed tools in prompt")
-			return nil, fmt.Errorf("failed to embed tools in message %d: %w", i, err)
-		}
+		finalText = values.unmask(finalText)
+
+		metrics.ResourcesEmbedded += resourceCount
+		metrics.ResourcesTruncated += truncatedCount
+		metrics.ToolsEmbedded += toolCount
+		metrics.RenderedBytes += len(finalText)
 
 		messages = append(messages, models.MCPPromptMessage{
 			Role: msgTemplate.Role,
@@ -324,15 +506,41 @@ func (pm *PromptManager) RenderPrompt(name string, arguments map[string]interfac
 		Messages:    messages,
 	}
 
-	duration := time.Since(startTime)
-	pm.recordSuccessfulRender(name, duration)
+	metrics.Duration = time.Since(startTime)
+	pm.recordSuccessfulRender(name, metrics)
 	pm.logger.WithContext("prompt_name", name).
 		WithContext("message_count", len(messages)).
-		WithContext("duration_ms", duration.Milliseconds()).
+		WithContext("resources_embedded", metrics.ResourcesEmbedded).
+		WithContext("resources_truncated", metrics.ResourcesTruncated).
+		WithContext("tools_embedded", metrics.ToolsEmbedded).
+		WithContext("rendered_bytes", metrics.RenderedBytes).
+		WithContext("duration_ms", metrics.Duration.Milliseconds()).
 		WithContext("arguments", sanitizedArgs).
 		Info("Prompt rendered successfully")
 
-	return result, nil
+	return result, metrics, nil
+}
+
+// PreviewPrompt renders a prompt like RenderPrompt and flattens the messages into a
+// single text for copy-paste debugging. Each message is headed by its role in
+// brackets, such as "[user]", and messages are separated by a blank line.
+func (pm *PromptManager) PreviewPrompt(name string, arguments map[string]interface{}) (*models.MCPPromptsPreviewResult, error) {
+	rendered, err := pm.RenderPrompt(name, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, 0, len(rendered.Messages))
+	for _, message := range rendered.Messages {
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", message.Role, message.Content.Text))
+	}
+
+	return &models.MCPPromptsPreviewResult{
+		Name:         name,
+		Description:  rendered.Description,
+		MessageCount: len(rendered.Messages),
+		Text:         strings.Join(parts, "\n\n"),
+	}, nil
 }
 
 // sanitizeArgumentsForLogging sanitizes argument values for safe logging
@@ -370,31 +578,50 @@ func (pm *PromptManager) ReloadPrompts() error {
 	return nil
 }
 
-// StartWatching sets up file system monitoring for the prompts directory
+// StartWatching sets up file system monitoring for the prompts directories
 func (pm *PromptManager) StartWatching() error {
-	// Check if prompts directory exists
-	if _, err := os.Stat(pm.promptsDir); os.IsNotExist(err) {
-		pm.logger.WithContext("prompts_dir", pm.promptsDir).
-			Warn("Prompts directory does not exist, skipping file system monitoring")
-		return nil
-	}
+	for _, dir := range pm.promptsDirs {
+		// Check if prompts directory exists
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			pm.logger.WithContext("prompts_dir", dir).
+				Warn("Prompts directory does not exist, skipping file system monitoring")
+			continue
+		}
 
-	// Set up file system monitoring with debounced callback
-	err := pm.monitor.WatchDirectory(pm.promptsDir, pm.handleFileEvent)
-	if err != nil {
-		return fmt.Errorf("failed to watch prompts directory: %w", err)
-	}
+		// Set up file system monitoring with debounced callback
+		err := pm.monitor.WatchDirectoryFiles(dir, isPromptFile, pm.handleFileEvent)
+		if err != nil {
+			return fmt.Errorf("failed to watch prompts directory %s: %w", dir, err)
+		}
 
-	pm.logger.WithContext("prompts_dir", pm.promptsDir).
-		Info("Started watching prompts directory for changes")
+		pm.logger.WithContext("prompts_dir", dir).
+			Info("Started watching prompts directory for changes")
+	}
 
 	return nil
 }
 
+// StopWatching cancels any pending reload and ignores later file events. The
+// directories themselves are unwatched when the monitor stops.
+func (pm *PromptManager) StopWatching() {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.watchStopped = true
+	if pm.debounceTimer != nil {
+		pm.debounceTimer.Stop()
+	}
+}
+
+// isPromptFile reports whether path is a prompt definition file
+func isPromptFile(path string) bool {
+	return filepath.Ext(path) == ".json"
+}
+
 // handleFileEvent processes file system events with debouncing
 func (pm *PromptManager) handleFileEvent(event models.FileEvent) {
 	// Only process JSON files
-	if filepath.Ext(event.Path) != ".json" {
+	if !isPromptFile(event.Path) {
 		return
 	}
 
@@ -404,13 +631,22 @@ func (pm *PromptManager) handleFileEvent(event models.FileEvent) {
 
 	// Debounce rapid file changes (500ms delay)
 	pm.mu.Lock()
+	if pm.watchStopped {
+		pm.mu.Unlock()
+		return
+	}
 	if pm.debounceTimer != nil {
 		pm.debounceTimer.Stop()
 	}
 
 	pm.debounceTimer = time.AfterFunc(500*time.Millisecond, func() {
+		// Invalid prompt files are logged and skipped by the reload
 		if err := pm.ReloadPrompts(); err != nil {
 			pm.logger.WithError(err).Error("Failed to reload prompts after file change")
+			return
+		}
+		if pm.changeCallback != nil {
+			pm.changeCallback()
 		}
 	})
 	pm.mu.Unlock()
@@ -425,14 +661,16 @@ func (pm *PromptManager) recordInvocation(name string) {
 	pm.stats.InvocationsByName[name]++
 }
 
-// recordSuccessfulRender records a successful prompt render with duration
-func (pm *PromptManager) recordSuccessfulRender(name string, duration time.Duration) {
+// recordSuccessfulRender records a successful prompt render with its metrics
+func (pm *PromptManager) recordSuccessfulRender(name string, metrics RenderMetrics) {
 	pm.stats.mu.Lock()
 	defer pm.stats.mu.Unlock()
 
-	durationMs := duration.Milliseconds()
+	durationMs := metrics.Duration.Milliseconds()
 	pm.stats.TotalRenderTimeMs += durationMs
 	pm.stats.RenderTimeByName[name] += durationMs
+	pm.stats.ToolEmbeddings += int64(metrics.ToolsEmbedded)
+	pm.stats.TotalRenderedBytes += int64(metrics.RenderedBytes)
 }
 
 // recordFailedInvocation records a failed prompt invocation
@@ -479,6 +717,12 @@ func (pm *PromptManager) GetPerformanceMetrics() map[string]interface{} {
 		resourceCacheHitRate = float64(pm.stats.ResourceEmbedCacheHit) / float64(pm.stats.ResourceEmbeddings) * 100.0
 	}
 
+	// Calculate average rendered size
+	var avgRenderedBytes float64
+	if successfulInvocations > 0 {
+		avgRenderedBytes = float64(pm.stats.TotalRenderedBytes) / float64(successfulInvocations)
+	}
+
 	// Copy invocations by name for safe return
 	invocationsByName := make(map[string]int64)
 	for name, count := range pm.stats.InvocationsByName {
@@ -504,6 +748,9 @@ func (pm *PromptManager) GetPerformanceMetrics() map[string]interface{} {
 		"total_render_time_ms":       pm.stats.TotalRenderTimeMs,
 		"resource_embeddings":        pm.stats.ResourceEmbeddings,
 		"resource_cache_hit_rate":    resourceCacheHitRate,
+		"tool_embeddings":            pm.stats.ToolEmbeddings,
+		"total_rendered_bytes":       pm.stats.TotalRenderedBytes,
+		"avg_rendered_bytes":         avgRenderedBytes,
 	}
 }
 
@@ -1,8 +1,10 @@
 package prompts
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/logging"
 	"mcp-architecture-service/pkg/monitor"
+	"mcp-architecture-service/pkg/tools"
 )
 
 func TestNewPromptManager(t *testing.T) {
@@ -30,8 +33,8 @@ func TestNewPromptManager(t *testing.T) {
 		t.Fatal("NewPromptManager() returned nil")
 	}
 
-	if pm.promptsDir != "prompts" {
-		t.Errorf("Expected promptsDir 'prompts', got '%s'", pm.promptsDir)
+	if len(pm.promptsDirs) != 1 || pm.promptsDirs[0] != "prompts" {
+		t.Errorf("Expected promptsDirs [prompts], got %v", pm.promptsDirs)
 	}
 
 	if pm.registry == nil {
@@ -144,6 +147,137 @@ func TestLoadPrompts(t *testing.T) {
 	if _, exists := pm.registry["invalid"]; exists {
 		t.Error("Expected 'invalid' prompt to be skipped")
 	}
+
+	// The skipped file is reported with why it failed
+	loadErrors := pm.LoadErrors()
+	if len(loadErrors) != 1 || !strings.Contains(loadErrors[0], "invalid-prompt.json") {
+		t.Errorf("Expected one load error for invalid-prompt.json, got %v", loadErrors)
+	}
+}
+
+func TestLoadPromptsNamePattern(t *testing.T) {
+	promptNamed := func(name string) string {
+		return `{"name": "` + name + `", "messages": [{"role": "user", "content": {"type": "text", "text": "Hello"}}]}`
+	}
+
+	tests := []struct {
+		name        string
+		promptName  string
+		pattern     string
+		lenient     bool
+		wantLoaded  bool
+		wantLoadErr bool
+	}{
+		{name: "conforming name loads", promptName: "review-code", wantLoaded: true},
+		{name: "non-conforming name rejected", promptName: "Review_Code", wantLoadErr: true},
+		{name: "non-conforming name loaded with warning when lenient", promptName: "Review_Code", lenient: true, wantLoaded: true},
+		{name: "custom pattern accepts its names", promptName: "review_code", pattern: `^[a-z_]+$`, wantLoaded: true},
+		{name: "custom pattern rejects other names", promptName: "review-code", pattern: `^[a-z_]+$`, wantLoadErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "prompt.json"), []byte(promptNamed(tt.promptName)), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			cache := cache.NewDocumentCache()
+			defer cache.Close()
+			pm := NewPromptManager(tmpDir, cache, nil, logging.NewStructuredLogger("test"))
+			if tt.pattern != "" {
+				if err := pm.SetNamePattern(tt.pattern); err != nil {
+					t.Fatalf("SetNamePattern() unexpected error: %v", err)
+				}
+			}
+			pm.SetLenientNames(tt.lenient)
+
+			if err := pm.LoadPrompts(); err != nil {
+				t.Fatalf("LoadPrompts() unexpected error: %v", err)
+			}
+			if _, loaded := pm.registry[tt.promptName]; loaded != tt.wantLoaded {
+				t.Errorf("Prompt loaded = %v, want %v", loaded, tt.wantLoaded)
+			}
+			loadErrors := pm.LoadErrors()
+			if (len(loadErrors) > 0) != tt.wantLoadErr {
+				t.Errorf("LoadErrors() = %v, want errors %v", loadErrors, tt.wantLoadErr)
+			}
+			if tt.wantLoadErr && !strings.Contains(loadErrors[0], "prompt name must match pattern") {
+				t.Errorf("Expected a name pattern error, got %q", loadErrors[0])
+			}
+		})
+	}
+
+	pm := NewPromptManager(t.TempDir(), nil, nil, logging.NewStructuredLogger("test"))
+	if err := pm.SetNamePattern("[a-z"); err == nil {
+		t.Error("Expected an error for an invalid name pattern")
+	}
+}
+
+func TestLoadPromptsMultipleDirectories(t *testing.T) {
+	sharedDir := t.TempDir()
+	localDir := t.TempDir()
+
+	writePrompt := func(dir, file, name, description string) {
+		t.Helper()
+		prompt := fmt.Sprintf(`{"name": %q, "description": %q, "messages": [{"role": "user", "content": {"type": "text", "text": "Hello"}}]}`, name, description)
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(prompt), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	writePrompt(sharedDir, "review.json", "review", "Shared review")
+	writePrompt(sharedDir, "shared-only.json", "shared-only", "Shared only")
+	// The file name differs; prompts collide by name
+	writePrompt(localDir, "code-review.json", "review", "Project review")
+	writePrompt(localDir, "local-only.json", "local-only", "Local only")
+
+	cache := cache.NewDocumentCache()
+	defer cache.Close()
+
+	pm := NewPromptManager(sharedDir, cache, nil, logging.NewStructuredLogger("test"))
+	missingDir := filepath.Join(t.TempDir(), "missing")
+	if err := pm.SetPromptDirectories([]string{sharedDir, missingDir, localDir}); err != nil {
+		t.Fatalf("SetPromptDirectories() unexpected error: %v", err)
+	}
+	if err := pm.LoadPrompts(); err != nil {
+		t.Fatalf("LoadPrompts() unexpected error: %v", err)
+	}
+
+	if len(pm.registry) != 3 {
+		t.Errorf("Expected 3 prompts in registry, got %d", len(pm.registry))
+	}
+	for _, name := range []string{"shared-only", "local-only"} {
+		if _, exists := pm.registry[name]; !exists {
+			t.Errorf("Expected %q to be in registry", name)
+		}
+	}
+	if review := pm.registry["review"]; review == nil || review.Description != "Project review" {
+		t.Errorf("Expected the project-local review prompt to win, got %+v", review)
+	}
+
+	// Reversing the precedence lets the shared prompt win
+	if err := pm.SetPromptDirectories([]string{localDir, sharedDir}); err != nil {
+		t.Fatalf("SetPromptDirectories() unexpected error: %v", err)
+	}
+	if err := pm.LoadPrompts(); err != nil {
+		t.Fatalf("LoadPrompts() unexpected error: %v", err)
+	}
+	if review := pm.registry["review"]; review == nil || review.Description != "Shared review" {
+		t.Errorf("Expected the shared review prompt to win, got %+v", review)
+	}
+}
+
+func TestSetPromptDirectoriesInvalid(t *testing.T) {
+	pm := NewPromptManager("prompts", nil, nil, logging.NewStructuredLogger("test"))
+
+	for _, dirs := range [][]string{nil, {"shared", ""}, {" "}} {
+		if err := pm.SetPromptDirectories(dirs); err == nil {
+			t.Errorf("SetPromptDirectories(%q) expected error, got nil", dirs)
+		}
+	}
+	if len(pm.promptsDirs) != 1 || pm.promptsDirs[0] != "prompts" {
+		t.Errorf("Expected rejected directories to leave promptsDirs unchanged, got %v", pm.promptsDirs)
+	}
 }
 
 func TestLoadPromptsNonExistentDirectory(t *testing.T) {
@@ -555,6 +689,54 @@ func TestHandleFileEvent(t *testing.T) {
 	}
 }
 
+func TestStartWatchingLoadsNewPromptFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cache := cache.NewDocumentCache()
+	defer cache.Close()
+
+	monitor, err := monitor.NewFileSystemMonitor()
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	defer monitor.StopWatching()
+
+	pm := NewPromptManager(tmpDir, cache, monitor, logging.NewStructuredLogger("test"))
+	changed := make(chan struct{}, 10)
+	pm.SetChangeCallback(func() { changed <- struct{}{} })
+
+	if err := pm.LoadPrompts(); err != nil {
+		t.Fatalf("LoadPrompts() unexpected error: %v", err)
+	}
+	if err := pm.StartWatching(); err != nil {
+		t.Fatalf("StartWatching() unexpected error: %v", err)
+	}
+
+	// An invalid file is skipped without stopping the watcher
+	if err := os.WriteFile(filepath.Join(tmpDir, "broken.json"), []byte(`{"name": `), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	promptContent := `{"name": "new-prompt", "messages": [{"role": "user", "content": {"type": "text", "text": "Test"}}]}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "new-prompt.json"), []byte(promptContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-changed:
+		case <-deadline:
+			t.Fatal("Timed out waiting for the new prompt to be loaded")
+		}
+		if _, err := pm.GetPrompt("new-prompt"); err == nil {
+			break
+		}
+	}
+	if len(pm.ListPrompts()) != 1 {
+		t.Errorf("Expected only the valid prompt to be loaded, got %d", len(pm.ListPrompts()))
+	}
+}
+
 func TestHandleFileEventNonJSON(t *testing.T) {
 	cache := cache.NewDocumentCache()
 	defer cache.Close()
@@ -578,3 +760,57 @@ func TestHandleFileEventNonJSON(t *testing.T) {
 	// Should not panic or error
 	pm.handleFileEvent(event)
 }
+
+func TestRenderPromptWithMetrics(t *testing.T) {
+	pm := NewPromptManager("prompts", newEmbedPolicyCache(t), nil, logging.NewStructuredLogger("test"))
+	pm.SetToolManager(&mockToolManager{tools: map[string]tools.Tool{
+		"validate-pattern": &mockTool{name: "validate-pattern", description: "Validates code"},
+	}})
+	pm.registry["review"] = &PromptDefinition{
+		Name: "review",
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "Guidelines:\n{{resource:architecture://guidelines/*}}\nPatterns:\n{{resource:architecture://patterns/*}}"}},
+			{Role: "assistant", Content: ContentTemplate{Type: "text", Text: "Checking with {{tool:validate-pattern}}"}},
+		},
+	}
+
+	result, metrics, err := pm.RenderPromptWithMetrics("review", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("RenderPromptWithMetrics() unexpected error: %v", err)
+	}
+
+	renderedBytes := 0
+	for _, message := range result.Messages {
+		renderedBytes += len(message.Content.Text)
+	}
+	if metrics.ResourcesEmbedded != 2 {
+		t.Errorf("ResourcesEmbedded = %d, want 2", metrics.ResourcesEmbedded)
+	}
+	if metrics.ToolsEmbedded != 1 {
+		t.Errorf("ToolsEmbedded = %d, want 1", metrics.ToolsEmbedded)
+	}
+	if metrics.RenderedBytes != renderedBytes {
+		t.Errorf("RenderedBytes = %d, want %d", metrics.RenderedBytes, renderedBytes)
+	}
+	if metrics.Duration <= 0 {
+		t.Errorf("Duration = %v, want a positive duration", metrics.Duration)
+	}
+
+	// A second render doubles the aggregate counts
+	if _, err := pm.RenderPrompt("review", map[string]interface{}{}); err != nil {
+		t.Fatalf("RenderPrompt() unexpected error: %v", err)
+	}
+	perf := pm.GetPerformanceMetrics()
+	if perf["resource_embeddings"] != int64(4) {
+		t.Errorf("resource_embeddings = %v, want 4", perf["resource_embeddings"])
+	}
+	if perf["tool_embeddings"] != int64(2) {
+		t.Errorf("tool_embeddings = %v, want 2", perf["tool_embeddings"])
+	}
+	if perf["total_rendered_bytes"] != int64(2*renderedBytes) {
+		t.Errorf("total_rendered_bytes = %v, want %d", perf["total_rendered_bytes"], 2*renderedBytes)
+	}
+	if perf["avg_rendered_bytes"] != float64(renderedBytes) {
+		t.Errorf("avg_rendered_bytes = %v, want %d", perf["avg_rendered_bytes"], renderedBytes)
+	}
+}
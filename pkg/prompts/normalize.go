@@ -0,0 +1,50 @@
+package prompts
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ArgumentNormalization controls how string argument values are cleaned up before
+// they are validated and substituted into templates. Arguments flagged preserve in
+// the prompt definition, such as code, are passed through untouched.
+type ArgumentNormalization struct {
+	Trim               bool // Strip leading and trailing whitespace
+	CollapseWhitespace bool // Replace each run of whitespace with a single space
+}
+
+// DefaultArgumentNormalization trims values and keeps their internal whitespace
+var DefaultArgumentNormalization = ArgumentNormalization{Trim: true}
+
+// whitespaceRunPattern matches a run of whitespace, newlines included
+var whitespaceRunPattern = regexp.MustCompile(`\s+`)
+
+// normalize returns the string argument values of args normalized, except those
+// of arguments def flags preserve. args itself is left unchanged.
+func (an ArgumentNormalization) normalize(def *PromptDefinition, args map[string]interface{}) map[string]interface{} {
+	if !an.Trim && !an.CollapseWhitespace {
+		return args
+	}
+
+	preserved := make(map[string]bool)
+	for _, argDef := range def.Arguments {
+		if argDef.Preserve {
+			preserved[argDef.Name] = true
+		}
+	}
+
+	normalized := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		if text, ok := value.(string); ok && !preserved[name] {
+			if an.CollapseWhitespace {
+				text = whitespaceRunPattern.ReplaceAllString(text, " ")
+			}
+			if an.Trim {
+				text = strings.TrimSpace(text)
+			}
+			value = text
+		}
+		normalized[name] = value
+	}
+	return normalized
+}
@@ -0,0 +1,110 @@
+package prompts
+
+import (
+	"path/filepath"
+	"testing"
+
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+func TestArgumentNormalization_Normalize(t *testing.T) {
+	def := &PromptDefinition{
+		Arguments: []ArgumentDefinition{
+			{Name: "topic"},
+			{Name: "code", Preserve: true},
+			{Name: "count"},
+		},
+	}
+	args := map[string]interface{}{
+		"topic": "  event \t sourcing\n",
+		"code":  "  if x {\n\treturn\n}\n",
+		"count": 3,
+	}
+
+	tests := []struct {
+		name          string
+		normalization ArgumentNormalization
+		wantTopic     string
+	}{
+		{name: "default trims", normalization: DefaultArgumentNormalization, wantTopic: "event \t sourcing"},
+		{name: "collapse and trim", normalization: ArgumentNormalization{Trim: true, CollapseWhitespace: true}, wantTopic: "event sourcing"},
+		{name: "collapse only", normalization: ArgumentNormalization{CollapseWhitespace: true}, wantTopic: " event sourcing "},
+		{name: "disabled", normalization: ArgumentNormalization{}, wantTopic: "  event \t sourcing\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.normalization.normalize(def, args)
+
+			if got["topic"] != tt.wantTopic {
+				t.Errorf("topic = %q, want %q", got["topic"], tt.wantTopic)
+			}
+			if got["code"] != args["code"] {
+				t.Errorf("Preserve-flagged code changed to %q", got["code"])
+			}
+			if got["count"] != 3 {
+				t.Errorf("Non-string value changed to %v", got["count"])
+			}
+		})
+	}
+
+	if args["topic"] != "  event \t sourcing\n" {
+		t.Error("normalize modified the caller's arguments")
+	}
+}
+
+func TestRenderPrompt_NormalizesArguments(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	defer docCache.Close()
+
+	pm := NewPromptManager("prompts", docCache, nil, logging.NewStructuredLogger("test"))
+	pm.registry["review"] = &PromptDefinition{
+		Name: "review",
+		Arguments: []ArgumentDefinition{
+			{Name: "language", MaxLength: 2},
+			{Name: "code", Preserve: true},
+		},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "Review this {{language}} code:\n{{code}}"}},
+		},
+	}
+
+	// The padded language only fits maxLength once trimmed
+	result, err := pm.RenderPrompt("review", map[string]interface{}{
+		"language": "  Go \n",
+		"code":     "    return nil\n",
+	})
+	if err != nil {
+		t.Fatalf("RenderPrompt() unexpected error: %v", err)
+	}
+
+	want := "Review this Go code:\n    return nil\n"
+	if got := result.Messages[0].Content.Text; got != want {
+		t.Errorf("Rendered %q, want %q", got, want)
+	}
+}
+
+func TestShippedPromptsPreserveCode(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	defer docCache.Close()
+
+	pm := NewPromptManager(filepath.Join("..", "..", config.PromptsBasePath), docCache, nil, logging.NewStructuredLogger("test"))
+	if err := pm.LoadPrompts(); err != nil {
+		t.Fatalf("LoadPrompts() unexpected error: %v", err)
+	}
+
+	// Submitted code keeps its indentation however normalization is configured
+	for _, name := range []string{"review-code-against-patterns", "guided-pattern-validation"} {
+		prompt, err := pm.GetPrompt(name)
+		if err != nil {
+			t.Fatalf("GetPrompt(%s) unexpected error: %v", name, err)
+		}
+		for _, arg := range prompt.Arguments {
+			if arg.Name == "code" && !arg.Preserve {
+				t.Errorf("Expected the code argument of %s to be flagged preserve", name)
+			}
+		}
+	}
+}
@@ -1,14 +1,19 @@
 package prompts
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/tokens"
+	"mcp-architecture-service/pkg/tools"
 )
 
 const (
@@ -23,6 +28,14 @@ type TemplateRenderer struct {
 	cache         *cache.DocumentCache
 	statsRecorder StatsRecorder
 	toolManager   ToolManagerInterface
+	embedFormat   EmbedFormat
+	delimiters    Delimiters
+	// tokenEstimator fills the {{tokens}} placeholder of embed headers and footers
+	// and measures embedded content against embedBudget
+	tokenEstimator tokens.Estimator
+	embedBudget    EmbedBudget
+	// ranker orders the documents of patterns with a query hint; nil rejects hints
+	ranker DocumentRanker
 }
 
 // StatsRecorder is an interface for recording statistics
@@ -33,9 +46,13 @@ type StatsRecorder interface {
 // NewTemplateRenderer creates a new template renderer with access to the document cache
 func NewTemplateRenderer(cache *cache.DocumentCache) *TemplateRenderer {
 	return &TemplateRenderer{
-		cache:         cache,
-		statsRecorder: nil, // Will be set later by SetStatsRecorder
-		toolManager:   nil, // Will be set later by SetToolManager
+		cache:          cache,
+		statsRecorder:  nil, // Will be set later by SetStatsRecorder
+		toolManager:    nil, // Will be set later by SetToolManager
+		embedFormat:    DefaultEmbedFormat,
+		delimiters:     DefaultDelimiters,
+		tokenEstimator: tokens.DefaultEstimator,
+		embedBudget:    DefaultEmbedBudget,
 	}
 }
 
@@ -49,116 +66,276 @@ func (tr *TemplateRenderer) SetToolManager(manager ToolManagerInterface) {
 	tr.toolManager = manager
 }
 
-var (
-	// variablePattern matches {{variableName}} for substitution
-	variablePattern = regexp.MustCompile(`\{\{([a-zA-Z0-9_-]+)\}\}`)
-	// resourcePattern matches {{resource:uri}} for resource embedding
-	resourcePattern = regexp.MustCompile(`\{\{resource:([^}]+)\}\}`)
-	// toolPattern matches {{tool:tool-name}} for tool reference embedding
-	toolPattern = regexp.MustCompile(`\{\{tool:([a-z0-9-]+)\}\}`)
-)
+// SetEmbedFormat sets how embedded documents are wrapped. See EmbedFormat.
+func (tr *TemplateRenderer) SetEmbedFormat(format EmbedFormat) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+	tr.embedFormat = format
+	return nil
+}
+
+// SetTokenEstimator sets how the {{tokens}} placeholder of embedded documents is
+// estimated. Passing nil restores tokens.DefaultEstimator.
+func (tr *TemplateRenderer) SetTokenEstimator(estimator tokens.Estimator) {
+	if estimator == nil {
+		estimator = tokens.DefaultEstimator
+	}
+	tr.tokenEstimator = estimator
+}
+
+// SetEmbedBudget sets the token budget of the documents each message embeds. See
+// EmbedBudget.
+func (tr *TemplateRenderer) SetEmbedBudget(budget EmbedBudget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+	tr.embedBudget = budget
+	return nil
+}
+
+// SetDocumentRanker sets how documents are ordered for resource patterns with a
+// query hint
+func (tr *TemplateRenderer) SetDocumentRanker(ranker DocumentRanker) {
+	tr.ranker = ranker
+}
+
+// SetDelimiters sets the delimiters of template placeholders for prompts that do
+// not set their own. Defaults to DefaultDelimiters.
+func (tr *TemplateRenderer) SetDelimiters(delimiters Delimiters) error {
+	if err := delimiters.Validate(); err != nil {
+		return err
+	}
+	tr.delimiters = delimiters
+	return nil
+}
 
 // RenderTemplate performs variable substitution on a template string
 // Variables are specified as {{variableName}} and replaced with values from args
 func (tr *TemplateRenderer) RenderTemplate(template string, args map[string]any) (string, error) {
-	matches := variablePattern.FindAllStringSubmatch(template, -1)
-	result := template
-
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
+	return tr.renderTemplate(tr.delimiters.syntax(), template, args)
+}
 
-		placeholder := match[0] // Full match like {{variableName}}
-		varName := match[1]     // Variable name without braces
+// renderTemplate is RenderTemplate with the placeholders of syntax. Variables are
+// replaced in a single pass, so a value containing a placeholder is not expanded.
+func (tr *TemplateRenderer) renderTemplate(syntax *templateSyntax, template string, args map[string]any) (string, error) {
+	result := syntax.variable.ReplaceAllStringFunc(template, func(placeholder string) string {
+		varName := syntax.variable.FindStringSubmatch(placeholder)[1]
 
 		value, exists := args[varName]
 		if !exists {
 			// Variable not provided - leave placeholder as-is
 			// Arguments are validated before rendering, so missing required args won't reach here
-			continue
+			return placeholder
 		}
-
-		strValue := fmt.Sprintf("%v", value)
-		result = strings.ReplaceAll(result, placeholder, strValue)
-	}
+		return fmt.Sprintf("%v", value)
+	})
 
 	return result, nil
 }
 
+// argumentMarkerPattern matches the markers maskArguments leaves in place of
+// argument values. Documents and tool descriptions cannot contain NUL, so embedded
+// content never looks like a marker.
+var argumentMarkerPattern = regexp.MustCompile(`\x00([0-9]+)\x00`)
+
+// maskedArguments holds the argument values maskArguments took out of a template
+type maskedArguments []string
+
+// maskArguments is renderTemplate, except that each substituted value is left out
+// behind a marker until unmask puts it back. Expanding directives in between keeps
+// directive-like text in argument values, such as {{resource:...}}, from being
+// processed as if the prompt author had written it.
+func (tr *TemplateRenderer) maskArguments(syntax *templateSyntax, template string, args map[string]any) (string, maskedArguments) {
+	var values maskedArguments
+	masked := syntax.variable.ReplaceAllStringFunc(template, func(placeholder string) string {
+		varName := syntax.variable.FindStringSubmatch(placeholder)[1]
+
+		value, exists := args[varName]
+		if !exists {
+			return placeholder
+		}
+		values = append(values, fmt.Sprintf("%v", value))
+		return fmt.Sprintf("\x00%d\x00", len(values)-1)
+	})
+	return masked, values
+}
+
+// unmask replaces the markers maskArguments left in text with their values
+func (values maskedArguments) unmask(text string) string {
+	if len(values) == 0 {
+		return text
+	}
+	return argumentMarkerPattern.ReplaceAllStringFunc(text, func(marker string) string {
+		index, err := strconv.Atoi(strings.Trim(marker, "\x00"))
+		if err != nil || index >= len(values) {
+			return marker
+		}
+		return values[index]
+	})
+}
+
 // EmbedResources processes resource embedding patterns in the template
-// Resource patterns are specified as {{resource:uri}} where uri can include wildcards
-func (tr *TemplateRenderer) EmbedResources(template string) (string, error) {
-	matches := resourcePattern.FindAllStringSubmatch(template, -1)
-	result := template
-	totalSize := 0
-	resourceCount := 0
+// Resource patterns are specified as {{resource:uri}} where uri can include wildcards.
+// Only categories in allowedCategories may be embedded; an empty list allows all.
+func (tr *TemplateRenderer) EmbedResources(template string, allowedCategories []string) (string, error) {
+	result, _, _, err := tr.embedResources(tr.delimiters.syntax(), template, allowedCategories, nil)
+	return result, err
+}
+
+// resourceEmbed is one {{resource:...}} directive and the documents it resolved to
+type resourceEmbed struct {
+	placeholder string
+	documents   []*models.Document
+}
+
+// embedResources is EmbedResources with the placeholders of syntax, also returning
+// the number of documents embedded and how many of them were truncated to fit the
+// embed budget. Argument values masked in template are put back into the URI
+// patterns of the directives they appear in.
+func (tr *TemplateRenderer) embedResources(syntax *templateSyntax, template string, allowedCategories []string, values maskedArguments) (string, int, int, error) {
+	matches := syntax.resource.FindAllStringSubmatch(template, -1)
+	embeds := make([]resourceEmbed, 0, len(matches))
+	var documents []*models.Document
 
 	for _, match := range matches {
 		if len(match) < 2 {
 			continue
 		}
 
-		placeholder := match[0] // Full match like {{resource:architecture://patterns/*}}
-		pattern := match[1]     // URI pattern
+		placeholder := match[0]                           // Full match like {{resource:architecture://patterns/*}}
+		pattern := values.unmaskResourcePattern(match[1]) // URI pattern
 
-		documents, err := tr.ResolveResourcePattern(pattern)
+		resolved, err := tr.ResolveResourcePattern(pattern, allowedCategories)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve resource pattern '%s': %w", pattern, err)
+			return "", 0, 0, fmt.Errorf("failed to resolve resource pattern '%s': %w", pattern, err)
 		}
 
-		resourceCount += len(documents)
-		if resourceCount > MaxResourcesPerPrompt {
-			return "", fmt.Errorf("resource limit exceeded: maximum %d resources allowed per prompt", MaxResourcesPerPrompt)
+		documents = append(documents, resolved...)
+		if len(documents) > MaxResourcesPerPrompt {
+			return "", 0, 0, fmt.Errorf("resource limit exceeded: maximum %d resources allowed per prompt", MaxResourcesPerPrompt)
 		}
+		embeds = append(embeds, resourceEmbed{placeholder: placeholder, documents: resolved})
+	}
 
-		embeddedContent, size, err := tr.buildEmbeddedContent(documents, totalSize)
+	// The budget covers every directive in the message, so all documents are
+	// resolved before any is cut to fit
+	contents, truncated, err := tr.embedBudget.fit(documents, tr.tokenEstimator)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	result := template
+	totalSize := 0
+	for _, embed := range embeds {
+		embeddedContent, size, err := tr.buildEmbeddedContent(embed.documents, contents[:len(embed.documents)], totalSize)
 		if err != nil {
-			return "", err
+			return "", 0, 0, err
 		}
+		contents = contents[len(embed.documents):]
 		totalSize = size
 
-		result = strings.ReplaceAll(result, placeholder, embeddedContent)
+		result = strings.ReplaceAll(result, embed.placeholder, embeddedContent)
 	}
 
-	return result, nil
+	return result, len(documents), truncated, nil
 }
 
 // EmbedTools processes tool reference patterns in the template
 // Tool patterns are specified as {{tool:tool-name}} and are expanded to include
-// the tool's description and input schema
+// the tool's description and input schema. A pattern with arguments, such as
+// {{tool:check-adr-alignment?decision_description=...}}, runs the tool instead and
+// is expanded to its result.
 func (tr *TemplateRenderer) EmbedTools(template string) (string, error) {
+	result, values, _, err := tr.embedTools(tr.delimiters.syntax(), template, nil)
+	return values.unmask(result), err
+}
+
+// embedTools is EmbedTools with the placeholders of syntax, also returning the
+// number of tool references expanded. Argument values masked in template are put
+// back into the tool names and arguments of the references they appear in. Tool
+// results are masked like argument values, so directive-like text in them is not
+// expanded; the returned values hold them along with the arguments.
+func (tr *TemplateRenderer) embedTools(syntax *templateSyntax, template string, values maskedArguments) (string, maskedArguments, int, error) {
 	if tr.toolManager == nil {
 		// If no tool manager is set, return template unchanged
 		// This allows the renderer to work without tools support
-		return template, nil
+		return template, values, 0, nil
 	}
 
-	matches := toolPattern.FindAllStringSubmatch(template, -1)
+	matches := syntax.tool.FindAllStringSubmatch(template, -1)
 	result := template
+	toolCount := 0
 
 	for _, match := range matches {
-		if len(match) < 2 {
+		if len(match) < 3 || !strings.Contains(result, match[0]) {
 			continue
 		}
 
-		placeholder := match[0] // Full match like {{tool:validate-against-pattern}}
-		toolName := match[1]    // Tool name
+		placeholder := match[0]             // Full match like {{tool:validate-against-pattern}}
+		toolName := values.unmask(match[1]) // Tool name
 
 		tool, err := tr.toolManager.GetTool(toolName)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve tool reference %s: %w", toolName, err)
+			return "", nil, 0, fmt.Errorf("failed to resolve tool reference %s: %w", toolName, err)
 		}
 
 		expandedContent := tr.buildToolReference(tool)
+		if match[2] != "" {
+			output, err := tr.runTool(tool, match[2], values)
+			if err != nil {
+				return "", nil, 0, err
+			}
+			values = append(values, output)
+			expandedContent = fmt.Sprintf("\x00%d\x00", len(values)-1)
+		}
+
 		result = strings.ReplaceAll(result, placeholder, expandedContent)
+		toolCount++
 	}
 
-	return result, nil
+	return result, values, toolCount, nil
+}
+
+// runTool runs tool with the arguments in query, a URL query string such as
+// decision_description=...&decision_context=..., and returns its result as text.
+// The query is parsed before masked argument values are put back, so a value
+// cannot add arguments of its own.
+func (tr *TemplateRenderer) runTool(tool tools.Tool, query string, values maskedArguments) (string, error) {
+	parsed, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid arguments for tool %s: %w", tool.Name(), err)
+	}
+
+	arguments := make(map[string]interface{}, len(parsed))
+	for name, list := range parsed {
+		if len(list) != 1 {
+			return "", fmt.Errorf("invalid arguments for tool %s: %s given %d times", tool.Name(), name, len(list))
+		}
+		arguments[name] = values.unmask(list[0])
+	}
+
+	output, err := tr.toolManager.ExecuteTool(context.Background(), tool.Name(), arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to run tool %s: %w", tool.Name(), err)
+	}
+
+	formatted, err := tools.FormatResult(tool, output)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Result of %s:\n", tool.Name()))
+	for _, content := range formatted.Content {
+		builder.WriteString(content.Text)
+		builder.WriteString("\n")
+	}
+	return builder.String(), nil
 }
 
 // buildToolReference formats a tool into an expanded reference with description and schema
-func (tr *TemplateRenderer) buildToolReference(tool ToolInterface) string {
+func (tr *TemplateRenderer) buildToolReference(tool tools.ToolInterface) string {
 	var builder strings.Builder
 
 	builder.WriteString(fmt.Sprintf("Tool: %s\n", tool.Name()))
@@ -170,8 +347,14 @@ func (tr *TemplateRenderer) buildToolReference(tool ToolInterface) string {
 
 		// Extract properties from schema
 		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			// Tools declare required fields as []string; decoded schemas carry []interface{}
 			required := make(map[string]bool)
-			if reqList, ok := schema["required"].([]interface{}); ok {
+			switch reqList := schema["required"].(type) {
+			case []string:
+				for _, r := range reqList {
+					required[r] = true
+				}
+			case []interface{}:
 				for _, r := range reqList {
 					if reqStr, ok := r.(string); ok {
 						required[reqStr] = true
@@ -243,13 +426,14 @@ func (tr *TemplateRenderer) formatParameterConstraints(paramMap map[string]inter
 	return ""
 }
 
-// buildEmbeddedContent formats documents into embedded content with size checking
-func (tr *TemplateRenderer) buildEmbeddedContent(documents []*models.Document, currentSize int) (string, int, error) {
+// buildEmbeddedContent formats documents, with the content to embed for each, into
+// embedded content with size checking
+func (tr *TemplateRenderer) buildEmbeddedContent(documents []*models.Document, contents []string, currentSize int) (string, int, error) {
 	var builder strings.Builder
 	totalSize := currentSize
 
 	for i, doc := range documents {
-		content := doc.Content.RawContent
+		content := contents[i]
 		totalSize += len(content)
 
 		if totalSize > MaxTotalContentSize {
@@ -257,28 +441,40 @@ func (tr *TemplateRenderer) buildEmbeddedContent(documents []*models.Document, c
 		}
 
 		if i > 0 {
-			builder.WriteString("\n\n---\n\n")
+			builder.WriteString(tr.embedFormat.Separator)
 		}
 
-		builder.WriteString(fmt.Sprintf("# %s\n", doc.Metadata.Title))
-		builder.WriteString(fmt.Sprintf("Source: %s\n\n", doc.Metadata.Path))
-		builder.WriteString(content)
+		builder.WriteString(tr.embedFormat.wrap(doc, content, tr.tokenEstimator))
 	}
 
 	return builder.String(), totalSize, nil
 }
 
 // ResolveResourcePattern matches a URI pattern against cached documents
-// Supports wildcards like architecture://patterns/* to match multiple documents
-func (tr *TemplateRenderer) ResolveResourcePattern(pattern string) ([]*models.Document, error) {
+// Supports wildcards like architecture://patterns/* to match multiple documents.
+// A query hint such as architecture://patterns/*?q=caching&limit=3 keeps only the
+// matches relevant to the query, most relevant first, up to limit.
+// Patterns outside allowedCategories are rejected; an empty list allows all.
+func (tr *TemplateRenderer) ResolveResourcePattern(pattern string, allowedCategories []string) ([]*models.Document, error) {
 	if !strings.HasPrefix(pattern, "architecture://") {
 		return nil, fmt.Errorf("invalid resource URI scheme: must start with architecture://")
 	}
 
-	category, resourcePath, err := tr.parseResourceURI(pattern)
+	uri, hint, err := splitResourceQuery(pattern)
 	if err != nil {
 		return nil, err
 	}
+	if hint != nil && tr.ranker == nil {
+		return nil, fmt.Errorf("query hints are not available: no document ranker is configured")
+	}
+
+	category, resourcePath, err := tr.parseResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEmbedCategory(category, allowedCategories); err != nil {
+		return nil, err
+	}
 
 	allDocs := tr.cache.GetAllDocuments()
 	matchedDocs := tr.matchDocuments(allDocs, category, resourcePath)
@@ -287,6 +483,16 @@ func (tr *TemplateRenderer) ResolveResourcePattern(pattern string) ([]*models.Do
 		return nil, fmt.Errorf("no resources found matching pattern: %s", pattern)
 	}
 
+	if hint != nil {
+		matchedDocs = tr.ranker.RankDocuments(hint.query, matchedDocs)
+		if len(matchedDocs) == 0 {
+			return nil, fmt.Errorf("no resources relevant to %q found matching pattern: %s", hint.query, uri)
+		}
+		if hint.limit > 0 && len(matchedDocs) > hint.limit {
+			matchedDocs = matchedDocs[:hint.limit]
+		}
+	}
+
 	// Record resource embedding with cache hit (all documents come from cache)
 	if tr.statsRecorder != nil {
 		for range matchedDocs {
@@ -366,9 +572,7 @@ func (tr *TemplateRenderer) exactMatch(docPath, resourcePath, category string) b
 		basePath = filepath.Join(config.ResourcesBasePath, category)
 	}
 
-	expectedPath := filepath.Join(basePath, resourcePath)
-	if !strings.HasSuffix(expectedPath, ".md") {
-		expectedPath += ".md"
-	}
-	return docPath == expectedPath
+	// Documents may use any of the markdown extensions, so compare without them
+	expectedPath := filepath.Join(basePath, config.TrimMarkdownExtension(resourcePath))
+	return config.TrimMarkdownExtension(docPath) == expectedPath
 }
@@ -1,6 +1,7 @@
 package prompts
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+	"mcp-architecture-service/pkg/tools"
 )
 
 func TestRenderTemplate(t *testing.T) {
@@ -213,7 +216,7 @@ func TestEmbedResources(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := renderer.EmbedResources(tt.template)
+			got, err := renderer.EmbedResources(tt.template, nil)
 			if tt.wantErr {
 				if err == nil {
 					t.Error("EmbedResources() expected error, got nil")
@@ -258,7 +261,7 @@ func TestEmbedResourcesSizeLimits(t *testing.T) {
 		renderer := NewTemplateRenderer(cache)
 		template := "{{resource:architecture://patterns/*}}"
 
-		_, err := renderer.EmbedResources(template)
+		_, err := renderer.EmbedResources(template, nil)
 		if err == nil {
 			t.Error("EmbedResources() expected error for resource count limit, got nil")
 		}
@@ -288,7 +291,7 @@ func TestEmbedResourcesSizeLimits(t *testing.T) {
 		renderer := NewTemplateRenderer(cache)
 		template := "{{resource:architecture://patterns/*}}"
 
-		_, err := renderer.EmbedResources(template)
+		_, err := renderer.EmbedResources(template, nil)
 		if err == nil {
 			t.Error("EmbedResources() expected error for content size limit, got nil")
 		}
@@ -336,9 +339,21 @@ func TestResolveResourcePattern(t *testing.T) {
 		},
 	}
 
+	doc4 := &models.Document{
+		Metadata: models.DocumentMetadata{
+			Title:    "Error Handling",
+			Category: "guidelines",
+			Path:     config.GuidelinesPath + "/error-handling.markdown",
+		},
+		Content: models.DocumentContent{
+			RawContent: "Error handling content",
+		},
+	}
+
 	cache.Set(config.GuidelinesPath+"/api-design.md", doc1)
 	cache.Set(config.PatternsPath+"/repository-pattern.md", doc2)
 	cache.Set(config.ADRPath+"/001-microservices-architecture.md", doc3)
+	cache.Set(config.GuidelinesPath+"/error-handling.markdown", doc4)
 
 	renderer := NewTemplateRenderer(cache)
 
@@ -360,6 +375,17 @@ func TestResolveResourcePattern(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "exact match of a .markdown document",
+			pattern:   "architecture://guidelines/error-handling",
+			wantErr:   false,
+			wantCount: 1,
+			validate: func(t *testing.T, docs []*models.Document) {
+				if docs[0].Metadata.Title != "Error Handling" {
+					t.Errorf("Expected 'Error Handling', got '%s'", docs[0].Metadata.Title)
+				}
+			},
+		},
 		{
 			name:      "wildcard all in category",
 			pattern:   "architecture://patterns/*",
@@ -406,7 +432,7 @@ func TestResolveResourcePattern(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			docs, err := renderer.ResolveResourcePattern(tt.pattern)
+			docs, err := renderer.ResolveResourcePattern(tt.pattern, nil)
 			if tt.wantErr {
 				if err == nil {
 					t.Error("ResolveResourcePattern() expected error, got nil")
@@ -467,7 +493,7 @@ Compare against our patterns:
 	}
 
 	// Then embed resources
-	final, err := renderer.EmbedResources(rendered)
+	final, err := renderer.EmbedResources(rendered, nil)
 	if err != nil {
 		t.Fatalf("EmbedResources() error = %v", err)
 	}
@@ -521,12 +547,16 @@ func (m *mockTool) InputSchema() map[string]interface{} {
 	return m.schema
 }
 
+func (m *mockTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	return map[string]interface{}{"tool": m.name}, nil
+}
+
 // Mock tool manager for testing
 type mockToolManager struct {
-	tools map[string]ToolInterface
+	tools map[string]tools.Tool
 }
 
-func (m *mockToolManager) GetTool(name string) (ToolInterface, error) {
+func (m *mockToolManager) GetTool(name string) (tools.Tool, error) {
 	tool, exists := m.tools[name]
 	if !exists {
 		return nil, fmt.Errorf("tool not found: %s", name)
@@ -534,6 +564,14 @@ func (m *mockToolManager) GetTool(name string) (ToolInterface, error) {
 	return tool, nil
 }
 
+func (m *mockToolManager) ExecuteTool(ctx context.Context, name string, arguments map[string]interface{}) (interface{}, error) {
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return nil, err
+	}
+	return tool.Execute(ctx, arguments)
+}
+
 func TestEmbedTools(t *testing.T) {
 	renderer := setupToolRenderer(t)
 
@@ -607,7 +645,7 @@ func setupToolRenderer(t *testing.T) *TemplateRenderer {
 
 func createMockToolManager() *mockToolManager {
 	mockManager := &mockToolManager{
-		tools: make(map[string]ToolInterface),
+		tools: make(map[string]tools.Tool),
 	}
 
 	mockManager.tools["validate-against-pattern"] = createValidateTool()
@@ -659,11 +697,19 @@ func createSearchTool() *mockTool {
 					"enum":        []interface{}{"guidelines", "patterns", "adr", "all"},
 				},
 			},
-			"required": []interface{}{"query"},
+			"required": []interface{}{"query"}, // as decoded from JSON
 		},
 	}
 }
 
+// createDeclaredSearchTool is createSearchTool with required fields declared as
+// []string, as the real tools declare them
+func createDeclaredSearchTool() *mockTool {
+	tool := createSearchTool()
+	tool.schema["required"] = []string{"query"}
+	return tool
+}
+
 func validateSingleToolEmbed(t *testing.T, result string) {
 	t.Helper()
 	checks := []struct {
@@ -692,6 +738,9 @@ func validateEnumConstraint(t *testing.T, result string) {
 	if !strings.Contains(result, "one of: guidelines, patterns, adr, all") {
 		t.Error("Result should contain enum constraint")
 	}
+	if !strings.Contains(result, "query (required)") {
+		t.Error("Result should mark required fields as required")
+	}
 }
 
 func validateNoToolReferences(t *testing.T, result string) {
@@ -748,7 +797,7 @@ func TestCombinedRenderingWithToolsAndResources(t *testing.T) {
 
 	// Set up mock tool manager
 	mockManager := &mockToolManager{
-		tools: make(map[string]ToolInterface),
+		tools: make(map[string]tools.Tool),
 	}
 	mockManager.tools["validate-pattern"] = &mockTool{
 		name:        "validate-pattern",
@@ -790,7 +839,7 @@ Validation tool:
 		t.Fatalf("RenderTemplate() error = %v", err)
 	}
 
-	withResources, err := renderer.EmbedResources(rendered)
+	withResources, err := renderer.EmbedResources(rendered, nil)
 	if err != nil {
 		t.Fatalf("EmbedResources() error = %v", err)
 	}
@@ -817,3 +866,167 @@ Validation tool:
 		t.Error("Final result should contain tool description")
 	}
 }
+
+func TestEmbedTools_RequiredFieldForms(t *testing.T) {
+	for name, tool := range map[string]*mockTool{
+		"decoded []interface{}": createSearchTool(),
+		"declared []string":     createDeclaredSearchTool(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			renderer := NewTemplateRenderer(cache.NewDocumentCache())
+			renderer.SetToolManager(&mockToolManager{tools: map[string]tools.Tool{tool.name: tool}})
+
+			rendered, err := renderer.EmbedTools("{{tool:search-architecture}}")
+			if err != nil {
+				t.Fatalf("EmbedTools() unexpected error = %v", err)
+			}
+			validateEnumConstraint(t, rendered)
+		})
+	}
+}
+
+func TestEmbedToolsSharesToolManagerRegistry(t *testing.T) {
+	toolManager := tools.NewToolManager(logging.NewStructuredLogger("test"))
+	renderer := NewTemplateRenderer(cache.NewDocumentCache())
+	renderer.SetToolManager(toolManager)
+
+	template := "Run this:\n\n{{tool:search-architecture}}"
+	if _, err := renderer.EmbedTools(template); err == nil {
+		t.Fatal("Expected unregistered tool reference to fail")
+	}
+
+	// Registering with the manager makes the tool both embeddable and executable
+	searchTool := createSearchTool()
+	if err := toolManager.RegisterTool(searchTool); err != nil {
+		t.Fatalf("RegisterTool failed: %v", err)
+	}
+
+	rendered, err := renderer.EmbedTools(template)
+	if err != nil {
+		t.Fatalf("EmbedTools failed: %v", err)
+	}
+	validateEnumConstraint(t, rendered)
+
+	result, err := toolManager.ExecuteTool(context.Background(), "search-architecture", map[string]interface{}{"query": "api"})
+	if err != nil {
+		t.Fatalf("ExecuteTool failed: %v", err)
+	}
+	if result.(map[string]interface{})["tool"] != "search-architecture" {
+		t.Errorf("Expected the embedded tool to be the one executed, got %v", result)
+	}
+}
+
+// recordingTool records the arguments it runs with and returns a fixed result
+type recordingTool struct {
+	mockTool
+	result    interface{}
+	arguments map[string]interface{}
+}
+
+func (r *recordingTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	r.arguments = arguments
+	return r.result, nil
+}
+
+func TestRenderPrompt_ToolResultDirective(t *testing.T) {
+	recorder := &recordingTool{
+		mockTool: mockTool{name: "recorder", schema: map[string]interface{}{"type": "object"}},
+		result:   "Conflicts with {{resource:architecture://guidelines/api-design}}",
+	}
+	manager := createMockToolManager()
+	manager.tools["recorder"] = recorder
+
+	pm := NewPromptManager("prompts", newEmbedPolicyCache(t), nil, logging.NewStructuredLogger("test"))
+	pm.SetToolManager(manager)
+	pm.registry["check"] = &PromptDefinition{
+		Name:      "check",
+		Arguments: []ArgumentDefinition{{Name: "topic", Required: true}},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "Topic: {{topic}}\n{{tool:recorder?decision={{topic}}&scope=all%20docs}}"}},
+		},
+	}
+
+	result, err := pm.RenderPrompt("check", map[string]interface{}{"topic": "caching&scope=none"})
+	if err != nil {
+		t.Fatalf("RenderPrompt() unexpected error = %v", err)
+	}
+
+	// Argument values are substituted after the directive's arguments are parsed
+	expected := map[string]interface{}{"decision": "caching&scope=none", "scope": "all docs"}
+	if len(recorder.arguments) != len(expected) {
+		t.Fatalf("Expected tool arguments %v, got %v", expected, recorder.arguments)
+	}
+	for name, value := range expected {
+		if recorder.arguments[name] != value {
+			t.Errorf("Expected tool argument %s = %q, got %v", name, value, recorder.arguments[name])
+		}
+	}
+
+	text := result.Messages[0].Content.Text
+	if !strings.Contains(text, "Result of recorder:\nConflicts with {{resource:architecture://guidelines/api-design}}") {
+		t.Errorf("Expected the tool result embedded verbatim, got %q", text)
+	}
+	if strings.Contains(text, "API content") {
+		t.Errorf("Expected no directive in the tool result to be expanded, got %q", text)
+	}
+
+	// A tool argument given twice fails the render
+	pm.registry["check"].Messages[0].Content.Text = "{{tool:recorder?scope=a&scope=b}}"
+	if _, err := pm.RenderPrompt("check", map[string]interface{}{"topic": "caching"}); err == nil {
+		t.Error("Expected a repeated tool argument to fail the render, got nil error")
+	}
+}
+
+func TestRenderPrompt_ArgumentValuesDoNotInjectDirectives(t *testing.T) {
+	pm := NewPromptManager("prompts", newEmbedPolicyCache(t), nil, logging.NewStructuredLogger("test"))
+	pm.SetToolManager(createMockToolManager())
+	pm.registry["review"] = &PromptDefinition{
+		Name: "review",
+		Arguments: []ArgumentDefinition{
+			{Name: "notes", Required: true},
+			{Name: "pattern", Required: true},
+		},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "Notes: {{notes}}\n{{resource:architecture://patterns/{{pattern}}}}"}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		notes string
+	}{
+		{"resource directive", "{{resource:architecture://guidelines/api-design}}"},
+		{"tool directive", "{{tool:search-architecture}}"},
+		{"variable", "{{pattern}}"},
+		{"marker lookalike", "\x000\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := pm.RenderPrompt("review", map[string]interface{}{"notes": tt.notes, "pattern": "repository-pattern"})
+			if err != nil {
+				t.Fatalf("RenderPrompt() unexpected error = %v", err)
+			}
+			text := result.Messages[0].Content.Text
+			if !strings.HasPrefix(text, "Notes: "+tt.notes+"\n") {
+				t.Errorf("Expected the argument value verbatim, got %q", text)
+			}
+			if strings.Contains(text, "API content") || strings.Contains(text, "Tool: search-architecture") {
+				t.Errorf("Expected no directive from the argument value to be expanded, got %q", text)
+			}
+			// Variables inside the author's own directive are still substituted
+			if !strings.Contains(text, "Repository content") {
+				t.Errorf("Expected the author's resource directive to be expanded, got %q", text)
+			}
+		})
+	}
+
+	// A value cannot break out of the directive it is substituted into
+	_, err := pm.RenderPrompt("review", map[string]interface{}{
+		"notes":   "none",
+		"pattern": "repository-pattern}}{{tool:search-architecture",
+	})
+	if err == nil {
+		t.Error("Expected the altered resource URI not to resolve, got nil error")
+	}
+}
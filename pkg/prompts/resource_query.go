@@ -0,0 +1,81 @@
+package prompts
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// DocumentRanker orders documents by relevance to a query, best first, leaving out
+// documents the query does not match. tools.SearchArchitectureTool implements it.
+type DocumentRanker interface {
+	RankDocuments(query string, documents []*models.Document) []*models.Document
+}
+
+// resourceQuery is the query hint of a {{resource:...}} pattern, such as
+// architecture://patterns/*?q=caching&limit=2, which embeds the documents most
+// relevant to "caching" instead of every match
+type resourceQuery struct {
+	query string
+	limit int // Most documents to embed; 0 embeds every relevant match
+}
+
+// splitResourceQuery separates a resource pattern from its query hint. The hint
+// takes q, the query, and optionally limit. A pattern without a hint, or whose q is
+// blank, as from an empty argument value, has no query; a limit then has nothing to
+// rank by and is rejected.
+func splitResourceQuery(pattern string) (string, *resourceQuery, error) {
+	base, rawQuery, found := strings.Cut(pattern, "?")
+	if !found {
+		return pattern, nil, nil
+	}
+
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid query hint %q: %v", rawQuery, err)
+	}
+
+	hint := &resourceQuery{}
+	for name, values := range params {
+		value := values[len(values)-1]
+		switch name {
+		case "q":
+			hint.query = strings.TrimSpace(value)
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 1 {
+				return "", nil, fmt.Errorf("query hint limit must be a positive integer, got %q", value)
+			}
+			hint.limit = limit
+		default:
+			return "", nil, fmt.Errorf("unknown query hint parameter %q, expected q or limit", name)
+		}
+	}
+
+	if hint.query == "" {
+		if hint.limit > 0 {
+			return "", nil, fmt.Errorf("query hint limit requires a non-blank q")
+		}
+		return base, nil, nil
+	}
+	return base, hint, nil
+}
+
+// unmaskResourcePattern puts masked argument values back into a resource pattern.
+// Values in its query hint are URL-escaped, so a value containing &, % or # stays
+// part of the parameter it was substituted into.
+func (values maskedArguments) unmaskResourcePattern(pattern string) string {
+	base, rawQuery, found := strings.Cut(pattern, "?")
+	if !found || len(values) == 0 {
+		return values.unmask(pattern)
+	}
+
+	escaped := make(maskedArguments, len(values))
+	for i, value := range values {
+		escaped[i] = url.QueryEscape(value)
+	}
+	return values.unmask(base) + "?" + escaped.unmask(rawQuery)
+}
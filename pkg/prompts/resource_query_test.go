@@ -0,0 +1,140 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+	"mcp-architecture-service/pkg/tools"
+)
+
+// newQueryHintRenderer returns a renderer ranking with search-architecture over
+// patterns of which two mention caching, one far more than the other
+func newQueryHintRenderer(t *testing.T) *TemplateRenderer {
+	t.Helper()
+
+	docCache := cache.NewDocumentCache()
+	t.Cleanup(docCache.Close)
+
+	for _, doc := range []struct{ file, content string }{
+		{"circuit-breaker.md", "Stop calling a failing dependency for a while."},
+		{"cache-aside.md", "Check the caching tier first, then load and populate the caching tier."},
+		{"read-model.md", "Serve queries from a denormalized read model, optionally with caching."},
+		{"retry.md", "Retry transient failures with backoff."},
+	} {
+		path := config.PatternsPath + "/" + doc.file
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Title: strings.TrimSuffix(doc.file, ".md"), Category: config.URIPatterns, Path: path},
+			Content:  models.DocumentContent{RawContent: doc.content},
+		})
+	}
+
+	renderer := NewTemplateRenderer(docCache)
+	renderer.SetDocumentRanker(tools.NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test")))
+	return renderer
+}
+
+func TestResolveResourcePattern_QueryHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string // titles, in order; nil for every match in any order
+		wantErr string
+	}{
+		{"ranks relevant documents first", "architecture://patterns/*?q=caching", []string{"cache-aside", "read-model"}, ""},
+		{"limits to the most relevant", "architecture://patterns/*?q=caching&limit=1", []string{"cache-aside"}, ""},
+		{"limit above the relevant count", "architecture://patterns/*?q=caching&limit=10", []string{"cache-aside", "read-model"}, ""},
+		{"multi-word query", "architecture://patterns/*?q=retry+backoff", []string{"retry"}, ""},
+		{"blank query embeds every match", "architecture://patterns/*?q=+", nil, ""},
+		{"nothing relevant", "architecture://patterns/*?q=kubernetes", nil, `no resources relevant to "kubernetes"`},
+		{"invalid limit", "architecture://patterns/*?q=caching&limit=0", nil, "limit must be a positive integer"},
+		{"limit without a query", "architecture://patterns/*?q=+&limit=2", nil, "limit requires a non-blank q"},
+		{"unknown parameter", "architecture://patterns/*?query=caching", nil, `unknown query hint parameter "query"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := newQueryHintRenderer(t)
+
+			docs, err := renderer.ResolveResourcePattern(tt.pattern, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveResourcePattern() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveResourcePattern() error = %v", err)
+			}
+
+			if tt.want == nil {
+				if len(docs) != 4 {
+					t.Errorf("Expected all 4 patterns, got %d", len(docs))
+				}
+				return
+			}
+			var titles []string
+			for _, doc := range docs {
+				titles = append(titles, doc.Metadata.Title)
+			}
+			if strings.Join(titles, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ResolveResourcePattern() = %v, want %v", titles, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbedResources_QueryHintOrder(t *testing.T) {
+	renderer := newQueryHintRenderer(t)
+	if err := renderer.SetEmbedFormat(EmbedFormat{Header: "[{{title}}] ", Separator: "\n"}); err != nil {
+		t.Fatalf("SetEmbedFormat() error = %v", err)
+	}
+
+	got, err := renderer.EmbedResources("{{resource:architecture://patterns/*?q=caching}}", nil)
+	if err != nil {
+		t.Fatalf("EmbedResources() error = %v", err)
+	}
+	want := "[cache-aside] Check the caching tier first, then load and populate the caching tier.\n" +
+		"[read-model] Serve queries from a denormalized read model, optionally with caching."
+	if got != want {
+		t.Errorf("EmbedResources() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderPrompt_QueryHintFromArgument(t *testing.T) {
+	renderer := newQueryHintRenderer(t)
+	pm := NewPromptManager("prompts", renderer.cache, nil, logging.NewStructuredLogger("test"))
+	pm.SetDocumentRanker(renderer.ranker)
+	pm.registry["focus"] = &PromptDefinition{
+		Name:      "focus",
+		Arguments: []ArgumentDefinition{{Name: "topic", Required: true}},
+		Messages: []MessageTemplate{
+			{Role: "user", Content: ContentTemplate{Type: "text", Text: "{{resource:architecture://patterns/*?q={{topic}}&limit=1}}"}},
+		},
+	}
+
+	// Values are escaped into the hint, so &, % and # stay part of the query
+	for _, topic := range []string{"backoff", "backoff&limit=3", "backoff&x=1", "backoff %zz", "backoff#x"} {
+		t.Run(topic, func(t *testing.T) {
+			result, err := pm.RenderPrompt("focus", map[string]interface{}{"topic": topic})
+			if err != nil {
+				t.Fatalf("RenderPrompt() error = %v", err)
+			}
+			if text := result.Messages[0].Content.Text; !strings.Contains(text, "Retry transient failures") || strings.Contains(text, "caching") {
+				t.Errorf("Expected only the retry pattern to be embedded, got %q", text)
+			}
+		})
+	}
+}
+
+func TestResolveResourcePattern_QueryHintWithoutRanker(t *testing.T) {
+	renderer := newQueryHintRenderer(t)
+	renderer.SetDocumentRanker(nil)
+
+	if _, err := renderer.ResolveResourcePattern("architecture://patterns/*?q=caching", nil); err == nil || !strings.Contains(err.Error(), "no document ranker") {
+		t.Errorf("Expected an error without a ranker, got %v", err)
+	}
+}
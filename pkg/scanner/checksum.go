@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// ChecksumAlgorithm selects how DocumentMetadata.Checksum is computed
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumMD5 is the hex MD5 digest of the content, the default
+	ChecksumMD5 ChecksumAlgorithm = "md5"
+	// ChecksumSHA256 is the hex SHA-256 digest of the content
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+	// ChecksumFNV is the hex 64-bit FNV-1a hash of the content; fast, but not
+	// suitable for detecting tampering
+	ChecksumFNV ChecksumAlgorithm = "fnv"
+	// ChecksumNone skips computing checksums, leaving them empty
+	ChecksumNone ChecksumAlgorithm = "none"
+)
+
+// DefaultChecksumAlgorithm is the checksum algorithm used unless configured otherwise
+const DefaultChecksumAlgorithm = ChecksumMD5
+
+// ChecksumAlgorithms returns the names of the supported checksum algorithms
+func ChecksumAlgorithms() []string {
+	return []string{string(ChecksumMD5), string(ChecksumSHA256), string(ChecksumFNV), string(ChecksumNone)}
+}
+
+// ParseChecksumAlgorithm converts a checksum algorithm name, case-insensitively
+func ParseChecksumAlgorithm(name string) (ChecksumAlgorithm, error) {
+	switch algorithm := ChecksumAlgorithm(strings.ToLower(name)); algorithm {
+	case ChecksumMD5, ChecksumSHA256, ChecksumFNV, ChecksumNone:
+		return algorithm, nil
+	default:
+		return "", fmt.Errorf("unknown checksum algorithm %q, expected one of %s", name, strings.Join(ChecksumAlgorithms(), ", "))
+	}
+}
+
+// Checksum returns the checksum of content, or an empty string for ChecksumNone
+func (algorithm ChecksumAlgorithm) Checksum(content []byte) string {
+	switch algorithm {
+	case ChecksumNone:
+		return ""
+	case ChecksumSHA256:
+		return fmt.Sprintf("%x", sha256.Sum256(content))
+	case ChecksumFNV:
+		hash := fnv.New64a()
+		hash.Write(content)
+		return fmt.Sprintf("%016x", hash.Sum64())
+	default:
+		return fmt.Sprintf("%x", md5.Sum(content))
+	}
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import "testing"
+
+func TestChecksumAlgorithms(t *testing.T) {
+	content := []byte("hello")
+
+	tests := []struct {
+		algorithm ChecksumAlgorithm
+		expected  string
+	}{
+		{ChecksumMD5, "5d41402abc4b2a76b9719d911017c592"},
+		{ChecksumSHA256, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{ChecksumFNV, "a430d84680aabd0b"},
+		{ChecksumNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.algorithm), func(t *testing.T) {
+			if got := tt.algorithm.Checksum(content); got != tt.expected {
+				t.Errorf("Checksum() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseChecksumAlgorithm(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  ChecksumAlgorithm
+		expectErr bool
+	}{
+		{"md5", "md5", ChecksumMD5, false},
+		{"sha256", "sha256", ChecksumSHA256, false},
+		{"fnv", "fnv", ChecksumFNV, false},
+		{"none", "none", ChecksumNone, false},
+		{"case insensitive", "SHA256", ChecksumSHA256, false},
+		{"unknown", "crc32", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			algorithm, err := ParseChecksumAlgorithm(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseChecksumAlgorithm(%q) error = %v, expectErr %v", tt.input, err, tt.expectErr)
+			}
+			if algorithm != tt.expected {
+				t.Errorf("ParseChecksumAlgorithm(%q) = %q, want %q", tt.input, algorithm, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetChecksumAlgorithm(t *testing.T) {
+	content := []byte("# Checksums\n\nThe same content under each algorithm.")
+	scanner := NewDocumentationScanner(".")
+
+	if scanner.ChecksumAlgorithm() != DefaultChecksumAlgorithm {
+		t.Errorf("Expected default algorithm %q, got %q", DefaultChecksumAlgorithm, scanner.ChecksumAlgorithm())
+	}
+
+	seen := make(map[string]ChecksumAlgorithm)
+	for _, algorithm := range []ChecksumAlgorithm{ChecksumMD5, ChecksumSHA256, ChecksumFNV} {
+		scanner.SetChecksumAlgorithm(algorithm)
+		metadata, err := scanner.ParseContent("checksums.md", content)
+		if err != nil {
+			t.Fatalf("ParseContent() unexpected error: %v", err)
+		}
+		if metadata.Checksum != algorithm.Checksum(content) {
+			t.Errorf("%s: checksum %q does not match the algorithm", algorithm, metadata.Checksum)
+		}
+		if other, ok := seen[metadata.Checksum]; ok {
+			t.Errorf("%s and %s produced the same checksum", algorithm, other)
+		}
+		seen[metadata.Checksum] = algorithm
+	}
+
+	scanner.SetChecksumAlgorithm(ChecksumNone)
+	metadata, err := scanner.ParseContent("checksums.md", content)
+	if err != nil {
+		t.Fatalf("ParseContent() unexpected error: %v", err)
+	}
+	if metadata.Checksum != "" {
+		t.Errorf("Expected no checksum when disabled, got %q", metadata.Checksum)
+	}
+}
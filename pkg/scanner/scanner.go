@@ -2,17 +2,20 @@ package scanner
 
 import (
 	"bytes"
-	"crypto/md5"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
 
@@ -24,9 +27,42 @@ import (
 
 // DocumentationScanner handles scanning and parsing of documentation files
 type DocumentationScanner struct {
-	rootPath string
-	parser   goldmark.Markdown
-	logger   *logging.StructuredLogger
+	rootPath  string
+	parser    goldmark.Markdown
+	logger    *logging.StructuredLogger
+	mimeTypes map[string]string // file extension -> MIME type; only these files are loaded
+
+	// maxFileSize guards against accidentally committed generated or binary files
+	maxFileSize int64
+
+	// workerCount fixes the number of parse workers; zero sizes the pool automatically
+	workerCount int
+
+	// followSymlinks loads files and directories reached through symbolic links
+	followSymlinks bool
+
+	// categoryExtensions restricts the extensions loaded for a category; categories
+	// without an entry load every extension in mimeTypes
+	categoryExtensions map[string]map[string]bool
+
+	// checksumAlgorithm computes DocumentMetadata.Checksum
+	checksumAlgorithm ChecksumAlgorithm
+}
+
+// DefaultMaxFileSize is the largest documentation file loaded by default (5MB)
+const DefaultMaxFileSize = 5 * 1024 * 1024
+
+// DefaultMimeTypes returns the file extensions loaded by default and their MIME types
+func DefaultMimeTypes() map[string]string {
+	return map[string]string{
+		config.MarkdownExtension: config.MimeTypeMarkdown,
+		".markdown":              config.MimeTypeMarkdown,
+		".mdx":                   config.MimeTypeMarkdown,
+		".txt":                   config.MimeTypePlainText,
+		".json":                  config.MimeTypeJSON,
+		".yaml":                  config.MimeTypeYAML,
+		".yml":                   config.MimeTypeYAML,
+	}
 }
 
 // NewDocumentationScanner creates a new documentation scanner
@@ -36,14 +72,138 @@ func NewDocumentationScanner(rootPath string) *DocumentationScanner {
 	logger := loggingManager.GetLogger("scanner")
 
 	return &DocumentationScanner{
-		rootPath: rootPath,
-		parser:   goldmark.New(goldmark.WithParserOptions(parser.WithAutoHeadingID())),
-		logger:   logger,
+		rootPath:          rootPath,
+		parser:            goldmark.New(goldmark.WithParserOptions(parser.WithAutoHeadingID())),
+		logger:            logger,
+		mimeTypes:         DefaultMimeTypes(),
+		maxFileSize:       DefaultMaxFileSize,
+		checksumAlgorithm: DefaultChecksumAlgorithm,
+	}
+}
+
+// SetChecksumAlgorithm changes how document checksums are computed; ChecksumNone
+// skips computing them. Must be called before scanning starts.
+func (ds *DocumentationScanner) SetChecksumAlgorithm(algorithm ChecksumAlgorithm) {
+	ds.checksumAlgorithm = algorithm
+}
+
+// ChecksumAlgorithm returns the algorithm document checksums are computed with
+func (ds *DocumentationScanner) ChecksumAlgorithm() ChecksumAlgorithm {
+	return ds.checksumAlgorithm
+}
+
+// SetMaxFileSize changes the largest file the scanner loads; larger files are
+// skipped and reported. Zero or less disables the limit.
+func (ds *DocumentationScanner) SetMaxFileSize(bytes int64) {
+	ds.maxFileSize = bytes
+}
+
+// MaxFileSize returns the largest file the scanner loads; zero or less means no limit
+func (ds *DocumentationScanner) MaxFileSize() int64 {
+	return ds.maxFileSize
+}
+
+// SetWorkerCount fixes the number of files parsed concurrently per directory.
+// Zero or less restores automatic sizing based on file count and CPUs.
+func (ds *DocumentationScanner) SetWorkerCount(workers int) {
+	ds.workerCount = workers
+}
+
+// SetFollowSymlinks controls whether symbolic links to files and directories are
+// followed while scanning. Following is off by default; skipped links are reported
+// in the index. Loops through followed directory links are detected and skipped.
+func (ds *DocumentationScanner) SetFollowSymlinks(follow bool) {
+	ds.followSymlinks = follow
+}
+
+// SetCategoryExtensions restricts the file extensions loaded for a category, for
+// example []string{".md", ".markdown"} for guidelines. Extensions without a
+// registered MIME type are loaded as markdown. An empty list removes the
+// restriction. Must be called before scanning starts.
+func (ds *DocumentationScanner) SetCategoryExtensions(category string, extensions []string) {
+	if len(extensions) == 0 {
+		delete(ds.categoryExtensions, category)
+		return
+	}
+
+	allowed := make(map[string]bool, len(extensions))
+	for _, extension := range extensions {
+		allowed[normalizeExtension(extension)] = true
+	}
+
+	if ds.categoryExtensions == nil {
+		ds.categoryExtensions = make(map[string]map[string]bool)
+	}
+	ds.categoryExtensions[category] = allowed
+}
+
+// IsLoadedFile reports whether a file in category has an extension the scanner loads
+func (ds *DocumentationScanner) IsLoadedFile(category, path string) bool {
+	if allowed, ok := ds.categoryExtensions[category]; ok {
+		return allowed[strings.ToLower(filepath.Ext(path))]
+	}
+	return ds.IsSupportedFile(path)
+}
+
+// LoadedExtensions returns the file extensions loaded for category in sorted order:
+// its restricted extensions when SetCategoryExtensions was used, otherwise every
+// extension with a registered MIME type
+func (ds *DocumentationScanner) LoadedExtensions(category string) []string {
+	var extensions []string
+	if allowed, ok := ds.categoryExtensions[category]; ok {
+		for extension := range allowed {
+			extensions = append(extensions, extension)
+		}
+	} else {
+		for extension := range ds.mimeTypes {
+			extensions = append(extensions, extension)
+		}
+	}
+	sort.Strings(extensions)
+	return extensions
+}
+
+// normalizeExtension lowercases an extension and adds the leading dot if missing
+func normalizeExtension(extension string) string {
+	extension = strings.ToLower(extension)
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	return extension
+}
+
+// SetMimeType registers the MIME type for a file extension, or stops loading that
+// extension when mimeType is empty. Must be called before scanning starts.
+func (ds *DocumentationScanner) SetMimeType(extension, mimeType string) {
+	extension = normalizeExtension(extension)
+
+	if mimeType == "" {
+		delete(ds.mimeTypes, extension)
+		return
 	}
+	ds.mimeTypes[extension] = mimeType
+}
+
+// MimeTypeFor returns the MIME type for a file path based on its extension
+func (ds *DocumentationScanner) MimeTypeFor(path string) (string, bool) {
+	mimeType, ok := ds.mimeTypes[strings.ToLower(filepath.Ext(path))]
+	return mimeType, ok
+}
+
+// IsSupportedFile reports whether a file has an extension the scanner loads
+func (ds *DocumentationScanner) IsSupportedFile(path string) bool {
+	_, ok := ds.MimeTypeFor(path)
+	return ok
 }
 
 // ScanDirectory recursively scans a directory for documentation files using concurrent processing
 func (ds *DocumentationScanner) ScanDirectory(path string) (*models.DocumentIndex, error) {
+	return ds.ScanDirectoryContext(context.Background(), path)
+}
+
+// ScanDirectoryContext is ScanDirectory that stops walking and parsing once ctx is
+// done, returning the context's error
+func (ds *DocumentationScanner) ScanDirectoryContext(ctx context.Context, path string) (*models.DocumentIndex, error) {
 	// Validate input path
 	if path == "" {
 		return nil, errors.NewValidationError(errors.ErrCodeInvalidParams,
@@ -60,26 +220,19 @@ func (ds *DocumentationScanner) ScanDirectory(path string) (*models.DocumentInde
 	category := ds.getCategoryFromPath(path)
 
 	// Use concurrent scanning for better performance
-	return ds.scanDirectoryConcurrent(path, category)
+	return ds.scanDirectoryConcurrent(ctx, path, category)
 }
 
 // scanDirectoryConcurrent performs concurrent file scanning for improved performance
-func (ds *DocumentationScanner) scanDirectoryConcurrent(path, category string) (*models.DocumentIndex, error) {
-	// First, collect all markdown files
+func (ds *DocumentationScanner) scanDirectoryConcurrent(ctx context.Context, path, category string) (*models.DocumentIndex, error) {
+	// First, collect all files with a supported extension
 	var markdownFiles []string
-	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Continue processing, errors will be handled during parsing
-		}
-
-		// Skip directories and non-markdown files
-		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
-			return nil
-		}
+	var skipped []models.SkippedFile
+	err := ds.collectFiles(ctx, category, path, path, nil, &markdownFiles, &skipped)
 
-		markdownFiles = append(markdownFiles, filePath)
-		return nil
-	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 
 	if err != nil {
 		return nil, errors.NewFileSystemError(errors.ErrCodeFileSystemUnavailable,
@@ -87,12 +240,19 @@ func (ds *DocumentationScanner) scanDirectoryConcurrent(path, category string) (
 			WithContext("path", path)
 	}
 
+	if len(skipped) > 0 {
+		ds.logger.WithContext("skipped_count", len(skipped)).
+			WithContext("directory", path).
+			Debug("Skipped files during scan")
+	}
+
 	if len(markdownFiles) == 0 {
 		return &models.DocumentIndex{
 			Category:  category,
 			Documents: []models.DocumentMetadata{},
 			Count:     0,
 			Errors:    []string{},
+			Skipped:   skipped,
 		}, nil
 	}
 
@@ -107,7 +267,7 @@ func (ds *DocumentationScanner) scanDirectoryConcurrent(path, category string) (
 	var wg sync.WaitGroup
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
-		go ds.parseWorker(&wg, fileChan, resultChan, category)
+		go ds.parseWorker(ctx, &wg, fileChan, resultChan, category)
 	}
 
 	// Send files to workers
@@ -134,6 +294,10 @@ func (ds *DocumentationScanner) scanDirectoryConcurrent(path, category string) (
 		}
 	}
 
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
 	// Log parse errors if any occurred
 	if len(parseErrors) > 0 {
 		ds.logger.WithContext("error_count", len(parseErrors)).
@@ -146,9 +310,134 @@ func (ds *DocumentationScanner) scanDirectoryConcurrent(path, category string) (
 		Documents: documents,
 		Count:     len(documents),
 		Errors:    parseErrors,
+		Skipped:   skipped,
 	}, nil
 }
 
+// collectFiles walks root for files with a supported extension. Paths are reported
+// under displayRoot so files reached through a followed symlink keep the link's
+// location. chain holds the real paths of the directories being walked and is used
+// to detect symlink loops.
+func (ds *DocumentationScanner) collectFiles(ctx context.Context, category, root, displayRoot string, chain []string, files *[]string, skipped *[]models.SkippedFile) error {
+	realRoot, err := realPath(root)
+	if err != nil {
+		realRoot = root
+	}
+	chain = append(append([]string{}, chain...), realRoot)
+
+	return filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if err != nil {
+			return nil // Continue processing, errors will be handled during parsing
+		}
+
+		displayPath := filePath
+		if displayRoot != root {
+			displayPath = displayRoot + strings.TrimPrefix(filePath, root)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return ds.collectSymlink(ctx, category, filePath, displayPath, chain, files, skipped)
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		ds.collectFile(category, displayPath, info, files, skipped)
+		return nil
+	})
+}
+
+// collectFile adds a file to files, or to skipped when its extension is not
+// supported or it exceeds the size limit
+func (ds *DocumentationScanner) collectFile(category, filePath string, info os.FileInfo, files *[]string, skipped *[]models.SkippedFile) {
+	if !ds.IsLoadedFile(category, filePath) {
+		*skipped = append(*skipped, models.SkippedFile{Path: filePath, Reason: "unsupported file extension"})
+		return
+	}
+
+	if ds.maxFileSize > 0 && info.Size() > ds.maxFileSize {
+		*skipped = append(*skipped, models.SkippedFile{
+			Path:   filePath,
+			Reason: fmt.Sprintf("file size %d bytes exceeds limit of %d bytes", info.Size(), ds.maxFileSize),
+		})
+		return
+	}
+
+	*files = append(*files, filePath)
+}
+
+// collectSymlink follows a symbolic link found while walking when following is
+// enabled, and otherwise records it as skipped
+func (ds *DocumentationScanner) collectSymlink(ctx context.Context, category, linkPath, displayPath string, chain []string, files *[]string, skipped *[]models.SkippedFile) error {
+	if !ds.followSymlinks {
+		*skipped = append(*skipped, models.SkippedFile{Path: displayPath, Reason: "symbolic link (following disabled)"})
+		ds.logger.WithContext("path", displayPath).
+			Info("Skipped symbolic link; following symlinks is disabled")
+		return nil
+	}
+
+	target, err := realPath(linkPath)
+	if err != nil {
+		*skipped = append(*skipped, models.SkippedFile{Path: displayPath, Reason: "unresolvable symbolic link"})
+		return nil
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		*skipped = append(*skipped, models.SkippedFile{Path: displayPath, Reason: "unresolvable symbolic link"})
+		return nil
+	}
+
+	if !info.IsDir() {
+		ds.collectFile(category, displayPath, info, files, skipped)
+		return nil
+	}
+
+	// A directory link loops when it points at a directory already being walked
+	// or at one of their ancestors
+	if linkDir, err := realPath(filepath.Dir(linkPath)); err == nil {
+		chain = append(append([]string{}, chain...), linkDir)
+	}
+	for _, dir := range chain {
+		if IsWithinDir(dir, target) {
+			*skipped = append(*skipped, models.SkippedFile{
+				Path:   displayPath,
+				Reason: fmt.Sprintf("symbolic link loop to %s", target),
+			})
+			ds.logger.WithContext("path", displayPath).
+				WithContext("target", target).
+				Warn("Symbolic link loop detected")
+			return nil
+		}
+	}
+
+	return ds.collectFiles(ctx, category, target, displayPath, chain, files, skipped)
+}
+
+// realPath resolves symbolic links in path and makes it absolute
+func realPath(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolved)
+}
+
+// IsWithinDir reports whether path is dir or one of its descendants. Both paths are
+// compared as given, so callers resolve symbolic links first where they matter.
+func IsWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
 // parseResult holds the result of parsing a single file
 type parseResult struct {
 	filePath string
@@ -156,11 +445,16 @@ type parseResult struct {
 	err      error
 }
 
-// parseWorker processes files from the work channel
-func (ds *DocumentationScanner) parseWorker(wg *sync.WaitGroup, fileChan <-chan string, resultChan chan<- parseResult, category string) {
+// parseWorker processes files from the work channel, draining it without parsing
+// once ctx is done
+func (ds *DocumentationScanner) parseWorker(ctx context.Context, wg *sync.WaitGroup, fileChan <-chan string, resultChan chan<- parseResult, category string) {
 	defer wg.Done()
 
 	for filePath := range fileChan {
+		if ctx.Err() != nil {
+			continue
+		}
+
 		metadata, err := ds.ParseMarkdownFile(filePath)
 
 		result := parseResult{
@@ -179,6 +473,10 @@ func (ds *DocumentationScanner) parseWorker(wg *sync.WaitGroup, fileChan <-chan
 
 // calculateOptimalWorkerCount determines the optimal number of workers based on file count and system resources
 func (ds *DocumentationScanner) calculateOptimalWorkerCount(fileCount int) int {
+	if ds.workerCount > 0 {
+		return min(ds.workerCount, fileCount)
+	}
+
 	// Get number of CPU cores
 	numCPU := runtime.NumCPU()
 
@@ -204,7 +502,8 @@ func min(a, b int) int {
 	return b
 }
 
-// ParseMarkdownFile parses a markdown file and extracts metadata using goldmark
+// ParseMarkdownFile parses a documentation file and extracts metadata. Markdown is
+// parsed with goldmark; other supported text types only get file system metadata.
 func (ds *DocumentationScanner) ParseMarkdownFile(filePath string) (*models.DocumentMetadata, error) {
 	// Validate file path
 	if filePath == "" {
@@ -237,12 +536,36 @@ func (ds *DocumentationScanner) ParseMarkdownFile(filePath string) (*models.Docu
 			"Failed to read file", err).WithContext("path", filePath)
 	}
 
+	metadata, err := ds.ParseContent(filePath, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set file system metadata
+	metadata.LastModified = info.ModTime()
+	metadata.Size = info.Size()
+
+	return metadata, nil
+}
+
+// ParseContent extracts the metadata of a documentation file's content, for
+// documents that do not come from disk. It validates the content like
+// ParseMarkdownFile; LastModified is left for the caller to set.
+func (ds *DocumentationScanner) ParseContent(filePath string, content []byte) (*models.DocumentMetadata, error) {
 	// Validate that content is not empty
 	if len(content) == 0 {
 		return nil, errors.NewParsingError(errors.ErrCodeMalformedMarkdown,
 			"File is empty", nil).WithContext("path", filePath)
 	}
 
+	// Reject binary and non-UTF-8 content before it reaches the cache
+	if err := ValidateTextContent(content); err != nil {
+		ds.logger.WithContext("path", filePath).
+			WithError(err).
+			Warn("Skipping file with binary or non-UTF-8 content")
+		return nil, err
+	}
+
 	// Validate that content appears to be valid text
 	if !ds.isValidMarkdown(content) {
 		return nil, errors.NewParsingError(errors.ErrCodeEncodingIssue,
@@ -251,13 +574,22 @@ func (ds *DocumentationScanner) ParseMarkdownFile(filePath string) (*models.Docu
 	}
 
 	// Calculate checksum
-	checksum := fmt.Sprintf("%x", md5.Sum(content))
+	checksum := ds.checksumAlgorithm.Checksum(content)
+
+	mimeType, ok := ds.MimeTypeFor(filePath)
+	if !ok {
+		mimeType = config.MimeTypeMarkdown
+	}
 
 	// Parse markdown using goldmark to extract structured metadata
-	metadata, err := ds.ExtractMetadata(string(content))
-	if err != nil {
-		return nil, errors.NewParsingError(errors.ErrCodeInvalidMetadata,
-			"Failed to extract metadata", err).WithContext("path", filePath)
+	metadata := &models.DocumentMetadata{}
+	if mimeType == config.MimeTypeMarkdown {
+		extracted, err := ds.ExtractMetadata(string(content))
+		if err != nil {
+			return nil, errors.NewParsingError(errors.ErrCodeInvalidMetadata,
+				"Failed to extract metadata", err).WithContext("path", filePath)
+		}
+		metadata = extracted
 	}
 
 	// Get relative path from root
@@ -268,18 +600,39 @@ func (ds *DocumentationScanner) ParseMarkdownFile(filePath string) (*models.Docu
 
 	// Use filename as fallback title if no title found
 	if metadata.Title == "" {
-		metadata.Title = strings.TrimSuffix(filepath.Base(filePath), ".md")
+		metadata.Title = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
 	}
 
-	// Set file system metadata
+	metadata.Status = ExtractStatus(string(content))
+
 	metadata.Path = relPath
-	metadata.LastModified = info.ModTime()
-	metadata.Size = info.Size()
+	metadata.Size = int64(len(content))
 	metadata.Checksum = checksum
+	metadata.MimeType = mimeType
 
 	return metadata, nil
 }
 
+// statusPatterns match a document status declared in front matter ("status: draft"),
+// a "## Status" section, or a bold "**Status**:" line. Each must start a line, so
+// prose such as "HTTP status: 404" is not taken for a status.
+var statusPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?im)^status:[ \t]*(\w+)`),
+	regexp.MustCompile(`(?im)^## status\s+(\w+)`),
+	regexp.MustCompile(`(?im)^\*\*status\*\*:[ \t]*(\w+)`),
+}
+
+// ExtractStatus returns the lowercased status declared in a document, such as
+// "draft" or "accepted", or an empty string when none is declared
+func ExtractStatus(content string) string {
+	for _, re := range statusPatterns {
+		if matches := re.FindStringSubmatch(content); len(matches) > 1 {
+			return strings.ToLower(matches[1])
+		}
+	}
+	return ""
+}
+
 // ExtractMetadata extracts structured metadata from markdown content using goldmark
 func (ds *DocumentationScanner) ExtractMetadata(content string) (*models.DocumentMetadata, error) {
 	// Parse the markdown document
@@ -327,6 +680,22 @@ func (ds *DocumentationScanner) extractTextFromNode(node ast.Node, source []byte
 	return buf.String()
 }
 
+// ValidateTextContent returns an encoding error when content holds binary data or
+// is not valid UTF-8, so such files are skipped rather than cached as garbage text
+func ValidateTextContent(content []byte) error {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return errors.NewParsingError(errors.ErrCodeEncodingIssue,
+			"File contains binary data", nil)
+	}
+
+	if !utf8.Valid(content) {
+		return errors.NewParsingError(errors.ErrCodeEncodingIssue,
+			"File is not valid UTF-8 text", nil)
+	}
+
+	return nil
+}
+
 // isValidMarkdown performs basic validation to check if content appears to be markdown
 func (ds *DocumentationScanner) isValidMarkdown(content []byte) bool {
 	// Empty content is considered valid
@@ -375,17 +744,23 @@ func (ds *DocumentationScanner) isValidMarkdown(content []byte) bool {
 
 // BuildIndex scans multiple directories concurrently and builds a comprehensive index
 func (ds *DocumentationScanner) BuildIndex(directories []string) (map[string]*models.DocumentIndex, error) {
+	return ds.BuildIndexContext(context.Background(), directories)
+}
+
+// BuildIndexContext is BuildIndex that stops scanning once ctx is done, returning
+// the context's error
+func (ds *DocumentationScanner) BuildIndexContext(ctx context.Context, directories []string) (map[string]*models.DocumentIndex, error) {
 	if len(directories) == 0 {
 		return nil, errors.NewValidationError(errors.ErrCodeInvalidParams,
 			"No directories provided for indexing", nil)
 	}
 
 	// Use concurrent scanning for multiple directories
-	return ds.buildIndexConcurrent(directories)
+	return ds.buildIndexConcurrent(ctx, directories)
 }
 
 // buildIndexConcurrent processes multiple directories concurrently for faster indexing
-func (ds *DocumentationScanner) buildIndexConcurrent(directories []string) (map[string]*models.DocumentIndex, error) {
+func (ds *DocumentationScanner) buildIndexConcurrent(ctx context.Context, directories []string) (map[string]*models.DocumentIndex, error) {
 	type indexResult struct {
 		index *models.DocumentIndex
 		err   error
@@ -401,7 +776,7 @@ func (ds *DocumentationScanner) buildIndexConcurrent(directories []string) (map[
 		go func(directory string) {
 			defer wg.Done()
 
-			index, err := ds.ScanDirectory(directory)
+			index, err := ds.ScanDirectoryContext(ctx, directory)
 			resultChan <- indexResult{
 				index: index,
 				err:   err,
@@ -433,6 +808,12 @@ func (ds *DocumentationScanner) buildIndexConcurrent(directories []string) (map[
 		indexes[result.index.Category] = result.index
 	}
 
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		ds.logger.WithContext("directory_count", len(directories)).
+			Warn("Indexing cancelled")
+		return nil, ctxErr
+	}
+
 	// Log overall indexing results
 	totalDocs := 0
 	for category, index := range indexes {
@@ -1,10 +1,13 @@
 package scanner
 
 import (
+	"context"
+	"fmt"
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/config"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -335,6 +338,29 @@ func TestParseMarkdownFileIntegration(t *testing.T) {
 	}
 }
 
+func TestParseContent(t *testing.T) {
+	scanner := NewDocumentationScanner(".")
+
+	content := []byte("# In Memory\n\nContent that never touched the disk.")
+	metadata, err := scanner.ParseContent("mcp/resources/patterns/in-memory.md", content)
+	if err != nil {
+		t.Fatalf("ParseContent() unexpected error: %v", err)
+	}
+	if metadata.Title != "In Memory" {
+		t.Errorf("Expected title 'In Memory', got '%s'", metadata.Title)
+	}
+	if metadata.Path != "mcp/resources/patterns/in-memory.md" {
+		t.Errorf("Expected path to be kept, got '%s'", metadata.Path)
+	}
+	if metadata.Size != int64(len(content)) || metadata.Checksum == "" {
+		t.Errorf("Expected size and checksum from the content, got %d and '%s'", metadata.Size, metadata.Checksum)
+	}
+
+	if _, err := scanner.ParseContent("mcp/resources/patterns/empty.md", nil); err == nil {
+		t.Error("Expected an error for empty content")
+	}
+}
+
 func TestBuildIndexErrors(t *testing.T) {
 	scanner := NewDocumentationScanner("/test")
 
@@ -407,3 +433,433 @@ func TestScanDirectoryWithMalformedFiles(t *testing.T) {
 		t.Error("Expected some parsing errors for malformed files")
 	}
 }
+
+func TestValidateTextContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr string
+	}{
+		{"ascii markdown", []byte("# Title\n\nBody."), ""},
+		{"utf-8 with accents and emoji", []byte("# Café ☕\n\nNaïve résumé."), ""},
+		{"utf-8 byte order mark", []byte("\xEF\xBB\xBF# Title"), ""},
+		{"latin-1 encoded", []byte("# Caf\xE9\n\nBody."), "not valid UTF-8"},
+		{"png header", []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n', 0x00, 0x00}, "binary data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTextContent(tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateTextContent() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateTextContent() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScanDirectorySkipsNonUTF8Files(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"valid.md":  []byte("# Valid\n\nContent here."),
+		"latin1.md": []byte("# Caf\xE9 Guidelines\n\nStill markdown, wrong encoding."),
+	}
+	for filename, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, filename), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", filename, err)
+		}
+	}
+
+	index, err := NewDocumentationScanner(tempDir).ScanDirectory(tempDir)
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+
+	if index.Count != 1 || index.Documents[0].Title != "Valid" {
+		t.Errorf("Expected only valid.md to be indexed, got %+v", index.Documents)
+	}
+	if len(index.Errors) != 1 || !strings.Contains(index.Errors[0], "latin1.md") {
+		t.Errorf("Expected an encoding error for latin1.md, got %v", index.Errors)
+	}
+}
+
+func TestMimeTypeFor(t *testing.T) {
+	scanner := NewDocumentationScanner("/test")
+
+	tests := []struct {
+		path          string
+		expectedMime  string
+		expectedFound bool
+	}{
+		{"guidelines/api.md", config.MimeTypeMarkdown, true},
+		{"guidelines/notes.txt", config.MimeTypePlainText, true},
+		{"patterns/catalog.json", config.MimeTypeJSON, true},
+		{"patterns/CATALOG.JSON", config.MimeTypeJSON, true},
+		{"adr/rules.yaml", config.MimeTypeYAML, true},
+		{"adr/rules.yml", config.MimeTypeYAML, true},
+		{"patterns/diagram.png", "", false},
+		{"patterns/README", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			mimeType, found := scanner.MimeTypeFor(test.path)
+			if mimeType != test.expectedMime || found != test.expectedFound {
+				t.Errorf("MimeTypeFor(%q) = (%q, %v), want (%q, %v)",
+					test.path, mimeType, found, test.expectedMime, test.expectedFound)
+			}
+		})
+	}
+}
+
+func TestSetMimeType(t *testing.T) {
+	scanner := NewDocumentationScanner("/test")
+
+	scanner.SetMimeType("ADOC", "text/asciidoc")
+	if mimeType, _ := scanner.MimeTypeFor("guide.adoc"); mimeType != "text/asciidoc" {
+		t.Errorf("Expected registered extension without dot to be normalized, got %q", mimeType)
+	}
+
+	scanner.SetMimeType(".txt", "")
+	if scanner.IsSupportedFile("notes.txt") {
+		t.Error("Expected empty MIME type to stop loading .txt files")
+	}
+}
+
+func TestScanDirectoryDetectsMimeTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	patternsDir := filepath.Join(tempDir, "patterns")
+	if err := os.MkdirAll(patternsDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	files := map[string]string{
+		"repository.md":    "# Repository Pattern\n\nEncapsulates data access.",
+		"catalog.json":     `{"patterns": ["repository", "outbox"]}`,
+		"diagram.png":      "not loaded",
+		"deprecations.txt": "The singleton pattern is deprecated.",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(patternsDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	scanner := NewDocumentationScanner(tempDir)
+	index, err := scanner.ScanDirectory(patternsDir)
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+
+	expected := map[string]struct{ mimeType, title string }{
+		"repository.md":    {config.MimeTypeMarkdown, "Repository Pattern"},
+		"catalog.json":     {config.MimeTypeJSON, "catalog"},
+		"deprecations.txt": {config.MimeTypePlainText, "deprecations"},
+	}
+	if index.Count != len(expected) {
+		t.Fatalf("Expected %d documents, got %d", len(expected), index.Count)
+	}
+	for _, doc := range index.Documents {
+		want, ok := expected[filepath.Base(doc.Path)]
+		if !ok {
+			t.Errorf("Unexpected document %s", doc.Path)
+			continue
+		}
+		if doc.MimeType != want.mimeType {
+			t.Errorf("%s: expected MIME type %q, got %q", doc.Path, want.mimeType, doc.MimeType)
+		}
+		if doc.Title != want.title {
+			t.Errorf("%s: expected title %q, got %q", doc.Path, want.title, doc.Title)
+		}
+	}
+}
+
+func TestScanDirectorySkipsOversizeFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"small.md":  "# Small\n\nFits.",
+		"large.md":  "# Large\n\n" + strings.Repeat("content ", 64),
+		"notes.doc": "unsupported",
+	}
+	for filename, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", filename, err)
+		}
+	}
+
+	scanner := NewDocumentationScanner(tempDir)
+	scanner.SetMaxFileSize(128)
+
+	index, err := scanner.ScanDirectory(tempDir)
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+
+	if index.Count != 1 || index.Documents[0].Title != "Small" {
+		t.Errorf("Expected only small.md to be indexed, got %+v", index.Documents)
+	}
+
+	reasons := make(map[string]string)
+	for _, skipped := range index.Skipped {
+		reasons[filepath.Base(skipped.Path)] = skipped.Reason
+	}
+	if !strings.Contains(reasons["large.md"], "exceeds limit of 128 bytes") {
+		t.Errorf("Expected large.md skipped for size, got reason %q", reasons["large.md"])
+	}
+	if reasons["notes.doc"] != "unsupported file extension" {
+		t.Errorf("Expected notes.doc skipped for extension, got reason %q", reasons["notes.doc"])
+	}
+}
+
+func TestScanWithCancelledContext(t *testing.T) {
+	tempDir := t.TempDir()
+	guidelinesDir := filepath.Join(tempDir, config.GuidelinesPath)
+	if err := os.MkdirAll(guidelinesDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		path := filepath.Join(guidelinesDir, fmt.Sprintf("doc-%d.md", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("# Doc %d\n\nContent.", i)), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	scanner := NewDocumentationScanner(tempDir)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := scanner.ScanDirectoryContext(ctx, guidelinesDir); err != context.Canceled {
+		t.Errorf("ScanDirectoryContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := scanner.BuildIndexContext(ctx, []string{guidelinesDir}); err != context.Canceled {
+		t.Errorf("BuildIndexContext() error = %v, want context.Canceled", err)
+	}
+
+	// The same scan completes with a live context
+	index, err := scanner.ScanDirectoryContext(context.Background(), guidelinesDir)
+	if err != nil {
+		t.Fatalf("ScanDirectoryContext() failed: %v", err)
+	}
+	if index.Count != 20 {
+		t.Errorf("Expected 20 documents, got %d", index.Count)
+	}
+}
+
+func TestScanDirectorySymlinks(t *testing.T) {
+	tempDir := t.TempDir()
+	docsDir := filepath.Join(tempDir, "docs")
+	sharedDir := filepath.Join(tempDir, "shared")
+	for _, dir := range []string{docsDir, sharedDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	files := map[string]string{
+		filepath.Join(docsDir, "local.md"):     "# Local\n\nOwned here.",
+		filepath.Join(tempDir, "security.md"):  "# Security\n\nShared file.",
+		filepath.Join(sharedDir, "logging.md"): "# Logging\n\nShared directory.",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	links := map[string]string{
+		filepath.Join(docsDir, "security.md"): filepath.Join(tempDir, "security.md"),
+		filepath.Join(docsDir, "shared"):      sharedDir,
+		filepath.Join(sharedDir, "back"):      docsDir, // docs/shared/back -> docs loops
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+
+	titles := func(index *models.DocumentIndex) map[string]string {
+		result := make(map[string]string)
+		for _, doc := range index.Documents {
+			result[doc.Title] = doc.Path
+		}
+		return result
+	}
+	reasons := func(index *models.DocumentIndex) map[string]string {
+		result := make(map[string]string)
+		for _, skipped := range index.Skipped {
+			rel, _ := filepath.Rel(docsDir, skipped.Path)
+			result[filepath.ToSlash(rel)] = skipped.Reason
+		}
+		return result
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		index, err := NewDocumentationScanner(tempDir).ScanDirectory(docsDir)
+		if err != nil {
+			t.Fatalf("ScanDirectory failed: %v", err)
+		}
+
+		if got := titles(index); len(got) != 1 || got["Local"] == "" {
+			t.Errorf("Expected only the local document, got %v", got)
+		}
+		skipped := reasons(index)
+		for _, link := range []string{"security.md", "shared"} {
+			if skipped[link] != "symbolic link (following disabled)" {
+				t.Errorf("Expected %s skipped as a symlink, got %q", link, skipped[link])
+			}
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		scanner := NewDocumentationScanner(tempDir)
+		scanner.SetFollowSymlinks(true)
+
+		index, err := scanner.ScanDirectory(docsDir)
+		if err != nil {
+			t.Fatalf("ScanDirectory failed: %v", err)
+		}
+
+		got := titles(index)
+		// Paths are relative to the scanner root and keep the link's location
+		expected := map[string]string{
+			"Local":    filepath.Join("docs", "local.md"),
+			"Security": filepath.Join("docs", "security.md"),
+			"Logging":  filepath.Join("docs", "shared", "logging.md"),
+		}
+		if len(got) != len(expected) {
+			t.Errorf("Expected %d documents, got %v", len(expected), got)
+		}
+		for title, path := range expected {
+			if got[title] != path {
+				t.Errorf("Expected %s at %s, got %q", title, path, got[title])
+			}
+		}
+
+		if reason := reasons(index)["shared/back"]; !strings.HasPrefix(reason, "symbolic link loop") {
+			t.Errorf("Expected shared/back reported as a loop, got %q", reason)
+		}
+	})
+}
+
+func TestSetCategoryExtensions(t *testing.T) {
+	tempDir := t.TempDir()
+	guidelinesDir := filepath.Join(tempDir, config.GuidelinesPath)
+	if err := os.MkdirAll(guidelinesDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	testFiles := map[string]string{
+		"api-design.md":       "# API Design\n\nContent.",
+		"deployment.markdown": "# Deployment\n\nContent.",
+		"components.mdx":      "# Components\n\nContent.",
+		"notes.txt":           "Plain notes.",
+	}
+	for filename, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(guidelinesDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", filename, err)
+		}
+	}
+
+	loaded := func(scanner *DocumentationScanner) map[string]models.DocumentMetadata {
+		index, err := scanner.ScanDirectory(guidelinesDir)
+		if err != nil {
+			t.Fatalf("ScanDirectory failed: %v", err)
+		}
+		result := make(map[string]models.DocumentMetadata)
+		for _, doc := range index.Documents {
+			result[filepath.Base(doc.Path)] = doc
+		}
+		return result
+	}
+
+	t.Run("defaults include markdown variants", func(t *testing.T) {
+		docs := loaded(NewDocumentationScanner(tempDir))
+		if len(docs) != 4 {
+			t.Errorf("Expected 4 documents, got %d", len(docs))
+		}
+		doc := docs["deployment.markdown"]
+		if doc.Title != "Deployment" || doc.MimeType != config.MimeTypeMarkdown {
+			t.Errorf("Expected .markdown parsed as markdown, got %+v", doc)
+		}
+	})
+
+	t.Run("restricted per category", func(t *testing.T) {
+		scanner := NewDocumentationScanner(tempDir)
+		scanner.SetCategoryExtensions(config.CategoryGuideline, []string{".md", "MARKDOWN"})
+
+		docs := loaded(scanner)
+		if len(docs) != 2 || docs["api-design.md"].Title == "" || docs["deployment.markdown"].Title == "" {
+			t.Errorf("Expected only .md and .markdown documents, got %v", docs)
+		}
+
+		// Other categories keep the defaults
+		if !scanner.IsLoadedFile(config.CategoryPattern, "patterns/catalog.mdx") {
+			t.Error("Expected the pattern category to be unaffected")
+		}
+
+		scanner.SetCategoryExtensions(config.CategoryGuideline, nil)
+		if docs := loaded(scanner); len(docs) != 4 {
+			t.Errorf("Expected clearing the restriction to load 4 documents, got %d", len(docs))
+		}
+	})
+}
+
+func TestExtractStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"front matter", "---\nstatus: Draft\n---\n# Title", "draft"},
+		{"status section", "# ADR-001\n\n## Status\nAccepted\n\n## Context", "accepted"},
+		{"bold status line", "# Guideline\n\n**Status**: Approved", "approved"},
+		{"no status", "# Guideline\n\nBody only.", ""},
+		{"status in prose", "# Errors\n\nReturn HTTP status: 404 when the resource is missing.", ""},
+		{"status in prose before section", "# ADR-002\n\nThe response status: 500\n\n## Status\nProposed", "proposed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractStatus(tt.content); got != tt.expected {
+				t.Errorf("ExtractStatus() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetWorkerCount(t *testing.T) {
+	scanner := NewDocumentationScanner("/test")
+
+	tests := []struct {
+		name      string
+		workers   int
+		fileCount int
+		expected  int
+	}{
+		{"fixed count", 3, 100, 3},
+		{"capped by file count", 8, 2, 2},
+		{"single worker", 1, 500, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner.SetWorkerCount(tt.workers)
+			if got := scanner.calculateOptimalWorkerCount(tt.fileCount); got != tt.expected {
+				t.Errorf("calculateOptimalWorkerCount(%d) with %d workers = %d, want %d",
+					tt.fileCount, tt.workers, got, tt.expected)
+			}
+		})
+	}
+
+	scanner.SetWorkerCount(0)
+	if got := scanner.calculateOptimalWorkerCount(5); got < 1 || got > 2 {
+		t.Errorf("Expected automatic sizing after reset, got %d workers", got)
+	}
+}
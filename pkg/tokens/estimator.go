@@ -0,0 +1,43 @@
+// Package tokens estimates how much of a model's context window text takes up
+package tokens
+
+import "unicode/utf8"
+
+// Estimator estimates how many tokens a model would split text into. Estimates
+// must not decrease as text grows, so they can be summed to budget context.
+type Estimator interface {
+	Estimate(text string) int
+}
+
+// EstimatorFunc adapts a function, such as a wrapper around a real tokenizer, to
+// the Estimator interface
+type EstimatorFunc func(text string) int
+
+// Estimate calls f(text)
+func (f EstimatorFunc) Estimate(text string) int {
+	return f(text)
+}
+
+// DefaultCharsPerToken is the characters per token of DefaultEstimator, a common
+// rule of thumb for English prose
+const DefaultCharsPerToken = 4
+
+// CharEstimator estimates one token per CharsPerToken characters, rounding up so
+// any non-empty text counts as at least one token
+type CharEstimator struct {
+	CharsPerToken int
+}
+
+// Estimate returns the estimated token count of text. Characters are runes, so
+// multi-byte text is not overestimated.
+func (c CharEstimator) Estimate(text string) int {
+	perToken := c.CharsPerToken
+	if perToken < 1 {
+		perToken = DefaultCharsPerToken
+	}
+	chars := utf8.RuneCountInString(text)
+	return (chars + perToken - 1) / perToken
+}
+
+// DefaultEstimator is the estimator used unless another is configured
+var DefaultEstimator Estimator = CharEstimator{CharsPerToken: DefaultCharsPerToken}
@@ -0,0 +1,51 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCharEstimator_Estimate(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimator CharEstimator
+		text      string
+		want      int
+	}{
+		{"empty text", CharEstimator{CharsPerToken: 4}, "", 0},
+		{"partial token rounds up", CharEstimator{CharsPerToken: 4}, "abc", 1},
+		{"exact tokens", CharEstimator{CharsPerToken: 4}, "abcdefgh", 2},
+		{"multi-byte characters count once", CharEstimator{CharsPerToken: 4}, "héllo wörld", 3},
+		{"custom ratio", CharEstimator{CharsPerToken: 3}, "abcdefg", 3},
+		{"unset ratio uses the default", CharEstimator{}, "abcdefgh", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.estimator.Estimate(tt.text); got != tt.want {
+				t.Errorf("Estimate(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultEstimator_MonotonicWithLength(t *testing.T) {
+	previous := 0
+	for length := 0; length <= 200; length++ {
+		estimate := DefaultEstimator.Estimate(strings.Repeat("a", length))
+		if estimate < previous {
+			t.Fatalf("Estimate dropped from %d to %d at %d characters", previous, estimate, length)
+		}
+		previous = estimate
+	}
+	if previous == 0 {
+		t.Error("Expected a positive estimate for non-empty text")
+	}
+}
+
+func TestEstimatorFunc(t *testing.T) {
+	words := EstimatorFunc(func(text string) int { return len(strings.Fields(text)) })
+	if got := words.Estimate("three short words"); got != 3 {
+		t.Errorf("Estimate() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditRecord describes one tool invocation in the audit log
+type AuditRecord struct {
+	Timestamp  time.Time              `json:"timestamp"`
+	Tool       string                 `json:"tool"`
+	SessionID  string                 `json:"session_id,omitempty"`
+	Arguments  map[string]interface{} `json:"arguments"` // sanitized like execution logs
+	DurationMs int64                  `json:"duration_ms"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// AuditLogger writes one JSON AuditRecord per line to its writer. It is separate
+// from the service logs so the audit trail can be kept and shipped on its own.
+type AuditLogger struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewAuditLogger creates an AuditLogger writing to w
+func NewAuditLogger(w io.Writer) *AuditLogger {
+	return &AuditLogger{encoder: json.NewEncoder(w)}
+}
+
+// Record writes record as a single JSON line
+func (al *AuditLogger) Record(record AuditRecord) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	return al.encoder.Encode(record)
+}
+
+// sessionIDKey is the context key for the session a tool call belongs to
+type sessionIDKey struct{}
+
+// ContextWithSessionID returns a context whose tool invocations are audited under
+// sessionID
+func ContextWithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// sessionIDFromContext returns the session set by ContextWithSessionID, if any
+func sessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/pkg/logging"
+)
+
+// decodeAuditRecords parses the JSON lines written by an AuditLogger
+func decodeAuditRecords(t *testing.T, buf *bytes.Buffer) []AuditRecord {
+	t.Helper()
+
+	var records []AuditRecord
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var record AuditRecord
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("Failed to decode audit record: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestToolManager_AuditLog tests that every invocation produces one audit record
+// with sanitized arguments and its outcome
+func TestToolManager_AuditLog(t *testing.T) {
+	manager := NewToolManager(logging.NewStructuredLogger("test"))
+	tool := &mockTool{
+		name:        "audited-tool",
+		description: "Tool used for audit tests",
+		schema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code": map[string]interface{}{"type": "string"},
+				"fail": map[string]interface{}{"type": "boolean"},
+			},
+			"required": []interface{}{"code"},
+		},
+		executeFunc: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			if fail, _ := arguments["fail"].(bool); fail {
+				return nil, fmt.Errorf("pattern not found")
+			}
+			return map[string]interface{}{"result": "success"}, nil
+		},
+	}
+	if err := manager.RegisterTool(tool); err != nil {
+		t.Fatalf("RegisterTool failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	manager.SetAuditLog(&buf)
+
+	longCode := strings.Repeat("x", 500)
+	ctx := ContextWithSessionID(context.Background(), "session-1")
+	manager.ExecuteTool(ctx, "audited-tool", map[string]interface{}{"code": longCode})
+	manager.ExecuteTool(ctx, "audited-tool", map[string]interface{}{"code": "short", "fail": true})
+	manager.ExecuteTool(ctx, "audited-tool", map[string]interface{}{})
+	manager.ExecuteTool(ctx, "missing-tool", map[string]interface{}{"code": "short"})
+
+	records := decodeAuditRecords(t, &buf)
+	if len(records) != 4 {
+		t.Fatalf("Expected one audit record per invocation, got %d: %s", len(records), buf.String())
+	}
+
+	tests := []struct {
+		name        string
+		record      AuditRecord
+		wantTool    string
+		wantSuccess bool
+		wantError   string
+	}{
+		{name: "success", record: records[0], wantTool: "audited-tool", wantSuccess: true},
+		{name: "tool failure", record: records[1], wantTool: "audited-tool", wantError: "pattern not found"},
+		{name: "validation failure", record: records[2], wantTool: "audited-tool", wantError: "missing required argument: code"},
+		{name: "unknown tool", record: records[3], wantTool: "missing-tool", wantError: "tool not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.record.Tool != tt.wantTool {
+				t.Errorf("Expected tool %q, got %q", tt.wantTool, tt.record.Tool)
+			}
+			if tt.record.SessionID != "session-1" {
+				t.Errorf("Expected session ID session-1, got %q", tt.record.SessionID)
+			}
+			if tt.record.Success != tt.wantSuccess {
+				t.Errorf("Expected success %v, got %v", tt.wantSuccess, tt.record.Success)
+			}
+			if !strings.Contains(tt.record.Error, tt.wantError) || (tt.wantError == "") != (tt.record.Error == "") {
+				t.Errorf("Expected error containing %q, got %q", tt.wantError, tt.record.Error)
+			}
+			if tt.record.Timestamp.IsZero() || tt.record.DurationMs < 0 {
+				t.Errorf("Expected a timestamp and duration, got %+v", tt.record)
+			}
+		})
+	}
+
+	code, _ := records[0].Arguments["code"].(string)
+	if strings.Contains(code, longCode) || !strings.Contains(code, "[500 chars]") {
+		t.Errorf("Expected the code argument to be truncated, got %q", code)
+	}
+	if records[1].Arguments["code"] != "short" || records[1].Arguments["fail"] != true {
+		t.Errorf("Expected short arguments to be recorded as given, got %v", records[1].Arguments)
+	}
+}
+
+// TestToolManager_AuditLogDisabled tests that auditing is opt-in
+func TestToolManager_AuditLogDisabled(t *testing.T) {
+	manager := NewToolManager(logging.NewStructuredLogger("test"))
+	if err := manager.RegisterTool(&mockTool{name: "quiet-tool", description: "Quiet"}); err != nil {
+		t.Fatalf("RegisterTool failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	manager.SetAuditLog(&buf)
+	manager.SetAuditLog(nil)
+
+	if _, err := manager.ExecuteTool(context.Background(), "quiet-tool", nil); err != nil {
+		t.Fatalf("ExecuteTool failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no audit records once disabled, got %s", buf.String())
+	}
+}
+
+// TestToolExecutor_AuditWorkflowSession tests that workflow invocations are audited
+// under their workflow session
+func TestToolExecutor_AuditWorkflowSession(t *testing.T) {
+	executor := NewToolExecutor(logging.NewStructuredLogger("test"))
+	var buf bytes.Buffer
+	executor.SetAuditLogger(NewAuditLogger(&buf))
+
+	workflowCtx := executor.CreateSession("workflow-42", "create-adr", nil)
+	tool := &mockToolForExecutor{name: "workflow-tool"}
+	if _, err := executor.ExecuteWithContext(context.Background(), tool, map[string]interface{}{}, workflowCtx); err != nil {
+		t.Fatalf("ExecuteWithContext failed: %v", err)
+	}
+
+	records := decodeAuditRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected one audit record, got %d", len(records))
+	}
+	if records[0].SessionID != "workflow-42" || records[0].Tool != "workflow-tool" || !records[0].Success {
+		t.Errorf("Unexpected audit record: %+v", records[0])
+	}
+}
+
+// TestToolManager_AuditLogRedaction tests that redacted fields never reach the audit log
+func TestToolManager_AuditLogRedaction(t *testing.T) {
+	manager := NewToolManager(logging.NewStructuredLogger("test"))
+	if err := manager.RegisterTool(&mockTool{name: "remote-tool", description: "Remote"}); err != nil {
+		t.Fatalf("RegisterTool failed: %v", err)
+	}
+	if err := manager.SetArgumentSanitization(10, []string{"credentials"}); err != nil {
+		t.Fatalf("SetArgumentSanitization failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	manager.SetAuditLog(&buf)
+	manager.ExecuteTool(context.Background(), "remote-tool", map[string]interface{}{
+		"credentials": "s3cr3t-value",
+		"description": "a description longer than ten characters",
+	})
+
+	if strings.Contains(buf.String(), "s3cr3t-value") {
+		t.Fatalf("Redacted value leaked into the audit log: %s", buf.String())
+	}
+	records := decodeAuditRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected one audit record, got %d", len(records))
+	}
+	if records[0].Arguments["credentials"] != "[redacted]" {
+		t.Errorf("Expected credentials to be redacted, got %v", records[0].Arguments["credentials"])
+	}
+	if records[0].Arguments["description"] != "a descript... [40 chars]" {
+		t.Errorf("Expected description truncated to 10 characters, got %v", records[0].Arguments["description"])
+	}
+}
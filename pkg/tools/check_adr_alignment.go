@@ -3,27 +3,84 @@ package tools
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/logging"
+	"mcp-architecture-service/pkg/scanner"
 )
 
+// DefaultProximityWindow is how many characters apart a decision keyword and an
+// opposing keyword such as "avoid" may be for the ADR to count as a conflict
+const DefaultProximityWindow = 100
+
+// DefaultMinAlignmentConfidence is the confidence below which a "supports" or
+// "conflicts" classification is reported as "related" instead
+const DefaultMinAlignmentConfidence = 0.3
+
 // CheckADRAlignmentTool checks if a decision aligns with existing ADRs
 type CheckADRAlignmentTool struct {
-	cache  *cache.DocumentCache
-	logger *logging.StructuredLogger
+	cache           *cache.DocumentCache
+	logger          *logging.StructuredLogger
+	proximityWindow int
+	minConfidence   float64
+	stopWords       map[string]bool
+	sectionLevels   sectionLevels
 }
 
 // NewCheckADRAlignmentTool creates a new CheckADRAlignmentTool instance
 func NewCheckADRAlignmentTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *CheckADRAlignmentTool {
 	return &CheckADRAlignmentTool{
-		cache:  cache,
-		logger: logger,
+		cache:           cache,
+		logger:          logger,
+		proximityWindow: DefaultProximityWindow,
+		minConfidence:   DefaultMinAlignmentConfidence,
+		stopWords:       defaultStopWords(),
+		sectionLevels:   defaultSectionLevels(),
+	}
+}
+
+// SetProximityWindow changes how close, in characters, an opposing keyword must be
+// to a decision keyword for the ADR to be reported as conflicting
+func (cat *CheckADRAlignmentTool) SetProximityWindow(chars int) error {
+	if chars < 1 {
+		return fmt.Errorf("proximity window must be at least 1 character, got %d", chars)
+	}
+	cat.proximityWindow = chars
+	return nil
+}
+
+// SetMinConfidence changes the confidence, between 0 and 1, a "supports" or "conflicts"
+// classification needs to be reported as such rather than as "related"
+func (cat *CheckADRAlignmentTool) SetMinConfidence(confidence float64) error {
+	if confidence < 0 || confidence > 1 {
+		return fmt.Errorf("minimum confidence must be between 0 and 1, got %g", confidence)
+	}
+	cat.minConfidence = confidence
+	return nil
+}
+
+// SetStopWords replaces the words left out of decision keywords, for ADRs written
+// in a language other than English. See StopWordsFor and NewStopWordSet.
+func (cat *CheckADRAlignmentTool) SetStopWords(stopWords map[string]bool) {
+	cat.stopWords = stopWords
+}
+
+// SetSectionLevels changes the heading levels an ADR's Decision section may use, for
+// ADRs that title it with # or ### rather than ##. Defaults to DefaultSectionLevels.
+func (cat *CheckADRAlignmentTool) SetSectionLevels(levels []int) error {
+	set, err := newSectionLevels(levels)
+	if err != nil {
+		return err
 	}
+	cat.sectionLevels = set
+	return nil
 }
 
 // Name returns the unique identifier for the tool
@@ -61,12 +118,12 @@ func (cat *CheckADRAlignmentTool) Execute(ctx context.Context, arguments map[str
 	// Extract arguments
 	decisionDescription, ok := arguments["decision_description"].(string)
 	if !ok {
-		return nil, fmt.Errorf("decision_description argument must be a string")
+		return nil, invalidArgument("decision_description argument must be a string")
 	}
 
 	// Validate decision_description length
 	if len(decisionDescription) > 5000 {
-		return nil, fmt.Errorf("decision_description exceeds maximum length of 5000 characters")
+		return nil, invalidArgument("decision_description exceeds maximum length of 5000 characters")
 	}
 
 	// Extract optional decision_context
@@ -75,7 +132,7 @@ func (cat *CheckADRAlignmentTool) Execute(ctx context.Context, arguments map[str
 		decisionContext = dc
 		// Validate decision_context length
 		if len(decisionContext) > 2000 {
-			return nil, fmt.Errorf("decision_context exceeds maximum length of 2000 characters")
+			return nil, invalidArgument("decision_context exceeds maximum length of 2000 characters")
 		}
 	}
 
@@ -91,13 +148,14 @@ func (cat *CheckADRAlignmentTool) Execute(ctx context.Context, arguments map[str
 
 // adrAlignment represents alignment information for a single ADR
 type adrAlignment struct {
-	URI       string
-	Title     string
-	ADRID     string
-	Status    string
-	Alignment string // "supports", "conflicts", "related"
-	Reason    string
-	Score     float64
+	URI        string
+	Title      string
+	ADRID      string
+	Status     string
+	Alignment  string // "supports", "conflicts", "related"
+	Reason     string
+	Score      float64
+	Confidence float64 // 0-1, how much the Alignment label can be trusted
 }
 
 // analyzeAlignment performs the ADR alignment analysis
@@ -142,12 +200,13 @@ func (cat *CheckADRAlignmentTool) analyzeAlignment(decisionDescription, decision
 	relatedADRs := make([]map[string]interface{}, 0, len(alignments))
 	for _, alignment := range alignments {
 		relatedADRs = append(relatedADRs, map[string]interface{}{
-			"uri":       alignment.URI,
-			"title":     alignment.Title,
-			"adr_id":    alignment.ADRID,
-			"status":    alignment.Status,
-			"alignment": alignment.Alignment,
-			"reason":    alignment.Reason,
+			"uri":        alignment.URI,
+			"title":      alignment.Title,
+			"adr_id":     alignment.ADRID,
+			"status":     alignment.Status,
+			"alignment":  alignment.Alignment,
+			"confidence": alignment.Confidence,
+			"reason":     alignment.Reason,
 		})
 	}
 
@@ -169,8 +228,7 @@ func (cat *CheckADRAlignmentTool) analyzeAlignment(decisionDescription, decision
 // extractKeywords extracts important keywords from decision text
 func (cat *CheckADRAlignmentTool) extractKeywords(decisionDescription, decisionContext string) []string {
 	text := cat.combineText(decisionDescription, decisionContext)
-	tokens := cat.tokenizeText(text)
-	return cat.filterKeywords(tokens)
+	return cat.filterKeywords(Tokenize(text))
 }
 
 func (cat *CheckADRAlignmentTool) combineText(description, context string) string {
@@ -181,20 +239,12 @@ func (cat *CheckADRAlignmentTool) combineText(description, context string) strin
 	return strings.ToLower(text)
 }
 
-func (cat *CheckADRAlignmentTool) tokenizeText(text string) []string {
-	return strings.FieldsFunc(text, func(r rune) bool {
-		return r == ' ' || r == '\t' || r == '\n' || r == ',' || r == '.' ||
-			r == ';' || r == ':' || r == '!' || r == '?' || r == '(' || r == ')'
-	})
-}
-
 func (cat *CheckADRAlignmentTool) filterKeywords(tokens []string) []string {
-	stopWords := cat.getStopWords()
+	stopWords := cat.stopWords
 	keywordSet := make(map[string]bool)
 	var keywords []string
 
 	for _, token := range tokens {
-		token = strings.TrimSpace(token)
 		if cat.isValidKeyword(token, stopWords, keywordSet) {
 			keywords = append(keywords, token)
 			keywordSet[token] = true
@@ -204,21 +254,8 @@ func (cat *CheckADRAlignmentTool) filterKeywords(tokens []string) []string {
 	return keywords
 }
 
-func (cat *CheckADRAlignmentTool) getStopWords() map[string]bool {
-	return map[string]bool{
-		"the": true, "a": true, "an": true, "and": true, "or": true, "but": true,
-		"in": true, "on": true, "at": true, "to": true, "for": true, "of": true,
-		"with": true, "by": true, "from": true, "as": true, "is": true, "was": true,
-		"are": true, "were": true, "be": true, "been": true, "being": true,
-		"have": true, "has": true, "had": true, "do": true, "does": true, "did": true,
-		"will": true, "would": true, "should": true, "could": true, "may": true,
-		"might": true, "must": true, "can": true, "this": true, "that": true,
-		"these": true, "those": true, "we": true, "our": true, "us": true,
-	}
-}
-
 func (cat *CheckADRAlignmentTool) isValidKeyword(token string, stopWords, keywordSet map[string]bool) bool {
-	return len(token) >= 3 && !stopWords[token] && !keywordSet[token]
+	return utf8.RuneCountInString(token) >= 3 && !stopWords[token] && !keywordSet[token]
 }
 
 // analyzeADR analyzes a single ADR for alignment with the decision
@@ -261,20 +298,54 @@ func (cat *CheckADRAlignmentTool) analyzeADR(doc *models.Document, path string,
 	// Determine alignment type
 	alignment, reason := cat.determineAlignment(content, decisionLower, status, keywords)
 
+	confidence := cat.calculateConfidence(content, status, keywords, matchedKeywords)
+	if alignment != "related" && confidence < cat.minConfidence {
+		reason = fmt.Sprintf("%s (low confidence, reported as related)", reason)
+		alignment = "related"
+	}
+
 	// Generate URI
 	uri := cat.generateURI(path)
 
 	return &adrAlignment{
-		URI:       uri,
-		Title:     doc.Metadata.Title,
-		ADRID:     adrID,
-		Status:    status,
-		Alignment: alignment,
-		Reason:    reason,
-		Score:     score,
+		URI:        uri,
+		Title:      doc.Metadata.Title,
+		ADRID:      adrID,
+		Status:     status,
+		Alignment:  alignment,
+		Reason:     reason,
+		Score:      score,
+		Confidence: confidence,
 	}
 }
 
+// calculateConfidence blends how many decision keywords the ADR mentions, how many
+// appear in its Decision section, and whether its status could be read
+func (cat *CheckADRAlignmentTool) calculateConfidence(content, status string, keywords []string, matchedKeywords int) float64 {
+	if len(keywords) == 0 {
+		return 0
+	}
+
+	density := float64(matchedKeywords) / float64(len(keywords))
+
+	sectionMatches := 0
+	decisionSectionLower := strings.ToLower(extractSection(content, "Decision", cat.sectionLevels))
+	for _, keyword := range keywords {
+		if decisionSectionLower != "" && strings.Contains(decisionSectionLower, keyword) {
+			sectionMatches++
+		}
+	}
+	sectionDensity := float64(sectionMatches) / float64(len(keywords))
+
+	statusCertainty := 0.0
+	if status != "unknown" {
+		statusCertainty = 1.0
+	}
+
+	confidence := 0.5*density + 0.3*sectionDensity + 0.2*statusCertainty
+	return math.Round(confidence*100) / 100
+}
+
 // extractADRID extracts the ADR ID from the file path
 func (cat *CheckADRAlignmentTool) extractADRID(path string) string {
 	// Extract filename from path
@@ -291,24 +362,11 @@ func (cat *CheckADRAlignmentTool) extractADRID(path string) string {
 	return "unknown"
 }
 
-// extractADRStatus extracts the status from ADR content
+// extractADRStatus extracts the status from ADR content, defaulting to "unknown"
 func (cat *CheckADRAlignmentTool) extractADRStatus(content string) string {
-	// Look for status in common ADR formats
-	statusPatterns := []string{
-		`(?i)status:\s*(\w+)`,
-		`(?i)## status\s+(\w+)`,
-		`(?i)\*\*status\*\*:\s*(\w+)`,
-	}
-
-	for _, pattern := range statusPatterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(content)
-		if len(matches) > 1 {
-			return strings.ToLower(matches[1])
-		}
+	if status := scanner.ExtractStatus(content); status != "" {
+		return status
 	}
-
-	// Default to "unknown" if status not found
 	return "unknown"
 }
 
@@ -357,38 +415,115 @@ func (cat *CheckADRAlignmentTool) checkForConflicts(adrContentLower, status stri
 func (cat *CheckADRAlignmentTool) checkOpposingPatterns(adrContentLower string, keywords []string) (string, string) {
 	opposingKeywords := []string{"avoid", "do not", "should not", "must not", "anti-pattern"}
 
-	for _, keyword := range opposingKeywords {
-		if !strings.Contains(adrContentLower, keyword) {
-			continue
-		}
-
-		if cat.hasNearbyOpposingKeywords(adrContentLower, keywords, opposingKeywords) {
-			return "conflicts", "ADR recommends avoiding this approach"
-		}
+	if cat.hasNearbyOpposingKeywords(adrContentLower, keywords, opposingKeywords) {
+		return "conflicts", "ADR recommends avoiding this approach"
 	}
 
 	return "", ""
 }
 
+// hasNearbyOpposingKeywords reports whether any occurrence of a decision keyword lies
+// within the proximity window of any non-negated occurrence of an opposing keyword
 func (cat *CheckADRAlignmentTool) hasNearbyOpposingKeywords(adrContentLower string, keywords, opposingKeywords []string) bool {
+	opposingPositions := findOpposingPositions(adrContentLower, opposingKeywords)
+	if len(opposingPositions) == 0 {
+		return false
+	}
+
 	for _, decisionKeyword := range keywords {
-		keywordPos := strings.Index(adrContentLower, decisionKeyword)
-		if keywordPos == -1 {
+		for _, keywordPos := range findAllOccurrences(adrContentLower, decisionKeyword) {
+			for _, opposingPos := range opposingPositions {
+				if abs(keywordPos-opposingPos) < cat.proximityWindow {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// negationTerms cancel an opposing keyword that directly follows them, so that
+// "do not avoid X" or "never avoid X" reads as support rather than opposition
+var negationTerms = []string{"not", "never", "don't", "dont", "shouldn't", "mustn't", "no need to"}
+
+// findOpposingPositions returns the start offset of every opposing keyword occurrence,
+// dropping double negatives. In "should not avoid" both the negated "avoid" and the
+// negating "should not" are dropped.
+func findOpposingPositions(content string, opposingKeywords []string) []int {
+	type occurrence struct{ start, end int }
+
+	var occurrences []occurrence
+	for _, opposing := range opposingKeywords {
+		for _, pos := range findAllOccurrences(content, opposing) {
+			occurrences = append(occurrences, occurrence{start: pos, end: pos + len(opposing)})
+		}
+	}
+
+	negated := make(map[int]bool)
+	for _, occ := range occurrences {
+		negationStart, negationEnd, ok := precedingNegation(content, occ.start)
+		if !ok {
 			continue
 		}
 
-		for _, opposing := range opposingKeywords {
-			opposingPos := strings.Index(adrContentLower, opposing)
-			if opposingPos != -1 && abs(keywordPos-opposingPos) < 100 {
-				return true
+		negated[occ.start] = true
+		for _, other := range occurrences {
+			if other.start < negationEnd && other.end > negationStart {
+				negated[other.start] = true
 			}
 		}
 	}
-	return false
+
+	var positions []int
+	for _, occ := range occurrences {
+		if !negated[occ.start] {
+			positions = append(positions, occ.start)
+		}
+	}
+	return positions
+}
+
+// precedingNegation reports the span of a negation term directly before pos, allowing
+// an article in between ("is not an anti-pattern")
+func precedingNegation(content string, pos int) (start, end int, ok bool) {
+	prefix := strings.TrimRight(content[:pos], " \t\n")
+	for _, article := range []string{" an", " a", " the"} {
+		if strings.HasSuffix(prefix, article) {
+			prefix = strings.TrimRight(strings.TrimSuffix(prefix, article), " \t\n")
+			break
+		}
+	}
+
+	for _, negation := range negationTerms {
+		if !strings.HasSuffix(prefix, negation) {
+			continue
+		}
+
+		start = len(prefix) - len(negation)
+		if start > 0 && unicode.IsLetter(rune(prefix[start-1])) {
+			continue // part of a longer word such as "knot"
+		}
+		return start, len(prefix), true
+	}
+
+	return 0, 0, false
+}
+
+// findAllOccurrences returns the start offset of every non-overlapping occurrence of substr
+func findAllOccurrences(s, substr string) []int {
+	var positions []int
+	for offset := 0; ; {
+		idx := strings.Index(s[offset:], substr)
+		if idx == -1 {
+			return positions
+		}
+		positions = append(positions, offset+idx)
+		offset += idx + len(substr)
+	}
 }
 
 func (cat *CheckADRAlignmentTool) checkDecisionAlignment(adrContent, status string, keywords []string) (string, string) {
-	decisionSection := cat.extractSection(adrContent, "## Decision")
+	decisionSection := extractSection(adrContent, "Decision", cat.sectionLevels)
 	if decisionSection == "" {
 		return "", ""
 	}
@@ -421,28 +556,6 @@ func (cat *CheckADRAlignmentTool) hasSupportingPatterns(adrContentLower string)
 	return false
 }
 
-// extractSection extracts content from a markdown section
-func (cat *CheckADRAlignmentTool) extractSection(content, sectionHeader string) string {
-	lines := strings.Split(content, "\n")
-	inSection := false
-	sectionContent := []string{}
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, sectionHeader) {
-			inSection = true
-			continue
-		}
-		if inSection && strings.HasPrefix(line, "## ") {
-			break
-		}
-		if inSection {
-			sectionContent = append(sectionContent, line)
-		}
-	}
-
-	return strings.Join(sectionContent, "\n")
-}
-
 // sortAlignments sorts alignments by score in descending order
 func (cat *CheckADRAlignmentTool) sortAlignments(alignments []adrAlignment) {
 	// Simple bubble sort for small lists
@@ -526,8 +639,8 @@ func (cat *CheckADRAlignmentTool) generateURI(path string) string {
 	parts := strings.Split(path, "/")
 	filename := parts[len(parts)-1]
 
-	// Remove .md extension
-	filename = strings.TrimSuffix(filename, config.MarkdownExtension)
+	// Remove markdown extension
+	filename = config.TrimMarkdownExtension(filename)
 
 	// Generate URI
 	return fmt.Sprintf("%s%s/%s", config.URIScheme, config.URIADR, filename)
@@ -986,3 +986,257 @@ Each microservice will have its own database to ensure loose coupling and indepe
 	cache.Set(adr2.Metadata.Path, adr2)
 	cache.Set(adr3.Metadata.Path, adr3)
 }
+
+// TestCheckADRAlignmentTool_HasNearbyOpposingKeywords tests proximity detection across all occurrences
+func TestCheckADRAlignmentTool_HasNearbyOpposingKeywords(t *testing.T) {
+	filler := strings.Repeat("unrelated text ", 20)
+
+	tests := []struct {
+		name     string
+		content  string
+		window   int
+		expected bool
+	}{
+		{
+			name:     "opposing keyword near first occurrence",
+			content:  "avoid caching here. " + filler,
+			window:   DefaultProximityWindow,
+			expected: true,
+		},
+		{
+			name:     "opposing keyword near later occurrence only",
+			content:  "caching is discussed. " + filler + "teams must avoid caching of sessions.",
+			window:   DefaultProximityWindow,
+			expected: true,
+		},
+		{
+			name:     "opposing keyword far from every occurrence",
+			content:  "caching is discussed. " + filler + "avoid shared state.",
+			window:   DefaultProximityWindow,
+			expected: false,
+		},
+		{
+			name:     "wider window reaches distant keyword",
+			content:  "caching is discussed. " + filler + "avoid shared state.",
+			window:   len(filler) + 100,
+			expected: true,
+		},
+		{
+			name:     "narrow window excludes nearby keyword",
+			content:  "avoid the legacy approach to caching",
+			window:   10,
+			expected: false,
+		},
+	}
+
+	opposing := []string{"avoid", "do not", "should not", "must not", "anti-pattern"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+			if err := tool.SetProximityWindow(tt.window); err != nil {
+				t.Fatalf("SetProximityWindow failed: %v", err)
+			}
+
+			got := tool.hasNearbyOpposingKeywords(tt.content, []string{"caching"}, opposing)
+			if got != tt.expected {
+				t.Errorf("hasNearbyOpposingKeywords() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCheckADRAlignmentTool_SetProximityWindow tests window validation
+func TestCheckADRAlignmentTool_SetProximityWindow(t *testing.T) {
+	tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+
+	if tool.proximityWindow != DefaultProximityWindow {
+		t.Errorf("Expected default window %d, got %d", DefaultProximityWindow, tool.proximityWindow)
+	}
+	if err := tool.SetProximityWindow(0); err == nil {
+		t.Error("Expected error for zero window")
+	}
+	if err := tool.SetProximityWindow(250); err != nil || tool.proximityWindow != 250 {
+		t.Errorf("Expected window 250, got %d (err: %v)", tool.proximityWindow, err)
+	}
+}
+
+// TestCheckADRAlignmentTool_SectionLevels tests that the Decision section is found
+// at the configured heading levels
+func TestCheckADRAlignmentTool_SectionLevels(t *testing.T) {
+	keywords := []string{"kafka", "events"}
+	tests := []struct {
+		name    string
+		levels  []int
+		content string
+		want    string
+	}{
+		{name: "default level 2", levels: DefaultSectionLevels, content: "# ADR 7\n## Decision\nWe will use Kafka for events.", want: "supports"},
+		{name: "level 1", levels: []int{1}, content: "# Decision\nWe will use Kafka for events.\n# Consequences\nMore ops", want: "supports"},
+		{name: "level 3", levels: []int{3}, content: "# ADR 7\n## Details\n### Decision\nWe will use Kafka for events.", want: "supports"},
+		{name: "level 1 with default levels", levels: DefaultSectionLevels, content: "# Decision\nWe will use Kafka for events.", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+			if err := tool.SetSectionLevels(tt.levels); err != nil {
+				t.Fatalf("SetSectionLevels failed: %v", err)
+			}
+			if got, _ := tool.checkDecisionAlignment(tt.content, "accepted", keywords); got != tt.want {
+				t.Errorf("checkDecisionAlignment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+	if err := tool.SetSectionLevels(nil); err == nil {
+		t.Error("Expected error for no heading levels")
+	}
+}
+
+// TestCheckADRAlignmentTool_CheckOpposingPatterns_Negation tests that double negatives are not flagged
+func TestCheckADRAlignmentTool_CheckOpposingPatterns_Negation(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantConflicts bool
+	}{
+		{"avoid", "teams should avoid microservices for small products", true},
+		{"do not", "do not adopt microservices before the domain is understood", true},
+		{"anti-pattern", "shared databases between microservices are an anti-pattern", true},
+		{"do not avoid", "do not avoid microservices when teams need independent deployment", false},
+		{"should not avoid", "we should not avoid microservices for the billing domain", false},
+		{"never avoid", "never avoid microservices just because of operational cost", false},
+		{"don't avoid", "don't avoid microservices where scaling differs", false},
+		{"not an anti-pattern", "splitting into microservices is not an anti-pattern here", false},
+		{"negation elsewhere", "this is not our concern. avoid microservices for now", true},
+	}
+
+	tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alignment, _ := tool.checkOpposingPatterns(tt.content, []string{"microservices"})
+			if got := alignment == "conflicts"; got != tt.wantConflicts {
+				t.Errorf("checkOpposingPatterns(%q) conflicts = %v, want %v", tt.content, got, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+// TestCheckADRAlignmentTool_Execute_Confidence tests that denser matches yield higher confidence
+func TestCheckADRAlignmentTool_Execute_Confidence(t *testing.T) {
+	cache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewCheckADRAlignmentTool(cache, logger)
+
+	adrs := map[string]string{
+		"mcp/resources/adr/001-event-driven-messaging.md": `# ADR 001: Event Driven Messaging
+
+## Status
+
+Accepted
+
+## Decision
+
+We recommend asynchronous event driven messaging between services using a message broker.
+`,
+		"mcp/resources/adr/002-team-structure.md": `# ADR 002: Team Structure
+
+Teams own services end to end. Avoid shared services ownership.
+`,
+	}
+	for path, content := range adrs {
+		cache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Title: strings.SplitN(content, "\n", 2)[0], Category: config.CategoryADR, Path: path},
+			Content:  models.DocumentContent{RawContent: content},
+		})
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"decision_description": "Use asynchronous event driven messaging between services with a message broker",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	confidence := make(map[string]float64)
+	alignment := make(map[string]string)
+	for _, adr := range result.(map[string]interface{})["related_adrs"].([]map[string]interface{}) {
+		id := adr["adr_id"].(string)
+		confidence[id] = adr["confidence"].(float64)
+		alignment[id] = adr["alignment"].(string)
+	}
+
+	if confidence["001"] <= confidence["002"] {
+		t.Errorf("Expected dense match ADR 001 to have higher confidence than ADR 002, got %.2f <= %.2f",
+			confidence["001"], confidence["002"])
+	}
+	if confidence["001"] < 0 || confidence["001"] > 1 || confidence["002"] < 0 || confidence["002"] > 1 {
+		t.Errorf("Confidence must be between 0 and 1, got %v", confidence)
+	}
+	if alignment["001"] != "supports" {
+		t.Errorf("Expected high-confidence ADR 001 to support, got %q", alignment["001"])
+	}
+	if alignment["002"] != "related" {
+		t.Errorf("Expected low-confidence ADR 002 to be reported as related, got %q", alignment["002"])
+	}
+}
+
+// TestCheckADRAlignmentTool_SetMinConfidence tests minimum confidence validation
+func TestCheckADRAlignmentTool_SetMinConfidence(t *testing.T) {
+	tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+
+	tests := []struct {
+		confidence float64
+		expectErr  bool
+	}{
+		{-0.1, true},
+		{1.5, true},
+		{0, false},
+		{0.75, false},
+	}
+
+	for _, tt := range tests {
+		err := tool.SetMinConfidence(tt.confidence)
+		if (err != nil) != tt.expectErr {
+			t.Errorf("SetMinConfidence(%g) error = %v, expectErr %v", tt.confidence, err, tt.expectErr)
+		}
+	}
+	if tool.minConfidence != 0.75 {
+		t.Errorf("Expected minimum confidence 0.75, got %g", tool.minConfidence)
+	}
+}
+
+// TestCheckADRAlignmentTool_NonEnglishStopWords tests keyword extraction with a configured stop-word set
+func TestCheckADRAlignmentTool_NonEnglishStopWords(t *testing.T) {
+	tool := NewCheckADRAlignmentTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+	description := "Wir müssen die Daten für den Bestellungsdienst in einer Datenbank speichern"
+
+	keywords := tool.extractKeywords(description, "")
+	if !containsString(keywords, "die") || !containsString(keywords, "für") {
+		t.Fatalf("English stop words should leave German function words in place, got %v", keywords)
+	}
+
+	stopWords, err := StopWordsFor("de")
+	if err != nil {
+		t.Fatalf("StopWordsFor failed: %v", err)
+	}
+	tool.SetStopWords(stopWords)
+
+	keywords = tool.extractKeywords(description, "")
+	expected := []string{"daten", "bestellungsdienst", "datenbank", "speichern"}
+	if strings.Join(keywords, "|") != strings.Join(expected, "|") {
+		t.Errorf("extractKeywords with German stop words = %q, want %q", keywords, expected)
+	}
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
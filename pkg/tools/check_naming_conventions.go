@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// Default filename conventions per category. ADRs carry a zero-padded sequence number
+// so they sort chronologically; everything else is plain kebab-case. Both accept any
+// of config.MarkdownExtensions.
+var (
+	DefaultADRNamingPattern     = `^\d{3}-[a-z0-9]+(-[a-z0-9]+)*` + markdownExtensionPattern()
+	DefaultKebabCaseNamePattern = `^[a-z0-9]+(-[a-z0-9]+)*` + markdownExtensionPattern()
+)
+
+var (
+	// nonAlphanumericPattern matches runs of characters that become a single hyphen
+	nonAlphanumericPattern = regexp.MustCompile(`[^a-z0-9]+`)
+	// camelCaseBoundaryPattern finds word boundaries in names like "RepositoryPattern"
+	camelCaseBoundaryPattern = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	// adrNumberPrefixPattern captures an existing ADR number, with or without an "adr-" prefix
+	adrNumberPrefixPattern = regexp.MustCompile(`^(?i:adr[-_]?)?(\d+)[-_ ]?(.*)$`)
+)
+
+// CheckNamingConventionsTool reports cached documents whose filenames break the
+// naming convention configured for their category
+type CheckNamingConventionsTool struct {
+	cache       *cache.DocumentCache
+	logger      *logging.StructuredLogger
+	conventions map[string]*regexp.Regexp
+}
+
+// NewCheckNamingConventionsTool creates a new CheckNamingConventionsTool with the default conventions
+func NewCheckNamingConventionsTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *CheckNamingConventionsTool {
+	return &CheckNamingConventionsTool{
+		cache:  cache,
+		logger: logger,
+		conventions: map[string]*regexp.Regexp{
+			config.CategoryADR:       regexp.MustCompile(DefaultADRNamingPattern),
+			config.CategoryPattern:   regexp.MustCompile(DefaultKebabCaseNamePattern),
+			config.CategoryGuideline: regexp.MustCompile(DefaultKebabCaseNamePattern),
+		},
+	}
+}
+
+// SetConvention overrides the filename convention for a category
+func (cnt *CheckNamingConventionsTool) SetConvention(category, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid naming convention for %s: %w", category, err)
+	}
+	cnt.conventions[category] = re
+	return nil
+}
+
+// Name returns the unique identifier for the tool
+func (cnt *CheckNamingConventionsTool) Name() string {
+	return "check-naming-conventions"
+}
+
+// Description returns a human-readable description
+func (cnt *CheckNamingConventionsTool) Description() string {
+	return "Checks documentation filenames against the naming convention for their category and suggests corrected names"
+}
+
+// InputSchema returns JSON schema for tool parameters
+func (cnt *CheckNamingConventionsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"resource_type": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{config.CategoryGuideline, config.CategoryPattern, config.CategoryADR, "all"},
+				"description": "Limit the check to one resource type (default: all)",
+			},
+		},
+	}
+}
+
+// Execute runs the tool with validated arguments
+func (cnt *CheckNamingConventionsTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	resourceType := "all"
+	if rt, ok := arguments["resource_type"].(string); ok {
+		resourceType = rt
+	}
+
+	if resourceType != "all" {
+		if _, ok := cnt.conventions[resourceType]; !ok {
+			return nil, invalidArgument("invalid resource_type: no naming convention configured for %s", resourceType)
+		}
+	}
+
+	cnt.logger.WithContext("resource_type", resourceType).
+		Info("Checking documentation naming conventions")
+
+	return cnt.checkConventions(resourceType), nil
+}
+
+// namingViolation describes a single filename that breaks its category convention
+type namingViolation struct {
+	Path            string
+	Category        string
+	Filename        string
+	ExpectedPattern string
+	SuggestedName   string
+}
+
+// checkConventions scans cached document paths and collects violations
+func (cnt *CheckNamingConventionsTool) checkConventions(resourceType string) map[string]interface{} {
+	allDocs := cnt.cache.GetAllDocuments()
+	nextADRNumber := cnt.nextADRNumber()
+
+	// Visit paths in order so unnumbered ADRs receive stable suggested numbers
+	paths := make([]string, 0, len(allDocs))
+	for path := range allDocs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var violations []namingViolation
+	checked := 0
+	for _, path := range paths {
+		doc := allDocs[path]
+		category := doc.Metadata.Category
+		if resourceType != "all" && category != resourceType {
+			continue
+		}
+
+		convention, ok := cnt.conventions[category]
+		if !ok {
+			continue
+		}
+		checked++
+
+		filename := filepath.Base(path)
+		if convention.MatchString(filename) {
+			continue
+		}
+
+		suggested := cnt.suggestName(filename, category, nextADRNumber)
+		if category == config.CategoryADR && !adrNumberPrefixPattern.MatchString(filename) {
+			// Each unnumbered ADR gets its own number so suggestions never collide
+			nextADRNumber++
+		}
+
+		violations = append(violations, namingViolation{
+			Path:            path,
+			Category:        category,
+			Filename:        filename,
+			ExpectedPattern: convention.String(),
+			SuggestedName:   suggested,
+		})
+	}
+
+	violationList := make([]map[string]interface{}, 0, len(violations))
+	for _, v := range violations {
+		violationList = append(violationList, map[string]interface{}{
+			"path":             v.Path,
+			"category":         v.Category,
+			"filename":         v.Filename,
+			"expected_pattern": v.ExpectedPattern,
+			"suggested_name":   v.SuggestedName,
+		})
+	}
+
+	conventions := make(map[string]string, len(cnt.conventions))
+	for category, re := range cnt.conventions {
+		conventions[category] = re.String()
+	}
+
+	return map[string]interface{}{
+		"violations":      violationList,
+		"violation_count": len(violations),
+		"checked_count":   checked,
+		"conventions":     conventions,
+	}
+}
+
+// markdownExtensionPattern matches a filename ending in one of config.MarkdownExtensions
+func markdownExtensionPattern() string {
+	extensions := make([]string, len(config.MarkdownExtensions))
+	for i, ext := range config.MarkdownExtensions {
+		extensions[i] = regexp.QuoteMeta(strings.TrimPrefix(ext, "."))
+	}
+	return `\.(` + strings.Join(extensions, "|") + `)$`
+}
+
+// suggestName derives a convention-compliant filename that keeps the document's own
+// markdown extension. Suggestions follow the default conventions; a custom regex
+// cannot be inverted into a generator.
+func (cnt *CheckNamingConventionsTool) suggestName(filename, category string, nextADRNumber int) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !config.IsMarkdownFile(filename) {
+		ext = config.MarkdownExtension
+	}
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+
+	if category != config.CategoryADR {
+		return toKebabCase(base) + ext
+	}
+
+	number := nextADRNumber
+	if matches := adrNumberPrefixPattern.FindStringSubmatch(base); matches != nil {
+		number, _ = strconv.Atoi(matches[1])
+		base = matches[2]
+	}
+
+	slug := toKebabCase(base)
+	if slug == "" {
+		slug = "untitled"
+	}
+	return fmt.Sprintf("%03d-%s%s", number, slug, ext)
+}
+
+// nextADRNumber returns one past the highest ADR number currently in the cache
+func (cnt *CheckNamingConventionsTool) nextADRNumber() int {
+	highest := 0
+	for _, doc := range cnt.cache.GetByCategory(config.CategoryADR) {
+		base := filepath.Base(doc.Metadata.Path)
+		if matches := adrNumberPrefixPattern.FindStringSubmatch(base); matches != nil {
+			if number, err := strconv.Atoi(matches[1]); err == nil && number > highest {
+				highest = number
+			}
+		}
+	}
+	return highest + 1
+}
+
+// toKebabCase splits camelCase words, lowercases, and collapses any run of
+// non-alphanumerics into a single hyphen
+func toKebabCase(text string) string {
+	text = camelCaseBoundaryPattern.ReplaceAllString(text, "$1-$2")
+	return strings.Trim(nonAlphanumericPattern.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// newNamingTestTool builds a tool over a cache holding the given path -> category documents
+func newNamingTestTool(t *testing.T, docs map[string]string) *CheckNamingConventionsTool {
+	t.Helper()
+	docCache := cache.NewDocumentCache()
+	for path, category := range docs {
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Path: path, Category: category, Title: path},
+			Content:  models.DocumentContent{RawContent: "# " + path},
+		})
+	}
+	return NewCheckNamingConventionsTool(docCache, logging.NewStructuredLogger("test"))
+}
+
+// violationsByPath runs the tool and indexes its violations by document path
+func violationsByPath(t *testing.T, tool *CheckNamingConventionsTool, arguments map[string]interface{}) (map[string]map[string]interface{}, map[string]interface{}) {
+	t.Helper()
+	result, err := tool.Execute(context.Background(), arguments)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	resultMap := result.(map[string]interface{})
+
+	indexed := make(map[string]map[string]interface{})
+	for _, v := range resultMap["violations"].([]map[string]interface{}) {
+		indexed[v["path"].(string)] = v
+	}
+	return indexed, resultMap
+}
+
+func TestCheckNamingConventionsTool_Name(t *testing.T) {
+	tool := newNamingTestTool(t, nil)
+	if tool.Name() != "check-naming-conventions" {
+		t.Errorf("Expected name check-naming-conventions, got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Description should not be empty")
+	}
+}
+
+func TestCheckNamingConventionsTool_Filenames(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		category      string
+		wantViolation bool
+		wantSuggested string
+	}{
+		{"compliant guideline", "docs/guidelines/api-design.md", config.CategoryGuideline, false, ""},
+		{"compliant pattern", "docs/patterns/repository-pattern.md", config.CategoryPattern, false, ""},
+		{"compliant adr", "docs/adr/001-use-postgres.md", config.CategoryADR, false, ""},
+		{"snake case pattern", "docs/patterns/Repository_Pattern.md", config.CategoryPattern, true, "repository-pattern.md"},
+		{"camel case guideline", "docs/guidelines/ApiDesign.md", config.CategoryGuideline, true, "api-design.md"},
+		{"spaces in guideline", "docs/guidelines/error handling.md", config.CategoryGuideline, true, "error-handling.md"},
+		{"adr with prefix", "docs/adr/adr-7-use-grpc.md", config.CategoryADR, true, "007-use-grpc.md"},
+		{"adr without padding", "docs/adr/12-Event-Sourcing.md", config.CategoryADR, true, "012-event-sourcing.md"},
+		{"adr without number", "docs/adr/use-kafka.md", config.CategoryADR, true, "002-use-kafka.md"},
+		{"compliant markdown guideline", "docs/guidelines/api-design.markdown", config.CategoryGuideline, false, ""},
+		{"compliant mdx adr", "docs/adr/002-use-grpc.mdx", config.CategoryADR, false, ""},
+		{"camel case markdown pattern", "docs/patterns/EventSourcing.markdown", config.CategoryPattern, true, "event-sourcing.markdown"},
+		{"adr with upper case extension", "docs/adr/adr-9-Use-Redis.MD", config.CategoryADR, true, "009-use-redis.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The compliant ADR fixes the next free number at 002
+			tool := newNamingTestTool(t, map[string]string{
+				"docs/adr/001-use-postgres.md": config.CategoryADR,
+				tt.path:                        tt.category,
+			})
+
+			violations, _ := violationsByPath(t, tool, map[string]interface{}{})
+			violation, found := violations[tt.path]
+			if found != tt.wantViolation {
+				t.Fatalf("Violation reported = %v, want %v", found, tt.wantViolation)
+			}
+			if !tt.wantViolation {
+				return
+			}
+			if violation["suggested_name"] != tt.wantSuggested {
+				t.Errorf("suggested_name = %v, want %s", violation["suggested_name"], tt.wantSuggested)
+			}
+			if violation["category"] != tt.category {
+				t.Errorf("category = %v, want %s", violation["category"], tt.category)
+			}
+		})
+	}
+}
+
+func TestCheckNamingConventionsTool_UnnumberedADRsGetDistinctNumbers(t *testing.T) {
+	tool := newNamingTestTool(t, map[string]string{
+		"docs/adr/003-use-go.md":  config.CategoryADR,
+		"docs/adr/use-kafka.md":   config.CategoryADR,
+		"docs/adr/use-redis.md":   config.CategoryADR,
+		"docs/adr/use-grafana.md": config.CategoryADR,
+	})
+
+	violations, _ := violationsByPath(t, tool, map[string]interface{}{})
+	expected := map[string]string{
+		"docs/adr/use-grafana.md": "004-use-grafana.md",
+		"docs/adr/use-kafka.md":   "005-use-kafka.md",
+		"docs/adr/use-redis.md":   "006-use-redis.md",
+	}
+	for path, want := range expected {
+		if got := violations[path]["suggested_name"]; got != want {
+			t.Errorf("suggested_name for %s = %v, want %s", path, got, want)
+		}
+	}
+}
+
+func TestCheckNamingConventionsTool_ResourceTypeFilter(t *testing.T) {
+	tool := newNamingTestTool(t, map[string]string{
+		"docs/patterns/Bad_Pattern.md":     config.CategoryPattern,
+		"docs/guidelines/Bad_Guideline.md": config.CategoryGuideline,
+		"docs/guidelines/good-one.md":      config.CategoryGuideline,
+	})
+
+	violations, result := violationsByPath(t, tool, map[string]interface{}{"resource_type": config.CategoryGuideline})
+	if result["checked_count"] != 2 {
+		t.Errorf("checked_count = %v, want 2", result["checked_count"])
+	}
+	if result["violation_count"] != 1 {
+		t.Errorf("violation_count = %v, want 1", result["violation_count"])
+	}
+	if _, found := violations["docs/patterns/Bad_Pattern.md"]; found {
+		t.Error("Pattern violation should be excluded by resource_type filter")
+	}
+}
+
+func TestCheckNamingConventionsTool_SetConvention(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		wantErr       bool
+		wantViolation bool
+	}{
+		{"custom convention accepts snake case", `^[a-z]+(_[a-z]+)*\.md$`, false, false},
+		{"custom convention rejects snake case", `^[A-Z][a-zA-Z]*\.md$`, false, true},
+		{"invalid regex", `^[a-z+\.md$`, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := newNamingTestTool(t, map[string]string{
+				"docs/patterns/event_sourcing.md": config.CategoryPattern,
+			})
+
+			err := tool.SetConvention(config.CategoryPattern, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetConvention() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			violations, _ := violationsByPath(t, tool, map[string]interface{}{})
+			if _, found := violations["docs/patterns/event_sourcing.md"]; found != tt.wantViolation {
+				t.Errorf("Violation reported = %v, want %v", found, tt.wantViolation)
+			}
+		})
+	}
+}
+
+func TestCheckNamingConventionsTool_UnknownResourceType(t *testing.T) {
+	tool := newNamingTestTool(t, nil)
+	if _, err := tool.Execute(context.Background(), map[string]interface{}{"resource_type": "unknown"}); err == nil {
+		t.Error("Expected error for resource_type without a convention")
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 )
 
-// Tool represents an executable function exposed via MCP
-type Tool interface {
+// ToolInterface describes a tool without executing it, which is all prompt
+// rendering needs to embed a tool reference
+type ToolInterface interface {
 	// Name returns the unique identifier for the tool
 	Name() string
 
@@ -14,6 +15,11 @@ type Tool interface {
 
 	// InputSchema returns JSON schema for tool parameters
 	InputSchema() map[string]interface{}
+}
+
+// Tool represents an executable function exposed via MCP
+type Tool interface {
+	ToolInterface
 
 	// Execute runs the tool with validated arguments
 	// Returns result data or error
@@ -28,7 +34,7 @@ type ToolDefinition struct {
 }
 
 // NewToolDefinition creates a ToolDefinition from a Tool
-func NewToolDefinition(tool Tool) ToolDefinition {
+func NewToolDefinition(tool ToolInterface) ToolDefinition {
 	return ToolDefinition{
 		Name:        tool.Name(),
 		Description: tool.Description(),
@@ -0,0 +1,77 @@
+package tools
+
+import "strings"
+
+// codeOmittedMarker replaces each fenced code block in excerpts when code
+// collapsing is on
+const codeOmittedMarker = "[code omitted]"
+
+// collapsedContent is document content with every fenced code block, fences
+// included, replaced by codeOmittedMarker
+type collapsedContent struct {
+	text   string
+	blocks []collapsedBlock
+}
+
+// collapsedBlock records where a code block was in the original content and where
+// its marker starts in the collapsed text
+type collapsedBlock struct {
+	start, end  int // byte range in the original content
+	markerStart int // offset of the marker in the collapsed text
+}
+
+// collapseCodeBlocks replaces each fenced code block in content with
+// codeOmittedMarker. A block left open runs to the end of the content.
+func collapseCodeBlocks(content string) collapsedContent {
+	var text strings.Builder
+	var blocks []collapsedBlock
+	copied := 0
+	collapse := func(start, end int) {
+		text.WriteString(content[copied:start])
+		blocks = append(blocks, collapsedBlock{start: start, end: end, markerStart: text.Len()})
+		text.WriteString(codeOmittedMarker)
+		copied = end
+	}
+
+	blockStart := -1
+	for lineStart := 0; lineStart <= len(content); {
+		lineEnd := len(content)
+		if i := strings.IndexByte(content[lineStart:], '\n'); i != -1 {
+			lineEnd = lineStart + i
+		}
+
+		if isFenceLine(content[lineStart:lineEnd]) {
+			if blockStart == -1 {
+				blockStart = lineStart
+			} else {
+				collapse(blockStart, lineEnd)
+				blockStart = -1
+			}
+		}
+		lineStart = lineEnd + 1
+	}
+	if blockStart != -1 {
+		collapse(blockStart, len(content))
+	}
+	text.WriteString(content[copied:])
+
+	return collapsedContent{text: text.String(), blocks: blocks}
+}
+
+// position maps an offset in the original content to the collapsed text. Offsets
+// inside a code block map to the start of its marker.
+func (c collapsedContent) position(pos int) int {
+	for _, block := range c.blocks {
+		if pos < block.start {
+			return pos + block.markerStart - block.start
+		}
+		if pos < block.end {
+			return block.markerStart
+		}
+	}
+	if len(c.blocks) == 0 {
+		return pos
+	}
+	last := c.blocks[len(c.blocks)-1]
+	return pos + last.markerStart + len(codeOmittedMarker) - last.end
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollapseCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no code", "Plain text\nover two lines", "Plain text\nover two lines"},
+		{"backtick fence", "Before\n```go\nfunc main() {}\n```\nAfter", "Before\n[code omitted]\nAfter"},
+		{"tilde fence", "Before\n~~~\nSELECT 1;\n~~~\nAfter", "Before\n[code omitted]\nAfter"},
+		{"several blocks", "A\n```\none\n```\nB\n```\ntwo\n```", "A\n[code omitted]\nB\n[code omitted]"},
+		{"unclosed block", "Before\n```\nrest of file\nmore", "Before\n[code omitted]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collapseCodeBlocks(tt.content).text; got != tt.want {
+				t.Errorf("collapseCodeBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapsedContent_Position(t *testing.T) {
+	content := "Before\n```\ncode here\n```\nAfter the block"
+	collapsed := collapseCodeBlocks(content)
+
+	for _, tt := range []struct {
+		name string
+		pos  int
+		want int
+	}{
+		{"before the block", strings.Index(content, "fore"), strings.Index(collapsed.text, "fore")},
+		{"inside the block", strings.Index(content, "code here"), strings.Index(collapsed.text, codeOmittedMarker)},
+		{"after the block", strings.Index(content, "the block"), strings.Index(collapsed.text, "the block")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collapsed.position(tt.pos); got != tt.want {
+				t.Errorf("position(%d) = %d, want %d", tt.pos, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
@@ -36,6 +37,11 @@ const (
 	MaxQueryLength       = 500   // 500 chars - maximum search query length
 	MaxDescriptionLength = 5000  // 5KB - maximum decision description length
 	MaxSearchResults     = 20    // Maximum number of search results to return
+	MaxExcerptsPerResult = 5     // Maximum excerpts returned per search result
+
+	// DefaultSanitizeLength is how many characters of a string argument are kept in
+	// logs and audit records before it is truncated
+	DefaultSanitizeLength = 100
 
 	// DefaultSessionTTL is the default time-to-live for workflow sessions (1 hour)
 	// Sessions are automatically cleaned up after this duration of inactivity
@@ -82,12 +88,21 @@ type WorkflowContext struct {
 type ToolExecutor struct {
 	maxExecutionTime time.Duration
 	logger           *logging.StructuredLogger
-	timeoutCallback  func() // Callback to notify manager of timeouts
+	timeoutCallback  func()       // Callback to notify manager of timeouts
+	audit            *AuditLogger // nil disables audit records
+
+	// Argument sanitization for logs and audit records
+	sanitizeLength int
+	redactedFields map[string]bool // lowercased field names
 
 	// Session-based context storage for workflow state management
 	sessions   map[string]*WorkflowContext
 	sessionsMu sync.RWMutex
 	sessionTTL time.Duration
+
+	// stopCleanup ends the session cleanup goroutine
+	stopCleanup chan struct{}
+	closeOnce   sync.Once
 }
 
 // NewToolExecutor creates a new ToolExecutor with default settings
@@ -95,8 +110,11 @@ func NewToolExecutor(logger *logging.StructuredLogger) *ToolExecutor {
 	executor := &ToolExecutor{
 		maxExecutionTime: DefaultToolTimeout,
 		logger:           logger,
+		sanitizeLength:   DefaultSanitizeLength,
+		redactedFields:   redactedFieldSet(DefaultRedactedFields),
 		sessions:         make(map[string]*WorkflowContext),
 		sessionTTL:       DefaultSessionTTL,
+		stopCleanup:      make(chan struct{}),
 	}
 
 	// Start background cleanup goroutine for expired sessions
@@ -105,13 +123,90 @@ func NewToolExecutor(logger *logging.StructuredLogger) *ToolExecutor {
 	return executor
 }
 
+// Close stops the background session cleanup. Safe to call more than once.
+func (te *ToolExecutor) Close() {
+	te.closeOnce.Do(func() { close(te.stopCleanup) })
+}
+
 // SetTimeoutCallback sets a callback function to be called when a timeout occurs
 func (te *ToolExecutor) SetTimeoutCallback(callback func()) {
 	te.timeoutCallback = callback
 }
 
-// Execute validates arguments and executes a tool with timeout protection
-func (te *ToolExecutor) Execute(ctx context.Context, tool Tool, arguments map[string]interface{}) (interface{}, error) {
+// DefaultRedactedFields are the argument names whose values are never logged or
+// audited. Matching ignores case.
+var DefaultRedactedFields = []string{"token", "secret", "password", "api_key"}
+
+// redactedValue replaces the value of a redacted field
+const redactedValue = "[redacted]"
+
+// SetSanitizeLength changes how many characters of a string argument are kept in
+// logs and audit records before it is truncated
+func (te *ToolExecutor) SetSanitizeLength(chars int) error {
+	if chars < 1 {
+		return fmt.Errorf("sanitize length must be at least 1 character, got %d", chars)
+	}
+	te.sanitizeLength = chars
+	return nil
+}
+
+// SetRedactedFields replaces the argument names whose values are logged and audited
+// as "[redacted]", at any nesting depth. Matching ignores case; an empty list
+// turns redaction off.
+func (te *ToolExecutor) SetRedactedFields(fields []string) {
+	te.redactedFields = redactedFieldSet(fields)
+}
+
+// redactedFieldSet builds a lowercased set of field names
+func redactedFieldSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			set[field] = true
+		}
+	}
+	return set
+}
+
+// SetAuditLogger enables an audit record for every tool invocation; nil disables them
+func (te *ToolExecutor) SetAuditLogger(audit *AuditLogger) {
+	te.audit = audit
+}
+
+// recordAudit writes an audit record for an invocation of toolName when auditing
+// is enabled. A failed write is logged but does not fail the invocation.
+func (te *ToolExecutor) recordAudit(ctx context.Context, toolName string, arguments map[string]interface{}, startTime time.Time, err error) {
+	if te.audit == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Timestamp:  startTime.UTC(),
+		Tool:       toolName,
+		SessionID:  sessionIDFromContext(ctx),
+		Arguments:  te.sanitizeArguments(arguments),
+		DurationMs: time.Since(startTime).Milliseconds(),
+		Success:    err == nil,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if auditErr := te.audit.Record(record); auditErr != nil {
+		te.logger.WithContext("tool", toolName).
+			WithError(auditErr).
+			Error("Failed to write tool audit record")
+	}
+}
+
+// Execute validates arguments and executes a tool with timeout protection. The
+// invocation is audited under the session from ContextWithSessionID, if any.
+func (te *ToolExecutor) Execute(ctx context.Context, tool Tool, arguments map[string]interface{}) (result interface{}, err error) {
+	startTime := time.Now()
+	defer func() {
+		te.recordAudit(ctx, tool.Name(), arguments, startTime, err)
+	}()
+
 	// Validate arguments against schema
 	if err := te.ValidateArguments(tool, arguments); err != nil {
 		te.logger.WithContext("tool", tool.Name()).
@@ -121,7 +216,7 @@ func (te *ToolExecutor) Execute(ctx context.Context, tool Tool, arguments map[st
 	}
 
 	// Create timeout context
-	ctx, cancel := context.WithTimeout(ctx, te.maxExecutionTime)
+	execCtx, cancel := context.WithTimeout(ctx, te.maxExecutionTime)
 	defer cancel()
 
 	// Log execution (with sanitized arguments)
@@ -133,11 +228,11 @@ func (te *ToolExecutor) Execute(ctx context.Context, tool Tool, arguments map[st
 	logger.Info("Executing tool")
 
 	// Execute tool
-	result, err := tool.Execute(ctx, arguments)
+	result, err = tool.Execute(execCtx, arguments)
 
 	if err != nil {
 		// Check if timeout occurred
-		if ctx.Err() == context.DeadlineExceeded {
+		if execCtx.Err() == context.DeadlineExceeded {
 			te.logger.WithContext("tool", tool.Name()).
 				WithContext("timeout", te.maxExecutionTime.String()).
 				Error("Tool execution timeout")
@@ -173,17 +268,8 @@ func (te *ToolExecutor) ValidateArguments(tool Tool, arguments map[string]interf
 		return nil // No schema means no validation required
 	}
 
-	// Get required fields
-	required, _ := schema["required"].([]interface{})
-	requiredFields := make(map[string]bool)
-	for _, field := range required {
-		if fieldName, ok := field.(string); ok {
-			requiredFields[fieldName] = true
-		}
-	}
-
 	// Check required fields are present
-	for fieldName := range requiredFields {
+	for _, fieldName := range requiredFields(schema) {
 		if _, exists := arguments[fieldName]; !exists {
 			return errors.NewValidationError(
 				errors.ErrCodeInvalidParams,
@@ -206,6 +292,49 @@ func (te *ToolExecutor) ValidateArguments(tool Tool, arguments map[string]interf
 	return nil
 }
 
+// invalidArgument reports an argument a tool rejects beyond what its schema checks.
+// It is a validation error, so tools/call answers it as invalid params rather than
+// as a failed execution.
+func invalidArgument(format string, args ...interface{}) error {
+	return errors.NewValidationError(errors.ErrCodeInvalidParams, fmt.Sprintf(format, args...), nil)
+}
+
+// requiredFields returns the schema's required field names
+func requiredFields(schema map[string]interface{}) []string {
+	fields, _ := schemaStrings(schema["required"])
+	return fields
+}
+
+// schemaStrings reads a list of strings from a schema keyword. Tools declare lists
+// as []string, while schemas decoded from JSON carry []interface{}; both are accepted.
+func schemaStrings(value interface{}) ([]string, bool) {
+	switch list := value.(type) {
+	case []string:
+		return list, true
+	case []interface{}:
+		strs := make([]string, 0, len(list))
+		for _, item := range list {
+			if str, ok := item.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs, true
+	}
+	return nil, false
+}
+
+// schemaInt reads a numeric schema keyword declared either as a Go int or as a
+// float64 decoded from JSON
+func schemaInt(schemaMap map[string]interface{}, key string) (int64, bool) {
+	switch v := schemaMap[key].(type) {
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
 // validateField validates a single field against its schema
 func (te *ToolExecutor) validateField(fieldName string, value interface{}, schema interface{}) error {
 	schemaMap, ok := schema.(map[string]interface{})
@@ -213,6 +342,10 @@ func (te *ToolExecutor) validateField(fieldName string, value interface{}, schem
 		return nil
 	}
 
+	if variants, ok := schemaMap["oneOf"].([]interface{}); ok {
+		return te.validateOneOf(fieldName, value, variants)
+	}
+
 	fieldType, _ := schemaMap["type"].(string)
 
 	switch fieldType {
@@ -220,6 +353,88 @@ func (te *ToolExecutor) validateField(fieldName string, value interface{}, schem
 		return te.validateStringField(fieldName, value, schemaMap)
 	case "integer":
 		return te.validateIntegerField(fieldName, value, schemaMap)
+	case "array":
+		return te.validateArrayField(fieldName, value, schemaMap)
+	}
+
+	return nil
+}
+
+// validateOneOf accepts a value matching any of the variants. The variants used by
+// the tools differ by type, so when none match, the error from the variant of the
+// value's own type is the most useful one to report.
+func (te *ToolExecutor) validateOneOf(fieldName string, value interface{}, variants []interface{}) error {
+	var typeMatchErr error
+	for _, variant := range variants {
+		err := te.validateField(fieldName, value, variant)
+		if err == nil {
+			return nil
+		}
+
+		if variantMap, ok := variant.(map[string]interface{}); ok && variantMap["type"] == jsonType(value) {
+			typeMatchErr = err
+		}
+	}
+
+	if typeMatchErr != nil {
+		return typeMatchErr
+	}
+
+	return errors.NewValidationError(
+		errors.ErrCodeInvalidParams,
+		fmt.Sprintf("field %s has an unsupported type", fieldName),
+		nil,
+	)
+}
+
+// jsonType returns the JSON Schema type name of a decoded argument value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int, int64, float64:
+		return "integer"
+	case []interface{}, []string:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case bool:
+		return "boolean"
+	}
+	return ""
+}
+
+func (te *ToolExecutor) validateArrayField(fieldName string, value interface{}, schemaMap map[string]interface{}) error {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case []string:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	default:
+		return errors.NewValidationError(
+			errors.ErrCodeInvalidParams,
+			fmt.Sprintf("field %s must be an array", fieldName),
+			nil,
+		)
+	}
+
+	if minItems, exists := schemaInt(schemaMap, "minItems"); exists && int64(len(items)) < minItems {
+		return errors.NewValidationError(
+			errors.ErrCodeInvalidParams,
+			fmt.Sprintf("field %s must contain at least %d items", fieldName, minItems),
+			nil,
+		)
+	}
+
+	if itemSchema, exists := schemaMap["items"]; exists {
+		for i, item := range items {
+			if err := te.validateField(fmt.Sprintf("%s[%d]", fieldName, i), item, itemSchema); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -236,33 +451,33 @@ func (te *ToolExecutor) validateStringField(fieldName string, value interface{},
 	}
 
 	// Check maxLength
-	if maxLength, exists := schemaMap["maxLength"].(float64); exists {
-		if len(strValue) > int(maxLength) {
+	if maxLength, exists := schemaInt(schemaMap, "maxLength"); exists {
+		if int64(len(strValue)) > maxLength {
 			return errors.NewValidationError(
 				errors.ErrCodeInvalidParams,
-				fmt.Sprintf("field %s exceeds maximum length of %d", fieldName, int(maxLength)),
+				fmt.Sprintf("field %s exceeds maximum length of %d", fieldName, maxLength),
 				nil,
 			)
 		}
 	}
 
 	// Check enum values
-	if enumValues, exists := schemaMap["enum"].([]interface{}); exists {
+	if enumValues, exists := schemaStrings(schemaMap["enum"]); exists {
 		return te.validateEnumValue(fieldName, strValue, enumValues)
 	}
 
 	return nil
 }
 
-func (te *ToolExecutor) validateEnumValue(fieldName, strValue string, enumValues []interface{}) error {
+func (te *ToolExecutor) validateEnumValue(fieldName, strValue string, enumValues []string) error {
 	for _, enumValue := range enumValues {
-		if enumStr, ok := enumValue.(string); ok && enumStr == strValue {
+		if enumValue == strValue {
 			return nil
 		}
 	}
 	return errors.NewValidationError(
 		errors.ErrCodeInvalidParams,
-		fmt.Sprintf("field %s has invalid value, must be one of allowed values", fieldName),
+		fmt.Sprintf("field %s has invalid value %q, must be one of: %s", fieldName, strValue, strings.Join(enumValues, ", ")),
 		nil,
 	)
 }
@@ -278,22 +493,22 @@ func (te *ToolExecutor) validateIntegerField(fieldName string, value interface{}
 	}
 
 	// Check minimum
-	if minimum, exists := schemaMap["minimum"].(float64); exists {
-		if intValue < int64(minimum) {
+	if minimum, exists := schemaInt(schemaMap, "minimum"); exists {
+		if intValue < minimum {
 			return errors.NewValidationError(
 				errors.ErrCodeInvalidParams,
-				fmt.Sprintf("field %s must be at least %d", fieldName, int64(minimum)),
+				fmt.Sprintf("field %s must be at least %d", fieldName, minimum),
 				nil,
 			)
 		}
 	}
 
 	// Check maximum
-	if maximum, exists := schemaMap["maximum"].(float64); exists {
-		if intValue > int64(maximum) {
+	if maximum, exists := schemaInt(schemaMap, "maximum"); exists {
+		if intValue > maximum {
 			return errors.NewValidationError(
 				errors.ErrCodeInvalidParams,
-				fmt.Sprintf("field %s must be at most %d", fieldName, int64(maximum)),
+				fmt.Sprintf("field %s must be at most %d", fieldName, maximum),
 				nil,
 			)
 		}
@@ -357,32 +572,54 @@ func ValidateResourcePath(path string) error {
 	return nil
 }
 
-// sanitizeArguments sanitizes arguments for logging by truncating large values.
+// sanitizeArguments sanitizes arguments for logging and audit records.
 //
 // Security: This function prevents sensitive data exposure in logs by:
-// 1. Truncating string values longer than 100 characters
-// 2. Showing only a preview with the total length
-// 3. Preventing large code blocks or descriptions from filling logs
+// 1. Replacing the values of redacted fields (see SetRedactedFields) with "[redacted]"
+// 2. Truncating string values longer than the sanitize length (see SetSanitizeLength)
+// 3. Showing only a preview with the total length
+// 4. Preventing large code blocks or descriptions from filling logs
+//
+// Nested objects and arrays are sanitized the same way.
 //
 // This is important because:
 // - Tool arguments may contain sensitive code or business logic
 // - Large arguments can make logs difficult to read and analyze
 // - Log aggregation systems may have size limits
 func (te *ToolExecutor) sanitizeArguments(arguments map[string]interface{}) map[string]interface{} {
-	const maxLogLength = 100
-
-	sanitized := make(map[string]interface{})
+	sanitized := make(map[string]interface{}, len(arguments))
 	for key, value := range arguments {
-		if strValue, ok := value.(string); ok && len(strValue) > maxLogLength {
-			// Truncate and show length for large strings
-			sanitized[key] = fmt.Sprintf("%s... [%d chars]", strValue[:maxLogLength], len(strValue))
-		} else {
-			sanitized[key] = value
+		if te.redactedFields[strings.ToLower(key)] {
+			sanitized[key] = redactedValue
+			continue
 		}
+		sanitized[key] = te.sanitizeValue(value)
 	}
 	return sanitized
 }
 
+// sanitizeValue truncates long strings and sanitizes nested objects and arrays
+func (te *ToolExecutor) sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if n := utf8.RuneCountInString(v); n > te.sanitizeLength {
+			// Truncate on a character boundary and show length for large strings
+			return fmt.Sprintf("%s... [%d chars]", string([]rune(v)[:te.sanitizeLength]), n)
+		}
+		return v
+	case map[string]interface{}:
+		return te.sanitizeArguments(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = te.sanitizeValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
 // ExecuteWithContext executes a tool within a workflow context, allowing access to
 // prompt arguments and previous tool results.
 //
@@ -408,6 +645,9 @@ func (te *ToolExecutor) ExecuteWithContext(ctx context.Context, tool Tool, argum
 	}
 
 	// Execute the tool (validation and execution logic is the same)
+	if workflowCtx != nil {
+		ctx = ContextWithSessionID(ctx, workflowCtx.SessionID)
+	}
 	result, err := te.Execute(ctx, tool, arguments)
 
 	// Store result in workflow context if execution succeeded
@@ -490,12 +730,18 @@ func (te *ToolExecutor) DeleteSession(sessionID string) {
 // been accessed within the TTL period.
 //
 // This prevents memory leaks from abandoned workflows and ensures resources are
-// freed for sessions that are no longer active.
+// freed for sessions that are no longer active. It returns once Close is called.
 func (te *ToolExecutor) cleanupExpiredSessions() {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ticker.C:
+		case <-te.stopCleanup:
+			return
+		}
+
 		now := time.Now()
 		expiredSessions := []string{}
 
@@ -3,9 +3,12 @@ package tools
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/logging"
 )
 
@@ -278,6 +281,71 @@ func TestToolExecutor_Execute(t *testing.T) {
 	})
 }
 
+func TestToolExecutor_RequiredFieldsFromToolSchemas(t *testing.T) {
+	logger := logging.NewStructuredLogger("test")
+	executor := NewToolExecutor(logger)
+	docCache := cache.NewDocumentCache()
+
+	tests := []struct {
+		name         string
+		tool         Tool
+		arguments    map[string]interface{}
+		missingField string
+	}{
+		{
+			name:         "search without query",
+			tool:         NewSearchArchitectureTool(docCache, logger),
+			arguments:    map[string]interface{}{"max_results": 5},
+			missingField: "query",
+		},
+		{
+			name:         "alignment without decision_description",
+			tool:         NewCheckADRAlignmentTool(docCache, logger),
+			arguments:    map[string]interface{}{"decision_context": "current REST API"},
+			missingField: "decision_description",
+		},
+		{
+			name:         "validation without code",
+			tool:         NewValidatePatternTool(docCache, logger),
+			arguments:    map[string]interface{}{"pattern_name": "repository-pattern"},
+			missingField: "code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Execute(context.Background(), tt.tool, tt.arguments)
+			if err == nil {
+				t.Fatal("Expected missing required argument to fail validation")
+			}
+			if !strings.Contains(err.Error(), "missing required argument: "+tt.missingField) {
+				t.Errorf("Expected missing %s error, got: %v", tt.missingField, err)
+			}
+		})
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		expected []string
+	}{
+		{"string slice", map[string]interface{}{"required": []string{"code", "pattern_name"}}, []string{"code", "pattern_name"}},
+		{"decoded JSON", map[string]interface{}{"required": []interface{}{"query", 42}}, []string{"query"}},
+		{"absent", map[string]interface{}{"type": "object"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requiredFields(tt.schema)
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("requiredFields() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestToolExecutor_SanitizeArguments(t *testing.T) {
 	logger := logging.NewStructuredLogger("test")
 	executor := NewToolExecutor(logger)
@@ -332,6 +400,84 @@ func TestToolExecutor_SanitizeArguments(t *testing.T) {
 	})
 }
 
+func TestToolExecutor_SanitizeArgumentsConfigurable(t *testing.T) {
+	logger := logging.NewStructuredLogger("test")
+
+	tests := []struct {
+		name           string
+		sanitizeLength int
+		redactedFields []string
+		arguments      map[string]interface{}
+		expected       map[string]interface{}
+	}{
+		{
+			name:      "default redaction ignores case",
+			arguments: map[string]interface{}{"Token": "abc123", "API_KEY": "k", "query": "api"},
+			expected:  map[string]interface{}{"Token": "[redacted]", "API_KEY": "[redacted]", "query": "api"},
+		},
+		{
+			name:           "custom truncation length",
+			sanitizeLength: 5,
+			arguments:      map[string]interface{}{"code": "package main", "lang": "go"},
+			expected:       map[string]interface{}{"code": "packa... [12 chars]", "lang": "go"},
+		},
+		{
+			name:           "truncation counts characters, not bytes",
+			sanitizeLength: 4,
+			arguments:      map[string]interface{}{"title": "Größenänderung"},
+			expected:       map[string]interface{}{"title": "Größ... [14 chars]"},
+		},
+		{
+			name:           "custom redaction list replaces defaults",
+			redactedFields: []string{"code", " Client_ID "},
+			arguments:      map[string]interface{}{"code": "x", "client_id": "c", "token": "t"},
+			expected:       map[string]interface{}{"code": "[redacted]", "client_id": "[redacted]", "token": "t"},
+		},
+		{
+			name:           "empty redaction list disables redaction",
+			redactedFields: []string{},
+			arguments:      map[string]interface{}{"secret": "s"},
+			expected:       map[string]interface{}{"secret": "s"},
+		},
+		{
+			name:           "nested fields are sanitized",
+			sanitizeLength: 3,
+			arguments: map[string]interface{}{
+				"auth":  map[string]interface{}{"password": "hunter2", "user": "alice"},
+				"items": []interface{}{"abcdef", map[string]interface{}{"secret": "s"}, 7},
+			},
+			expected: map[string]interface{}{
+				"auth":  map[string]interface{}{"password": "[redacted]", "user": "ali... [5 chars]"},
+				"items": []interface{}{"abc... [6 chars]", map[string]interface{}{"secret": "[redacted]"}, 7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewToolExecutor(logger)
+			if tt.sanitizeLength > 0 {
+				if err := executor.SetSanitizeLength(tt.sanitizeLength); err != nil {
+					t.Fatalf("SetSanitizeLength failed: %v", err)
+				}
+			}
+			if tt.redactedFields != nil {
+				executor.SetRedactedFields(tt.redactedFields)
+			}
+
+			sanitized := executor.sanitizeArguments(tt.arguments)
+			if !reflect.DeepEqual(sanitized, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, sanitized)
+			}
+		})
+	}
+
+	executor := NewToolExecutor(logger)
+	if err := executor.SetSanitizeLength(0); err == nil {
+		t.Error("Expected an error for a sanitize length below 1")
+	}
+}
+
 // mockToolForExecutor is a mock tool for executor tests
 type mockToolForExecutor struct {
 	name        string
@@ -357,3 +503,17 @@ func (m *mockToolForExecutor) Execute(ctx context.Context, arguments map[string]
 	}
 	return nil, nil
 }
+
+func TestToolExecutor_Close(t *testing.T) {
+	executor := NewToolExecutor(logging.NewStructuredLogger("test"))
+
+	executor.Close()
+	// Closing again is a no-op
+	executor.Close()
+
+	select {
+	case <-executor.stopCleanup:
+	default:
+		t.Error("Expected Close to stop the session cleanup")
+	}
+}
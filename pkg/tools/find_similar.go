@@ -0,0 +1,331 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+const (
+	// similarityKeyTermCount is how many TF-IDF terms represent the source document;
+	// enough to capture its topic without dragging in incidental vocabulary
+	similarityKeyTermCount = 10
+	defaultSimilarResults  = 5
+)
+
+// FindSimilarTool finds documents similar to a given document ("more like this")
+type FindSimilarTool struct {
+	cache  *cache.DocumentCache
+	logger *logging.StructuredLogger
+	search *SearchArchitectureTool // reused for tokenization, scoring and excerpts
+
+	// related caches RelatedDocuments results for one document cache version
+	relatedMutex sync.Mutex
+	related      *relatedCorpus
+}
+
+// relatedCorpus holds what RelatedDocuments computed at one document cache version:
+// the documents and their term statistics, shared by every lookup, and the
+// related documents found per source path and count
+type relatedCorpus struct {
+	version           uint64
+	allDocs           map[string]*models.Document
+	documentFrequency map[string]int
+	related           map[string][]*models.Document
+}
+
+// NewFindSimilarTool creates a new FindSimilarTool instance
+func NewFindSimilarTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *FindSimilarTool {
+	return &FindSimilarTool{
+		cache:  cache,
+		logger: logger,
+		search: NewSearchArchitectureTool(cache, logger),
+	}
+}
+
+// Name returns the unique identifier for the tool
+func (fst *FindSimilarTool) Name() string {
+	return "find-similar"
+}
+
+// Description returns a human-readable description
+func (fst *FindSimilarTool) Description() string {
+	return "Finds documents similar to a given document by extracting its key terms and ranking the rest of the documentation against them"
+}
+
+// InputSchema returns JSON schema for tool parameters
+func (fst *FindSimilarTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"uri": map[string]interface{}{
+				"type":        "string",
+				"description": "URI of the source document (e.g., 'architecture://patterns/repository-pattern')",
+			},
+			"max_results": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     1,
+				"maximum":     MaxSearchResults,
+				"description": fmt.Sprintf("Maximum similar documents to return (default: %d)", defaultSimilarResults),
+			},
+		},
+		"required": []string{"uri"},
+	}
+}
+
+// Execute runs the tool with validated arguments
+func (fst *FindSimilarTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	uri, ok := arguments["uri"].(string)
+	if !ok || uri == "" {
+		return nil, invalidArgument("uri argument must be a non-empty string")
+	}
+
+	maxResults := defaultSimilarResults
+	if mr, ok := arguments["max_results"].(float64); ok {
+		maxResults = int(mr)
+	} else if mr, ok := arguments["max_results"].(int); ok {
+		maxResults = mr
+	}
+
+	if maxResults < 1 || maxResults > MaxSearchResults {
+		return nil, invalidArgument("max_results must be between 1 and %d", MaxSearchResults)
+	}
+
+	allDocs := fst.cache.GetAllDocuments()
+	sourcePath, source := fst.findSource(uri, allDocs)
+	if source == nil {
+		return nil, fmt.Errorf("document not found: %s", uri)
+	}
+
+	fst.logger.WithContext("uri", uri).
+		WithContext("max_results", maxResults).
+		Info("Finding similar documents")
+
+	keyTerms := fst.extractKeyTerms(sourcePath, allDocs)
+	return fst.rankSimilar(sourcePath, keyTerms, allDocs, maxResults), nil
+}
+
+// RelatedDocuments returns up to count documents most similar to the document
+// stored under path, best first, using the same scoring as Execute. Results are
+// cached until a document is added, changed or removed, so repeated lookups are
+// cheap. Unknown paths have no related documents.
+func (fst *FindSimilarTool) RelatedDocuments(path string, count int) []*models.Document {
+	if count < 1 {
+		return nil
+	}
+
+	fst.relatedMutex.Lock()
+	defer fst.relatedMutex.Unlock()
+
+	version := fst.cache.Version()
+	if fst.related == nil || fst.related.version != version {
+		allDocs := fst.cache.GetAllDocuments()
+		fst.related = &relatedCorpus{
+			version:           version,
+			allDocs:           allDocs,
+			documentFrequency: fst.documentFrequencies(allDocs),
+			related:           make(map[string][]*models.Document),
+		}
+	}
+
+	corpus := fst.related
+	path = filepath.ToSlash(filepath.Clean(path))
+	key := fmt.Sprintf("%s|%d", path, count)
+	if related, ok := corpus.related[key]; ok {
+		return related
+	}
+
+	source, ok := corpus.allDocs[path]
+	if !ok {
+		return nil
+	}
+
+	keyTerms := fst.keyTerms(fst.terms(source.Content.RawContent), corpus.documentFrequency, len(corpus.allDocs))
+	results := fst.scoreSimilar(path, keyTerms, corpus.allDocs)
+	if len(results) > count {
+		results = results[:count]
+	}
+
+	related := make([]*models.Document, 0, len(results))
+	for _, result := range results {
+		related = append(related, corpus.allDocs[result.path])
+	}
+	corpus.related[key] = related
+	return related
+}
+
+// findSource resolves a URI in the format returned by search-architecture. ADRs may
+// also be addressed by number alone, as resources/list does.
+func (fst *FindSimilarTool) findSource(uri string, allDocs map[string]*models.Document) (string, *models.Document) {
+	for path, doc := range allDocs {
+		if fst.search.generateURI(doc.Metadata.Category, path) == uri {
+			return path, doc
+		}
+	}
+
+	adrPrefix := config.URIScheme + config.URIADR + "/"
+	if adrID := strings.TrimPrefix(uri, adrPrefix); adrID != uri && adrID != "" {
+		for path, doc := range allDocs {
+			filename := filepath.Base(path)
+			if doc.Metadata.Category == config.CategoryADR && strings.HasPrefix(filename, adrID+"-") {
+				return path, doc
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// extractKeyTerms returns the source document's highest TF-IDF terms over the corpus
+func (fst *FindSimilarTool) extractKeyTerms(sourcePath string, allDocs map[string]*models.Document) []string {
+	var sourceTerms []string
+	if source, ok := allDocs[sourcePath]; ok {
+		sourceTerms = fst.terms(source.Content.RawContent)
+	}
+	return fst.keyTerms(sourceTerms, fst.documentFrequencies(allDocs), len(allDocs))
+}
+
+// documentFrequencies counts the documents each term appears in
+func (fst *FindSimilarTool) documentFrequencies(allDocs map[string]*models.Document) map[string]int {
+	documentFrequency := make(map[string]int)
+	for _, doc := range allDocs {
+		seen := make(map[string]bool)
+		for _, term := range fst.terms(doc.Content.RawContent) {
+			if !seen[term] {
+				seen[term] = true
+				documentFrequency[term]++
+			}
+		}
+	}
+	return documentFrequency
+}
+
+// keyTerms returns the highest TF-IDF terms of sourceTerms in a corpus of
+// corpusSize documents with the given document frequencies
+func (fst *FindSimilarTool) keyTerms(sourceTerms []string, documentFrequency map[string]int, corpusSize int) []string {
+	termFrequency := make(map[string]int)
+	for _, term := range sourceTerms {
+		termFrequency[term]++
+	}
+
+	type weightedTerm struct {
+		term   string
+		weight float64
+	}
+	var weighted []weightedTerm
+	for term, tf := range termFrequency {
+		// Terms found in every document carry no signal and get zero weight
+		idf := math.Log(float64(corpusSize) / float64(documentFrequency[term]))
+		if idf > 0 {
+			weighted = append(weighted, weightedTerm{term: term, weight: float64(tf) * idf})
+		}
+	}
+
+	sort.Slice(weighted, func(i, j int) bool {
+		if weighted[i].weight != weighted[j].weight {
+			return weighted[i].weight > weighted[j].weight
+		}
+		return weighted[i].term < weighted[j].term
+	})
+
+	if len(weighted) > similarityKeyTermCount {
+		weighted = weighted[:similarityKeyTermCount]
+	}
+
+	keyTerms := make([]string, 0, len(weighted))
+	for _, w := range weighted {
+		keyTerms = append(keyTerms, w.term)
+	}
+	return keyTerms
+}
+
+// terms tokenizes content and drops markdown punctuation and very short words
+func (fst *FindSimilarTool) terms(content string) []string {
+	var terms []string
+	for _, token := range fst.search.tokenize(content) {
+		token = strings.TrimFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(token) >= 3 && strings.IndexFunc(token, unicode.IsLetter) != -1 {
+			terms = append(terms, token)
+		}
+	}
+	return terms
+}
+
+// similarDocument is another document's similarity to the source document
+type similarDocument struct {
+	path  string
+	uri   string
+	score float64
+}
+
+// scoreSimilar scores every other document against the key terms using search
+// scoring, best first, leaving out documents that share none of them
+func (fst *FindSimilarTool) scoreSimilar(sourcePath string, keyTerms []string, allDocs map[string]*models.Document) []similarDocument {
+	var results []similarDocument
+	for path, doc := range allDocs {
+		if path == sourcePath {
+			continue
+		}
+
+		score := fst.search.calculateRelevance(keyTerms, doc.Content.RawContent, doc.Metadata.Title, doc.Metadata.Path)
+		if score <= 0 {
+			continue
+		}
+
+		results = append(results, similarDocument{
+			path:  path,
+			uri:   fst.search.generateURI(doc.Metadata.Category, path),
+			score: score,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].uri < results[j].uri
+	})
+	return results
+}
+
+// rankSimilar returns the tool output for the documents most similar to the source
+func (fst *FindSimilarTool) rankSimilar(sourcePath string, keyTerms []string, allDocs map[string]*models.Document, maxResults int) map[string]interface{} {
+	results := fst.scoreSimilar(sourcePath, keyTerms, allDocs)
+
+	totalMatches := len(results)
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+
+	// Excerpts are only extracted for the documents returned
+	resultList := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		doc := allDocs[result.path]
+		resultList = append(resultList, map[string]interface{}{
+			"uri":              result.uri,
+			"title":            doc.Metadata.Title,
+			"resource_type":    doc.Metadata.Category,
+			"similarity_score": result.score,
+			"excerpt":          fst.search.extractExcerpt(doc.Content.RawContent, keyTerms, ExcerptUnitChars),
+		})
+	}
+
+	return map[string]interface{}{
+		"source_uri":    fst.search.generateURI(allDocs[sourcePath].Metadata.Category, sourcePath),
+		"key_terms":     keyTerms,
+		"results":       resultList,
+		"total_matches": totalMatches,
+	}
+}
@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// newSimilarityTestTool builds a tool over a small corpus where the persistence
+// patterns share vocabulary and the other documents do not
+func newSimilarityTestTool(t *testing.T) *FindSimilarTool {
+	t.Helper()
+	docs := []struct {
+		path, category, title, content string
+	}{
+		{
+			"mcp/resources/patterns/repository-pattern.md", config.CategoryPattern, "Repository Pattern",
+			"# Repository Pattern\n\nThe repository mediates between the domain and persistence layers. " +
+				"Each aggregate gets one repository that hides database queries and transaction handling.",
+		},
+		{
+			"mcp/resources/patterns/unit-of-work.md", config.CategoryPattern, "Unit of Work",
+			"# Unit of Work\n\nA unit of work tracks changes to each aggregate and commits them to persistence " +
+				"in one database transaction. It is usually paired with a repository per aggregate.",
+		},
+		{
+			"mcp/resources/patterns/circuit-breaker.md", config.CategoryPattern, "Circuit Breaker",
+			"# Circuit Breaker\n\nA circuit breaker stops calls to a failing remote service and retries after a cooldown.",
+		},
+		{
+			"mcp/resources/guidelines/logging.md", config.CategoryGuideline, "Logging",
+			"# Logging\n\nEmit structured JSON logs with request identifiers and avoid logging secrets.",
+		},
+		{
+			"mcp/resources/adr/001-use-kafka.md", config.CategoryADR, "Use Kafka",
+			"# ADR 001: Use Kafka\n\nWe publish domain events to Kafka topics for asynchronous integration.",
+		},
+	}
+
+	docCache := cache.NewDocumentCache()
+	for _, d := range docs {
+		docCache.Set(d.path, &models.Document{
+			Metadata: models.DocumentMetadata{Path: d.path, Category: d.category, Title: d.title},
+			Content:  models.DocumentContent{RawContent: d.content},
+		})
+	}
+	return NewFindSimilarTool(docCache, logging.NewStructuredLogger("test"))
+}
+
+func TestFindSimilarTool_Name(t *testing.T) {
+	tool := NewFindSimilarTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+	if tool.Name() != "find-similar" {
+		t.Errorf("Expected name find-similar, got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Description should not be empty")
+	}
+}
+
+func TestFindSimilarTool_PatternSharingTerminologyRanksFirst(t *testing.T) {
+	tool := newSimilarityTestTool(t)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"uri": "architecture://patterns/repository-pattern",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	resultMap := result.(map[string]interface{})
+	results := resultMap["results"].([]map[string]interface{})
+	if len(results) == 0 {
+		t.Fatal("Expected similar documents")
+	}
+
+	if results[0]["uri"] != "architecture://patterns/unit-of-work" {
+		t.Errorf("Expected unit-of-work to be most similar, got %v", results[0]["uri"])
+	}
+	for _, r := range results {
+		if r["uri"] == "architecture://patterns/repository-pattern" {
+			t.Error("Source document must be excluded from results")
+		}
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1]["similarity_score"].(float64) < results[i]["similarity_score"].(float64) {
+			t.Error("Results not sorted by similarity score")
+		}
+	}
+
+	keyTerms := resultMap["key_terms"].([]string)
+	found := false
+	for _, term := range keyTerms {
+		if term == "repository" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected 'repository' among key terms, got %v", keyTerms)
+	}
+}
+
+func TestFindSimilarTool_ADRByNumber(t *testing.T) {
+	tool := newSimilarityTestTool(t)
+
+	tests := []struct {
+		name string
+		uri  string
+	}{
+		{"full filename", "architecture://adr/001-use-kafka"},
+		{"number only", "architecture://adr/001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), map[string]interface{}{"uri": tt.uri})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if source := result.(map[string]interface{})["source_uri"]; source != "architecture://adr/001-use-kafka" {
+				t.Errorf("Expected source to resolve to the Kafka ADR, got %v", source)
+			}
+		})
+	}
+}
+
+func TestFindSimilarTool_InvalidArguments(t *testing.T) {
+	tool := newSimilarityTestTool(t)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+	}{
+		{"missing uri", map[string]interface{}{}},
+		{"unknown document", map[string]interface{}{"uri": "architecture://patterns/missing"}},
+		{"max_results too large", map[string]interface{}{"uri": "architecture://patterns/unit-of-work", "max_results": float64(MaxSearchResults + 1)}},
+		{"max_results zero", map[string]interface{}{"uri": "architecture://patterns/unit-of-work", "max_results": 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tool.Execute(context.Background(), tt.arguments); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestFindSimilarTool_RelatedDocuments(t *testing.T) {
+	tool := newSimilarityTestTool(t)
+	source := "mcp/resources/patterns/repository-pattern.md"
+
+	related := tool.RelatedDocuments(source, 3)
+	if len(related) == 0 || len(related) > 3 {
+		t.Fatalf("Expected 1 to 3 related documents, got %d", len(related))
+	}
+	if related[0].Metadata.Path != "mcp/resources/patterns/unit-of-work.md" {
+		t.Errorf("Expected unit-of-work to be most related, got %s", related[0].Metadata.Path)
+	}
+	for _, doc := range related {
+		if doc.Metadata.Path == source {
+			t.Error("Source document must be excluded from related documents")
+		}
+	}
+
+	if got := tool.RelatedDocuments(source, 1); len(got) != 1 || got[0] != related[0] {
+		t.Errorf("Expected the top related document for count 1, got %v", got)
+	}
+	if got := tool.RelatedDocuments("mcp/resources/patterns/missing.md", 3); len(got) != 0 {
+		t.Errorf("Expected no related documents for an unknown path, got %d", len(got))
+	}
+	if got := tool.RelatedDocuments(source, 0); got != nil {
+		t.Errorf("Expected no related documents for count 0, got %d", len(got))
+	}
+
+	t.Run("cached until the documentation changes", func(t *testing.T) {
+		version := tool.related.version
+		tool.RelatedDocuments(source, 3)
+		if tool.related.version != version {
+			t.Error("Expected the unchanged corpus to be reused")
+		}
+
+		closer := "mcp/resources/patterns/repository-aggregate.md"
+		tool.cache.Set(closer, &models.Document{
+			Metadata: models.DocumentMetadata{Path: closer, Category: config.CategoryPattern, Title: "Repository Aggregate"},
+			Content: models.DocumentContent{RawContent: "# Repository Aggregate\n\nThe repository mediates between the domain and " +
+				"persistence layers. Each aggregate gets one repository that hides database queries and transaction handling."},
+		})
+
+		related := tool.RelatedDocuments(source, 3)
+		if tool.related.version == version {
+			t.Error("Expected the corpus to be rebuilt after a document was added")
+		}
+		if len(related) == 0 || related[0].Metadata.Path != closer {
+			t.Errorf("Expected the new near-duplicate to rank first after the change, got %v", related)
+		}
+	})
+}
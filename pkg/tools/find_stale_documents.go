@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// DefaultStaleThresholdDays is how long a document may go unmodified before it is
+// reported; a year is long enough that guidance has likely drifted from practice
+const DefaultStaleThresholdDays = 365
+
+// FindStaleDocumentsTool reports cached documents whose LastModified is older than
+// a threshold, to support periodic documentation hygiene
+type FindStaleDocumentsTool struct {
+	cache         *cache.DocumentCache
+	logger        *logging.StructuredLogger
+	thresholdDays int
+}
+
+// NewFindStaleDocumentsTool creates a new FindStaleDocumentsTool with the default threshold
+func NewFindStaleDocumentsTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *FindStaleDocumentsTool {
+	return &FindStaleDocumentsTool{
+		cache:         cache,
+		logger:        logger,
+		thresholdDays: DefaultStaleThresholdDays,
+	}
+}
+
+// SetThresholdDays changes the default age after which documents count as stale
+func (fst *FindStaleDocumentsTool) SetThresholdDays(days int) error {
+	if days < 1 {
+		return fmt.Errorf("stale threshold must be at least 1 day, got %d", days)
+	}
+	fst.thresholdDays = days
+	return nil
+}
+
+// Name returns the unique identifier for the tool
+func (fst *FindStaleDocumentsTool) Name() string {
+	return "find-stale-documents"
+}
+
+// Description returns a human-readable description
+func (fst *FindStaleDocumentsTool) Description() string {
+	return "Finds documentation that has not been modified within a given number of days, grouped by category"
+}
+
+// InputSchema returns JSON schema for tool parameters
+func (fst *FindStaleDocumentsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"threshold_days": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     1,
+				"description": fmt.Sprintf("Report documents older than this many days (default: %d)", fst.thresholdDays),
+			},
+			"resource_type": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{config.CategoryGuideline, config.CategoryPattern, config.CategoryADR, "all"},
+				"description": "Filter by resource type (default: all)",
+			},
+		},
+	}
+}
+
+// Execute runs the tool with validated arguments
+func (fst *FindStaleDocumentsTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	thresholdDays := fst.thresholdDays
+	if td, ok := arguments["threshold_days"].(float64); ok {
+		thresholdDays = int(td)
+	} else if td, ok := arguments["threshold_days"].(int); ok {
+		thresholdDays = td
+	}
+
+	if thresholdDays < 1 {
+		return nil, invalidArgument("threshold_days must be at least 1")
+	}
+
+	resourceType := "all"
+	if rt, ok := arguments["resource_type"].(string); ok {
+		resourceType = rt
+	}
+
+	validTypes := map[string]bool{
+		config.CategoryGuideline: true,
+		config.CategoryPattern:   true,
+		config.CategoryADR:       true,
+		"all":                    true,
+	}
+	if !validTypes[resourceType] {
+		return nil, invalidArgument("invalid resource_type: must be one of %s, %s, %s, all",
+			config.CategoryGuideline, config.CategoryPattern, config.CategoryADR)
+	}
+
+	fst.logger.WithContext("threshold_days", thresholdDays).
+		WithContext("resource_type", resourceType).
+		Info("Finding stale documentation")
+
+	return fst.findStale(thresholdDays, resourceType, time.Now()), nil
+}
+
+// staleDocument represents a single document past the staleness threshold
+type staleDocument struct {
+	URI          string
+	Title        string
+	Path         string
+	LastModified time.Time
+	AgeDays      int
+}
+
+// findStale groups documents older than the threshold by category, oldest first
+func (fst *FindStaleDocumentsTool) findStale(thresholdDays int, resourceType string, now time.Time) map[string]interface{} {
+	cutoff := now.AddDate(0, 0, -thresholdDays)
+
+	byCategory := make(map[string][]staleDocument)
+	checked := 0
+	for path, doc := range fst.cache.GetAllDocuments() {
+		category := doc.Metadata.Category
+		if resourceType != "all" && category != resourceType {
+			continue
+		}
+		checked++
+
+		// A zero LastModified means the loader never recorded one, not that the
+		// document dates from year one
+		if doc.Metadata.LastModified.IsZero() || !doc.Metadata.LastModified.Before(cutoff) {
+			continue
+		}
+
+		byCategory[category] = append(byCategory[category], staleDocument{
+			URI:          config.ResourceURI(category, path),
+			Title:        doc.Metadata.Title,
+			Path:         path,
+			LastModified: doc.Metadata.LastModified,
+			AgeDays:      int(now.Sub(doc.Metadata.LastModified).Hours() / 24),
+		})
+	}
+
+	staleCount := 0
+	grouped := make(map[string]interface{}, len(byCategory))
+	for category, docs := range byCategory {
+		sort.Slice(docs, func(i, j int) bool {
+			if docs[i].AgeDays != docs[j].AgeDays {
+				return docs[i].AgeDays > docs[j].AgeDays
+			}
+			return docs[i].Path < docs[j].Path
+		})
+
+		entries := make([]map[string]interface{}, 0, len(docs))
+		for _, d := range docs {
+			entries = append(entries, map[string]interface{}{
+				"uri":           d.URI,
+				"title":         d.Title,
+				"path":          d.Path,
+				"last_modified": d.LastModified.Format(time.RFC3339),
+				"age_days":      d.AgeDays,
+			})
+		}
+		grouped[category] = entries
+		staleCount += len(docs)
+	}
+
+	return map[string]interface{}{
+		"stale_documents": grouped,
+		"stale_count":     staleCount,
+		"checked_count":   checked,
+		"threshold_days":  thresholdDays,
+	}
+}
@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/errors"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// newStaleTestTool builds a tool over documents modified the given number of days ago
+func newStaleTestTool(t *testing.T, ages map[string]int, categories map[string]string) *FindStaleDocumentsTool {
+	t.Helper()
+	docCache := cache.NewDocumentCache()
+	now := time.Now()
+	for path, days := range ages {
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{
+				Path:         path,
+				Category:     categories[path],
+				Title:        path,
+				LastModified: now.AddDate(0, 0, -days),
+			},
+		})
+	}
+	return NewFindStaleDocumentsTool(docCache, logging.NewStructuredLogger("test"))
+}
+
+// stalePaths flattens the grouped result into category -> ordered paths
+func stalePaths(t *testing.T, result interface{}) map[string][]string {
+	t.Helper()
+	grouped := result.(map[string]interface{})["stale_documents"].(map[string]interface{})
+	paths := make(map[string][]string)
+	for category, entries := range grouped {
+		for _, entry := range entries.([]map[string]interface{}) {
+			paths[category] = append(paths[category], entry["path"].(string))
+		}
+	}
+	return paths
+}
+
+func TestFindStaleDocumentsTool_Name(t *testing.T) {
+	tool := NewFindStaleDocumentsTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+	if tool.Name() != "find-stale-documents" {
+		t.Errorf("Expected name find-stale-documents, got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Description should not be empty")
+	}
+}
+
+func TestFindStaleDocumentsTool_ReportsOnlyOldDocuments(t *testing.T) {
+	categories := map[string]string{
+		"docs/guidelines/api-design.md":     config.CategoryGuideline,
+		"docs/guidelines/testing.md":        config.CategoryGuideline,
+		"docs/guidelines/logging.md":        config.CategoryGuideline,
+		"docs/patterns/repository.md":       config.CategoryPattern,
+		"docs/adr/001-use-postgres.md":      config.CategoryADR,
+		"docs/adr/002-adopt-event-store.md": config.CategoryADR,
+	}
+	ages := map[string]int{
+		"docs/guidelines/api-design.md":     800,
+		"docs/guidelines/testing.md":        400,
+		"docs/guidelines/logging.md":        30,
+		"docs/patterns/repository.md":       10,
+		"docs/adr/001-use-postgres.md":      1200,
+		"docs/adr/002-adopt-event-store.md": 5,
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		expected  map[string][]string
+	}{
+		{
+			name:      "default threshold",
+			arguments: map[string]interface{}{},
+			expected: map[string][]string{
+				config.CategoryGuideline: {"docs/guidelines/api-design.md", "docs/guidelines/testing.md"},
+				config.CategoryADR:       {"docs/adr/001-use-postgres.md"},
+			},
+		},
+		{
+			name:      "custom threshold",
+			arguments: map[string]interface{}{"threshold_days": float64(20)},
+			expected: map[string][]string{
+				config.CategoryGuideline: {"docs/guidelines/api-design.md", "docs/guidelines/testing.md", "docs/guidelines/logging.md"},
+				config.CategoryADR:       {"docs/adr/001-use-postgres.md"},
+			},
+		},
+		{
+			name:      "filtered by resource type",
+			arguments: map[string]interface{}{"resource_type": config.CategoryADR},
+			expected: map[string][]string{
+				config.CategoryADR: {"docs/adr/001-use-postgres.md"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := newStaleTestTool(t, ages, categories)
+			result, err := tool.Execute(context.Background(), tt.arguments)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			got := stalePaths(t, result)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected categories %v, got %v", tt.expected, got)
+			}
+			for category, want := range tt.expected {
+				if len(got[category]) != len(want) {
+					t.Fatalf("Category %s: expected %v, got %v", category, want, got[category])
+				}
+				for i := range want {
+					if got[category][i] != want[i] {
+						t.Errorf("Category %s[%d]: expected %s, got %s", category, i, want[i], got[category][i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFindStaleDocumentsTool_AgeDays(t *testing.T) {
+	tool := newStaleTestTool(t,
+		map[string]int{"docs/adr/001-use-postgres.md": 500},
+		map[string]string{"docs/adr/001-use-postgres.md": config.CategoryADR})
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	resultMap := result.(map[string]interface{})
+	entries := resultMap["stale_documents"].(map[string]interface{})[config.CategoryADR].([]map[string]interface{})
+	if entries[0]["age_days"] != 500 {
+		t.Errorf("Expected age_days 500, got %v", entries[0]["age_days"])
+	}
+	if entries[0]["uri"] != "architecture://adr/001" {
+		t.Errorf("Unexpected uri %v", entries[0]["uri"])
+	}
+	if resultMap["stale_count"] != 1 || resultMap["checked_count"] != 1 {
+		t.Errorf("Unexpected counts: stale=%v checked=%v", resultMap["stale_count"], resultMap["checked_count"])
+	}
+}
+
+func TestFindStaleDocumentsTool_SkipsUnknownModificationTime(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	docCache.Set("docs/patterns/legacy.md", &models.Document{
+		Metadata: models.DocumentMetadata{Path: "docs/patterns/legacy.md", Category: config.CategoryPattern},
+	})
+	tool := NewFindStaleDocumentsTool(docCache, logging.NewStructuredLogger("test"))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if count := result.(map[string]interface{})["stale_count"]; count != 0 {
+		t.Errorf("Expected zero LastModified to be ignored, got stale_count %v", count)
+	}
+}
+
+func TestFindStaleDocumentsTool_InvalidArguments(t *testing.T) {
+	tool := NewFindStaleDocumentsTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+	}{
+		{"zero threshold", map[string]interface{}{"threshold_days": float64(0)}},
+		{"negative threshold", map[string]interface{}{"threshold_days": -5}},
+		{"invalid resource type", map[string]interface{}{"resource_type": "unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tool.Execute(context.Background(), tt.arguments)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			structuredErr, ok := err.(*errors.StructuredError)
+			if !ok || structuredErr.Code != errors.ErrCodeInvalidParams {
+				t.Errorf("Expected an invalid params error, got %T: %v", err, err)
+			}
+		})
+	}
+
+	if err := tool.SetThresholdDays(0); err == nil {
+		t.Error("Expected SetThresholdDays(0) to fail")
+	}
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// ResultFormatter is implemented by tools that render their own results as MCP
+// content instead of the default JSON text block
+type ResultFormatter interface {
+	FormatResult(result interface{}) (models.MCPToolsCallResult, error)
+}
+
+// FormatResult converts a tool result into tools/call content. Strings become a
+// single text block. Other results become an indented JSON text block, plus a
+// structured copy for clients that read structuredContent when the result is a
+// JSON object.
+func FormatResult(tool ToolInterface, result interface{}) (models.MCPToolsCallResult, error) {
+	if formatter, ok := tool.(ResultFormatter); ok {
+		return formatter.FormatResult(result)
+	}
+
+	if text, ok := result.(string); ok {
+		return models.MCPToolsCallResult{
+			Content: []models.MCPToolContent{{Type: "text", Text: text}},
+		}, nil
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return models.MCPToolsCallResult{}, fmt.Errorf("failed to serialize result of %s: %w", tool.Name(), err)
+	}
+
+	formatted := models.MCPToolsCallResult{
+		Content: []models.MCPToolContent{{Type: "text", Text: string(jsonBytes)}},
+	}
+
+	// structuredContent must be an object, so arrays and scalars stay text-only
+	var structured map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &structured); err == nil && structured != nil {
+		formatted.StructuredContent = structured
+	}
+
+	return formatted, nil
+}
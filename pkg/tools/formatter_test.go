@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// customFormattedTool renders its result as a short summary
+type customFormattedTool struct {
+	mockToolForExecutor
+}
+
+func (c *customFormattedTool) FormatResult(result interface{}) (models.MCPToolsCallResult, error) {
+	return models.MCPToolsCallResult{
+		Content: []models.MCPToolContent{{Type: "text", Text: "custom summary"}},
+	}, nil
+}
+
+func TestFormatResult_SearchTool(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	setupTestDocuments(docCache)
+	tool := NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test"))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"query": "architecture"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	formatted, err := FormatResult(tool, result)
+	if err != nil {
+		t.Fatalf("FormatResult failed: %v", err)
+	}
+
+	if len(formatted.Content) != 1 || formatted.Content[0].Type != "text" {
+		t.Fatalf("Expected a single text content block, got %+v", formatted.Content)
+	}
+
+	text := formatted.Content[0].Text
+	if !strings.Contains(text, "\n  \"results\": [") {
+		t.Errorf("Expected indented JSON text, got:\n%s", text)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("Text block should be valid JSON: %v", err)
+	}
+
+	results, ok := formatted.StructuredContent["results"].([]interface{})
+	if !ok || len(results) == 0 {
+		t.Fatalf("Expected structured results, got %v", formatted.StructuredContent)
+	}
+	if formatted.StructuredContent["total_matches"] != decoded["total_matches"] {
+		t.Error("Structured content should match the text block")
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	plainTool := &mockToolForExecutor{name: "plain-tool"}
+
+	tests := []struct {
+		name           string
+		tool           ToolInterface
+		result         interface{}
+		expectedText   string
+		wantStructured bool
+		wantErr        bool
+	}{
+		{"string result", plainTool, "already formatted", "already formatted", false, false},
+		{"array result", plainTool, []string{"a", "b"}, "[\n  \"a\",\n  \"b\"\n]", false, false},
+		{"object result", plainTool, map[string]interface{}{"ok": true}, "{\n  \"ok\": true\n}", true, false},
+		{"custom formatter", &customFormattedTool{mockToolForExecutor{name: "custom-tool"}}, map[string]interface{}{"ok": true}, "custom summary", false, false},
+		{"unserializable result", plainTool, make(chan int), "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted, err := FormatResult(tt.tool, tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if formatted.Content[0].Text != tt.expectedText {
+				t.Errorf("Expected text %q, got %q", tt.expectedText, formatted.Content[0].Text)
+			}
+			if (formatted.StructuredContent != nil) != tt.wantStructured {
+				t.Errorf("Expected structured content %v, got %v", tt.wantStructured, formatted.StructuredContent)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
 	"time"
 
@@ -51,6 +53,11 @@ func NewToolManager(logger *logging.StructuredLogger) *ToolManager {
 	return tm
 }
 
+// Close stops the manager's background work. Safe to call more than once.
+func (tm *ToolManager) Close() {
+	tm.executor.Close()
+}
+
 // RegisterTool registers a new tool in the manager
 func (tm *ToolManager) RegisterTool(tool Tool) error {
 	if tool == nil {
@@ -100,7 +107,7 @@ func (tm *ToolManager) GetTool(name string) (Tool, error) {
 	return tool, nil
 }
 
-// ListTools returns all registered tool definitions
+// ListTools returns all registered tool definitions sorted by name
 func (tm *ToolManager) ListTools() []ToolDefinition {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
@@ -110,9 +117,36 @@ func (tm *ToolManager) ListTools() []ToolDefinition {
 		tools = append(tools, NewToolDefinition(tool))
 	}
 
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
 	return tools
 }
 
+// SetAuditLog writes a JSON audit record of every tool invocation to w: the tool,
+// session, sanitized arguments, duration and outcome. Audit records are off by
+// default; a nil w turns them off again.
+func (tm *ToolManager) SetAuditLog(w io.Writer) {
+	if w == nil {
+		tm.executor.SetAuditLogger(nil)
+		return
+	}
+	tm.executor.SetAuditLogger(NewAuditLogger(w))
+}
+
+// SetArgumentSanitization changes how tool arguments are sanitized in logs and audit
+// records: string values longer than maxLength characters are truncated and the
+// values of redactedFields are replaced with "[redacted]". See DefaultSanitizeLength
+// and DefaultRedactedFields.
+func (tm *ToolManager) SetArgumentSanitization(maxLength int, redactedFields []string) error {
+	if err := tm.executor.SetSanitizeLength(maxLength); err != nil {
+		return err
+	}
+	tm.executor.SetRedactedFields(redactedFields)
+	return nil
+}
+
 // ExecuteTool executes a tool by name with the provided arguments
 func (tm *ToolManager) ExecuteTool(ctx context.Context, name string, arguments map[string]interface{}) (interface{}, error) {
 	startTime := time.Now()
@@ -121,6 +155,7 @@ func (tm *ToolManager) ExecuteTool(ctx context.Context, name string, arguments m
 	tool, err := tm.GetTool(name)
 	if err != nil {
 		tm.recordFailure(name)
+		tm.executor.recordAudit(ctx, name, arguments, startTime, err)
 		return nil, err
 	}
 
@@ -1,12 +1,16 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
+	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
 	"mcp-architecture-service/pkg/logging"
 )
 
@@ -574,3 +578,69 @@ func TestToolManager_WithRealTools(t *testing.T) {
 		}
 	})
 }
+
+// TestBuiltinToolsDoNotModifyCachedDocuments tests that the built-in tools treat
+// cached documents as read-only
+func TestBuiltinToolsDoNotModifyCachedDocuments(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	defer docCache.Close()
+	logger := logging.NewStructuredLogger("test")
+
+	setupTestDocuments(docCache)
+	docCache.Set("mcp/resources/patterns/circuit-breaker.md", &models.Document{
+		Metadata: models.DocumentMetadata{
+			Title:        "Circuit Breaker",
+			Category:     config.CategoryPattern,
+			Path:         "mcp/resources/patterns/circuit-breaker.md",
+			LastModified: time.Now().AddDate(-2, 0, 0),
+		},
+		Content: models.DocumentContent{
+			RawContent: "# Circuit Breaker\n\n## Repository calls\nWrap repository calls in a breaker.\n",
+			Sections: []models.DocumentSection{{
+				Heading:     "Circuit Breaker",
+				Level:       1,
+				Subsections: []models.DocumentSection{{Heading: "Repository calls", Level: 2, Content: "Wrap repository calls in a breaker."}},
+			}},
+		},
+	})
+
+	// Snapshot the documents as JSON, which captures every nested section
+	before := make(map[string][]byte)
+	for key, doc := range docCache.GetAllDocuments() {
+		snapshot, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("Failed to snapshot document %s: %v", key, err)
+		}
+		before[key] = snapshot
+	}
+
+	calls := []struct {
+		tool      Tool
+		arguments map[string]interface{}
+	}{
+		{NewValidatePatternTool(docCache, logger), map[string]interface{}{"code": "type Repository interface{}", "pattern_name": "repository-pattern"}},
+		{NewSearchArchitectureTool(docCache, logger), map[string]interface{}{"query": "repository", "max_excerpts": 3}},
+		{NewCheckADRAlignmentTool(docCache, logger), map[string]interface{}{"decision_description": "Split the monolith into microservices"}},
+		{NewCheckNamingConventionsTool(docCache, logger), map[string]interface{}{}},
+		{NewFindStaleDocumentsTool(docCache, logger), map[string]interface{}{"threshold_days": 30}},
+		{NewFindSimilarTool(docCache, logger), map[string]interface{}{"uri": "architecture://patterns/repository-pattern"}},
+	}
+
+	for _, call := range calls {
+		t.Run(call.tool.Name(), func(t *testing.T) {
+			if _, err := call.tool.Execute(context.Background(), call.arguments); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			for key, want := range before {
+				got, err := docCache.Get(key)
+				if err != nil {
+					t.Fatalf("Document %s disappeared from the cache: %v", key, err)
+				}
+				if snapshot, _ := json.Marshal(got); !bytes.Equal(snapshot, want) {
+					t.Errorf("Tool modified cached document %s", key)
+				}
+			}
+		})
+	}
+}
@@ -2,27 +2,119 @@ package tools
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
 )
 
+// defaultSearchResults applies when max_results is omitted
+const defaultSearchResults = 10
+
+// DefaultMinQueryLength is the shortest query, in characters after trimming
+// surrounding whitespace, that search-architecture accepts unless configured otherwise
+const DefaultMinQueryLength = 2
+
 // SearchArchitectureTool searches architectural documentation by keywords
 type SearchArchitectureTool struct {
-	cache  *cache.DocumentCache
-	logger *logging.StructuredLogger
+	cache          *cache.DocumentCache
+	logger         *logging.StructuredLogger
+	stopWords      map[string]bool
+	minQueryLength int
+	resultCache    *searchResultCache // nil disables result caching
+	categoryBoosts map[string]float64 // category -> score multiplier; missing means DefaultCategoryBoost
+	collapseCode   bool               // Collapse fenced code blocks in excerpts to codeOmittedMarker
 }
 
 // NewSearchArchitectureTool creates a new SearchArchitectureTool instance
 func NewSearchArchitectureTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *SearchArchitectureTool {
 	return &SearchArchitectureTool{
-		cache:  cache,
-		logger: logger,
+		cache:          cache,
+		logger:         logger,
+		minQueryLength: DefaultMinQueryLength,
+		resultCache:    newSearchResultCache(DefaultSearchCacheSize, DefaultSearchCacheTTL),
+	}
+}
+
+// SetStopWords sets the words dropped from queries and documents. None are dropped
+// unless set. See StopWordsFor and NewStopWordSet.
+func (sat *SearchArchitectureTool) SetStopWords(stopWords map[string]bool) {
+	sat.stopWords = stopWords
+	if sat.resultCache != nil {
+		sat.resultCache.clear()
+	}
+}
+
+// SetResultCache changes how many recent search results are kept and for how long.
+// Identical searches within ttl reuse the cached result until a document is added,
+// changed or removed. Zero entries disables result caching.
+func (sat *SearchArchitectureTool) SetResultCache(entries int, ttl time.Duration) error {
+	if entries < 0 {
+		return fmt.Errorf("result cache size must not be negative, got %d", entries)
+	}
+	if entries > 0 && ttl <= 0 {
+		return fmt.Errorf("result cache TTL must be positive, got %s", ttl)
+	}
+	if entries == 0 {
+		sat.resultCache = nil
+		return nil
+	}
+	sat.resultCache = newSearchResultCache(entries, ttl)
+	return nil
+}
+
+// SetMinQueryLength changes the shortest query, in characters after trimming
+// surrounding whitespace, that is searched rather than rejected
+func (sat *SearchArchitectureTool) SetMinQueryLength(chars int) error {
+	if chars < 1 {
+		return fmt.Errorf("minimum query length must be at least 1 character, got %d", chars)
+	}
+	sat.minQueryLength = chars
+	return nil
+}
+
+// SetCategoryBoosts sets per-category score multipliers, so operators can bias
+// ranking toward the categories most authoritative for their queries. Keys are
+// categories or URI segments and multipliers must be positive; categories left out
+// keep DefaultCategoryBoost. See NormalizeCategoryBoosts.
+func (sat *SearchArchitectureTool) SetCategoryBoosts(boosts map[string]float64) error {
+	normalized, err := NormalizeCategoryBoosts(boosts)
+	if err != nil {
+		return err
+	}
+	sat.categoryBoosts = normalized
+	if sat.resultCache != nil {
+		sat.resultCache.clear()
+	}
+	return nil
+}
+
+// SetCollapseCodeExcerpts collapses each fenced code block in returned excerpts to
+// "[code omitted]", for audiences that should not be shown large code samples.
+// Terms inside code blocks still count toward relevance. Off by default.
+func (sat *SearchArchitectureTool) SetCollapseCodeExcerpts(enabled bool) {
+	sat.collapseCode = enabled
+	if sat.resultCache != nil {
+		sat.resultCache.clear()
+	}
+}
+
+// categoryBoost returns the score multiplier for category
+func (sat *SearchArchitectureTool) categoryBoost(category string) float64 {
+	if boost, ok := sat.categoryBoosts[category]; ok {
+		return boost
 	}
+	return DefaultCategoryBoost
 }
 
 // Name returns the unique identifier for the tool
@@ -42,19 +134,42 @@ func (sat *SearchArchitectureTool) InputSchema() map[string]interface{} {
 		"properties": map[string]interface{}{
 			"query": map[string]interface{}{
 				"type":        "string",
-				"description": "Search query",
+				"description": fmt.Sprintf("Search query; surrounding whitespace is trimmed and at least %d characters must remain", sat.minQueryLength),
 				"maxLength":   500,
 			},
 			"resource_type": map[string]interface{}{
-				"type":        "string",
-				"enum":        []string{config.CategoryGuideline, config.CategoryPattern, config.CategoryADR, "all"},
-				"description": "Filter by resource type (default: all)",
+				"oneOf": []interface{}{
+					map[string]interface{}{
+						"type": "string",
+						"enum": append(searchableCategoryNames(), "all"),
+					},
+					map[string]interface{}{
+						"type":     "array",
+						"minItems": 1,
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": searchableCategoryNames(),
+						},
+					},
+				},
+				"description": "Filter by resource type, or an array of types to search several at once (default: all)",
 			},
 			"max_results": map[string]interface{}{
 				"type":        "integer",
 				"minimum":     1,
-				"maximum":     20,
-				"description": "Maximum results to return (default: 10)",
+				"maximum":     MaxSearchResults,
+				"description": fmt.Sprintf("Maximum results to return (default: %d)", defaultSearchResults),
+			},
+			"max_excerpts": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     1,
+				"maximum":     MaxExcerptsPerResult,
+				"description": "Maximum non-overlapping excerpts per result, one per cluster of matches (default: 1)",
+			},
+			"excerpt_unit": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{ExcerptUnitChars, ExcerptUnitWords, ExcerptUnitSentences},
+				"description": "Unit excerpt windows are trimmed to: chars, whole words, or whole sentences; excerpts never exceed the length cap (default: chars)",
 			},
 		},
 		"required": []string{"query"},
@@ -63,57 +178,159 @@ func (sat *SearchArchitectureTool) InputSchema() map[string]interface{} {
 
 // Execute runs the tool with validated arguments
 func (sat *SearchArchitectureTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	opts, err := sat.parseSearchOptions(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	sat.logger.WithContext("query", opts.query).
+		WithContext("resource_type", arguments["resource_type"]).
+		WithContext("max_results", opts.maxResults).
+		Info("Searching architecture documentation")
+
+	// Perform search
+	return sat.search(ctx, opts)
+}
+
+// SearchResultFunc receives one formatted search result at a time. Returning an
+// error stops the stream and is passed back to the caller.
+type SearchResultFunc func(result map[string]interface{}) error
+
+// ExecuteStream runs the search like Execute but hands results to emit one at a
+// time instead of returning them together. By default all documents are scored
+// first and results are emitted in score order; with arrivalOrder set each result
+// is emitted as soon as its document is scored, in no particular order. Either way
+// at most max_results results are emitted.
+func (sat *SearchArchitectureTool) ExecuteStream(ctx context.Context, arguments map[string]interface{}, arrivalOrder bool, emit SearchResultFunc) error {
+	opts, err := sat.parseSearchOptions(arguments)
+	if err != nil {
+		return err
+	}
+
+	sat.logger.WithContext("query", opts.query).
+		WithContext("resource_type", arguments["resource_type"]).
+		WithContext("max_results", opts.maxResults).
+		WithContext("arrival_order", arrivalOrder).
+		Info("Streaming architecture documentation search")
+
+	if arrivalOrder {
+		emitted := 0
+		_, err := sat.scoreDocuments(ctx, opts, func(result searchResult) error {
+			if err := emit(result.toMap()); err != nil {
+				return err
+			}
+			emitted++
+			if emitted == opts.maxResults {
+				return errStopScoring
+			}
+			return nil
+		})
+		return err
+	}
+
+	var results []searchResult
+	_, err = sat.scoreDocuments(ctx, opts, func(result searchResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, result := range rankResults(results, opts.maxResults) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := emit(result.toMap()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchOptions holds the parsed search-architecture arguments. A nil categories
+// set searches every category.
+type searchOptions struct {
+	query       string
+	categories  map[string]bool
+	maxResults  int
+	maxExcerpts int
+	unit        string
+}
+
+// parseSearchOptions extracts and validates the search arguments
+func (sat *SearchArchitectureTool) parseSearchOptions(arguments map[string]interface{}) (searchOptions, error) {
 	// Extract arguments
 	query, ok := arguments["query"].(string)
 	if !ok {
-		return nil, fmt.Errorf("query argument must be a string")
+		return searchOptions{}, invalidArgument("query argument must be a string")
 	}
 
-	// Validate query length
+	// Validate query length once surrounding whitespace is trimmed, so a blank
+	// query is rejected instead of matching every document
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return searchOptions{}, errors.NewValidationError(errors.ErrCodeInvalidParams,
+			"query must not be empty or whitespace", nil)
+	}
+	if n := utf8.RuneCountInString(query); n < sat.minQueryLength {
+		return searchOptions{}, errors.NewValidationError(errors.ErrCodeInvalidParams,
+			fmt.Sprintf("query must be at least %d characters, got %d", sat.minQueryLength, n), nil)
+	}
 	if len(query) > 500 {
-		return nil, fmt.Errorf("query exceeds maximum length of 500 characters")
+		return searchOptions{}, invalidArgument("query exceeds maximum length of 500 characters")
 	}
 
 	// Extract optional resource_type
-	resourceType := "all"
-	if rt, ok := arguments["resource_type"].(string); ok {
-		resourceType = rt
-	}
-
-	// Validate resource_type
-	validTypes := map[string]bool{
-		config.CategoryGuideline: true,
-		config.CategoryPattern:   true,
-		config.CategoryADR:       true,
-		"all":                    true,
-	}
-	if !validTypes[resourceType] {
-		return nil, fmt.Errorf("invalid resource_type: must be one of %s, %s, %s, all",
-			config.CategoryGuideline, config.CategoryPattern, config.CategoryADR)
+	categories, err := parseResourceTypes(arguments["resource_type"])
+	if err != nil {
+		return searchOptions{}, err
 	}
 
 	// Extract optional max_results
-	maxResults := 10
+	maxResults := defaultSearchResults
 	if mr, ok := arguments["max_results"].(float64); ok {
 		maxResults = int(mr)
 	} else if mr, ok := arguments["max_results"].(int); ok {
 		maxResults = mr
 	}
 
-	// Validate max_results
-	if maxResults < 1 || maxResults > 20 {
-		return nil, fmt.Errorf("max_results must be between 1 and 20")
+	// Bounds are declared in the schema and enforced by the executor; this guards direct calls
+	if maxResults < 1 || maxResults > MaxSearchResults {
+		return searchOptions{}, invalidArgument("max_results must be between 1 and %d", MaxSearchResults)
 	}
 
-	sat.logger.WithContext("query", query).
-		WithContext("resource_type", resourceType).
-		WithContext("max_results", maxResults).
-		Info("Searching architecture documentation")
+	// Extract optional max_excerpts
+	maxExcerpts := 1
+	if me, ok := arguments["max_excerpts"].(float64); ok {
+		maxExcerpts = int(me)
+	} else if me, ok := arguments["max_excerpts"].(int); ok {
+		maxExcerpts = me
+	}
 
-	// Perform search
-	results := sat.search(query, resourceType, maxResults)
+	if maxExcerpts < 1 || maxExcerpts > MaxExcerptsPerResult {
+		return searchOptions{}, invalidArgument("max_excerpts must be between 1 and %d", MaxExcerptsPerResult)
+	}
+
+	// Extract optional excerpt_unit
+	unit := ExcerptUnitChars
+	if eu, ok := arguments["excerpt_unit"].(string); ok {
+		unit = eu
+	}
 
-	return results, nil
+	switch unit {
+	case ExcerptUnitChars, ExcerptUnitWords, ExcerptUnitSentences:
+	default:
+		return searchOptions{}, invalidArgument("excerpt_unit must be one of %s, %s, %s", ExcerptUnitChars, ExcerptUnitWords, ExcerptUnitSentences)
+	}
+
+	return searchOptions{
+		query:       query,
+		categories:  categories,
+		maxResults:  maxResults,
+		maxExcerpts: maxExcerpts,
+		unit:        unit,
+	}, nil
 }
 
 // searchResult represents a single search result with relevance score
@@ -123,86 +340,261 @@ type searchResult struct {
 	ResourceType   string
 	RelevanceScore float64
 	Excerpt        string
+	Excerpts       []string
+}
+
+// toMap converts a result to its output format
+func (r searchResult) toMap() map[string]interface{} {
+	entry := map[string]interface{}{
+		"uri":             r.URI,
+		"title":           r.Title,
+		"resource_type":   r.ResourceType,
+		"relevance_score": r.RelevanceScore,
+		"excerpt":         r.Excerpt,
+	}
+	if r.Excerpts != nil {
+		entry["excerpts"] = r.Excerpts
+	}
+	return entry
 }
 
+// errStopScoring ends scoreDocuments early without being reported as a failure
+var errStopScoring = stderrors.New("stop scoring")
+
 // search performs the actual search and ranking logic
-func (sat *SearchArchitectureTool) search(query, resourceType string, maxResults int) map[string]interface{} {
+func (sat *SearchArchitectureTool) search(ctx context.Context, opts searchOptions) (map[string]interface{}, error) {
+	// Read the version before scoring so a change made while scoring leaves the
+	// stored result stale rather than served
+	var key string
+	version := sat.cache.Version()
+	if sat.resultCache != nil {
+		key = searchCacheKey(opts)
+		if output, ok := sat.resultCache.get(key, version); ok {
+			sat.logger.WithContext("query", opts.query).
+				Debug("Serving cached search result")
+			return output, nil
+		}
+	}
+
+	var results []searchResult
+	emptyPaths, err := sat.scoreDocuments(ctx, opts, func(result searchResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results = rankResults(results, opts.maxResults)
+
+	// Convert to output format
+	resultList := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		resultList = append(resultList, result.toMap())
+	}
+
+	output := map[string]interface{}{
+		"results":       resultList,
+		"total_matches": len(results),
+	}
+	if len(emptyPaths) > 0 {
+		warnings := make([]string, 0, len(emptyPaths))
+		for _, path := range emptyPaths {
+			warnings = append(warnings, fmt.Sprintf("%s has no content and was not searched", path))
+		}
+		output["warnings"] = warnings
+	}
+
+	if sat.resultCache != nil {
+		sat.resultCache.put(key, version, output)
+	}
+	return output, nil
+}
+
+// scoreDocuments scores every cached document in the selected categories and passes
+// each match to fn as soon as it is scored. It stops at the first error from fn or
+// from ctx; errStopScoring stops it quietly. Documents with empty content are not
+// scored; their paths are logged and returned, sorted.
+func (sat *SearchArchitectureTool) scoreDocuments(ctx context.Context, opts searchOptions, fn func(searchResult) error) (emptyPaths []string, err error) {
 	// Get all documents from cache
 	allDocs := sat.cache.GetAllDocuments()
 
 	// Tokenize query
-	queryTokens := sat.tokenize(query)
+	queryTokens := sat.tokenize(opts.query)
+
+	defer func() {
+		if len(emptyPaths) > 0 {
+			sort.Strings(emptyPaths)
+			sat.logger.WithContext("empty_documents", emptyPaths).
+				Warn("Skipped cached documents with empty content; they are not indexed for search")
+		}
+	}()
 
-	// Search and score documents
-	var results []searchResult
 	for path, doc := range allDocs {
+		if err := ctx.Err(); err != nil {
+			return emptyPaths, err
+		}
+
 		// Filter by resource type if specified
-		if resourceType != "all" && doc.Metadata.Category != resourceType {
+		if opts.categories != nil && !opts.categories[doc.Metadata.Category] {
+			continue
+		}
+
+		// An empty document usually means a failed read; it would otherwise only
+		// match on its title
+		if strings.TrimSpace(doc.Content.RawContent) == "" {
+			emptyPaths = append(emptyPaths, path)
+			continue
+		}
+
+		// Calculate relevance score, biased by the category's configured boost
+		score := sat.calculateRelevance(queryTokens, doc.Content.RawContent, doc.Metadata.Title, doc.Metadata.Path)
+		if score <= 0 {
 			continue
 		}
+		score *= sat.categoryBoost(doc.Metadata.Category)
 
-		// Calculate relevance score
-		score := sat.calculateRelevance(queryTokens, doc.Content.RawContent, doc.Metadata.Title)
-		if score > 0 {
-			// Extract excerpt
-			excerpt := sat.extractExcerpt(doc.Content.RawContent, queryTokens)
-
-			// Generate URI
-			uri := sat.generateURI(doc.Metadata.Category, path)
-
-			results = append(results, searchResult{
-				URI:            uri,
-				Title:          doc.Metadata.Title,
-				ResourceType:   doc.Metadata.Category,
-				RelevanceScore: score,
-				Excerpt:        excerpt,
-			})
+		// Extract excerpt
+		excerpt := sat.extractExcerpt(doc.Content.RawContent, queryTokens, opts.unit)
+		var excerpts []string
+		if opts.maxExcerpts > 1 {
+			excerpts = sat.extractExcerpts(doc.Content.RawContent, queryTokens, opts.maxExcerpts, opts.unit)
+		}
+
+		err := fn(searchResult{
+			URI:            sat.generateURI(doc.Metadata.Category, path),
+			Title:          doc.Metadata.Title,
+			ResourceType:   doc.Metadata.Category,
+			RelevanceScore: score,
+			Excerpt:        excerpt,
+			Excerpts:       excerpts,
+		})
+		if err == errStopScoring {
+			return emptyPaths, nil
+		}
+		if err != nil {
+			return emptyPaths, err
 		}
 	}
 
-	// Sort by relevance score (descending)
+	return emptyPaths, nil
+}
+
+// RankDocuments orders documents by relevance to query, best first, scoring them as
+// search-architecture does. Documents the query does not match are left out, and
+// equal scores are ordered by path so the ranking is stable.
+func (sat *SearchArchitectureTool) RankDocuments(query string, documents []*models.Document) []*models.Document {
+	queryTokens := sat.tokenize(query)
+
+	type rankedDocument struct {
+		doc   *models.Document
+		score float64
+	}
+	ranked := make([]rankedDocument, 0, len(documents))
+	for _, doc := range documents {
+		score := sat.calculateRelevance(queryTokens, doc.Content.RawContent, doc.Metadata.Title, doc.Metadata.Path)
+		if score <= 0 {
+			continue
+		}
+		ranked = append(ranked, rankedDocument{doc: doc, score: score * sat.categoryBoost(doc.Metadata.Category)})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].doc.Metadata.Path < ranked[j].doc.Metadata.Path
+	})
+
+	result := make([]*models.Document, len(ranked))
+	for i, entry := range ranked {
+		result[i] = entry.doc
+	}
+	return result
+}
+
+// rankResults sorts results by relevance score (descending) and keeps the top maxResults
+func rankResults(results []searchResult, maxResults int) []searchResult {
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].RelevanceScore > results[j].RelevanceScore
 	})
 
-	// Limit results
 	if len(results) > maxResults {
 		results = results[:maxResults]
 	}
+	return results
+}
 
-	// Convert to output format
-	resultList := make([]map[string]interface{}, 0, len(results))
-	for _, result := range results {
-		resultList = append(resultList, map[string]interface{}{
-			"uri":             result.URI,
-			"title":           result.Title,
-			"resource_type":   result.ResourceType,
-			"relevance_score": result.RelevanceScore,
-			"excerpt":         result.Excerpt,
-		})
-	}
+// categoryAliases maps accepted resource_type names to categories. URI segments
+// ("patterns") are accepted alongside category names ("pattern") since clients
+// see both forms.
+var categoryAliases = map[string]string{
+	config.CategoryGuideline: config.CategoryGuideline,
+	config.URIGuidelines:     config.CategoryGuideline,
+	config.CategoryPattern:   config.CategoryPattern,
+	config.URIPatterns:       config.CategoryPattern,
+	config.CategoryADR:       config.CategoryADR,
+}
 
-	return map[string]interface{}{
-		"results":       resultList,
-		"total_matches": len(results),
+// searchableCategoryNames returns the names accepted inside a resource_type array
+func searchableCategoryNames() []string {
+	return []string{
+		config.CategoryGuideline, config.URIGuidelines,
+		config.CategoryPattern, config.URIPatterns,
+		config.CategoryADR,
 	}
 }
 
-// tokenize splits text into lowercase tokens
-func (sat *SearchArchitectureTool) tokenize(text string) []string {
-	// Convert to lowercase
-	text = strings.ToLower(text)
+// parseResourceTypes converts a resource_type argument (missing, a single name,
+// "all", or an array of names) into a category set. A nil set means all categories.
+// The executor checks values against the schema before Execute runs; this guards
+// direct calls.
+func parseResourceTypes(value interface{}) (map[string]bool, error) {
+	var names []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "all" {
+			return nil, nil
+		}
+		names = []string{v}
+	case []string:
+		names = v
+	case []interface{}:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, invalidArgument("invalid resource_type: array entries must be strings")
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, invalidArgument("invalid resource_type: must be a string or an array of strings")
+	}
 
-	// Split on whitespace and common punctuation
-	tokens := strings.FieldsFunc(text, func(r rune) bool {
-		return r == ' ' || r == '\t' || r == '\n' || r == ',' || r == '.' || r == ';' || r == ':' || r == '!' || r == '?'
-	})
+	if len(names) == 0 {
+		return nil, invalidArgument("invalid resource_type: array must not be empty")
+	}
 
-	// Remove empty tokens and very short tokens
+	categories := make(map[string]bool, len(names))
+	for _, name := range names {
+		category, ok := categoryAliases[name]
+		if !ok {
+			return nil, invalidArgument("invalid resource_type %q: must be one of %s, all",
+				name, strings.Join(searchableCategoryNames(), ", "))
+		}
+		categories[category] = true
+	}
+	return categories, nil
+}
+
+// tokenize splits text into lowercase tokens, dropping very short tokens and any
+// configured stop words
+func (sat *SearchArchitectureTool) tokenize(text string) []string {
 	var filtered []string
-	for _, token := range tokens {
-		token = strings.TrimSpace(token)
-		if len(token) >= 2 {
+	for _, token := range Tokenize(text) {
+		if utf8.RuneCountInString(token) >= 2 && !sat.stopWords[token] {
 			filtered = append(filtered, token)
 		}
 	}
@@ -210,21 +602,38 @@ func (sat *SearchArchitectureTool) tokenize(text string) []string {
 	return filtered
 }
 
-// calculateRelevance computes a relevance score for a document
-func (sat *SearchArchitectureTool) calculateRelevance(queryTokens []string, content, title string) float64 {
+// Weights of a query token found in a document's title and in its filename. A
+// filename names the document almost as deliberately as its title, so a file
+// called repository-pattern.md ranks well for "repository" even when its body
+// rarely says so.
+const (
+	titleMatchWeight    = 10.0
+	filenameMatchWeight = 6.0
+)
+
+// calculateRelevance computes a relevance score for the document at path
+func (sat *SearchArchitectureTool) calculateRelevance(queryTokens []string, content, title, path string) float64 {
 	if len(queryTokens) == 0 {
 		return 0
 	}
 
 	contentLower := strings.ToLower(content)
 	titleLower := strings.ToLower(title)
+	identifier := documentIdentifier(path)
 
 	var score float64
 
 	// Score based on title matches (higher weight)
 	for _, token := range queryTokens {
 		if strings.Contains(titleLower, token) {
-			score += 10.0
+			score += titleMatchWeight
+		}
+	}
+
+	// Score based on filename matches, weighted just below the title
+	for _, token := range queryTokens {
+		if identifier != "" && strings.Contains(identifier, token) {
+			score += filenameMatchWeight
 		}
 	}
 
@@ -258,10 +667,37 @@ func (sat *SearchArchitectureTool) calculateRelevance(queryTokens []string, cont
 	return score
 }
 
-// extractExcerpt extracts a relevant excerpt from the document
-func (sat *SearchArchitectureTool) extractExcerpt(content string, queryTokens []string) string {
-	const maxExcerptLength = 200
+// documentIdentifier returns the lowercased filename of path without its extension,
+// with separators such as "-" and "_" turned into spaces: "repository pattern" for
+// mcp/resources/patterns/repository-pattern.md
+func documentIdentifier(path string) string {
+	base := filepath.Base(filepath.ToSlash(path))
+	if base == "." || base == "/" {
+		return ""
+	}
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	return strings.Join(strings.FieldsFunc(strings.ToLower(base), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Excerpt windows reach this far before and after the match they are centred on
+const (
+	excerptLeadingContext  = 50
+	excerptTrailingContext = 150
+	maxExcerptLength       = excerptLeadingContext + excerptTrailingContext
+)
+
+// Units accepted by excerpt_unit. Windows are always cut to the character bounds
+// above first; words and sentences then shrink them to whole units.
+const (
+	ExcerptUnitChars     = "chars"
+	ExcerptUnitWords     = "words"
+	ExcerptUnitSentences = "sentences"
+)
 
+// extractExcerpt extracts a relevant excerpt from the document
+func (sat *SearchArchitectureTool) extractExcerpt(content string, queryTokens []string, unit string) string {
 	if len(content) == 0 {
 		return ""
 	}
@@ -277,29 +713,114 @@ func (sat *SearchArchitectureTool) extractExcerpt(content string, queryTokens []
 		}
 	}
 
+	// Matches inside code blocks still place the excerpt when code is collapsed
+	text, position := sat.excerptSource(content)
+
 	// If no match found, return beginning of content
 	if bestPos == -1 {
-		if len(content) <= maxExcerptLength {
-			return content
+		return excerptWindow(text, 0, 0, maxExcerptLength, unit)
+	}
+
+	return excerptAround(text, position(bestPos), unit)
+}
+
+// excerptSource returns the text excerpts are cut from, with fenced code blocks
+// collapsed when that is configured, and maps offsets in content onto it
+func (sat *SearchArchitectureTool) excerptSource(content string) (string, func(int) int) {
+	if !sat.collapseCode {
+		return content, func(pos int) int { return pos }
+	}
+	collapsed := collapseCodeBlocks(content)
+	return collapsed.text, collapsed.position
+}
+
+// extractExcerpts returns up to maxExcerpts non-overlapping excerpts, one per cluster
+// of matches. Clusters with the most matches win; the excerpts keep document order.
+func (sat *SearchArchitectureTool) extractExcerpts(content string, queryTokens []string, maxExcerpts int, unit string) []string {
+	contentLower := strings.ToLower(content)
+	text, position := sat.excerptSource(content)
+
+	var positions []int
+	for _, token := range queryTokens {
+		offset := 0
+		for {
+			pos := strings.Index(contentLower[offset:], token)
+			if pos == -1 {
+				break
+			}
+			positions = append(positions, position(offset+pos))
+			offset += pos + len(token)
+		}
+	}
+
+	if len(positions) == 0 {
+		return []string{sat.extractExcerpt(content, queryTokens, unit)}
+	}
+	sort.Ints(positions)
+
+	// A match joins the current cluster while its window would overlap the
+	// cluster's first window; otherwise it starts a new cluster
+	type cluster struct {
+		start   int
+		matches int
+	}
+	var clusters []cluster
+	for _, pos := range positions {
+		last := len(clusters) - 1
+		if last >= 0 && pos < clusters[last].start+excerptLeadingContext+excerptTrailingContext {
+			clusters[last].matches++
+			continue
 		}
-		return content[:maxExcerptLength] + "..."
+		clusters = append(clusters, cluster{start: pos, matches: 1})
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].matches > clusters[j].matches
+	})
+	if len(clusters) > maxExcerpts {
+		clusters = clusters[:maxExcerpts]
 	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].start < clusters[j].start
+	})
 
-	// Extract excerpt around the match
-	start := bestPos - 50
+	excerpts := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		excerpts = append(excerpts, excerptAround(text, c.start, unit))
+	}
+	return excerpts
+}
+
+// excerptAround cuts an excerpt window around a match position
+func excerptAround(content string, pos int, unit string) string {
+	start := pos - excerptLeadingContext
 	if start < 0 {
 		start = 0
 	}
 
-	end := bestPos + 150
+	end := pos + excerptTrailingContext
+	if end > len(content) {
+		end = len(content)
+	}
+
+	return excerptWindow(content, pos, start, end, unit)
+}
+
+// excerptWindow trims content[start:end] to the excerpt unit, keeping pos inside
+// the window, and marks truncated ends with an ellipsis
+func excerptWindow(content string, pos, start, end int, unit string) string {
 	if end > len(content) {
 		end = len(content)
 	}
 
-	excerpt := content[start:end]
+	switch unit {
+	case ExcerptUnitWords:
+		start, end = trimToWords(content, pos, start, end)
+	case ExcerptUnitSentences:
+		start, end = trimToSentences(content, pos, start, end)
+	}
 
-	// Trim to word boundaries
-	excerpt = strings.TrimSpace(excerpt)
+	excerpt := strings.TrimSpace(content[start:end])
 
 	// Add ellipsis if truncated
 	if start > 0 {
@@ -312,14 +833,88 @@ func (sat *SearchArchitectureTool) extractExcerpt(content string, queryTokens []
 	return excerpt
 }
 
+// trimToWords shrinks a window so it neither starts nor ends inside a word. A
+// window holding a single partial word is left as is.
+func trimToWords(content string, pos, start, end int) (int, int) {
+	if start > 0 && !isSpace(content[start-1]) {
+		if i := strings.IndexAny(content[start:pos], excerptSpace); i != -1 {
+			start += i + 1
+		}
+	}
+
+	if end < len(content) && !isSpace(content[end]) {
+		if i := strings.LastIndexAny(content[pos:end], excerptSpace); i > 0 {
+			end = pos + i
+		}
+	}
+
+	return start, end
+}
+
+// trimToSentences shrinks a window to the sentences around pos, falling back to
+// word boundaries on a side where no sentence boundary fits inside the window
+func trimToSentences(content string, pos, start, end int) (int, int) {
+	sentenceStart, sentenceEnd := start, end
+
+	if start > 0 {
+		sentenceStart = -1
+		for i := pos - 1; i >= start; i-- {
+			if isSentenceEnd(content, i) {
+				sentenceStart = i + 1
+				break
+			}
+		}
+	}
+
+	if end < len(content) {
+		sentenceEnd = -1
+		for i := pos; i < end; i++ {
+			if isSentenceEnd(content, i) {
+				sentenceEnd = i + 1
+				break
+			}
+		}
+	}
+
+	wordStart, wordEnd := trimToWords(content, pos, start, end)
+	if sentenceStart == -1 {
+		sentenceStart = wordStart
+	}
+	if sentenceEnd == -1 {
+		sentenceEnd = wordEnd
+	}
+
+	return sentenceStart, sentenceEnd
+}
+
+// isSentenceEnd reports whether content[i] closes a sentence: a line break, or
+// terminal punctuation followed by whitespace or the end of the content
+func isSentenceEnd(content string, i int) bool {
+	switch content[i] {
+	case '\n':
+		return true
+	case '.', '!', '?':
+		return i+1 == len(content) || isSpace(content[i+1])
+	}
+	return false
+}
+
+// excerptSpace lists the whitespace bytes words are separated by
+const excerptSpace = " \t\n\r"
+
+// isSpace reports whether b is one of excerptSpace
+func isSpace(b byte) bool {
+	return strings.IndexByte(excerptSpace, b) != -1
+}
+
 // generateURI creates a proper architecture:// URI for a document
 func (sat *SearchArchitectureTool) generateURI(category, path string) string {
 	// Extract filename from path
 	parts := strings.Split(path, "/")
 	filename := parts[len(parts)-1]
 
-	// Remove .md extension
-	filename = strings.TrimSuffix(filename, config.MarkdownExtension)
+	// Remove markdown extension
+	filename = config.TrimMarkdownExtension(filename)
 
 	// Map category to URI path segment
 	var uriCategory string
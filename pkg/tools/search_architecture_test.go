@@ -2,6 +2,8 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/errors"
 	"mcp-architecture-service/pkg/logging"
 )
 
@@ -117,8 +120,12 @@ func TestSearchArchitectureTool_InputSchema(t *testing.T) {
 		t.Fatal("Schema should have 'resource_type' property")
 	}
 
-	if resourceTypeProperty["type"] != "string" {
-		t.Error("Resource_type property should be string type")
+	variants, ok := resourceTypeProperty["oneOf"].([]interface{})
+	if !ok || len(variants) != 2 {
+		t.Fatal("Resource_type property should accept a string or an array")
+	}
+	if variants[0].(map[string]interface{})["type"] != "string" || variants[1].(map[string]interface{})["type"] != "array" {
+		t.Error("Resource_type variants should be string and array")
 	}
 
 	// Verify max_results property
@@ -314,6 +321,208 @@ func TestSearchArchitectureTool_Execute_ResourceTypeFiltering(t *testing.T) {
 	}
 }
 
+// TestSearchArchitectureTool_Execute_MultipleResourceTypes tests searching several categories at once
+func TestSearchArchitectureTool_Execute_MultipleResourceTypes(t *testing.T) {
+	cache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(cache, logger)
+	setupTestDocuments(cache)
+
+	tests := []struct {
+		name         string
+		resourceType interface{}
+		expectTypes  map[string]bool
+	}{
+		{
+			name:         "URI segment names",
+			resourceType: []interface{}{"patterns", "adr"},
+			expectTypes:  map[string]bool{config.CategoryPattern: true, config.CategoryADR: true},
+		},
+		{
+			name:         "category names",
+			resourceType: []interface{}{config.CategoryGuideline, config.CategoryADR},
+			expectTypes:  map[string]bool{config.CategoryGuideline: true, config.CategoryADR: true},
+		},
+		{
+			name:         "single entry array",
+			resourceType: []string{config.CategoryPattern},
+			expectTypes:  map[string]bool{config.CategoryPattern: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), map[string]interface{}{
+				"query":         "architecture overview implementation microservices guidelines",
+				"resource_type": tt.resourceType,
+			})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			results := result.(map[string]interface{})["results"].([]map[string]interface{})
+			seen := make(map[string]bool)
+			for _, res := range results {
+				resType := res["resource_type"].(string)
+				if !tt.expectTypes[resType] {
+					t.Errorf("Result of excluded type %s returned", resType)
+				}
+				seen[resType] = true
+			}
+			for expected := range tt.expectTypes {
+				if !seen[expected] {
+					t.Errorf("Expected results from %s", expected)
+				}
+			}
+		})
+	}
+}
+
+// TestParseResourceTypes tests resource_type argument normalization
+func TestParseResourceTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		expected  map[string]bool
+		wantError bool
+	}{
+		{"missing", nil, nil, false},
+		{"all", "all", nil, false},
+		{"single category", config.CategoryADR, map[string]bool{config.CategoryADR: true}, false},
+		{"uri segment", "patterns", map[string]bool{config.CategoryPattern: true}, false},
+		{"array", []interface{}{"guidelines", "pattern"}, map[string]bool{config.CategoryGuideline: true, config.CategoryPattern: true}, false},
+		{"string slice", []string{"adr", "adr"}, map[string]bool{config.CategoryADR: true}, false},
+		{"unknown name", "runbooks", nil, true},
+		{"unknown entry", []interface{}{"patterns", "runbooks"}, nil, true},
+		{"non-string entry", []interface{}{"patterns", 7}, nil, true},
+		{"empty array", []interface{}{}, nil, true},
+		{"wrong type", 42, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, err := parseResourceTypes(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("parseResourceTypes() error = %v, wantError %v", err, tt.wantError)
+			}
+			if (categories == nil) != (tt.expected == nil) || len(categories) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, categories)
+			}
+			for category := range tt.expected {
+				if !categories[category] {
+					t.Errorf("Expected category %s in %v", category, categories)
+				}
+			}
+		})
+	}
+}
+
+// TestSearchArchitectureTool_ResourceTypeValidatedByExecutor tests that invalid
+// resource_type values are rejected by the executor before Execute runs
+func TestSearchArchitectureTool_ResourceTypeValidatedByExecutor(t *testing.T) {
+	logger := logging.NewStructuredLogger("test")
+	executor := NewToolExecutor(logger)
+	tool := NewSearchArchitectureTool(cache.NewDocumentCache(), logger)
+
+	tests := []struct {
+		name         string
+		resourceType interface{}
+		wantError    string
+	}{
+		{"valid category", "pattern", ""},
+		{"valid uri segment", "guidelines", ""},
+		{"valid all", "all", ""},
+		{"valid array", []interface{}{"adr", "patterns"}, ""},
+		{"invalid string", "invalid", `field resource_type has invalid value "invalid", must be one of:`},
+		{"invalid array entry", []interface{}{"patterns", "runbooks"}, `field resource_type[1] has invalid value "runbooks"`},
+		{"all inside array", []interface{}{"all"}, `field resource_type[0] has invalid value "all"`},
+		{"non-string entry", []interface{}{"patterns", 7.0}, "field resource_type[1] must be a string"},
+		{"empty array", []interface{}{}, "field resource_type must contain at least 1 items"},
+		{"wrong type", 42.0, "field resource_type has an unsupported type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.ValidateArguments(tool, map[string]interface{}{
+				"query":         "test",
+				"resource_type": tt.resourceType,
+			})
+
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("Expected valid resource_type, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+// TestSearchArchitectureTool_MaxResultsValidatedByExecutor tests that max_results bounds
+// declared in the schema are enforced by the executor, for JSON and Go integers alike
+func TestSearchArchitectureTool_MaxResultsValidatedByExecutor(t *testing.T) {
+	logger := logging.NewStructuredLogger("test")
+	executor := NewToolExecutor(logger)
+	docCache := cache.NewDocumentCache()
+	setupTestDocuments(docCache)
+	tool := NewSearchArchitectureTool(docCache, logger)
+
+	tests := []struct {
+		name       string
+		maxResults interface{}
+		wantError  string
+	}{
+		{"zero from JSON", 0.0, "field max_results must be at least 1"},
+		{"negative int", -3, "field max_results must be at least 1"},
+		{"too large from JSON", 21.0, "field max_results must be at most 20"},
+		{"too large int", 100, "field max_results must be at most 20"},
+		{"lower bound", 1.0, ""},
+		{"upper bound", 20, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Execute(context.Background(), tool, map[string]interface{}{
+				"query":       "architecture",
+				"max_results": tt.maxResults,
+			})
+
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("Expected max_results %v to be accepted, got: %v", tt.maxResults, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantError, err)
+			}
+		})
+	}
+
+	t.Run("omitted uses default", func(t *testing.T) {
+		for i := 0; i < defaultSearchResults+5; i++ {
+			path := fmt.Sprintf("mcp/resources/guidelines/extra-%d.md", i)
+			docCache.Set(path, &models.Document{
+				Metadata: models.DocumentMetadata{Title: fmt.Sprintf("Extra %d", i), Category: config.CategoryGuideline, Path: path},
+				Content:  models.DocumentContent{RawContent: "architecture notes"},
+			})
+		}
+
+		result, err := executor.Execute(context.Background(), tool, map[string]interface{}{"query": "architecture"})
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		results := result.(map[string]interface{})["results"].([]map[string]interface{})
+		if len(results) != defaultSearchResults {
+			t.Errorf("Expected %d results by default, got %d", defaultSearchResults, len(results))
+		}
+	})
+}
+
 // TestSearchArchitectureTool_Execute_ResultLimiting tests max_results parameter
 func TestSearchArchitectureTool_Execute_ResultLimiting(t *testing.T) {
 	cache := cache.NewDocumentCache()
@@ -452,6 +661,35 @@ func TestSearchArchitectureTool_Execute_RelevanceScoring(t *testing.T) {
 	}
 }
 
+func TestSearchArchitectureTool_RankDocuments(t *testing.T) {
+	tool := NewSearchArchitectureTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+
+	newDoc := func(name, content string) *models.Document {
+		return &models.Document{
+			Metadata: models.DocumentMetadata{Title: name, Category: config.CategoryPattern, Path: "mcp/resources/patterns/" + name + ".md"},
+			Content:  models.DocumentContent{RawContent: content},
+		}
+	}
+	documents := []*models.Document{
+		newDoc("circuit-breaker", "Stop calling a failing dependency for a while."),
+		newDoc("cache-aside", "Read through a caching layer: check the caching tier first, then load and populate the caching tier."),
+		newDoc("read-model", "Serve queries from a denormalized read model, optionally with caching."),
+	}
+
+	ranked := tool.RankDocuments("caching", documents)
+	var names []string
+	for _, doc := range ranked {
+		names = append(names, doc.Metadata.Title)
+	}
+	if strings.Join(names, ",") != "cache-aside,read-model" {
+		t.Errorf("Expected cache-aside then read-model with circuit-breaker left out, got %v", names)
+	}
+
+	if ranked := tool.RankDocuments("kubernetes", documents); len(ranked) != 0 {
+		t.Errorf("Expected no documents for an unmatched query, got %d", len(ranked))
+	}
+}
+
 // TestSearchArchitectureTool_Execute_ExcerptExtraction tests excerpt extraction
 func TestSearchArchitectureTool_Execute_ExcerptExtraction(t *testing.T) {
 	cache := cache.NewDocumentCache()
@@ -567,6 +805,22 @@ func TestSearchArchitectureTool_Execute_InvalidArguments(t *testing.T) {
 			},
 			wantError: "must be between 1 and 20",
 		},
+		{
+			name: "max_excerpts too large",
+			arguments: map[string]interface{}{
+				"query":        "test",
+				"max_excerpts": MaxExcerptsPerResult + 1,
+			},
+			wantError: "max_excerpts must be between 1 and",
+		},
+		{
+			name: "unknown excerpt_unit",
+			arguments: map[string]interface{}{
+				"query":        "test",
+				"excerpt_unit": "paragraphs",
+			},
+			wantError: "excerpt_unit must be one of",
+		},
 	}
 
 	for _, tt := range tests {
@@ -584,6 +838,452 @@ func TestSearchArchitectureTool_Execute_InvalidArguments(t *testing.T) {
 	}
 }
 
+// TestSearchArchitectureTool_Execute_QueryLength tests that blank and too-short queries
+// are rejected as validation errors once surrounding whitespace is trimmed
+func TestSearchArchitectureTool_Execute_QueryLength(t *testing.T) {
+	cache := cache.NewDocumentCache()
+	setupTestDocuments(cache)
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(cache, logger)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		query          string
+		minQueryLength int
+		wantError      string
+	}{
+		{name: "empty query", query: "", wantError: "query must not be empty or whitespace"},
+		{name: "whitespace-only query", query: " \t\n  ", wantError: "query must not be empty or whitespace"},
+		{name: "single character", query: "a", wantError: "query must be at least 2 characters, got 1"},
+		{name: "single character padded with whitespace", query: "   a   ", wantError: "query must be at least 2 characters, got 1"},
+		{name: "single multibyte character", query: "é", wantError: "query must be at least 2 characters, got 1"},
+		{name: "minimum length", query: " db "},
+		{name: "single character with lowered minimum", query: "a", minQueryLength: 1},
+		{name: "below raised minimum", query: "repo", minQueryLength: 5, wantError: "query must be at least 5 characters, got 4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minQueryLength := DefaultMinQueryLength
+			if tt.minQueryLength > 0 {
+				minQueryLength = tt.minQueryLength
+			}
+			if err := tool.SetMinQueryLength(minQueryLength); err != nil {
+				t.Fatalf("SetMinQueryLength failed: %v", err)
+			}
+
+			_, err := tool.Execute(ctx, map[string]interface{}{"query": tt.query})
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("Expected query %q to be accepted, got: %v", tt.query, err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected query %q to be rejected", tt.query)
+			}
+			structuredErr, ok := err.(*errors.StructuredError)
+			if !ok || structuredErr.Category != errors.ErrorCategoryValidation {
+				t.Errorf("Expected a validation error, got %T: %v", err, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantError, err)
+			}
+		})
+	}
+
+	if err := tool.SetMinQueryLength(0); err == nil {
+		t.Error("Expected an error for a minimum query length below 1")
+	}
+}
+
+// TestSearchArchitectureTool_Execute_MultipleExcerpts tests distinct excerpts for separated matches
+func TestSearchArchitectureTool_Execute_MultipleExcerpts(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(docCache, logger)
+
+	filler := strings.Repeat("Unrelated prose about team process and meeting cadence. ", 8)
+	docCache.Set("mcp/resources/guidelines/resilience.md", &models.Document{
+		Metadata: models.DocumentMetadata{
+			Title:    "Resilience",
+			Category: config.CategoryGuideline,
+			Path:     "mcp/resources/guidelines/resilience.md",
+		},
+		Content: models.DocumentContent{
+			RawContent: "# Resilience\n\n## Timeouts\nEvery outbound call needs a timeout.\n\n" + filler +
+				"\n\n## Retries\nRetries must back off before the timeout expires.\n\n" + filler +
+				"\n\n## Bulkheads\nIsolate pools so one timeout cannot exhaust threads.\n",
+		},
+	})
+
+	tests := []struct {
+		name          string
+		maxExcerpts   interface{}
+		expectedCount int
+	}{
+		{"default returns single excerpt", nil, 0},
+		{"two excerpts", float64(2), 2},
+		{"capped by match clusters", 5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]interface{}{"query": "timeout"}
+			if tt.maxExcerpts != nil {
+				arguments["max_excerpts"] = tt.maxExcerpts
+			}
+
+			result, err := tool.Execute(context.Background(), arguments)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			results := result.(map[string]interface{})["results"].([]map[string]interface{})
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %d", len(results))
+			}
+
+			if _, ok := results[0]["excerpt"].(string); !ok {
+				t.Error("Result should always have 'excerpt' field")
+			}
+
+			excerpts, _ := results[0]["excerpts"].([]string)
+			if len(excerpts) != tt.expectedCount {
+				t.Fatalf("Expected %d excerpts, got %d: %v", tt.expectedCount, len(excerpts), excerpts)
+			}
+
+			seen := make(map[string]bool)
+			for _, excerpt := range excerpts {
+				if !strings.Contains(strings.ToLower(excerpt), "timeout") {
+					t.Errorf("Excerpt should contain a match: %q", excerpt)
+				}
+				if len(strings.Trim(excerpt, ".")) > 200 {
+					t.Errorf("Excerpt exceeds length cap: %d chars", len(excerpt))
+				}
+				if seen[excerpt] {
+					t.Errorf("Duplicate excerpt returned: %q", excerpt)
+				}
+				seen[excerpt] = true
+			}
+			if tt.expectedCount == 3 && !strings.Contains(excerpts[2], "Bulkheads") {
+				t.Errorf("Expected excerpts in document order, last was %q", excerpts[2])
+			}
+		})
+	}
+}
+
+// TestSearchArchitectureTool_Execute_ExcerptUnit tests excerpt windows trimmed to each unit
+func TestSearchArchitectureTool_Execute_ExcerptUnit(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(docCache, logger)
+
+	content := "Services talk to each other over well defined contracts and versioned schemas. " +
+		"Consumers pin the contract version they were built against. " +
+		"A breaking change needs a new idempotency key strategy before rollout. " +
+		"Producers keep serving the previous version until every consumer has migrated away from it. " +
+		"Deprecation notices go out one release ahead of removal."
+	docCache.Set("mcp/resources/guidelines/contracts.md", &models.Document{
+		Metadata: models.DocumentMetadata{
+			Title:    "Contracts",
+			Category: config.CategoryGuideline,
+			Path:     "mcp/resources/guidelines/contracts.md",
+		},
+		Content: models.DocumentContent{RawContent: content},
+	})
+
+	isWholeWords := func(text string) bool {
+		start := strings.Index(content, text)
+		end := start + len(text)
+		return start != -1 &&
+			(start == 0 || content[start-1] == ' ') &&
+			(end == len(content) || content[end] == ' ')
+	}
+
+	tests := []struct {
+		name  string
+		unit  interface{}
+		check func(t *testing.T, text string)
+	}{
+		{
+			name: "chars by default",
+			unit: nil,
+			check: func(t *testing.T, text string) {
+				if isWholeWords(text) {
+					t.Errorf("Expected a character window cutting into words, got %q", text)
+				}
+			},
+		},
+		{
+			name: "whole words",
+			unit: ExcerptUnitWords,
+			check: func(t *testing.T, text string) {
+				if !isWholeWords(text) {
+					t.Errorf("Expected excerpt to start and end on word boundaries, got %q", text)
+				}
+			},
+		},
+		{
+			name: "whole sentences",
+			unit: ExcerptUnitSentences,
+			check: func(t *testing.T, text string) {
+				if !strings.HasPrefix(text, "A breaking change") || !strings.HasSuffix(text, "before rollout.") {
+					t.Errorf("Expected the sentence holding the match, got %q", text)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]interface{}{"query": "idempotency", "max_excerpts": 2}
+			if tt.unit != nil {
+				arguments["excerpt_unit"] = tt.unit
+			}
+
+			result, err := tool.Execute(context.Background(), arguments)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			results := result.(map[string]interface{})["results"].([]map[string]interface{})
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %d", len(results))
+			}
+
+			excerpts := append([]string{results[0]["excerpt"].(string)}, results[0]["excerpts"].([]string)...)
+			for _, excerpt := range excerpts {
+				text := strings.TrimSuffix(strings.TrimPrefix(excerpt, "..."), "...")
+				if len(text) > maxExcerptLength {
+					t.Errorf("Excerpt exceeds length cap: %d chars", len(text))
+				}
+				if !strings.Contains(text, "idempotency") {
+					t.Errorf("Excerpt should contain the match: %q", text)
+				}
+				tt.check(t, text)
+			}
+		})
+	}
+}
+
+// TestSearchArchitectureTool_ExecuteStream tests incremental delivery of search results
+func TestSearchArchitectureTool_ExecuteStream(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(docCache, logger)
+
+	for i := 1; i <= 6; i++ {
+		path := fmt.Sprintf("mcp/resources/patterns/caching-%d.md", i)
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{
+				Title:    fmt.Sprintf("Pattern %d", i),
+				Category: config.CategoryPattern,
+				Path:     path,
+			},
+			Content: models.DocumentContent{
+				RawContent: strings.Repeat("Caching keeps reads fast. ", i) + strings.Repeat("Unrelated filler text. ", 6-i),
+			},
+		})
+	}
+
+	errStop := fmt.Errorf("client went away")
+
+	tests := []struct {
+		name         string
+		maxResults   int
+		arrivalOrder bool
+		stopAfter    int
+		wantCount    int
+		wantErr      error
+	}{
+		{"score order", 4, false, 0, 4, nil},
+		{"arrival order", 4, true, 0, 4, nil},
+		{"arrival order beyond matches", 10, true, 0, 6, nil},
+		{"callback error stops stream", 4, false, 2, 2, errStop},
+		{"callback error stops arrival stream", 4, true, 2, 2, errStop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]interface{}{"query": "caching", "max_results": tt.maxResults}
+
+			var streamed []map[string]interface{}
+			err := tool.ExecuteStream(context.Background(), arguments, tt.arrivalOrder, func(result map[string]interface{}) error {
+				streamed = append(streamed, result)
+				if len(streamed) == tt.stopAfter {
+					return errStop
+				}
+				return nil
+			})
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(streamed) != tt.wantCount {
+				t.Fatalf("Expected %d streamed results, got %d", tt.wantCount, len(streamed))
+			}
+
+			if tt.arrivalOrder || tt.wantErr != nil {
+				return
+			}
+
+			batch, err := tool.Execute(context.Background(), arguments)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			batchResults := batch.(map[string]interface{})["results"].([]map[string]interface{})
+			for i, result := range streamed {
+				if i > 0 && result["relevance_score"].(float64) > streamed[i-1]["relevance_score"].(float64) {
+					t.Errorf("Result %d scored higher than the one before it", i)
+				}
+				if result["uri"] != batchResults[i]["uri"] {
+					t.Errorf("Result %d: streamed %v, batch returned %v", i, result["uri"], batchResults[i]["uri"])
+				}
+			}
+		})
+	}
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		err := tool.ExecuteStream(context.Background(), map[string]interface{}{}, false, func(map[string]interface{}) error {
+			t.Error("No results should be emitted for invalid arguments")
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "query argument must be a string") {
+			t.Errorf("Expected query validation error, got %v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := tool.ExecuteStream(ctx, map[string]interface{}{"query": "caching"}, true, func(map[string]interface{}) error {
+			t.Error("No results should be emitted after cancellation")
+			return nil
+		})
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+// TestSearchArchitectureTool_Execute_EmptyContentDocument tests that documents cached
+// without content produce a warning instead of a result
+func TestSearchArchitectureTool_Execute_EmptyContentDocument(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(docCache, logger)
+
+	setupTestDocuments(docCache)
+	docCache.Set("mcp/resources/patterns/repository-caching.md", &models.Document{
+		Metadata: models.DocumentMetadata{
+			Title:    "Repository Caching",
+			Category: config.CategoryPattern,
+			Path:     "mcp/resources/patterns/repository-caching.md",
+		},
+		Content: models.DocumentContent{RawContent: "  \n"},
+	})
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"query": "repository"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	resultMap := result.(map[string]interface{})
+
+	for _, res := range resultMap["results"].([]map[string]interface{}) {
+		if res["uri"] == "architecture://patterns/repository-caching" {
+			t.Error("Empty document should not be returned as a result")
+		}
+	}
+
+	warnings, ok := resultMap["warnings"].([]string)
+	if !ok || len(warnings) != 1 || !strings.Contains(warnings[0], "repository-caching.md has no content") {
+		t.Errorf("Expected a warning for the empty document, got %v", resultMap["warnings"])
+	}
+
+	// Documents outside the searched categories are not reported
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"query": "repository", "resource_type": "adr"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if _, ok := result.(map[string]interface{})["warnings"]; ok {
+		t.Error("Expected no warnings when the empty document's category is not searched")
+	}
+}
+
+// TestSearchArchitectureTool_Execute_NonEnglishDocumentation tests accented queries
+// and a configured non-English stop-word set
+func TestSearchArchitectureTool_Execute_NonEnglishDocumentation(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	tool := NewSearchArchitectureTool(docCache, logger)
+
+	docs := map[string]string{
+		"mcp/resources/guidelines/datenbank.md": "# Datenbank\n\nJeder Dienst besitzt seine eigene Datenbank.",
+		"mcp/resources/guidelines/teams.md":     "# Teams\n\nDie Teams planen die Arbeit und die Releases.",
+		"mcp/resources/guidelines/cache.md":     "# Cache\n\nLa mise en cache côté serveur réduit la latence.",
+	}
+	for path, content := range docs {
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Title: path, Category: config.CategoryGuideline, Path: path},
+			Content:  models.DocumentContent{RawContent: content},
+		})
+	}
+
+	search := func(query string) []string {
+		t.Helper()
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"query": query})
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		var uris []string
+		for _, res := range result.(map[string]interface{})["results"].([]map[string]interface{}) {
+			uris = append(uris, res["uri"].(string))
+		}
+		return uris
+	}
+
+	if uris := search("CÔTÉ SERVEUR"); len(uris) != 1 || uris[0] != "architecture://guidelines/cache" {
+		t.Errorf("Expected accented query to match the cache guideline case-insensitively, got %v", uris)
+	}
+
+	if uris := search("die Datenbank"); len(uris) != 2 {
+		t.Errorf("Expected the default to keep \"die\" and match 2 documents, got %v", uris)
+	}
+
+	stopWords, err := StopWordsFor("de")
+	if err != nil {
+		t.Fatalf("StopWordsFor failed: %v", err)
+	}
+	tool.SetStopWords(stopWords)
+
+	if uris := search("die Datenbank"); len(uris) != 1 || uris[0] != "architecture://guidelines/datenbank" {
+		t.Errorf("Expected German stop words to drop \"die\", got %v", uris)
+	}
+}
+
+// TestSearchArchitectureTool_StopWordsOptIn tests that queries keep every word
+// unless stop words are configured
+func TestSearchArchitectureTool_StopWordsOptIn(t *testing.T) {
+	tool := NewSearchArchitectureTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+
+	expected := []string{"the", "repository", "and", "the", "cache"}
+	if tokens := tool.tokenize("The repository and the cache"); !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("tokenize() by default = %v, want %v", tokens, expected)
+	}
+
+	stopWords, err := StopWordsFor("en")
+	if err != nil {
+		t.Fatalf("StopWordsFor failed: %v", err)
+	}
+	tool.SetStopWords(stopWords)
+	expected = []string{"repository", "cache"}
+	if tokens := tool.tokenize("The repository and the cache"); !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("tokenize() with English stop words = %v, want %v", tokens, expected)
+	}
+}
+
 // TestSearchArchitectureTool_Execute_EmptyCache tests search with no documents
 func TestSearchArchitectureTool_Execute_EmptyCache(t *testing.T) {
 	cache := cache.NewDocumentCache()
@@ -920,3 +1620,163 @@ We will adopt a microservices architecture to enable independent deployment and
 	cache.Set(pattern.Metadata.Path, pattern)
 	cache.Set(adr.Metadata.Path, adr)
 }
+
+// TestSearchArchitectureTool_CategoryBoosts tests that a boosted category outranks
+// an equally relevant document in another category
+func TestSearchArchitectureTool_CategoryBoosts(t *testing.T) {
+	content := "# Retry Handling\n\nRetry transient failures with exponential backoff and jitter."
+	for _, tt := range []struct {
+		name    string
+		boosts  map[string]float64
+		wantTop string
+	}{
+		{"boosted pattern ranks first", map[string]float64{"patterns": 2}, config.CategoryPattern},
+		{"boosted guideline ranks first", map[string]float64{config.CategoryGuideline: 2}, config.CategoryGuideline},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			docCache := cache.NewDocumentCache()
+			for _, category := range []string{config.CategoryGuideline, config.CategoryPattern} {
+				path := "mcp/resources/" + category + "/retry-handling.md"
+				docCache.Set(path, &models.Document{
+					Metadata: models.DocumentMetadata{Title: "Retry Handling", Category: category, Path: path},
+					Content:  models.DocumentContent{RawContent: content},
+				})
+			}
+
+			tool := NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test"))
+			if err := tool.SetCategoryBoosts(tt.boosts); err != nil {
+				t.Fatalf("SetCategoryBoosts failed: %v", err)
+			}
+
+			result, err := tool.Execute(context.Background(), map[string]interface{}{"query": "retry backoff"})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			results := result.(map[string]interface{})["results"].([]map[string]interface{})
+			if len(results) != 2 {
+				t.Fatalf("Expected 2 results, got %d", len(results))
+			}
+
+			if results[0]["resource_type"] != tt.wantTop {
+				t.Errorf("Expected %s to rank first, got %v", tt.wantTop, results[0]["resource_type"])
+			}
+			top, other := results[0]["relevance_score"].(float64), results[1]["relevance_score"].(float64)
+			if top != 2*other {
+				t.Errorf("Expected the boosted score to be double the other, got %.2f and %.2f", top, other)
+			}
+		})
+	}
+
+	t.Run("invalid boosts are rejected", func(t *testing.T) {
+		tool := NewSearchArchitectureTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+		for _, boosts := range []map[string]float64{{"runbook": 2}, {config.CategoryADR: 0}} {
+			if err := tool.SetCategoryBoosts(boosts); err == nil {
+				t.Errorf("Expected SetCategoryBoosts(%v) to fail", boosts)
+			}
+		}
+	})
+}
+
+// TestSearchArchitectureTool_FilenameMatch tests that a query term in a document's
+// filename outranks a sparse match in another document's body
+func TestSearchArchitectureTool_FilenameMatch(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	docs := []models.DocumentMetadata{
+		{Title: "Persistence Layer", Category: config.CategoryPattern, Path: "mcp/resources/patterns/repository-pattern.md"},
+		{Title: "Data Access", Category: config.CategoryPattern, Path: "mcp/resources/patterns/data-access.md"},
+	}
+	contents := []string{
+		"# Persistence Layer\n\nKeep queries behind an interface so callers never touch SQL. A repository hides storage details.",
+		"# Data Access\n\nKeep queries behind an interface. The repository layer, like any repository, hides storage details.",
+	}
+	for i, metadata := range docs {
+		docCache.Set(metadata.Path, &models.Document{Metadata: metadata, Content: models.DocumentContent{RawContent: contents[i]}})
+	}
+
+	tool := NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test"))
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"query": "repository"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	results := result.(map[string]interface{})["results"].([]map[string]interface{})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0]["title"] != "Persistence Layer" {
+		t.Errorf("Expected the document named after the query to rank first, got %v", results[0]["title"])
+	}
+
+	// The filename counts for less than the title
+	titleScore := tool.calculateRelevance([]string{"repository"}, contents[1], "Repository", "data-access.md")
+	filenameScore := tool.calculateRelevance([]string{"repository"}, contents[1], "Data Access", "repository.md")
+	bodyScore := tool.calculateRelevance([]string{"repository"}, contents[1], "Data Access", "data-access.md")
+	if !(titleScore > filenameScore && filenameScore > bodyScore) {
+		t.Errorf("Expected title > filename > body scores, got %.2f, %.2f, %.2f", titleScore, filenameScore, bodyScore)
+	}
+}
+
+func TestDocumentIdentifier(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"mcp/resources/patterns/repository-pattern.md", "repository pattern"},
+		{"mcp/resources/adr/001_Event_Sourcing.markdown", "001 event sourcing"},
+		{"api-design", "api design"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := documentIdentifier(tt.path); got != tt.want {
+				t.Errorf("documentIdentifier(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSearchArchitectureTool_CollapseCodeExcerpts tests that terms inside code blocks
+// still match while the blocks are collapsed in returned excerpts
+func TestSearchArchitectureTool_CollapseCodeExcerpts(t *testing.T) {
+	content := "# Retry Client\n\nWrap outbound calls in a client that retries.\n\n```go\nfunc withBackoff(call func() error) error {\n\treturn call()\n}\n```\n\nTune the attempts per dependency."
+	path := "mcp/resources/patterns/retry-client.md"
+
+	for _, tt := range []struct {
+		name     string
+		collapse bool
+	}{
+		{"collapsed", true},
+		{"shown by default", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			docCache := cache.NewDocumentCache()
+			docCache.Set(path, &models.Document{
+				Metadata: models.DocumentMetadata{Title: "Retry Client", Category: config.CategoryPattern, Path: path},
+				Content:  models.DocumentContent{RawContent: content},
+			})
+			tool := NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test"))
+			tool.SetCollapseCodeExcerpts(tt.collapse)
+
+			// "withbackoff" only appears inside the code block
+			result, err := tool.Execute(context.Background(), map[string]interface{}{"query": "withBackoff", "max_excerpts": 2})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			results := result.(map[string]interface{})["results"].([]map[string]interface{})
+			if len(results) != 1 {
+				t.Fatalf("Expected the code block match to be scored, got %d results", len(results))
+			}
+
+			excerpts := append([]string{results[0]["excerpt"].(string)}, results[0]["excerpts"].([]string)...)
+			for _, excerpt := range excerpts {
+				hasCode := strings.Contains(excerpt, "func withBackoff")
+				hasMarker := strings.Contains(excerpt, "[code omitted]")
+				if tt.collapse && (hasCode || !hasMarker) {
+					t.Errorf("Expected the code block to be collapsed, got %q", excerpt)
+				}
+				if !tt.collapse && (!hasCode || hasMarker) {
+					t.Errorf("Expected the code block to be shown, got %q", excerpt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCategoryBoost is the search score multiplier of a category without a
+// configured boost
+const DefaultCategoryBoost = 1.0
+
+// NormalizeCategoryBoosts validates per-category search score multipliers, keyed by
+// category or URI segment as resource_type accepts them ("pattern" or "patterns"),
+// and returns them keyed by category. Multipliers must be positive.
+func NormalizeCategoryBoosts(boosts map[string]float64) (map[string]float64, error) {
+	normalized := make(map[string]float64, len(boosts))
+	for name, boost := range boosts {
+		category, ok := categoryAliases[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown search boost category %q, expected one of %s",
+				name, strings.Join(searchableCategoryNames(), ", "))
+		}
+		if boost <= 0 || math.IsInf(boost, 0) || math.IsNaN(boost) {
+			return nil, fmt.Errorf("search boost for %s must be a positive number, got %v", category, boost)
+		}
+		normalized[category] = boost
+	}
+	return normalized, nil
+}
+
+// ParseCategoryBoosts parses a comma-separated list of category=multiplier pairs
+// such as "adr=1.5,patterns=2"
+func ParseCategoryBoosts(value string) (map[string]float64, error) {
+	boosts := make(map[string]float64)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, multiplier, found := strings.Cut(field, "=")
+		if !found {
+			return nil, fmt.Errorf("expected category=multiplier, got %q", field)
+		}
+		boost, err := strconv.ParseFloat(strings.TrimSpace(multiplier), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search boost %q for %s", multiplier, name)
+		}
+		boosts[name] = boost
+	}
+	return NormalizeCategoryBoosts(boosts)
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"mcp-architecture-service/pkg/config"
+)
+
+func TestParseCategoryBoosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{"empty", "", map[string]float64{}, false},
+		{"category names", "adr=1.5,pattern=2", map[string]float64{config.CategoryADR: 1.5, config.CategoryPattern: 2}, false},
+		{"URI segments and spacing", " guidelines = 0.5 , patterns=3", map[string]float64{config.CategoryGuideline: 0.5, config.CategoryPattern: 3}, false},
+		{"unknown category", "runbook=2", nil, true},
+		{"missing multiplier", "adr", nil, true},
+		{"non-numeric multiplier", "adr=high", nil, true},
+		{"zero multiplier", "adr=0", nil, true},
+		{"negative multiplier", "adr=-1", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCategoryBoosts(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCategoryBoosts(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCategoryBoosts(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
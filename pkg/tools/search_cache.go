@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSearchCacheSize is how many recent search-architecture results are kept
+const DefaultSearchCacheSize = 64
+
+// DefaultSearchCacheTTL is how long a cached search result is reused
+const DefaultSearchCacheTTL = 5 * time.Minute
+
+// searchResultCache is a small LRU of search outputs with a TTL. Each entry records
+// the document cache version it was computed at and is only served while the
+// document cache is still at that version. Outputs are shared between callers and
+// must not be modified.
+type searchResultCache struct {
+	mutex    sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+	hits     int64
+	misses   int64
+}
+
+// searchCacheEntry is a cached search output
+type searchCacheEntry struct {
+	key      string
+	version  uint64
+	output   map[string]interface{}
+	storedAt time.Time
+}
+
+// newSearchResultCache creates a cache holding up to capacity results for ttl
+func newSearchResultCache(capacity int, ttl time.Duration) *searchResultCache {
+	return &searchResultCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the output cached under key if it was computed at version and has
+// not expired
+func (c *searchResultCache) get(key string, version uint64) (map[string]interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	entry := element.Value.(*searchCacheEntry)
+	if entry.version != version || time.Since(entry.storedAt) > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		c.misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits++
+	return entry.output, true
+}
+
+// put caches output under key, evicting the least recently used entry when full
+func (c *searchResultCache) put(key string, version uint64, output map[string]interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := &searchCacheEntry{key: key, version: version, output: output, storedAt: time.Now()}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*searchCacheEntry).key)
+	}
+}
+
+// clear drops every cached output
+func (c *searchResultCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// searchCacheKey identifies searches that produce the same output. Queries are
+// compared after lowercasing and collapsing whitespace, matching tokenization. A nil
+// category set, searching every category, is written as * to keep it apart from an
+// empty set.
+func searchCacheKey(opts searchOptions) string {
+	categoryKey := "*"
+	if opts.categories != nil {
+		categories := make([]string, 0, len(opts.categories))
+		for category := range opts.categories {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		categoryKey = strings.Join(categories, ",")
+	}
+
+	return fmt.Sprintf("%s|%s|%d|%d|%s",
+		strings.Join(strings.Fields(strings.ToLower(opts.query)), " "),
+		categoryKey,
+		opts.maxResults, opts.maxExcerpts, opts.unit)
+}
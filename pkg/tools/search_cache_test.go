@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// TestSearchArchitectureTool_ResultCache tests that repeated searches are served from
+// the result cache until the documents change
+func TestSearchArchitectureTool_ResultCache(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	defer docCache.Close()
+	setupTestDocuments(docCache)
+	tool := NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test"))
+
+	ctx := context.Background()
+	search := func(arguments map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		result, err := tool.Execute(ctx, arguments)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		return result.(map[string]interface{})
+	}
+
+	first := search(map[string]interface{}{"query": "repository pattern"})
+	if tool.resultCache.hits != 0 || tool.resultCache.misses != 1 {
+		t.Fatalf("Expected a miss on the first search, got %d hits, %d misses", tool.resultCache.hits, tool.resultCache.misses)
+	}
+
+	t.Run("hit on repeated search", func(t *testing.T) {
+		second := search(map[string]interface{}{"query": "  Repository   PATTERN "})
+		if tool.resultCache.hits != 1 {
+			t.Fatalf("Expected a cache hit for the normalized query, got %d hits", tool.resultCache.hits)
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("Expected the cached result, got %v", second)
+		}
+	})
+
+	t.Run("miss for different filters", func(t *testing.T) {
+		hits := tool.resultCache.hits
+		search(map[string]interface{}{"query": "repository pattern", "resource_type": "guideline"})
+		search(map[string]interface{}{"query": "repository pattern", "max_results": 1})
+		if tool.resultCache.hits != hits {
+			t.Errorf("Expected searches with other filters to miss, got %d new hits", tool.resultCache.hits-hits)
+		}
+	})
+
+	t.Run("miss after a document changes", func(t *testing.T) {
+		docCache.Set("mcp/resources/patterns/unit-of-work.md", &models.Document{
+			Metadata: models.DocumentMetadata{
+				Title:    "Unit of Work",
+				Category: config.CategoryPattern,
+				Path:     "mcp/resources/patterns/unit-of-work.md",
+			},
+			Content: models.DocumentContent{RawContent: "# Unit of Work\n\nCoordinates repository pattern writes in one transaction."},
+		})
+
+		hits := tool.resultCache.hits
+		result := search(map[string]interface{}{"query": "repository pattern"})
+		if tool.resultCache.hits != hits {
+			t.Fatal("Expected a miss after a document was added")
+		}
+		if result["total_matches"] == first["total_matches"] {
+			t.Errorf("Expected the new document to be matched, got %v", result)
+		}
+
+		docCache.Invalidate("mcp/resources/patterns/unit-of-work.md")
+		result = search(map[string]interface{}{"query": "repository pattern"})
+		if tool.resultCache.hits != hits {
+			t.Fatal("Expected a miss after a document was removed")
+		}
+		if !reflect.DeepEqual(first, result) {
+			t.Errorf("Expected the original result once the document was removed, got %v", result)
+		}
+	})
+
+	t.Run("miss after stop words change", func(t *testing.T) {
+		hits := tool.resultCache.hits
+		tool.SetStopWords(defaultStopWords())
+		search(map[string]interface{}{"query": "repository pattern"})
+		if tool.resultCache.hits != hits {
+			t.Error("Expected changing stop words to clear cached results")
+		}
+	})
+}
+
+// TestSearchResultCache tests expiry and least-recently-used eviction
+func TestSearchResultCache(t *testing.T) {
+	output := map[string]interface{}{"total_matches": 0}
+
+	t.Run("expired entries miss", func(t *testing.T) {
+		c := newSearchResultCache(2, time.Millisecond)
+		c.put("a", 1, output)
+		time.Sleep(5 * time.Millisecond)
+		if _, ok := c.get("a", 1); ok {
+			t.Error("Expected an expired entry to miss")
+		}
+	})
+
+	t.Run("other version misses", func(t *testing.T) {
+		c := newSearchResultCache(2, time.Minute)
+		c.put("a", 1, output)
+		if _, ok := c.get("a", 2); ok {
+			t.Error("Expected an entry from an older version to miss")
+		}
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		c := newSearchResultCache(2, time.Minute)
+		c.put("a", 1, output)
+		c.put("b", 1, output)
+		c.get("a", 1)
+		c.put("c", 1, output)
+
+		if _, ok := c.get("b", 1); ok {
+			t.Error("Expected the least recently used entry to be evicted")
+		}
+		for _, key := range []string{"a", "c"} {
+			if _, ok := c.get(key, 1); !ok {
+				t.Errorf("Expected %q to stay cached", key)
+			}
+		}
+	})
+}
+
+// TestSearchCacheKey tests that searches over every category and over none get
+// separate cache entries
+func TestSearchCacheKey(t *testing.T) {
+	all := searchCacheKey(searchOptions{query: "cache", maxResults: 5})
+	none := searchCacheKey(searchOptions{query: "cache", categories: map[string]bool{}, maxResults: 5})
+	adr := searchCacheKey(searchOptions{query: "cache", categories: map[string]bool{config.CategoryADR: true}, maxResults: 5})
+
+	if all == none || all == adr || none == adr {
+		t.Errorf("Expected distinct keys, got %q, %q and %q", all, none, adr)
+	}
+}
+
+// TestSearchArchitectureTool_SetResultCache tests configuring and disabling the result cache
+func TestSearchArchitectureTool_SetResultCache(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	defer docCache.Close()
+	setupTestDocuments(docCache)
+	tool := NewSearchArchitectureTool(docCache, logging.NewStructuredLogger("test"))
+
+	if err := tool.SetResultCache(-1, time.Minute); err == nil {
+		t.Error("Expected an error for a negative size")
+	}
+	if err := tool.SetResultCache(8, 0); err == nil {
+		t.Error("Expected an error for a zero TTL")
+	}
+	if err := tool.SetResultCache(0, 0); err != nil {
+		t.Fatalf("Disabling the result cache failed: %v", err)
+	}
+	if tool.resultCache != nil {
+		t.Fatal("Expected the result cache to be disabled")
+	}
+	if _, err := tool.Execute(context.Background(), map[string]interface{}{"query": "repository"}); err != nil {
+		t.Errorf("Search without a result cache failed: %v", err)
+	}
+}
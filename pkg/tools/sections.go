@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-architecture-service/internal/models"
+)
+
+// DefaultSectionLevels are the heading levels of the named sections tools look
+// up, such as an ADR's "Decision" or a pattern's "Best Practices"
+var DefaultSectionLevels = []int{2}
+
+// maxHeadingLevel is the deepest markdown heading level
+const maxHeadingLevel = 6
+
+// sectionLevels is the set of heading levels that named sections may use
+type sectionLevels map[int]bool
+
+// newSectionLevels validates levels and returns them as a set
+func newSectionLevels(levels []int) (sectionLevels, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("at least one section heading level is required")
+	}
+
+	set := make(sectionLevels, len(levels))
+	for _, level := range levels {
+		if level < 1 || level > maxHeadingLevel {
+			return nil, fmt.Errorf("section heading level must be between 1 and %d, got %d", maxHeadingLevel, level)
+		}
+		set[level] = true
+	}
+	return set, nil
+}
+
+// ValidateSectionLevels returns an error unless levels holds at least one heading
+// level, each between 1 and 6
+func ValidateSectionLevels(levels []int) error {
+	_, err := newSectionLevels(levels)
+	return err
+}
+
+// defaultSectionLevels returns DefaultSectionLevels as a set
+func defaultSectionLevels() sectionLevels {
+	set, _ := newSectionLevels(DefaultSectionLevels)
+	return set
+}
+
+// ParseSectionLevels parses a comma-separated list of heading levels such as "2,3"
+func ParseSectionLevels(value string) ([]int, error) {
+	var levels []int
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		level, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid section heading level %q", field)
+		}
+		levels = append(levels, level)
+	}
+
+	if err := ValidateSectionLevels(levels); err != nil {
+		return nil, err
+	}
+	sort.Ints(levels)
+	return levels, nil
+}
+
+// ParseSections parses the markdown heading tree of content. Each section holds the
+// text between its heading and the next heading, and the deeper sections under it
+// as Subsections. Text before the first heading belongs to no section, and lines
+// inside fenced code blocks are never headings.
+func ParseSections(content string) []models.DocumentSection {
+	var roots []models.DocumentSection
+	// stack holds the path from a root to the section being filled, as indexes
+	// into each parent's children so appends never leave stale pointers
+	var stack []int
+	var lines []string
+
+	current := func() *models.DocumentSection {
+		if len(stack) == 0 {
+			return nil
+		}
+		section := &roots[stack[0]]
+		for _, index := range stack[1:] {
+			section = &section.Subsections[index]
+		}
+		return section
+	}
+	flush := func() {
+		if section := current(); section != nil {
+			section.Content = strings.TrimSpace(strings.Join(lines, "\n"))
+		}
+		lines = nil
+	}
+
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if isFenceLine(line) {
+			inFence = !inFence
+		}
+
+		level, heading, ok := 0, "", false
+		if !inFence {
+			level, heading, ok = parseHeading(line)
+		}
+		if !ok {
+			lines = append(lines, line)
+			continue
+		}
+
+		flush()
+		for len(stack) > 0 && current().Level >= level {
+			stack = stack[:len(stack)-1]
+		}
+
+		section := models.DocumentSection{Heading: heading, Level: level}
+		if parent := current(); parent != nil {
+			parent.Subsections = append(parent.Subsections, section)
+			stack = append(stack, len(parent.Subsections)-1)
+		} else {
+			roots = append(roots, section)
+			stack = append(stack, len(roots)-1)
+		}
+	}
+	flush()
+
+	return roots
+}
+
+// parseHeading returns the level and text of an ATX heading line such as "## Decision"
+func parseHeading(line string) (int, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0, "", false // indented code
+	}
+
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > maxHeadingLevel {
+		return 0, "", false
+	}
+
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+
+	// Drop an optional closing sequence of #s
+	heading := strings.TrimSpace(rest)
+	if stripped := strings.TrimRight(heading, "#"); stripped == "" || strings.HasSuffix(stripped, " ") {
+		heading = strings.TrimSpace(stripped)
+	}
+	return level, heading, true
+}
+
+// isFenceLine reports whether line opens or closes a fenced code block
+func isFenceLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// findSection returns the first section, searching depth first, whose heading is
+// at one of levels and starts with name, ignoring case, so "Decision" also finds
+// "Decision Outcome"
+func findSection(sections []models.DocumentSection, name string, levels sectionLevels) (models.DocumentSection, bool) {
+	name = strings.ToLower(name)
+	for _, section := range sections {
+		if levels[section.Level] && strings.HasPrefix(strings.ToLower(section.Heading), name) {
+			return section, true
+		}
+		if found, ok := findSection(section.Subsections, name, levels); ok {
+			return found, true
+		}
+	}
+	return models.DocumentSection{}, false
+}
+
+// extractSection returns the text of the section named name in content, including
+// its subsections with their headings, or an empty string when there is none
+func extractSection(content, name string, levels sectionLevels) string {
+	section, ok := findSection(ParseSections(content), name, levels)
+	if !ok {
+		return ""
+	}
+	return sectionText(section)
+}
+
+// sectionText renders a section's content followed by its subsections
+func sectionText(section models.DocumentSection) string {
+	parts := []string{}
+	if section.Content != "" {
+		parts = append(parts, section.Content)
+	}
+	for _, subsection := range section.Subsections {
+		parts = append(parts, strings.Repeat("#", subsection.Level)+" "+subsection.Heading)
+		if text := sectionText(subsection); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
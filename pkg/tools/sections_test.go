@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSections(t *testing.T) {
+	content := strings.Join([]string{
+		"Preamble outside any section",
+		"# Title",
+		"Intro",
+		"## First",
+		"First body",
+		"### Nested",
+		"Nested body",
+		"```",
+		"# not a heading",
+		"```",
+		"## Second ##",
+		"Second body",
+		"#Not a heading either",
+		"# Appendix",
+	}, "\n")
+
+	sections := ParseSections(content)
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 top-level sections, got %d: %+v", len(sections), sections)
+	}
+
+	title := sections[0]
+	if title.Heading != "Title" || title.Level != 1 || title.Content != "Intro" {
+		t.Errorf("Unexpected title section %+v", title)
+	}
+	if len(title.Subsections) != 2 {
+		t.Fatalf("Expected 2 subsections under the title, got %+v", title.Subsections)
+	}
+
+	first := title.Subsections[0]
+	if first.Heading != "First" || first.Level != 2 || first.Content != "First body" {
+		t.Errorf("Unexpected first section %+v", first)
+	}
+	if len(first.Subsections) != 1 || first.Subsections[0].Heading != "Nested" {
+		t.Fatalf("Expected Nested under First, got %+v", first.Subsections)
+	}
+	if nested := first.Subsections[0].Content; !strings.Contains(nested, "# not a heading") {
+		t.Errorf("Expected the fenced heading to stay in the content, got %q", nested)
+	}
+
+	second := title.Subsections[1]
+	if second.Heading != "Second" || !strings.Contains(second.Content, "#Not a heading either") {
+		t.Errorf("Unexpected second section %+v", second)
+	}
+	if sections[1].Heading != "Appendix" || sections[1].Content != "" {
+		t.Errorf("Unexpected appendix section %+v", sections[1])
+	}
+}
+
+func TestExtractSection_Levels(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		levels  []int
+		want    string
+	}{
+		{
+			name:    "default level",
+			content: "# ADR 1\n## Decision\nUse Kafka\n### Notes\nPartitions\n## Consequences\nMore ops",
+			levels:  DefaultSectionLevels,
+			want:    "Use Kafka\n### Notes\nPartitions",
+		},
+		{
+			name:    "level 1 headings",
+			content: "# Context\nSlow queues\n# Decision\nUse Kafka\n## Rollout\nGradual\n# Consequences\nMore ops",
+			levels:  []int{1},
+			want:    "Use Kafka\n## Rollout\nGradual",
+		},
+		{
+			name:    "level 3 headings",
+			content: "# ADR 1\n## Details\n### Decision\nUse Kafka\n### Consequences\nMore ops",
+			levels:  []int{3},
+			want:    "Use Kafka",
+		},
+		{
+			name:    "ends at a shallower heading",
+			content: "## Details\n### Decision\nUse Kafka\n## Appendix\nLinks",
+			levels:  []int{2, 3},
+			want:    "Use Kafka",
+		},
+		{
+			name:    "level not configured",
+			content: "# Decision\nUse Kafka",
+			levels:  DefaultSectionLevels,
+			want:    "",
+		},
+		{
+			name:    "heading prefix and case",
+			content: "## decision outcome\nUse Kafka",
+			levels:  DefaultSectionLevels,
+			want:    "Use Kafka",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels, err := newSectionLevels(tt.levels)
+			if err != nil {
+				t.Fatalf("newSectionLevels failed: %v", err)
+			}
+			if got := extractSection(tt.content, "Decision", levels); got != tt.want {
+				t.Errorf("extractSection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSectionLevels(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []int
+		wantErr bool
+	}{
+		{value: "2", want: []int{2}},
+		{value: " 3, 1 ,", want: []int{1, 3}},
+		{value: "", wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "7", wantErr: true},
+		{value: "two", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSectionLevels(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSectionLevels(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSectionLevels(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultStopWordLanguage is the stop-word set check-adr-alignment uses unless
+// another language is configured. search-architecture keeps every query word unless
+// stop words are configured.
+const DefaultStopWordLanguage = "en"
+
+// stopWordLists holds the built-in stop-word sets by ISO 639-1 language code
+var stopWordLists = map[string][]string{
+	"en": {
+		"the", "a", "an", "and", "or", "but", "in", "on", "at", "to", "for", "of",
+		"with", "by", "from", "as", "is", "was", "are", "were", "be", "been", "being",
+		"have", "has", "had", "do", "does", "did", "will", "would", "should", "could",
+		"may", "might", "must", "can", "this", "that", "these", "those", "we", "our", "us",
+	},
+	"es": {
+		"el", "la", "los", "las", "un", "una", "unos", "unas", "y", "o", "pero", "en",
+		"a", "de", "del", "al", "con", "por", "para", "como", "es", "son", "fue", "ser",
+		"ha", "han", "se", "que", "este", "esta", "estos", "estas", "lo", "su", "sus",
+		"nosotros", "nuestro", "nuestra", "debe", "deben", "puede", "pueden", "más",
+	},
+	"fr": {
+		"le", "la", "les", "un", "une", "des", "et", "ou", "mais", "en", "à", "au",
+		"aux", "de", "du", "dans", "par", "pour", "avec", "sur", "comme", "est", "sont",
+		"été", "être", "a", "ont", "se", "que", "qui", "ce", "cette", "ces", "son", "sa",
+		"ses", "nous", "notre", "nos", "doit", "doivent", "peut", "peuvent", "plus",
+	},
+	"de": {
+		"der", "die", "das", "den", "dem", "des", "ein", "eine", "einer", "eines",
+		"einem", "einen", "und", "oder", "aber", "in", "im", "an", "am", "zu", "zum",
+		"zur", "von", "vom", "mit", "für", "auf", "als", "ist", "sind", "war", "waren",
+		"sein", "hat", "haben", "wird", "werden", "soll", "sollen", "muss", "müssen",
+		"kann", "können", "dies", "diese", "dieser", "wir", "unser", "unsere", "nicht",
+	},
+	"pt": {
+		"o", "a", "os", "as", "um", "uma", "uns", "umas", "e", "ou", "mas", "em", "no",
+		"na", "nos", "nas", "de", "do", "da", "dos", "das", "com", "por", "para", "como",
+		"é", "são", "foi", "ser", "tem", "têm", "se", "que", "este", "esta", "estes",
+		"estas", "seu", "sua", "nós", "nosso", "nossa", "deve", "devem", "pode", "podem",
+	},
+}
+
+// StopWordLanguages returns the language codes with a built-in stop-word set
+func StopWordLanguages() []string {
+	languages := make([]string, 0, len(stopWordLists))
+	for language := range stopWordLists {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// StopWordsFor returns the built-in stop-word set for a language code
+func StopWordsFor(language string) (map[string]bool, error) {
+	words, ok := stopWordLists[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("no stop words for language %q, available: %s",
+			language, strings.Join(StopWordLanguages(), ", "))
+	}
+	return NewStopWordSet(words), nil
+}
+
+// NewStopWordSet builds a stop-word set from a custom word list. Words are
+// lowercased to match tokenized text.
+func NewStopWordSet(words []string) map[string]bool {
+	stopWords := make(map[string]bool, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			stopWords[word] = true
+		}
+	}
+	return stopWords
+}
+
+// defaultStopWords returns the stop-word set for DefaultStopWordLanguage
+func defaultStopWords() map[string]bool {
+	return NewStopWordSet(stopWordLists[DefaultStopWordLanguage])
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestStopWordsFor tests lookup of the built-in stop-word sets
+func TestStopWordsFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		stopWord  string
+		expectErr bool
+	}{
+		{"english", "en", "the", false},
+		{"german", "de", "und", false},
+		{"spanish", "es", "para", false},
+		{"french with accents", "fr", "été", false},
+		{"portuguese with accents", "pt", "são", false},
+		{"case insensitive code", "DE", "der", false},
+		{"unknown language", "xx", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopWords, err := StopWordsFor(tt.language)
+			if tt.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "available: de, en, es, fr, pt") {
+					t.Errorf("Expected an error listing the available languages, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StopWordsFor(%q) failed: %v", tt.language, err)
+			}
+			if !stopWords[tt.stopWord] {
+				t.Errorf("Expected %q in the %s stop words", tt.stopWord, tt.language)
+			}
+		})
+	}
+}
+
+// TestNewStopWordSet tests building a custom stop-word set
+func TestNewStopWordSet(t *testing.T) {
+	stopWords := NewStopWordSet([]string{"Und", " oder ", "", "ÜBER"})
+
+	if len(stopWords) != 3 {
+		t.Errorf("Expected 3 stop words, got %v", stopWords)
+	}
+	for _, word := range []string{"und", "oder", "über"} {
+		if !stopWords[word] {
+			t.Errorf("Expected lowercased %q in the set, got %v", word, stopWords)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+const (
+	// DefaultSummaryDocuments applies when max_documents is omitted
+	DefaultSummaryDocuments = 20
+	// maxSummaryDocuments bounds max_documents so one call cannot return the whole corpus
+	maxSummaryDocuments = 100
+	// maxOverviewLength is the most characters of a document's overview returned
+	maxOverviewLength = 400
+)
+
+// overviewHeadings name the sections read as a document's overview, best first.
+// ADRs rarely have an Overview, but their Context plays the same part.
+var overviewHeadings = []string{"overview", "summary", "introduction", "context"}
+
+// SummarizeCategoryTool gives a quick overview of a category's documents for
+// onboarding: each document's title, overview paragraph and key headings. The
+// overview is extracted from the document, not generated.
+type SummarizeCategoryTool struct {
+	cache  *cache.DocumentCache
+	logger *logging.StructuredLogger
+}
+
+// NewSummarizeCategoryTool creates a new SummarizeCategoryTool instance
+func NewSummarizeCategoryTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *SummarizeCategoryTool {
+	return &SummarizeCategoryTool{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// Name returns the unique identifier for the tool
+func (sct *SummarizeCategoryTool) Name() string {
+	return "summarize-category"
+}
+
+// Description returns a human-readable description
+func (sct *SummarizeCategoryTool) Description() string {
+	return "Summarizes every document in a category with its title, overview paragraph and key headings, for a quick orientation"
+}
+
+// InputSchema returns JSON schema for tool parameters
+func (sct *SummarizeCategoryTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"category": map[string]interface{}{
+				"type":        "string",
+				"enum":        searchableCategoryNames(),
+				"description": "Category of documentation to summarize",
+			},
+			"max_documents": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     1,
+				"maximum":     maxSummaryDocuments,
+				"description": fmt.Sprintf("Maximum number of documents to summarize, in path order (default: %d)", DefaultSummaryDocuments),
+			},
+		},
+		"required": []string{"category"},
+	}
+}
+
+// Execute runs the tool with validated arguments
+func (sct *SummarizeCategoryTool) Execute(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	name, _ := arguments["category"].(string)
+	category, ok := categoryAliases[name]
+	if !ok {
+		return nil, invalidArgument("invalid category: must be one of %s", strings.Join(searchableCategoryNames(), ", "))
+	}
+
+	maxDocuments := DefaultSummaryDocuments
+	if md, ok := arguments["max_documents"].(float64); ok {
+		maxDocuments = int(md)
+	} else if md, ok := arguments["max_documents"].(int); ok {
+		maxDocuments = md
+	}
+	if maxDocuments < 1 || maxDocuments > maxSummaryDocuments {
+		return nil, invalidArgument("max_documents must be between 1 and %d", maxSummaryDocuments)
+	}
+
+	sct.logger.WithContext("category", category).
+		WithContext("max_documents", maxDocuments).
+		Info("Summarizing category")
+
+	return sct.summarize(category, maxDocuments), nil
+}
+
+// summarize describes up to maxDocuments documents of category in path order, so
+// ADRs come out by number and repeated calls agree
+func (sct *SummarizeCategoryTool) summarize(category string, maxDocuments int) map[string]interface{} {
+	var docs []*models.Document
+	for _, doc := range sct.cache.GetAllDocuments() {
+		if doc.Metadata.Category == category {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Metadata.Path < docs[j].Metadata.Path
+	})
+
+	total := len(docs)
+	if len(docs) > maxDocuments {
+		docs = docs[:maxDocuments]
+	}
+
+	summaries := make([]map[string]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		sections := ParseSections(doc.Content.RawContent)
+		summaries = append(summaries, map[string]interface{}{
+			"uri":      config.ResourceURI(category, doc.Metadata.Path),
+			"title":    doc.Metadata.Title,
+			"path":     doc.Metadata.Path,
+			"overview": documentOverview(sections),
+			"headings": keyHeadings(sections),
+		})
+	}
+
+	return map[string]interface{}{
+		"category":        category,
+		"documents":       summaries,
+		"document_count":  len(summaries),
+		"total_documents": total,
+		"truncated":       total > len(summaries),
+	}
+}
+
+// allHeadingLevels lets findSection match a heading at any level
+var allHeadingLevels = sectionLevels{1: true, 2: true, 3: true, 4: true, 5: true, 6: true}
+
+// documentOverview returns the first paragraph of the document's overview section,
+// or failing that its first paragraph of prose, shortened to maxOverviewLength
+func documentOverview(sections []models.DocumentSection) string {
+	for _, name := range overviewHeadings {
+		if section, ok := findSection(sections, name, allHeadingLevels); ok {
+			if paragraph := firstParagraph(section.Content); paragraph != "" {
+				return shortenOverview(paragraph)
+			}
+		}
+	}
+	return shortenOverview(firstSectionParagraph(sections))
+}
+
+// firstSectionParagraph returns the first paragraph of prose in sections, depth first
+func firstSectionParagraph(sections []models.DocumentSection) string {
+	for _, section := range sections {
+		if paragraph := firstParagraph(section.Content); paragraph != "" {
+			return paragraph
+		}
+		if paragraph := firstSectionParagraph(section.Subsections); paragraph != "" {
+			return paragraph
+		}
+	}
+	return ""
+}
+
+// firstParagraph returns the first paragraph of text joined onto one line, skipping
+// code blocks and tables, which do not read as an overview
+func firstParagraph(text string) string {
+	inFence := false
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isFenceLine(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		if trimmed == "" {
+			if len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return strings.Join(lines, " ")
+}
+
+// shortenOverview cuts text longer than maxOverviewLength back to a word boundary
+func shortenOverview(text string) string {
+	if utf8.RuneCountInString(text) <= maxOverviewLength {
+		return text
+	}
+	cut := string([]rune(text)[:maxOverviewLength])
+	if space := strings.LastIndexByte(cut, ' '); space > 0 {
+		cut = cut[:space]
+	}
+	return strings.TrimRight(cut, " ,;:") + "..."
+}
+
+// keyHeadings returns the headings of a document's top-level sections. A document
+// under a single title heading is described by the sections below its title.
+func keyHeadings(sections []models.DocumentSection) []string {
+	if len(sections) == 1 && sections[0].Level == 1 {
+		sections = sections[0].Subsections
+	}
+	headings := make([]string, 0, len(sections))
+	for _, section := range sections {
+		headings = append(headings, section.Heading)
+	}
+	return headings
+}
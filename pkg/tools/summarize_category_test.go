@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/internal/models"
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/config"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// newSummaryTestTool builds a tool over three patterns and one guideline
+func newSummaryTestTool(t *testing.T) *SummarizeCategoryTool {
+	t.Helper()
+	docCache := cache.NewDocumentCache()
+	t.Cleanup(docCache.Close)
+
+	docs := map[string]struct{ category, title, content string }{
+		config.PatternsPath + "/repository.md": {config.CategoryPattern, "Repository Pattern", `---
+status: approved
+---
+# Repository Pattern
+
+## Overview
+
+The repository mediates between the domain and data mapping layers.
+It acts like an in-memory collection of domain objects.
+
+A second paragraph that is not part of the overview.
+
+## Implementation
+
+` + "```go\ntype Repository interface{}\n```" + `
+
+## Trade-offs
+
+More indirection.`},
+		config.PatternsPath + "/cqrs.md": {config.CategoryPattern, "CQRS", `# CQRS
+
+| Side | Model |
+|------|-------|
+| Read | Views |
+
+Separate the models that read data from the models that write it.
+
+## When to Use
+
+### Collaborative Domains
+
+Many users work on the same data.`},
+		config.PatternsPath + "/adapter.md":      {config.CategoryPattern, "Adapter", "# Adapter\n\n## Structure\n\nWraps an incompatible interface."},
+		config.GuidelinesPath + "/api-design.md": {config.CategoryGuideline, "API Design", "# API Design\n\nUse REST."},
+	}
+	for path, doc := range docs {
+		docCache.Set(path, &models.Document{
+			Metadata: models.DocumentMetadata{Path: path, Category: doc.category, Title: doc.title},
+			Content:  models.DocumentContent{RawContent: doc.content},
+		})
+	}
+	return NewSummarizeCategoryTool(docCache, logging.NewStructuredLogger("test"))
+}
+
+func TestSummarizeCategoryTool_Name(t *testing.T) {
+	tool := NewSummarizeCategoryTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+	if tool.Name() != "summarize-category" {
+		t.Errorf("Expected name summarize-category, got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Description should not be empty")
+	}
+	required, _ := tool.InputSchema()["required"].([]string)
+	if !reflect.DeepEqual(required, []string{"category"}) {
+		t.Errorf("Expected category to be required, got %v", required)
+	}
+}
+
+func TestSummarizeCategoryTool_Execute(t *testing.T) {
+	tool := newSummaryTestTool(t)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"category": config.URIPatterns})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	output := result.(map[string]interface{})
+
+	if output["category"] != config.CategoryPattern {
+		t.Errorf("Expected the patterns alias to resolve to %s, got %v", config.CategoryPattern, output["category"])
+	}
+	if output["document_count"] != 3 || output["total_documents"] != 3 || output["truncated"] != false {
+		t.Errorf("Expected 3 of 3 documents, got %v of %v (truncated %v)", output["document_count"], output["total_documents"], output["truncated"])
+	}
+
+	expected := []struct {
+		title    string
+		uri      string
+		overview string
+		headings []string
+	}{
+		{"Adapter", "architecture://patterns/adapter", "Wraps an incompatible interface.", []string{"Structure"}},
+		{"CQRS", "architecture://patterns/cqrs", "Separate the models that read data from the models that write it.", []string{"When to Use"}},
+		{
+			"Repository Pattern", "architecture://patterns/repository",
+			"The repository mediates between the domain and data mapping layers. It acts like an in-memory collection of domain objects.",
+			[]string{"Overview", "Implementation", "Trade-offs"},
+		},
+	}
+
+	documents := output["documents"].([]map[string]interface{})
+	if len(documents) != len(expected) {
+		t.Fatalf("Expected %d documents, got %d", len(expected), len(documents))
+	}
+	for i, want := range expected {
+		doc := documents[i]
+		if doc["title"] != want.title || doc["uri"] != want.uri {
+			t.Errorf("Document %d: expected %s at %s, got %v at %v", i, want.title, want.uri, doc["title"], doc["uri"])
+		}
+		if doc["overview"] != want.overview {
+			t.Errorf("%s: expected overview %q, got %q", want.title, want.overview, doc["overview"])
+		}
+		if !reflect.DeepEqual(doc["headings"], want.headings) {
+			t.Errorf("%s: expected headings %v, got %v", want.title, want.headings, doc["headings"])
+		}
+	}
+}
+
+func TestSummarizeCategoryTool_MaxDocuments(t *testing.T) {
+	tool := newSummaryTestTool(t)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"category":      config.CategoryPattern,
+		"max_documents": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	output := result.(map[string]interface{})
+
+	var titles []string
+	for _, doc := range output["documents"].([]map[string]interface{}) {
+		titles = append(titles, doc["title"].(string))
+	}
+	if !reflect.DeepEqual(titles, []string{"Adapter", "CQRS"}) {
+		t.Errorf("Expected the first 2 patterns in path order, got %v", titles)
+	}
+	if output["document_count"] != 2 || output["total_documents"] != 3 || output["truncated"] != true {
+		t.Errorf("Expected 2 of 3 documents and truncated, got %v of %v (truncated %v)", output["document_count"], output["total_documents"], output["truncated"])
+	}
+}
+
+func TestSummarizeCategoryTool_InvalidArguments(t *testing.T) {
+	tool := newSummaryTestTool(t)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		wantErr   string
+	}{
+		{"missing category", map[string]interface{}{}, "invalid category"},
+		{"unknown category", map[string]interface{}{"category": "runbooks"}, "invalid category"},
+		{"zero max_documents", map[string]interface{}{"category": "adr", "max_documents": 0}, "max_documents must be between 1 and 100"},
+		{"excessive max_documents", map[string]interface{}{"category": "adr", "max_documents": float64(101)}, "max_documents must be between 1 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tool.Execute(context.Background(), tt.arguments)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDocumentOverview(t *testing.T) {
+	longSentence := strings.Repeat("word ", 100)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"overview section", "# Title\n\nIntro text.\n\n## Overview\n\nThe overview.", "The overview."},
+		{"ADR context", "# ADR-001: Use Go\n\n## Status\nAccepted\n\n## Context\nWe need a fast service.\n\n## Decision\nUse Go.", "We need a fast service."},
+		{"first paragraph fallback", "# Title\n\nFirst paragraph\nwraps lines.\n\nSecond paragraph.", "First paragraph wraps lines."},
+		{"empty overview section falls back", "# Title\n\n## Overview\n\n### Details\n\nDetail text.", "Detail text."},
+		{"no prose", "# Title\n\n```\ncode only\n```", ""},
+		{"long overview shortened", "# Title\n\n" + longSentence, strings.TrimSpace(strings.Repeat("word ", 80)) + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := documentOverview(ParseSections(tt.content))
+			if got != tt.expected {
+				t.Errorf("documentOverview() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeyHeadings(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []string
+	}{
+		{"# Title\n## One\n### Nested\n## Two", []string{"One", "Two"}},
+		{"## One\n## Two", []string{"One", "Two"}},
+		{"# First\n# Second", []string{"First", "Second"}},
+		{"No headings at all", []string{}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			if got := keyHeadings(ParseSections(tt.content)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("keyHeadings(%q) = %v, want %v", tt.content, got, tt.expected)
+			}
+		})
+	}
+}
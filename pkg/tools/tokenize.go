@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lowercase words for keyword matching. Words are runs of
+// letters, digits and combining marks in any script; hyphens and underscores join
+// words into one term ("event-driven", "max_results"). Everything else separates
+// words, including Unicode punctuation such as em-dashes, smart quotes and slashes.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r) && r != '-' && r != '_'
+	})
+
+	tokens := fields[:0]
+	for _, field := range fields {
+		if field = strings.Trim(field, "-_"); field != "" {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// isWordRune reports whether r can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
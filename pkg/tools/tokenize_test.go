@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-architecture-service/pkg/cache"
+	"mcp-architecture-service/pkg/logging"
+)
+
+// TestTokenize tests splitting text into words across punctuation and scripts
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"lowercases", "Event Sourcing", []string{"event", "sourcing"}},
+		{"hyphenated terms kept", "event-driven read-model", []string{"event-driven", "read-model"}},
+		{"underscored terms kept", "max_results limit", []string{"max_results", "limit"}},
+		{"dangling hyphens trimmed", "-- services - queues-", []string{"services", "queues"}},
+		{"slashes", "CI/CD and/or read/write", []string{"ci", "cd", "and", "or", "read", "write"}},
+		{"em and en dashes", "caching—when needed–saves calls", []string{"caching", "when", "needed", "saves", "calls"}},
+		{"smart quotes", "the “outbox” pattern’s ‘relay’", []string{"the", "outbox", "pattern", "s", "relay"}},
+		{"brackets and symbols", "(retries) [timeouts] {breaker} #tag @owner", []string{"retries", "timeouts", "breaker", "tag", "owner"}},
+		{"accented latin", "mise en cache, côté serveur.", []string{"mise", "en", "cache", "côté", "serveur"}},
+		{"combining marks", "café décision", []string{"café", "décision"}},
+		{"cyrillic", "использовать «кэширование»!", []string{"использовать", "кэширование"}},
+		{"greek", "Χρήση: μικροϋπηρεσίες", []string{"χρήση", "μικροϋπηρεσίες"}},
+		{"digits", "ADR 001 uses HTTP/2", []string{"adr", "001", "uses", "http", "2"}},
+		{"empty", "  — “” ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := Tokenize(tt.text)
+			if strings.Join(tokens, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, tokens, tt.expected)
+			}
+		})
+	}
+}
+
+// TestTokenize_SharedAcrossTools tests that search and ADR alignment find the same terms
+func TestTokenize_SharedAcrossTools(t *testing.T) {
+	docCache := cache.NewDocumentCache()
+	logger := logging.NewStructuredLogger("test")
+	search := NewSearchArchitectureTool(docCache, logger)
+	alignment := NewCheckADRAlignmentTool(docCache, logger)
+
+	text := "Adopt event-driven messaging—“outbox” relay via Kafka/RabbitMQ"
+	expected := []string{"adopt", "event-driven", "messaging", "outbox", "relay", "via", "kafka", "rabbitmq"}
+
+	if tokens := search.tokenize(text); strings.Join(tokens, "|") != strings.Join(expected, "|") {
+		t.Errorf("search tokens = %q, want %q", tokens, expected)
+	}
+	if keywords := alignment.extractKeywords(text, ""); strings.Join(keywords, "|") != strings.Join(expected, "|") {
+		t.Errorf("ADR keywords = %q, want %q", keywords, expected)
+	}
+}
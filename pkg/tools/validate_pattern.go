@@ -5,24 +5,39 @@ import (
 	"fmt"
 	"strings"
 
+	"mcp-architecture-service/internal/models"
 	"mcp-architecture-service/pkg/cache"
 	"mcp-architecture-service/pkg/logging"
 )
 
 // ValidatePatternTool validates code against documented architectural patterns
 type ValidatePatternTool struct {
-	cache  *cache.DocumentCache
-	logger *logging.StructuredLogger
+	cache         *cache.DocumentCache
+	logger        *logging.StructuredLogger
+	sectionLevels sectionLevels
 }
 
 // NewValidatePatternTool creates a new ValidatePatternTool instance
 func NewValidatePatternTool(cache *cache.DocumentCache, logger *logging.StructuredLogger) *ValidatePatternTool {
 	return &ValidatePatternTool{
-		cache:  cache,
-		logger: logger,
+		cache:         cache,
+		logger:        logger,
+		sectionLevels: defaultSectionLevels(),
 	}
 }
 
+// SetSectionLevels changes the heading levels a pattern's Best Practices, Common
+// Pitfalls and Implementation sections may use; each rule is a heading directly
+// under them. Defaults to DefaultSectionLevels.
+func (vpt *ValidatePatternTool) SetSectionLevels(levels []int) error {
+	set, err := newSectionLevels(levels)
+	if err != nil {
+		return err
+	}
+	vpt.sectionLevels = set
+	return nil
+}
+
 // Name returns the unique identifier for the tool
 func (vpt *ValidatePatternTool) Name() string {
 	return "validate-against-pattern"
@@ -61,19 +76,19 @@ func (vpt *ValidatePatternTool) Execute(ctx context.Context, arguments map[strin
 	// Extract arguments
 	code, ok := arguments["code"].(string)
 	if !ok {
-		return nil, fmt.Errorf("code argument must be a string")
+		return nil, invalidArgument("code argument must be a string")
 	}
 
 	patternName, ok := arguments["pattern_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("pattern_name argument must be a string")
+		return nil, invalidArgument("pattern_name argument must be a string")
 	}
 
 	language, _ := arguments["language"].(string)
 
 	// Validate code length
 	if len(code) > 50000 {
-		return nil, fmt.Errorf("code exceeds maximum length of 50000 characters")
+		return nil, invalidArgument("code exceeds maximum length of 50000 characters")
 	}
 
 	// Construct and validate pattern path
@@ -81,7 +96,7 @@ func (vpt *ValidatePatternTool) Execute(ctx context.Context, arguments map[strin
 
 	// Validate path to prevent directory traversal
 	if err := ValidateResourcePath(patternPath); err != nil {
-		return nil, fmt.Errorf("invalid pattern path: %w", err)
+		return nil, invalidArgument("invalid pattern path: %v", err)
 	}
 
 	// Load pattern document from cache
@@ -144,120 +159,55 @@ type validationRule struct {
 func (vpt *ValidatePatternTool) extractValidationRules(patternContent string) []validationRule {
 	rules := []validationRule{}
 
+	sections := ParseSections(patternContent)
+
 	// Extract rules from "Best Practices" section
-	if strings.Contains(patternContent, "## Best Practices") {
-		bestPractices := vpt.extractSection(patternContent, "## Best Practices")
-		rules = append(rules, vpt.parseBestPractices(bestPractices)...)
+	if bestPractices, ok := findSection(sections, "Best Practices", vpt.sectionLevels); ok {
+		rules = append(rules, vpt.parseRuleSections(bestPractices, "warning")...)
 	}
 
 	// Extract rules from "Common Pitfalls" section
-	if strings.Contains(patternContent, "## Common Pitfalls") {
-		pitfalls := vpt.extractSection(patternContent, "## Common Pitfalls")
-		rules = append(rules, vpt.parsePitfalls(pitfalls)...)
+	if pitfalls, ok := findSection(sections, "Common Pitfalls", vpt.sectionLevels); ok {
+		rules = append(rules, vpt.parseRuleSections(pitfalls, "error")...)
 	}
 
 	// Extract rules from "Implementation" section
-	if strings.Contains(patternContent, "## Implementation") {
-		implementation := vpt.extractSection(patternContent, "## Implementation")
-		rules = append(rules, vpt.parseImplementation(implementation)...)
+	if implementation, ok := findSection(sections, "Implementation", vpt.sectionLevels); ok {
+		rules = append(rules, vpt.parseImplementation(sectionText(implementation))...)
 	}
 
 	return rules
 }
 
-// extractSection extracts content between a section header and the next section
-func (vpt *ValidatePatternTool) extractSection(content, sectionHeader string) string {
-	lines := strings.Split(content, "\n")
-	inSection := false
-	sectionContent := []string{}
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, sectionHeader) {
-			inSection = true
-			continue
-		}
-		if inSection && strings.HasPrefix(line, "## ") {
-			break
-		}
-		if inSection {
-			sectionContent = append(sectionContent, line)
-		}
-	}
-
-	return strings.Join(sectionContent, "\n")
-}
-
-// parseBestPractices extracts validation rules from best practices section
-func (vpt *ValidatePatternTool) parseBestPractices(content string) []validationRule {
+// parseRuleSections turns each heading directly under section into a validation
+// rule with the given severity. The first lines under the heading give its
+// description and, as list items, its keywords.
+func (vpt *ValidatePatternTool) parseRuleSections(section models.DocumentSection, severity string) []validationRule {
 	rules := []validationRule{}
-	lines := strings.Split(content, "\n")
-
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "### ") {
-			ruleName := strings.TrimPrefix(line, "### ")
-			description := ""
-			keywords := []string{}
-
-			// Look ahead for description
-			if i+1 < len(lines) {
-				for j := i + 1; j < len(lines) && j < i+5; j++ {
-					nextLine := strings.TrimSpace(lines[j])
-					if strings.HasPrefix(nextLine, "- ") {
-						keywords = append(keywords, strings.TrimPrefix(nextLine, "- "))
-					} else if nextLine != "" && !strings.HasPrefix(nextLine, "###") {
-						if description == "" {
-							description = nextLine
-						}
-					}
-				}
-			}
 
-			rules = append(rules, validationRule{
-				name:        ruleName,
-				description: description,
-				keywords:    keywords,
-				severity:    "warning",
-			})
-		}
-	}
-
-	return rules
-}
+	for _, ruleSection := range section.Subsections {
+		description := ""
+		keywords := []string{}
 
-// parsePitfalls extracts validation rules from common pitfalls section
-func (vpt *ValidatePatternTool) parsePitfalls(content string) []validationRule {
-	rules := []validationRule{}
-	lines := strings.Split(content, "\n")
-
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "### ") {
-			ruleName := strings.TrimPrefix(line, "### ")
-			description := ""
-			keywords := []string{}
-
-			// Look ahead for description
-			if i+1 < len(lines) {
-				for j := i + 1; j < len(lines) && j < i+5; j++ {
-					nextLine := strings.TrimSpace(lines[j])
-					if strings.HasPrefix(nextLine, "- ") {
-						keywords = append(keywords, strings.TrimPrefix(nextLine, "- "))
-					} else if nextLine != "" && !strings.HasPrefix(nextLine, "###") {
-						if description == "" {
-							description = nextLine
-						}
-					}
-				}
+		lines := strings.Split(ruleSection.Content, "\n")
+		if len(lines) > 4 {
+			lines = lines[:4]
+		}
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "- ") {
+				keywords = append(keywords, strings.TrimPrefix(line, "- "))
+			} else if line != "" && description == "" {
+				description = line
 			}
-
-			rules = append(rules, validationRule{
-				name:        ruleName,
-				description: description,
-				keywords:    keywords,
-				severity:    "error",
-			})
 		}
+
+		rules = append(rules, validationRule{
+			name:        ruleSection.Heading,
+			description: description,
+			keywords:    keywords,
+			severity:    severity,
+		})
 	}
 
 	return rules
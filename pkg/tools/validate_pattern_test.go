@@ -749,3 +749,68 @@ Provide a concrete implementation of the pattern interface.
 - Avoid returning data source-specific types
 - Keep the interface technology-agnostic
 `
+
+// TestValidatePatternTool_SectionLevels tests rule extraction from patterns whose
+// sections use other heading levels
+func TestValidatePatternTool_SectionLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		levels    []int
+		content   string
+		wantRules []string
+	}{
+		{
+			name:   "default level 2",
+			levels: DefaultSectionLevels,
+			content: "# Repository Pattern\n## Best Practices\n### Use Interfaces\nDepend on abstractions\n- interface\n" +
+				"## Common Pitfalls\n### Leaky Abstraction\nDo not expose SQL",
+			wantRules: []string{"Use Interfaces", "Leaky Abstraction"},
+		},
+		{
+			name:   "level 1 sections",
+			levels: []int{1},
+			content: "# Best Practices\n## Use Interfaces\nDepend on abstractions\n- interface\n" +
+				"# Common Pitfalls\n## Leaky Abstraction\nDo not expose SQL",
+			wantRules: []string{"Use Interfaces", "Leaky Abstraction"},
+		},
+		{
+			name:   "level 3 sections",
+			levels: []int{3},
+			content: "# Repository Pattern\n## Guidance\n### Best Practices\n#### Use Interfaces\nDepend on abstractions\n" +
+				"### Common Pitfalls\n#### Leaky Abstraction\nDo not expose SQL",
+			wantRules: []string{"Use Interfaces", "Leaky Abstraction"},
+		},
+		{
+			name:      "level 1 sections with default levels",
+			levels:    DefaultSectionLevels,
+			content:   "# Best Practices\n## Use Interfaces\nDepend on abstractions",
+			wantRules: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewValidatePatternTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+			if err := tool.SetSectionLevels(tt.levels); err != nil {
+				t.Fatalf("SetSectionLevels failed: %v", err)
+			}
+
+			rules := tool.extractValidationRules(tt.content)
+			names := []string{}
+			for _, rule := range rules {
+				names = append(names, rule.name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("Expected rules %v, got %v", tt.wantRules, names)
+			}
+			if len(rules) > 0 && (rules[0].description != "Depend on abstractions" || rules[0].severity != "warning") {
+				t.Errorf("Unexpected best practice rule %+v", rules[0])
+			}
+		})
+	}
+
+	tool := NewValidatePatternTool(cache.NewDocumentCache(), logging.NewStructuredLogger("test"))
+	if err := tool.SetSectionLevels([]int{0}); err == nil {
+		t.Error("Expected error for heading level 0")
+	}
+}